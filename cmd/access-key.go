@@ -27,6 +27,16 @@ type credential struct {
 	SecretAccessKey string `json:"secretKey"`
 }
 
+// redactedSecretKey - replaces secret keys in structured logs.
+const redactedSecretKey = "[REDACTED]"
+
+// redacted - returns credential with the secret key redacted, safe to
+// be written to logs.
+func (c credential) redacted() credential {
+	c.SecretAccessKey = redactedSecretKey
+	return c
+}
+
 const (
 	accessKeyMinLen = 5
 	accessKeyMaxLen = 20
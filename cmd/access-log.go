@@ -0,0 +1,296 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Supported formats of the access log.
+const (
+	accessLogFormatJSON     = "json"
+	accessLogFormatCombined = "combined"
+	accessLogFormatCLF      = "clf"
+)
+
+// Time format of the access log entries in common and combined format.
+const accessLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// accessLogEntry - information logged about a served request.
+type accessLogEntry struct {
+	RemoteHost string        `json:"remoteHost"`
+	AccessKey  string        `json:"accessKey"`
+	Time       time.Time     `json:"time"`
+	Method     string        `json:"method"`
+	RequestURI string        `json:"requestURI"`
+	Proto      string        `json:"proto"`
+	StatusCode int           `json:"statusCode"`
+	Size       int64         `json:"size"`
+	Referer    string        `json:"referer"`
+	UserAgent  string        `json:"userAgent"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// accessLogFormatter - formats an entry as a single log line, without
+// the trailing newline.
+type accessLogFormatter func(entry accessLogEntry) (string, error)
+
+// getAccessLogFormatter - returns the formatter of an access log
+// format, errInvalidArgument for unsupported formats.
+func getAccessLogFormatter(format string) (accessLogFormatter, error) {
+	switch format {
+	case "", accessLogFormatJSON:
+		return formatAccessLogJSON, nil
+	case accessLogFormatCombined:
+		return formatAccessLogCombined, nil
+	case accessLogFormatCLF:
+		return formatAccessLogCLF, nil
+	}
+	return nil, errInvalidArgument
+}
+
+// Returns "-" for fields which are not available, as expected by
+// common log format parsers, and the escaped value otherwise.
+func accessLogField(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return escapeAccessLogField(value)
+}
+
+// escapeAccessLogField - escapes a field the way Apache does, `"` and
+// `\` are backslash escaped and all but the printable ASCII characters
+// are written as \xNN, so that a field can not end its quotes or split
+// the line.
+func escapeAccessLogField(value string) string {
+	var buf bytes.Buffer
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == '"' || c == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case c >= 0x20 && c <= 0x7e:
+			buf.WriteByte(c)
+		default:
+			fmt.Fprintf(&buf, "\\x%02x", c)
+		}
+	}
+	return buf.String()
+}
+
+// formatAccessLogCLF - formats an entry in common log format, i.e
+// `%h %l %u %t "%r" %s %b`.
+func formatAccessLogCLF(entry accessLogEntry) (string, error) {
+	size := "-"
+	if entry.Size > 0 {
+		size = fmt.Sprintf("%d", entry.Size)
+	}
+	return fmt.Sprintf("%s - %s [%s] \"%s %s %s\" %d %s",
+		accessLogField(entry.RemoteHost),
+		accessLogField(entry.AccessKey),
+		entry.Time.Format(accessLogTimeFormat),
+		escapeAccessLogField(entry.Method), escapeAccessLogField(entry.RequestURI), escapeAccessLogField(entry.Proto),
+		entry.StatusCode, size), nil
+}
+
+// formatAccessLogCombined - formats an entry in Apache combined log
+// format, i.e `%h %l %u %t "%r" %s %b "%{Referer}i" "%{User-agent}i"`.
+func formatAccessLogCombined(entry accessLogEntry) (string, error) {
+	line, err := formatAccessLogCLF(entry)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s \"%s\" \"%s\"", line,
+		accessLogField(entry.Referer), accessLogField(entry.UserAgent)), nil
+}
+
+// formatAccessLogJSON - formats an entry as a JSON object.
+func formatAccessLogJSON(entry accessLogEntry) (string, error) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// getRequestAccessKey - returns the access key of the signature in the
+// Authorization header or the query of presigned requests, empty for
+// anonymous and unsigned requests.
+func getRequestAccessKey(r *http.Request) string {
+	authHeader := r.Header.Get("Authorization")
+	switch {
+	case isRequestPresignedSignatureV4(r):
+		credHeader, s3Error := parseCredentialHeader("Credential=" + r.URL.Query().Get("X-Amz-Credential"))
+		if s3Error != ErrNone {
+			return ""
+		}
+		return credHeader.accessKey
+	case isRequestPresignedSignatureV2(r):
+		return r.URL.Query().Get("AWSAccessKeyId")
+	case strings.HasPrefix(authHeader, signV4Algorithm):
+		signV4Values, s3Error := parseSignV4(authHeader)
+		if s3Error != ErrNone {
+			return ""
+		}
+		return signV4Values.Credential.accessKey
+	case strings.HasPrefix(authHeader, signV2Algorithm+" "):
+		// Authorization = "AWS" + " " + AWSAccessKeyId + ":" + Signature
+		credential := strings.TrimPrefix(authHeader, signV2Algorithm+" ")
+		if i := strings.LastIndex(credential, ":"); i > 0 {
+			return strings.TrimSpace(credential[:i])
+		}
+	}
+	return ""
+}
+
+// Query parameters carrying the signature of presigned requests, their
+// values are not logged.
+var accessLogRedactedParams = []string{"X-Amz-Signature", "Signature"}
+
+// redactRequestURI - returns the request URI with the signature of
+// presigned requests removed, the signature would allow anyone reading
+// the log to replay the request until it expires.
+func redactRequestURI(requestURI string) string {
+	i := strings.Index(requestURI, "?")
+	if i < 0 {
+		return requestURI
+	}
+	params := strings.Split(requestURI[i+1:], "&")
+	for j, param := range params {
+		key := strings.SplitN(param, "=", 2)[0]
+		for _, redacted := range accessLogRedactedParams {
+			if key == redacted {
+				params[j] = key + "=REDACTED"
+			}
+		}
+	}
+	return requestURI[:i+1] + strings.Join(params, "&")
+}
+
+// accessLogResponseWriter - records the status code and the number of
+// bytes of a response.
+type accessLogResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	size       int64
+}
+
+func (w *accessLogResponseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *accessLogResponseWriter) Write(p []byte) (n int, err error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	n, err = w.ResponseWriter.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// ReadFrom - keeps the underlying io.ReaderFrom reachable, objects are
+// sent with sendfile(2) through it.
+func (w *accessLogResponseWriter) ReadFrom(src io.Reader) (n int64, err error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	if rf, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(src)
+	} else {
+		n, err = io.Copy(w.ResponseWriter, src)
+	}
+	w.size += n
+	return n, err
+}
+
+// Flush - flushes the underlying writer, response writers are always
+// flushable for ListenBucketNotification.
+func (w *accessLogResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// accessLogHandler - writes an entry to the access log for every
+// served request.
+type accessLogHandler struct {
+	handler   http.Handler
+	formatter accessLogFormatter
+	mu        *sync.Mutex
+	writer    io.Writer
+}
+
+func setAccessLogHandler(h http.Handler) http.Handler {
+	if globalAccessLog == nil {
+		// Access log is disabled.
+		return h
+	}
+	formatter, err := getAccessLogFormatter(globalAccessLogFormat)
+	fatalIf(err, "Unknown access log format %s.", globalAccessLogFormat)
+	return accessLogHandler{
+		handler:   h,
+		formatter: formatter,
+		mu:        &sync.Mutex{},
+		writer:    globalAccessLog,
+	}
+}
+
+func (h accessLogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+	lw := &accessLogResponseWriter{ResponseWriter: w}
+	h.handler.ServeHTTP(lw, r)
+
+	remoteHost, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remoteHost = r.RemoteAddr
+	}
+	if lw.statusCode == 0 {
+		lw.statusCode = http.StatusOK
+	}
+	line, err := h.formatter(accessLogEntry{
+		RemoteHost: remoteHost,
+		AccessKey:  getRequestAccessKey(r),
+		Time:       startTime,
+		Method:     r.Method,
+		RequestURI: redactRequestURI(r.RequestURI),
+		Proto:      r.Proto,
+		StatusCode: lw.statusCode,
+		Size:       lw.size,
+		Referer:    r.Referer(),
+		UserAgent:  r.UserAgent(),
+		Duration:   time.Since(startTime),
+	})
+	if err != nil {
+		errorIf(err, "Unable to format access log entry.")
+		return
+	}
+	h.mu.Lock()
+	_, err = io.WriteString(h.writer, line+"\n")
+	h.mu.Unlock()
+	errorIf(err, "Unable to write access log entry.")
+}
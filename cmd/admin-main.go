@@ -0,0 +1,278 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+var adminFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "help, h",
+		Usage: "Show help.",
+	},
+	cli.StringFlag{
+		Name:  "endpoint",
+		Value: "http://localhost:9000",
+		Usage: "Address of the Minio server.",
+	},
+	cli.StringFlag{
+		Name:   "access-key",
+		Usage:  "Access key of the Minio server.",
+		EnvVar: "MINIO_ACCESS_KEY",
+	},
+	cli.StringFlag{
+		Name:   "secret-key",
+		Usage:  "Secret key of the Minio server.",
+		EnvVar: "MINIO_SECRET_KEY",
+	},
+}
+
+var adminCmd = cli.Command{
+	Name:  "admin",
+	Usage: "Manage a running Minio server.",
+	Subcommands: []cli.Command{
+		adminInfoCmd,
+		adminHealCmd,
+		adminSetCredentialsCmd,
+		adminSetLogLevelCmd,
+	},
+}
+
+var adminInfoCmd = cli.Command{
+	Name:   "info",
+	Usage:  "Display system and storage information of the server.",
+	Flags:  adminFlags,
+	Action: mainAdminInfo,
+	CustomHelpTemplate: `NAME:
+  minio admin {{.Name}} - {{.Usage}}
+
+USAGE:
+  minio admin {{.Name}} [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Display information of a remote server.
+      $ minio admin {{.Name}} --endpoint https://play.minio.io:9000 --access-key KEY --secret-key SECRET
+`,
+}
+
+var adminHealCmd = cli.Command{
+	Name:   "heal",
+	Usage:  "Heal buckets and objects on the erasure coded disks of the server.",
+	Flags:  append(adminFlags, healFlags...),
+	Action: mainAdminHeal,
+	CustomHelpTemplate: `NAME:
+  minio admin {{.Name}} - {{.Usage}}
+
+USAGE:
+  minio admin {{.Name}} [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Heal all buckets and objects.
+      $ minio admin {{.Name}} --recursive
+
+  2. Report objects in bucket "photos" which need healing, without repairing them.
+      $ minio admin {{.Name}} --dry-run --bucket photos
+`,
+}
+
+var adminSetCredentialsCmd = cli.Command{
+	Name:  "set-credentials",
+	Usage: "Change the credentials of the server and all its peers.",
+	Flags: append(adminFlags,
+		cli.StringFlag{
+			Name:  "new-access-key",
+			Usage: "New access key.",
+		},
+		cli.StringFlag{
+			Name:  "new-secret-key",
+			Usage: "New secret key.",
+		},
+	),
+	Action: mainAdminSetCredentials,
+	CustomHelpTemplate: `NAME:
+  minio admin {{.Name}} - {{.Usage}}
+
+USAGE:
+  minio admin {{.Name}} [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Change the credentials of the local server.
+      $ minio admin {{.Name}} --access-key OLDKEY --secret-key OLDSECRET --new-access-key NEWKEY --new-secret-key NEWSECRET
+`,
+}
+
+var adminSetLogLevelCmd = cli.Command{
+	Name:  "set-loglevel",
+	Usage: "Change the log level of the server.",
+	Flags: append(adminFlags,
+		cli.StringFlag{
+			Name:  "level",
+			Usage: "New log level, one of debug, info, warning, error, fatal or panic.",
+		},
+	),
+	Action: mainAdminSetLogLevel,
+	CustomHelpTemplate: `NAME:
+  minio admin {{.Name}} - {{.Usage}}
+
+USAGE:
+  minio admin {{.Name}} [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Enable debug logging.
+      $ minio admin {{.Name}} --level debug
+`,
+}
+
+// newAdminClient - returns an authenticated RPC client for the admin
+// endpoint of the server given with --endpoint.
+func newAdminClient(c *cli.Context) (*AuthRPCClient, error) {
+	u, err := url.Parse(c.String("endpoint"))
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("Invalid endpoint %s, should be of the form http(s)://host:port", c.String("endpoint"))
+	}
+	accessKey, secretKey := c.String("access-key"), c.String("secret-key")
+	if accessKey == "" || secretKey == "" {
+		return nil, errors.New("--access-key and --secret-key are required")
+	}
+	return newAuthClient(&authConfig{
+		accessKey:   accessKey,
+		secretKey:   secretKey,
+		secureConn:  u.Scheme == "https",
+		address:     u.Host,
+		path:        path.Join(reservedBucket, adminPath),
+		loginMethod: "Admin.LoginHandler",
+	}), nil
+}
+
+// adminCall - makes an admin RPC call, exits on any error.
+func adminCall(c *cli.Context, serviceMethod string, args interface {
+	SetToken(token string)
+	SetTimestamp(tstamp time.Time)
+}, reply interface{}) {
+	client, err := newAdminClient(c)
+	if err != nil {
+		console.Fatalln(err)
+	}
+	defer client.Close()
+	if err = toRPCError(client.Call(serviceMethod, args, reply)); err != nil {
+		switch e := err.(type) {
+		case *net.OpError:
+			console.Fatalf("Unable to reach %s. %s\n", c.String("endpoint"), err)
+		case RPCError:
+			if e.Code == rpcErrAuth {
+				console.Fatalf("Unable to authenticate with %s. %s\n", c.String("endpoint"), err)
+			}
+		}
+		console.Fatalf("Unable to call %s on %s. %s\n", serviceMethod, c.String("endpoint"), err)
+	}
+}
+
+// mainAdminInfo handler called for 'minio admin info' command.
+func mainAdminInfo(c *cli.Context) {
+	sysInfo := SysInfoReply{}
+	adminCall(c, "Admin.SysInfo", &GenericArgs{}, &sysInfo)
+	diskStats := DiskStatsReply{}
+	adminCall(c, "Admin.DiskStats", &GenericArgs{}, &diskStats)
+
+	console.Printf("Host: %s (%s/%s)\n", sysInfo.Hostname, sysInfo.OS, sysInfo.Arch)
+	console.Printf("CPUs: %d, Goroutines: %d, %s\n", sysInfo.NumCPU, sysInfo.NumGoroutine, sysInfo.GoVersion)
+	console.Printf("Memory: %s allocated, %s from the system\n",
+		humanize.IBytes(sysInfo.MemAlloc), humanize.IBytes(sysInfo.MemSys))
+	uptime := time.Duration(sysInfo.UptimeSeconds) * time.Second
+	console.Printf("Uptime: %s, started at %s\n", timeDurationToHumanizedDuration(uptime), sysInfo.StartTime)
+	if sysInfo.ProfileURL != "" {
+		console.Printf("Heap profile: %s\n", sysInfo.ProfileURL)
+	}
+
+	storageInfo := diskStats.StorageInfo
+	console.Printf("Storage: %s Free, %s Total\n",
+		humanize.IBytes(uint64(storageInfo.Free)), humanize.IBytes(uint64(storageInfo.Total)))
+	if storageInfo.Backend.Type == XL {
+		console.Printf("Disks: %d online, %d offline, read quorum %d, write quorum %d\n",
+			storageInfo.Backend.OnlineDisks, storageInfo.Backend.OfflineDisks,
+			storageInfo.Backend.ReadQuorum, storageInfo.Backend.WriteQuorum)
+	}
+	for _, d := range storageInfo.Disks {
+		console.Printf("  %s: %s Free, %s Used, %s Total, %d of %d inodes free\n", d.Path,
+			humanize.IBytes(uint64(d.FreeBytes)), humanize.IBytes(uint64(d.UsedBytes)),
+			humanize.IBytes(uint64(d.TotalBytes)), d.InodeFree, d.InodeTotal)
+	}
+}
+
+// mainAdminHeal handler called for 'minio admin heal' command.
+func mainAdminHeal(c *cli.Context) {
+	args := HealArgs{
+		Bucket:    c.String("bucket"),
+		Object:    c.String("object"),
+		DryRun:    c.Bool("dry-run"),
+		Recursive: c.Bool("recursive"),
+	}
+	reply := HealReply{}
+	adminCall(c, "Admin.Heal", &args, &reply)
+	console.Println(reply.Report)
+}
+
+// mainAdminSetCredentials handler called for 'minio admin set-credentials' command.
+func mainAdminSetCredentials(c *cli.Context) {
+	args := SetConfigArgs{
+		AccessKey: c.String("new-access-key"),
+		SecretKey: c.String("new-secret-key"),
+	}
+	if args.AccessKey == "" || args.SecretKey == "" {
+		cli.ShowCommandHelpAndExit(c, "set-credentials", 1)
+	}
+	reply := SetConfigReply{}
+	adminCall(c, "Admin.SetConfig", &args, &reply)
+	for svr, errMsg := range reply.PeerErrMsgs {
+		console.Printf("Unable to change credentials on %s. %s\n", svr, errMsg)
+	}
+	console.Println("Credentials changed.")
+}
+
+// mainAdminSetLogLevel handler called for 'minio admin set-loglevel' command.
+func mainAdminSetLogLevel(c *cli.Context) {
+	args := SetLogLevelArgs{Level: c.String("level")}
+	if args.Level == "" {
+		cli.ShowCommandHelpAndExit(c, "set-loglevel", 1)
+	}
+	adminCall(c, "Admin.SetLogLevel", &args, &GenericReply{})
+	console.Printf("Log level set to %s.\n", args.Level)
+}
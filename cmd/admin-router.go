@@ -0,0 +1,48 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"net/rpc"
+
+	router "github.com/gorilla/mux"
+)
+
+const (
+	adminPath = "/admin"
+)
+
+// adminAPIHandlers - RPC handlers for the 'minio admin' command.
+type adminAPIHandlers struct {
+	ObjectAPI func() ObjectLayer
+}
+
+// registerAdminRPCRouter - registers the admin RPC router.
+func registerAdminRPCRouter(mux *router.Router) error {
+	adminHandlers := &adminAPIHandlers{
+		ObjectAPI: newObjectLayerFn,
+	}
+
+	adminRPCServer := rpc.NewServer()
+	err := adminRPCServer.RegisterName("Admin", adminHandlers)
+	if err != nil {
+		return traceError(err)
+	}
+
+	adminRouter := mux.NewRoute().PathPrefix(reservedBucket).Subrouter()
+	adminRouter.Path(adminPath).Handler(adminRPCServer)
+	return nil
+}
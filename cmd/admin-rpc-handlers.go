@@ -0,0 +1,206 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"time"
+)
+
+// LoginHandler - authenticates the admin client and returns a JWT token
+// to be used with subsequent calls.
+func (a *adminAPIHandlers) LoginHandler(args *RPCLoginArgs, reply *RPCLoginReply) error {
+	jwt, err := newJWT(defaultInterNodeJWTExpiry, serverConfig.GetCredential())
+	if err != nil {
+		return err
+	}
+	if err = jwt.Authenticate(args.Username, args.Password); err != nil {
+		return err
+	}
+	token, err := jwt.GenerateToken(args.Username)
+	if err != nil {
+		return err
+	}
+	reply.Token = token
+	reply.ServerVersion = Version
+	reply.Timestamp = time.Now().UTC()
+	return nil
+}
+
+// SysInfoReply - system information of the server.
+type SysInfoReply struct {
+	Hostname     string
+	OS           string
+	Arch         string
+	NumCPU       int
+	NumGoroutine int
+	GoVersion    string
+	MemAlloc     uint64
+	MemSys       uint64
+	// URL of the heap profile, empty unless started with --enable-pprof.
+	ProfileURL string
+	// Start time of the server in RFC3339 format and its uptime.
+	StartTime     string
+	UptimeSeconds int64
+}
+
+// SysInfo - returns system information of the server.
+func (a *adminAPIHandlers) SysInfo(args *GenericArgs, reply *SysInfoReply) error {
+	if !isRPCTokenValid(args.Token) {
+		return errInvalidToken
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	*reply = SysInfoReply{
+		Hostname:     hostname,
+		OS:           runtime.GOOS,
+		Arch:         runtime.GOARCH,
+		NumCPU:       runtime.NumCPU(),
+		NumGoroutine: runtime.NumGoroutine(),
+		GoVersion:    runtime.Version(),
+		MemAlloc:     memStats.Alloc,
+		MemSys:       memStats.Sys,
+		ProfileURL:   getHeapProfileURL(),
+
+		StartTime:     globalServerStartTime.Format(time.RFC3339),
+		UptimeSeconds: int64(time.Since(globalServerStartTime).Seconds()),
+	}
+	return nil
+}
+
+// DiskStatsReply - storage usage and disks of the server.
+type DiskStatsReply struct {
+	StorageInfo StorageInfo
+}
+
+// DiskStats - returns storage usage of the server.
+func (a *adminAPIHandlers) DiskStats(args *GenericArgs, reply *DiskStatsReply) error {
+	if !isRPCTokenValid(args.Token) {
+		return errInvalidToken
+	}
+
+	objAPI := a.ObjectAPI()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+	reply.StorageInfo = objAPI.StorageInfo()
+	return nil
+}
+
+// HealArgs - scope of the heal RPC call, same as the 'minio heal' flags.
+type HealArgs struct {
+	// For Auth
+	GenericArgs
+
+	Bucket    string
+	Object    string
+	DryRun    bool
+	Recursive bool
+}
+
+// HealReply - summary of the heal run.
+type HealReply struct {
+	Report healReport
+}
+
+// Heal - heals buckets and objects on erasure coded disks.
+func (a *adminAPIHandlers) Heal(args *HealArgs, reply *HealReply) error {
+	if !isRPCTokenValid(args.Token) {
+		return errInvalidToken
+	}
+
+	objAPI := a.ObjectAPI()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+	xl, ok := objAPI.(*xlObjects)
+	if !ok {
+		return errors.New("Healing is only supported on erasure coded disks")
+	}
+
+	report, err := healXL(*xl, args.Bucket, args.Object, args.DryRun, args.Recursive)
+	reply.Report = report
+	return err
+}
+
+// SetConfigArgs - new credentials for the server.
+type SetConfigArgs struct {
+	// For Auth
+	GenericArgs
+
+	AccessKey string
+	SecretKey string
+}
+
+// SetConfigReply - errors from peers which could not be updated.
+type SetConfigReply struct {
+	PeerErrMsgs map[string]string
+}
+
+// SetConfig - updates credentials of the server and all its peers.
+func (a *adminAPIHandlers) SetConfig(args *SetConfigArgs, reply *SetConfigReply) error {
+	if !isRPCTokenValid(args.Token) {
+		return errInvalidToken
+	}
+	if !isValidAccessKey(args.AccessKey) {
+		return errInvalidAccessKeyLength
+	}
+	if !isValidSecretKey(args.SecretKey) {
+		return errInvalidSecretKeyLength
+	}
+
+	cred := credential{args.AccessKey, args.SecretKey}
+	// Notify all other Minio peers to update credentials
+	errsMap := updateCredsOnPeers(cred)
+
+	// Update local credentials
+	serverConfig.SetCredential(cred)
+	if err := serverConfig.Save(); err != nil {
+		errorIf(err, "Unable to save config file with new credentials.")
+		return err
+	}
+
+	reply.PeerErrMsgs = make(map[string]string)
+	for svr, errVal := range errsMap {
+		errorIf(errVal, "Unable to change credentials on %s.", svr)
+		reply.PeerErrMsgs[svr] = errVal.Error()
+	}
+	return nil
+}
+
+// SetLogLevelArgs - new log level for the server.
+type SetLogLevelArgs struct {
+	// For Auth
+	GenericArgs
+
+	Level string
+}
+
+// SetLogLevel - changes the level of all the loggers of the server.
+func (a *adminAPIHandlers) SetLogLevel(args *SetLogLevelArgs, reply *GenericReply) error {
+	if !isRPCTokenValid(args.Token) {
+		return errInvalidToken
+	}
+	return setLogLevel(args.Level)
+}
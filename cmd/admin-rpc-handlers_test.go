@@ -0,0 +1,135 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	router "github.com/gorilla/mux"
+)
+
+// Test admin RPC handlers.
+func TestAdminRPC(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	objLayer, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = objLayer
+	globalObjLayerMutex.Unlock()
+
+	muxRouter := router.NewRouter()
+	if err = registerAdminRPCRouter(muxRouter); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(muxRouter)
+	defer server.Close()
+
+	creds := serverConfig.GetCredential()
+	authConf := &authConfig{
+		address:     server.Listener.Addr().String(),
+		accessKey:   creds.AccessKeyID,
+		secretKey:   creds.SecretAccessKey,
+		path:        path.Join(reservedBucket, adminPath),
+		loginMethod: "Admin.LoginHandler",
+	}
+
+	// Validate for invalid token.
+	args := GenericArgs{Token: "garbage", Timestamp: time.Now().UTC()}
+	rclient := newClient(authConf.address, authConf.path, false)
+	defer rclient.Close()
+	if err = rclient.Call("Admin.SysInfo", &args, &SysInfoReply{}); err == nil || err.Error() != errInvalidToken.Error() {
+		t.Fatalf("Expected %v, got %v", errInvalidToken, err)
+	}
+
+	client := newAuthClient(authConf)
+	defer client.Close()
+
+	sysInfo := SysInfoReply{}
+	if err = client.Call("Admin.SysInfo", &GenericArgs{}, &sysInfo); err != nil {
+		t.Fatal(err)
+	}
+	if sysInfo.NumCPU == 0 || sysInfo.Hostname == "" {
+		t.Fatalf("Unexpected system info %#v", sysInfo)
+	}
+	if sysInfo.UptimeSeconds < 0 {
+		t.Fatalf("Unexpected uptime %d", sysInfo.UptimeSeconds)
+	}
+	if _, err = time.Parse(time.RFC3339, sysInfo.StartTime); err != nil {
+		t.Fatalf("Unable to parse start time %q: %v", sysInfo.StartTime, err)
+	}
+
+	diskStats := DiskStatsReply{}
+	if err = client.Call("Admin.DiskStats", &GenericArgs{}, &diskStats); err != nil {
+		t.Fatal(err)
+	}
+	if diskStats.StorageInfo.Backend.Type != XL || diskStats.StorageInfo.Backend.OnlineDisks != len(fsDirs) {
+		t.Fatalf("Unexpected disk stats %#v", diskStats)
+	}
+
+	bucket := getRandomBucketName()
+	if err = objLayer.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	healReply := HealReply{}
+	if err = client.Call("Admin.Heal", &HealArgs{Recursive: true, DryRun: true}, &healReply); err != nil {
+		t.Fatal(err)
+	}
+	if !healReply.Report.DryRun || healReply.Report.BucketsScanned != 1 {
+		t.Fatalf("Unexpected heal report %#v", healReply.Report)
+	}
+
+	// Log level is changed on all the registered loggers.
+	testLog := logrus.New()
+	testLog.Level = logrus.ErrorLevel
+	savedLoggers := log.loggers
+	log.loggers = []*logrus.Logger{testLog}
+	defer func() {
+		log.loggers = savedLoggers
+	}()
+	if err = client.Call("Admin.SetLogLevel", &SetLogLevelArgs{Level: "debug"}, &GenericReply{}); err != nil {
+		t.Fatal(err)
+	}
+	if testLog.Level != logrus.DebugLevel {
+		t.Fatalf("Expected log level %s, got %s", logrus.DebugLevel, testLog.Level)
+	}
+	if err = client.Call("Admin.SetLogLevel", &SetLogLevelArgs{Level: "unknown"}, &GenericReply{}); err == nil {
+		t.Fatal("Expected an error for an unknown log level")
+	}
+
+	// Credentials are validated before they are changed.
+	if err = client.Call("Admin.SetConfig", &SetConfigArgs{AccessKey: "ab", SecretKey: "abcd1234"}, &SetConfigReply{}); err == nil || err.Error() != errInvalidAccessKeyLength.Error() {
+		t.Fatalf("Expected %v, got %v", errInvalidAccessKeyLength, err)
+	}
+	newCreds := credential{AccessKeyID: "newaccesskey", SecretAccessKey: "newsecretkey"}
+	if err = client.Call("Admin.SetConfig", &SetConfigArgs{AccessKey: newCreds.AccessKeyID, SecretKey: newCreds.SecretAccessKey}, &SetConfigReply{}); err != nil {
+		t.Fatal(err)
+	}
+	if serverConfig.GetCredential() != newCreds {
+		t.Fatalf("Expected credentials %#v, got %#v", newCreds, serverConfig.GetCredential())
+	}
+}
@@ -48,7 +48,11 @@ const (
 	ErrNone APIErrorCode = iota
 	ErrAccessDenied
 	ErrBadDigest
+	ErrBadChecksum
+	ErrObjectTampered
+	ErrInvalidEncryptionMethod
 	ErrEntityTooSmall
+	ErrPartEntityTooSmall
 	ErrEntityTooLarge
 	ErrIncompleteBody
 	ErrInternalError
@@ -57,12 +61,15 @@ const (
 	ErrInvalidDigest
 	ErrInvalidRange
 	ErrInvalidMaxKeys
+	ErrInvalidContinuationToken
 	ErrInvalidMaxUploads
 	ErrInvalidMaxParts
 	ErrInvalidPartNumberMarker
+	ErrInvalidPartNumber
 	ErrInvalidRequestBody
 	ErrInvalidCopySource
 	ErrInvalidCopyDest
+	ErrInvalidCopyPartRange
 	ErrInvalidPolicyDocument
 	ErrInvalidObjectState
 	ErrMalformedXML
@@ -76,6 +83,8 @@ const (
 	ErrNotImplemented
 	ErrPreconditionFailed
 	ErrRequestTimeTooSkewed
+	ErrOperationTimedOut
+	ErrRequestTimeout
 	ErrSignatureDoesNotMatch
 	ErrMethodNotAllowed
 	ErrInvalidPart
@@ -109,6 +118,25 @@ const (
 	ErrInvalidQuerySignatureAlgo
 	ErrInvalidQueryParams
 	ErrBucketAlreadyOwnedByYou
+	ErrInvalidCannedACL
+	ErrInvalidStorageClass
+	ErrUnsupportedContentType
+	ErrMetadataTooLarge
+	ErrInvalidMetadataKey
+	ErrQuotaExceeded
+	ErrNoSuchBucketQuota
+	ErrInvalidBucketQuota
+	ErrInvalidLocationConstraint
+	ErrNoSuchLifecycleConfiguration
+	ErrInvalidLifecycleConfiguration
+	ErrTooManyMultipartUploads
+	ErrTooManyRequests
+	ErrSlowDown
+	ErrNoSuchObjectLockConfiguration
+	ErrInvalidObjectLockConfiguration
+	ErrObjectLockConfigurationNotAllowed
+	ErrObjectLockNotEnabled
+	ErrInvalidObjectLockHeaders
 	// Add new error codes here.
 
 	// Bucket notification related errors.
@@ -132,6 +160,7 @@ const (
 	ErrWriteQuorum
 	ErrStorageFull
 	ErrObjectExistsAsDirectory
+	ErrObjectInUse
 	ErrPolicyNesting
 	ErrInvalidObjectName
 	ErrServerNotInitialized
@@ -148,6 +177,11 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "This copy request is illegal because it is trying to copy an object to itself.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrInvalidCopyPartRange: {
+		Code:           "InvalidArgument",
+		Description:    "The x-amz-copy-source-range value must be of the form bytes=first-last where first and last are the zero-based offsets of the first and last bytes to copy",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrInvalidCopySource: {
 		Code:           "InvalidArgument",
 		Description:    "Copy Source must mention the source bucket and key: sourcebucket/sourcekey.",
@@ -168,6 +202,11 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Argument maxKeys must be an integer between 0 and 2147483647",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrInvalidContinuationToken: {
+		Code:           "InvalidArgument",
+		Description:    "The continuation token provided is incorrect",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrInvalidMaxParts: {
 		Code:           "InvalidArgument",
 		Description:    "Argument max-parts must be an integer between 0 and 2147483647",
@@ -178,6 +217,11 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Argument partNumberMarker must be an integer.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrInvalidPartNumber: {
+		Code:           "InvalidArgument",
+		Description:    "Part number must be between 1 and 10000",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrInvalidPolicyDocument: {
 		Code:           "InvalidPolicyDocument",
 		Description:    "The content of the form does not meet the conditions specified in the policy document.",
@@ -193,11 +237,31 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "The Content-Md5 you specified did not match what we received.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrBadChecksum: {
+		Code:           "BadChecksum",
+		Description:    "The x-amz-checksum-sha256 you specified did not match what we received.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrObjectTampered: {
+		Code:           "ObjectTampered",
+		Description:    "The requested object was modified and may be compromised.",
+		HTTPStatusCode: http.StatusInternalServerError,
+	},
+	ErrInvalidEncryptionMethod: {
+		Code:           "InvalidArgument",
+		Description:    "The encryption method specified is not supported.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrEntityTooSmall: {
 		Code:           "EntityTooSmall",
 		Description:    "Your proposed upload is smaller than the minimum allowed object size.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrPartEntityTooSmall: {
+		Code:           "EntityTooSmall",
+		Description:    "Your proposed upload part is smaller than the minimum allowed size of 5 MiB, only the last part may be smaller.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrEntityTooLarge: {
 		Code:           "EntityTooLarge",
 		Description:    "Your proposed upload exceeds the maximum allowed object size.",
@@ -288,6 +352,16 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "The difference between the request time and the server's time is too large.",
 		HTTPStatusCode: http.StatusForbidden,
 	},
+	ErrOperationTimedOut: {
+		Code:           "RequestTimeout",
+		Description:    "A timeout occurred while processing the request, please retry the request.",
+		HTTPStatusCode: http.StatusServiceUnavailable,
+	},
+	ErrRequestTimeout: {
+		Code:           "RequestTimeout",
+		Description:    "Your socket connection to the server was not read from or written to within the timeout period.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrSignatureDoesNotMatch: {
 		Code:           "SignatureDoesNotMatch",
 		Description:    "The request signature we calculated does not match the signature you provided. Check your key and signing method.",
@@ -471,6 +545,101 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Your previous request to create the named bucket succeeded and you already own it.",
 		HTTPStatusCode: http.StatusConflict,
 	},
+	ErrInvalidCannedACL: {
+		Code:           "InvalidArgument",
+		Description:    "The specified canned ACL is not supported.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidStorageClass: {
+		Code:           "InvalidStorageClass",
+		Description:    "The storage class you specified is not valid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrUnsupportedContentType: {
+		Code:           "InvalidArgument",
+		Description:    "Content-Type application/x-www-form-urlencoded is not supported for object uploads.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrMetadataTooLarge: {
+		Code:           "MetadataTooLarge",
+		Description:    "Your metadata headers exceed the maximum allowed metadata size.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidMetadataKey: {
+		Code:           "InvalidArgument",
+		Description:    "User metadata keys must only contain printable ASCII characters.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrQuotaExceeded: {
+		Code:           "QuotaExceeded",
+		Description:    "The upload would exceed the quota of the bucket.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrNoSuchBucketQuota: {
+		Code:           "NoSuchBucketQuota",
+		Description:    "The bucket quota does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrTooManyMultipartUploads: {
+		Code:           "TooManyRequests",
+		Description:    "The bucket has reached the maximum number of in-progress multipart uploads.",
+		HTTPStatusCode: http.StatusTooManyRequests,
+	},
+	ErrTooManyRequests: {
+		Code:           "TooManyRequests",
+		Description:    "Your client has exceeded its rate of requests, please retry later.",
+		HTTPStatusCode: http.StatusTooManyRequests,
+	},
+	ErrSlowDown: {
+		Code:           "SlowDown",
+		Description:    "Please reduce your request rate.",
+		HTTPStatusCode: http.StatusServiceUnavailable,
+	},
+	ErrInvalidBucketQuota: {
+		Code:           "InvalidArgument",
+		Description:    "The bucket quota is malformed or has a negative size.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidLocationConstraint: {
+		Code:           "IllegalLocationConstraintException",
+		Description:    "The location constraint is incompatible with the region configured on the server.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchLifecycleConfiguration: {
+		Code:           "NoSuchLifecycleConfiguration",
+		Description:    "The lifecycle configuration does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidLifecycleConfiguration: {
+		Code:           "InvalidArgument",
+		Description:    "The lifecycle configuration is not valid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchObjectLockConfiguration: {
+		Code:           "ObjectLockConfigurationNotFoundError",
+		Description:    "Object Lock configuration does not exist for this bucket.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidObjectLockConfiguration: {
+		Code:           "InvalidArgument",
+		Description:    "The object lock configuration is not valid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrObjectLockConfigurationNotAllowed: {
+		Code:           "InvalidBucketState",
+		Description:    "Object Lock configuration cannot be enabled on existing buckets.",
+		HTTPStatusCode: http.StatusConflict,
+	},
+	ErrObjectLockNotEnabled: {
+		Code:           "InvalidRequest",
+		Description:    "Bucket is missing Object Lock Configuration.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidObjectLockHeaders: {
+		Code:           "InvalidArgument",
+		Description:    "The object lock mode or retain until date is not valid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 
 	/// Bucket notification related errors.
 	ErrEventNotification: {
@@ -537,6 +706,11 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Object name already exists as a directory.",
 		HTTPStatusCode: http.StatusConflict,
 	},
+	ErrObjectInUse: {
+		Code:           "XMinioObjectInUse",
+		Description:    "Object is being read and cannot be deleted, please try again.",
+		HTTPStatusCode: http.StatusConflict,
+	},
 	ErrReadQuorum: {
 		Code:           "XMinioReadQuorum",
 		Description:    "Multiple disk failures, unable to reconstruct data.",
@@ -580,6 +754,13 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		apiErr = ErrSignatureDoesNotMatch
 	case errContentSHA256Mismatch:
 		apiErr = ErrContentSHA256Mismatch
+	case errTransferRateTooLow:
+		apiErr = ErrRequestTimeout
+	case errQuotaExceeded:
+		apiErr = ErrQuotaExceeded
+	case errRequestBodyTooLarge:
+		// Request body crossed the allowed limit.
+		apiErr = ErrEntityTooLarge
 	}
 
 	if apiErr != ErrNone {
@@ -588,15 +769,21 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 	}
 
 	switch err.(type) {
-	case StorageFull:
+	case StorageFull, InodeExhausted:
 		apiErr = ErrStorageFull
 	case BadDigest:
 		apiErr = ErrBadDigest
+	case BadChecksum:
+		apiErr = ErrBadChecksum
+	case ObjectTampered:
+		apiErr = ErrObjectTampered
 	case IncompleteBody:
 		apiErr = ErrIncompleteBody
 	case ObjectExistsAsDirectory:
 		apiErr = ErrObjectExistsAsDirectory
-	case PrefixAccessDenied:
+	case ObjectInUse:
+		apiErr = ErrObjectInUse
+	case PrefixAccessDenied, ObjectLocked:
 		apiErr = ErrAccessDenied
 	case BucketNameInvalid:
 		apiErr = ErrInvalidBucketName
@@ -616,6 +803,8 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		apiErr = ErrInvalidPart
 	case InsufficientWriteQuorum:
 		apiErr = ErrWriteQuorum
+	case ListingBusy:
+		apiErr = ErrSlowDown
 	case InsufficientReadQuorum:
 		apiErr = ErrReadQuorum
 	case UnsupportedDelimiter:
@@ -628,6 +817,8 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		apiErr = ErrNoSuchUpload
 	case PartTooSmall:
 		apiErr = ErrEntityTooSmall
+	case PartNotLastTooSmall:
+		apiErr = ErrPartEntityTooSmall
 	case SHA256Mismatch:
 		apiErr = ErrContentSHA256Mismatch
 	case ObjectTooLarge:
@@ -23,8 +23,12 @@ import (
 	"net/http"
 	"runtime"
 	"strconv"
+	"strings"
 )
 
+// Content-Type of objects whose type is not known.
+const defaultContentType = "application/octet-stream"
+
 // Static alphanumeric table used for generating unique request ids
 var alphaNumericTable = []byte("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ")
 
@@ -38,6 +42,18 @@ func newRequestID() string {
 	return string(alpha)
 }
 
+// sanitizeHeaderValue - strips the characters which are not allowed in
+// HTTP header values, i.e CR and LF which would inject headers or split
+// the response, and all the other control characters but horizontal tab.
+func sanitizeHeaderValue(v string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' || (r >= 0x20 && r != 0x7f) {
+			return r
+		}
+		return -1
+	}, v)
+}
+
 // Write http common headers
 func setCommonHeaders(w http.ResponseWriter) {
 	// Set unique request ID for each reply.
@@ -68,20 +84,42 @@ func setObjectHeaders(w http.ResponseWriter, objInfo ObjectInfo, contentRange *h
 	w.Header().Set("Last-Modified", lastModified)
 
 	// Set Etag if available.
-	if objInfo.MD5Sum != "" {
-		w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
+	if etag := getObjectETag(objInfo); etag != "" {
+		w.Header().Set("ETag", "\""+sanitizeHeaderValue(etag)+"\"")
 	}
 
 	// Set all other user defined metadata.
 	for k, v := range objInfo.UserDefined {
-		w.Header().Set(k, v)
+		if strings.HasPrefix(k, internalMetaPrefix) {
+			continue
+		}
+		w.Header().Set(k, sanitizeHeaderValue(v))
+	}
+
+	// Storage class is always returned, STANDARD unless saved.
+	w.Header().Set(storageClassMetaKey, getObjectStorageClass(objInfo))
+
+	// Checksum of encrypted and compressed objects is of the stored
+	// transformed data.
+	if isObjectEncrypted(objInfo) || isObjectCompressed(objInfo) {
+		w.Header().Del(objectChecksumMetaKey)
+	}
+
+	// Set content type, objects without a saved content type are
+	// served as binary data.
+	contentType := objInfo.ContentType
+	if contentType == "" {
+		contentType = defaultContentType
 	}
+	w.Header().Set("Content-Type", sanitizeHeaderValue(contentType))
 
 	// for providing ranged content
 	if contentRange != nil && contentRange.offsetBegin > -1 {
 		// Override content-length
 		w.Header().Set("Content-Length", strconv.FormatInt(contentRange.getLength(), 10))
 		w.Header().Set("Content-Range", contentRange.String())
+		// Checksum is of the whole object, not of the requested range.
+		w.Header().Del(objectChecksumMetaKey)
 		w.WriteHeader(http.StatusPartialContent)
 	}
 }
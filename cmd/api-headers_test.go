@@ -38,3 +38,24 @@ func TestNewRequestID(t *testing.T) {
 		}
 	}
 }
+
+// Tests that characters not allowed in HTTP header values are stripped.
+func TestSanitizeHeaderValue(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{"", ""},
+		{"text/plain; charset=utf-8", "text/plain; charset=utf-8"},
+		{"value\twith tab", "value\twith tab"},
+		{"evil\r\nX-Injected: evil", "evilX-Injected: evil"},
+		{"split\r\n\r\nHTTP/1.1 200 OK", "splitHTTP/1.1 200 OK"},
+		{"null\x00byte\x7f", "nullbyte"},
+		{"ünïcödé", "ünïcödé"},
+	}
+	for i, testCase := range testCases {
+		if value := sanitizeHeaderValue(testCase.value); value != testCase.expected {
+			t.Errorf("Test %d: Expected %q, got %q", i+1, testCase.expected, value)
+		}
+	}
+}
@@ -31,6 +31,10 @@ func getListObjectsV1Args(values url.Values) (prefix, marker, delimiter string,
 	} else {
 		maxkeys = maxObjectList
 	}
+	// Values above the limit are clamped to it.
+	if maxkeys > maxObjectList {
+		maxkeys = maxObjectList
+	}
 	encodingType = values.Get("encoding-type")
 	return
 }
@@ -46,6 +50,10 @@ func getListObjectsV2Args(values url.Values) (prefix, token, startAfter, delimit
 	} else {
 		maxkeys = maxObjectList
 	}
+	// Values above the limit are clamped to it.
+	if maxkeys > maxObjectList {
+		maxkeys = maxObjectList
+	}
 	fetchOwner = values.Get("fetch-owner") == "true"
 	encodingType = values.Get("encoding-type")
 	return
@@ -62,6 +70,10 @@ func getBucketMultipartResources(values url.Values) (prefix, keyMarker, uploadID
 	} else {
 		maxUploads = maxUploadsList
 	}
+	// Zero means the default, values above the limit are clamped to it.
+	if maxUploads == 0 || maxUploads > maxUploadsList {
+		maxUploads = maxUploadsList
+	}
 	encodingType = values.Get("encoding-type")
 	return
 }
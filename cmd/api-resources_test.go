@@ -129,6 +129,12 @@ func TestListObjectsV1Resources(t *testing.T) {
 			maxKeys:      1000,
 			encodingType: "gzip",
 		},
+		{
+			values: url.Values{
+				"max-keys": []string{"1500"},
+			},
+			maxKeys: 1000,
+		},
 	}
 
 	for i, testCase := range testCases {
@@ -151,6 +157,36 @@ func TestListObjectsV1Resources(t *testing.T) {
 	}
 }
 
+// Validates max-uploads defaults and limits for ?uploads.
+func TestBucketMultipartResources(t *testing.T) {
+	testCases := []struct {
+		maxUploads         string
+		expectedMaxUploads int
+	}{
+		// Not set, the default is used.
+		{"", 1000},
+		// Zero also means the default.
+		{"0", 1000},
+		{"100", 100},
+		{"1000", 1000},
+		// Clamped to the limit.
+		{"1500", 1000},
+		// Negative values are left for the handler to reject.
+		{"-1", -1},
+	}
+
+	for i, testCase := range testCases {
+		values := url.Values{}
+		if testCase.maxUploads != "" {
+			values.Set("max-uploads", testCase.maxUploads)
+		}
+		_, _, _, _, maxUploads, _ := getBucketMultipartResources(values)
+		if maxUploads != testCase.expectedMaxUploads {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.expectedMaxUploads, maxUploads)
+		}
+	}
+}
+
 // Validates extracting information for object resources.
 func TestGetObjectsResources(t *testing.T) {
 	testCases := []struct {
@@ -17,9 +17,13 @@
 package cmd
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/xml"
 	"net/http"
 	"path"
+	"strings"
 	"time"
 )
 
@@ -171,6 +175,9 @@ type Upload struct {
 	Owner        Owner
 	StorageClass string
 	Initiated    string
+
+	// Total size of the parts committed so far, not part of S3.
+	CommittedSize int64 `xml:"CommittedSize,omitempty"`
 }
 
 // CommonPrefix container for prefix response in ListObjectsResponse
@@ -205,6 +212,13 @@ type CopyObjectResponse struct {
 	ETag         string   // md5sum of the copied object.
 }
 
+// CopyObjectPartResponse container returns ETag and LastModified of the successfully copied object part
+type CopyObjectPartResponse struct {
+	XMLName      xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CopyPartResult" json:"-"`
+	LastModified string   // time string of format "2006-01-02T15:04:05.000Z"
+	ETag         string   // md5sum of the copied object part.
+}
+
 // Initiator inherit from Owner struct, fields are same
 type Initiator Owner
 
@@ -214,6 +228,33 @@ type Owner struct {
 	DisplayName string
 }
 
+// Grantee - grantee of an ACL grant, either the owner or a predefined group.
+type Grantee struct {
+	XMLNS       string `xml:"xmlns:xsi,attr"`
+	Type        string `xml:"xsi:type,attr"`
+	ID          string `xml:",omitempty"`
+	DisplayName string `xml:",omitempty"`
+	URI         string `xml:",omitempty"`
+}
+
+// Grant - container for a grantee and the permission granted.
+type Grant struct {
+	Grantee    Grantee
+	Permission string
+}
+
+// AccessControlPolicy - format for get object ACL response.
+type AccessControlPolicy struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ AccessControlPolicy" json:"-"`
+
+	Owner Owner
+
+	// Container for all the grants.
+	AccessControlList struct {
+		Grants []Grant `xml:"Grant"`
+	}
+}
+
 // InitiateMultipartUploadResponse container for InitiateMultiPartUpload response, provides uploadID to start MultiPart upload
 type InitiateMultipartUploadResponse struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ InitiateMultipartUploadResult" json:"-"`
@@ -261,6 +302,17 @@ func getObjectLocation(bucketName string, key string) string {
 	return "/" + bucketName + "/" + key
 }
 
+// getCanonicalOwnerID - returns the canonical ID of the owner of all
+// buckets and objects, HMAC-SHA256 of the access key keyed by the
+// secret key. It is the same in all the responses of a server, yet the
+// access key can not be recovered from it by hashing candidates.
+func getCanonicalOwnerID() string {
+	cred := serverConfig.GetCredential()
+	mac := hmac.New(sha256.New, []byte(cred.SecretAccessKey))
+	mac.Write([]byte(cred.AccessKeyID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 // generates ListBucketsResponse from array of BucketInfo which can be
 // serialized to match XML and JSON API spec output.
 func generateListBucketsResponse(buckets []BucketInfo) ListBucketsResponse {
@@ -268,7 +320,7 @@ func generateListBucketsResponse(buckets []BucketInfo) ListBucketsResponse {
 	var data = ListBucketsResponse{}
 	var owner = Owner{}
 
-	owner.ID = "minio"
+	owner.ID = getCanonicalOwnerID()
 	owner.DisplayName = "minio"
 
 	for _, bucket := range buckets {
@@ -284,14 +336,63 @@ func generateListBucketsResponse(buckets []BucketInfo) ListBucketsResponse {
 	return data
 }
 
+// Predefined grantee groups used by canned ACLs.
+const (
+	allUsersGroupURI           = "http://acs.amazonaws.com/groups/global/AllUsers"
+	authenticatedUsersGroupURI = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+)
+
+// generates an AccessControlPolicy response with the grants implied by
+// the canned ACL, an empty ACL is treated as private.
+func generateAccessControlPolicyResponse(acl string) AccessControlPolicy {
+	var data = AccessControlPolicy{}
+	var owner = Owner{}
+
+	owner.ID = getCanonicalOwnerID()
+	owner.DisplayName = "minio"
+
+	newGrant := func(grantee Grantee, permission string) Grant {
+		grantee.XMLNS = "http://www.w3.org/2001/XMLSchema-instance"
+		return Grant{Grantee: grantee, Permission: permission}
+	}
+
+	// Owner always has full control.
+	grants := []Grant{
+		newGrant(Grantee{Type: "CanonicalUser", ID: owner.ID, DisplayName: owner.DisplayName}, "FULL_CONTROL"),
+	}
+	switch acl {
+	case cannedACLPublicRead:
+		grants = append(grants, newGrant(Grantee{Type: "Group", URI: allUsersGroupURI}, "READ"))
+	case cannedACLPublicReadWrite:
+		grants = append(grants, newGrant(Grantee{Type: "Group", URI: allUsersGroupURI}, "READ"))
+		grants = append(grants, newGrant(Grantee{Type: "Group", URI: allUsersGroupURI}, "WRITE"))
+	case cannedACLAuthenticatedRead:
+		grants = append(grants, newGrant(Grantee{Type: "Group", URI: authenticatedUsersGroupURI}, "READ"))
+	}
+
+	data.Owner = owner
+	data.AccessControlList.Grants = grants
+
+	return data
+}
+
+// s3EncodeName - encodes a name in a list response with the requested
+// encoding type, "url" is the only one supported by S3.
+func s3EncodeName(name, encodingType string) string {
+	if strings.ToLower(encodingType) != "url" {
+		return name
+	}
+	return getURLEncodedName(name)
+}
+
 // generates an ListObjectsV1 response for the said bucket with other enumerated options.
-func generateListObjectsV1Response(bucket, prefix, marker, delimiter string, maxKeys int, resp ListObjectsInfo) ListObjectsResponse {
+func generateListObjectsV1Response(bucket, prefix, marker, delimiter, encodingType string, maxKeys int, resp ListObjectsInfo) ListObjectsResponse {
 	var contents []Object
 	var prefixes []CommonPrefix
 	var owner = Owner{}
 	var data = ListObjectsResponse{}
 
-	owner.ID = "minio"
+	owner.ID = getCanonicalOwnerID()
 	owner.DisplayName = "minio"
 
 	for _, object := range resp.Objects {
@@ -299,30 +400,30 @@ func generateListObjectsV1Response(bucket, prefix, marker, delimiter string, max
 		if object.Name == "" {
 			continue
 		}
-		content.Key = object.Name
+		content.Key = s3EncodeName(object.Name, encodingType)
 		content.LastModified = object.ModTime.UTC().Format(timeFormatAMZLong)
-		if object.MD5Sum != "" {
-			content.ETag = "\"" + object.MD5Sum + "\""
+		if etag := getObjectETag(object); etag != "" {
+			content.ETag = "\"" + etag + "\""
 		}
 		content.Size = object.Size
-		content.StorageClass = "STANDARD"
+		content.StorageClass = getObjectStorageClass(object)
 		content.Owner = owner
 		contents = append(contents, content)
 	}
-	// TODO - support EncodingType in xml decoding
 	data.Name = bucket
 	data.Contents = contents
 
-	data.Prefix = prefix
-	data.Marker = marker
-	data.Delimiter = delimiter
+	data.EncodingType = encodingType
+	data.Prefix = s3EncodeName(prefix, encodingType)
+	data.Marker = s3EncodeName(marker, encodingType)
+	data.Delimiter = s3EncodeName(delimiter, encodingType)
 	data.MaxKeys = maxKeys
 
-	data.NextMarker = resp.NextMarker
+	data.NextMarker = s3EncodeName(resp.NextMarker, encodingType)
 	data.IsTruncated = resp.IsTruncated
 	for _, prefix := range resp.Prefixes {
 		var prefixItem = CommonPrefix{}
-		prefixItem.Prefix = prefix
+		prefixItem.Prefix = s3EncodeName(prefix, encodingType)
 		prefixes = append(prefixes, prefixItem)
 	}
 	data.CommonPrefixes = prefixes
@@ -330,14 +431,14 @@ func generateListObjectsV1Response(bucket, prefix, marker, delimiter string, max
 }
 
 // generates an ListObjectsV2 response for the said bucket with other enumerated options.
-func generateListObjectsV2Response(bucket, prefix, token, startAfter, delimiter string, fetchOwner bool, maxKeys int, resp ListObjectsInfo) ListObjectsV2Response {
+func generateListObjectsV2Response(bucket, prefix, token, startAfter, delimiter, encodingType string, fetchOwner bool, maxKeys int, resp ListObjectsInfo) ListObjectsV2Response {
 	var contents []Object
 	var prefixes []CommonPrefix
 	var owner = Owner{}
 	var data = ListObjectsV2Response{}
 
 	if fetchOwner {
-		owner.ID = "minio"
+		owner.ID = getCanonicalOwnerID()
 		owner.DisplayName = "minio"
 	}
 
@@ -346,30 +447,30 @@ func generateListObjectsV2Response(bucket, prefix, token, startAfter, delimiter
 		if object.Name == "" {
 			continue
 		}
-		content.Key = object.Name
+		content.Key = s3EncodeName(object.Name, encodingType)
 		content.LastModified = object.ModTime.UTC().Format(timeFormatAMZLong)
-		if object.MD5Sum != "" {
-			content.ETag = "\"" + object.MD5Sum + "\""
+		if etag := getObjectETag(object); etag != "" {
+			content.ETag = "\"" + etag + "\""
 		}
 		content.Size = object.Size
-		content.StorageClass = "STANDARD"
+		content.StorageClass = getObjectStorageClass(object)
 		content.Owner = owner
 		contents = append(contents, content)
 	}
-	// TODO - support EncodingType in xml decoding
 	data.Name = bucket
 	data.Contents = contents
 
-	data.StartAfter = startAfter
-	data.Delimiter = delimiter
-	data.Prefix = prefix
+	data.EncodingType = encodingType
+	data.StartAfter = s3EncodeName(startAfter, encodingType)
+	data.Delimiter = s3EncodeName(delimiter, encodingType)
+	data.Prefix = s3EncodeName(prefix, encodingType)
 	data.MaxKeys = maxKeys
 	data.ContinuationToken = token
 	data.NextContinuationToken = resp.NextMarker
 	data.IsTruncated = resp.IsTruncated
 	for _, prefix := range resp.Prefixes {
 		var prefixItem = CommonPrefix{}
-		prefixItem.Prefix = prefix
+		prefixItem.Prefix = s3EncodeName(prefix, encodingType)
 		prefixes = append(prefixes, prefixItem)
 	}
 	data.CommonPrefixes = prefixes
@@ -385,6 +486,14 @@ func generateCopyObjectResponse(etag string, lastModified time.Time) CopyObjectR
 	}
 }
 
+// generates CopyObjectPartResponse from etag and lastModified time.
+func generateCopyObjectPartResponse(etag string, lastModified time.Time) CopyObjectPartResponse {
+	return CopyObjectPartResponse{
+		ETag:         "\"" + etag + "\"",
+		LastModified: lastModified.UTC().Format(timeFormatAMZLong),
+	}
+}
+
 // generates InitiateMultipartUploadResponse for given bucket, key and uploadID.
 func generateInitiateMultipartUploadResponse(bucket, key, uploadID string) InitiateMultipartUploadResponse {
 	return InitiateMultipartUploadResponse{
@@ -412,9 +521,9 @@ func generateListPartsResponse(partsInfo ListPartsInfo) ListPartsResponse {
 	listPartsResponse.Key = partsInfo.Object
 	listPartsResponse.UploadID = partsInfo.UploadID
 	listPartsResponse.StorageClass = "STANDARD"
-	listPartsResponse.Initiator.ID = "minio"
+	listPartsResponse.Initiator.ID = getCanonicalOwnerID()
 	listPartsResponse.Initiator.DisplayName = "minio"
-	listPartsResponse.Owner.ID = "minio"
+	listPartsResponse.Owner.ID = getCanonicalOwnerID()
 	listPartsResponse.Owner.DisplayName = "minio"
 
 	listPartsResponse.MaxParts = partsInfo.MaxParts
@@ -459,6 +568,7 @@ func generateListMultipartUploadsResponse(bucket string, multipartsInfo ListMult
 		newUpload.UploadID = upload.UploadID
 		newUpload.Key = upload.Object
 		newUpload.Initiated = upload.Initiated.UTC().Format(timeFormatAMZLong)
+		newUpload.CommittedSize = upload.CommittedSize
 		listMultipartUploadsResponse.Uploads[index] = newUpload
 	}
 	return listMultipartUploadsResponse
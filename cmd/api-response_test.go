@@ -0,0 +1,107 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"os"
+	"testing"
+)
+
+// Tests the grants of the access control policy of each canned ACL.
+func TestGenerateAccessControlPolicyResponse(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	ownerID := getCanonicalOwnerID()
+	ownerGrant := Grant{Grantee: Grantee{Type: "CanonicalUser", ID: ownerID, DisplayName: "minio"}, Permission: "FULL_CONTROL"}
+	allUsersRead := Grant{Grantee: Grantee{Type: "Group", URI: allUsersGroupURI}, Permission: "READ"}
+	allUsersWrite := Grant{Grantee: Grantee{Type: "Group", URI: allUsersGroupURI}, Permission: "WRITE"}
+	authUsersRead := Grant{Grantee: Grantee{Type: "Group", URI: authenticatedUsersGroupURI}, Permission: "READ"}
+
+	testCases := []struct {
+		acl    string
+		grants []Grant
+	}{
+		{"", []Grant{ownerGrant}},
+		{cannedACLPrivate, []Grant{ownerGrant}},
+		{cannedACLPublicRead, []Grant{ownerGrant, allUsersRead}},
+		{cannedACLPublicReadWrite, []Grant{ownerGrant, allUsersRead, allUsersWrite}},
+		{cannedACLAuthenticatedRead, []Grant{ownerGrant, authUsersRead}},
+	}
+	for i, testCase := range testCases {
+		data, err := xml.Marshal(generateAccessControlPolicyResponse(testCase.acl))
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		// Grantee types are parsed in the XML schema instance namespace, as
+		// S3 clients do.
+		var policy struct {
+			Owner             Owner
+			AccessControlList struct {
+				Grants []struct {
+					Grantee struct {
+						Type string `xml:"http://www.w3.org/2001/XMLSchema-instance type,attr"`
+						ID   string
+						URI  string
+					}
+					Permission string
+				} `xml:"Grant"`
+			}
+		}
+		if err = xml.Unmarshal(data, &policy); err != nil {
+			t.Fatalf("Test %d: Unable to parse %s: %v", i+1, data, err)
+		}
+		if policy.Owner.ID != ownerID {
+			t.Errorf("Test %d: Expected owner %s, got %s", i+1, ownerID, policy.Owner.ID)
+		}
+		grants := policy.AccessControlList.Grants
+		if len(grants) != len(testCase.grants) {
+			t.Fatalf("Test %d: Expected %d grants, got %d", i+1, len(testCase.grants), len(grants))
+		}
+		for j, grant := range grants {
+			expected := testCase.grants[j]
+			if grant.Permission != expected.Permission || grant.Grantee.Type != expected.Grantee.Type ||
+				grant.Grantee.ID != expected.Grantee.ID || grant.Grantee.URI != expected.Grantee.URI {
+				t.Errorf("Test %d: Expected grant %d to be %#v, got %#v", i+1, j+1, expected, grant)
+			}
+		}
+	}
+
+	// The owner ID only depends on the credentials, and is not a plain
+	// hash of the access key.
+	cred := serverConfig.GetCredential()
+	if getCanonicalOwnerID() != ownerID {
+		t.Fatal("Expected the same owner ID for the same credentials")
+	}
+	if sum := sha256.Sum256([]byte(cred.AccessKeyID)); ownerID == hex.EncodeToString(sum[:]) {
+		t.Fatal("Expected the owner ID to be keyed by the secret key")
+	}
+	serverConfig.SetCredential(credential{AccessKeyID: cred.AccessKeyID, SecretAccessKey: mustGenAccessKeys().SecretAccessKey})
+	if getCanonicalOwnerID() == ownerID {
+		t.Fatal("Expected a different owner ID for a different secret key")
+	}
+	serverConfig.SetCredential(mustGenAccessKeys())
+	if getCanonicalOwnerID() == ownerID {
+		t.Fatal("Expected a different owner ID for a different access key")
+	}
+}
@@ -0,0 +1,203 @@
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	router "github.com/gorilla/mux"
+)
+
+// Tests virtual host style requests are routed when a domain is configured.
+func TestVirtualHostStyleRequests(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = obj
+	globalObjLayerMutex.Unlock()
+
+	bucket := "bucket"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	globalMinioDomain = "s3.example.com"
+	defer func() { globalMinioDomain = "" }()
+	apiRouter := router.NewRouter()
+	registerAPIRouter(apiRouter)
+	credentials := serverConfig.GetCredential()
+
+	// Upload an object through a virtual host style request.
+	data := []byte("hello, world")
+	rec := httptest.NewRecorder()
+	req, err := newTestSignedRequestV4("PUT", getPutObjectURL("http://bucket.s3.example.com", "", "object"),
+		int64(len(data)), bytes.NewReader(data), credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the response status to be `%d`, but instead found `%d`: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if _, err = obj.GetObjectInfo(bucket, "object"); err != nil {
+		t.Fatalf("Expected the object to be uploaded to %s, got %v", bucket, err)
+	}
+
+	// Read it back using path style.
+	rec = httptest.NewRecorder()
+	req, err = newTestSignedRequestV4("GET", getGetObjectURL("http://s3.example.com", bucket, "object"),
+		0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !bytes.Equal(rec.Body.Bytes(), data) {
+		t.Fatalf("Unexpected response `%d`: %s", rec.Code, rec.Body.String())
+	}
+
+	// List the bucket through a virtual host style request.
+	rec = httptest.NewRecorder()
+	req, err = newTestSignedRequestV4("GET", getListObjectsV1URL("http://bucket.s3.example.com", "", ""),
+		0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the response status to be `%d`, but instead found `%d`: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var listResponse ListObjectsResponse
+	if err = xml.Unmarshal(rec.Body.Bytes(), &listResponse); err != nil {
+		t.Fatal(err)
+	}
+	if listResponse.Name != bucket || len(listResponse.Contents) != 1 || listResponse.Contents[0].Key != "object" {
+		t.Fatalf("Unexpected list response %#v", listResponse)
+	}
+
+	// Without a domain the host is not used for routing, ListBuckets is served.
+	globalMinioDomain = ""
+	apiRouter = router.NewRouter()
+	registerAPIRouter(apiRouter)
+	rec = httptest.NewRecorder()
+	req, err = newTestSignedRequestV4("GET", "http://bucket.s3.example.com/",
+		0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	var bucketsResponse ListBucketsResponse
+	if err = xml.Unmarshal(rec.Body.Bytes(), &bucketsResponse); err != nil {
+		t.Fatalf("Expected a list buckets response, got %s", rec.Body.String())
+	}
+}
+
+// Tests virtual host style requests through the complete handler chain,
+// bucket policies and request body limits must apply to the bucket named
+// by the host and not to the first element of the path.
+func TestVirtualHostStyleHandlerChain(t *testing.T) {
+	globalMinioDomain = "s3.example.com"
+	defer func() { globalMinioDomain = "" }()
+
+	testServer := StartTestServer(t, "XL")
+	defer testServer.Stop()
+
+	// Dial the test server for every host name.
+	serverAddr := testServer.Server.Listener.Addr().String()
+	client := http.Client{Transport: &http.Transport{
+		Dial: func(network, addr string) (net.Conn, error) {
+			return net.Dial(network, serverAddr)
+		},
+	}}
+	endPoint := "http://bucket.s3.example.com"
+	doRequest := func(method, urlStr string, data []byte, accessKey, secretKey string) *http.Response {
+		req, err := newTestSignedRequestV4(method, urlStr, int64(len(data)), bytes.NewReader(data), accessKey, secretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	accessKey, secretKey := testServer.AccessKey, testServer.SecretKey
+	if resp := doRequest("PUT", getMakeBucketURL(endPoint, ""), nil, accessKey, secretKey); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected make bucket to succeed, got %d", resp.StatusCode)
+	}
+
+	// Objects larger than the bucket body limit are accepted.
+	largeData := bytes.Repeat([]byte("a"), 2*requestMaxBucketBodySize)
+	if resp := doRequest("PUT", getPutObjectURL(endPoint, "", "public/object"), largeData, accessKey, secretKey); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected a 2MiB virtual host style upload to succeed, got %d", resp.StatusCode)
+	}
+	if resp := doRequest("PUT", getPutObjectURL(endPoint, "", "bucket/public/secret"), []byte("secret"), accessKey, secretKey); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected upload to succeed, got %d", resp.StatusCode)
+	}
+
+	// Allow anonymous reads of 'bucket/public/*' only.
+	policyStr := fmt.Sprintf(`{"Version":"2012-10-17","Statement":[{"Action":["s3:GetObject"],"Effect":"Allow","Principal":{"AWS":["*"]},"Resource":["arn:aws:s3:::%s/public/*"],"Sid":""}]}`, "bucket")
+	if resp := doRequest("PUT", getPutPolicyURL(endPoint, ""), []byte(policyStr), accessKey, secretKey); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("Expected put bucket policy to succeed, got %d", resp.StatusCode)
+	}
+
+	testCases := []struct {
+		object             string
+		expectedStatusCode int
+	}{
+		{"public/object", http.StatusOK},
+		// Object 'bucket/public/secret' is not covered by the policy.
+		{"bucket/public/secret", http.StatusForbidden},
+	}
+	for i, testCase := range testCases {
+		resp := doRequest("GET", getGetObjectURL(endPoint, "", testCase.object), nil, "", "")
+		if resp.StatusCode != testCase.expectedStatusCode {
+			t.Errorf("Test %d: Expected anonymous GET of %s to return %d, got %d", i+1,
+				testCase.object, testCase.expectedStatusCode, resp.StatusCode)
+		}
+	}
+
+	// The same object with path style falls under the policy of 'bucket'.
+	req, err := newTestSignedRequestV4("GET", getGetObjectURL("http://s3.example.com", "bucket", "public/object"), 0, nil, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Unexpected path style response %d", resp.StatusCode)
+	}
+}
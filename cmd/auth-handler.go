@@ -66,6 +66,21 @@ func isRequestSignStreamingV4(r *http.Request) bool {
 	return r.Header.Get("x-amz-content-sha256") == streamingContentSHA256 && r.Method == "PUT"
 }
 
+// Verify if the request was made with a client certificate verified
+// against the configured client CAs, i.e over mutual TLS.
+func isRequestClientCert(r *http.Request) bool {
+	return r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0
+}
+
+// getClientCertPrincipal - returns the common name of the verified client
+// certificate, empty if the request was not made over mutual TLS.
+func getClientCertPrincipal(r *http.Request) string {
+	if !isRequestClientCert(r) {
+		return ""
+	}
+	return r.TLS.VerifiedChains[0][0].Subject.CommonName
+}
+
 // Authorization type.
 type authType int
 
@@ -80,6 +95,7 @@ const (
 	authTypeSigned
 	authTypeSignedV2
 	authTypeJWT
+	authTypeClientCert
 )
 
 // Get request authentication type.
@@ -90,6 +106,10 @@ func getRequestAuthType(r *http.Request) authType {
 		return authTypePresignedV2
 	} else if isRequestSignStreamingV4(r) {
 		return authTypeStreamingSigned
+	} else if isRequestClientCert(r) {
+		// Streaming signed payloads are still decoded and verified
+		// chunk by chunk, all the others are pre-authorized.
+		return authTypeClientCert
 	} else if isRequestSignatureV4(r) {
 		return authTypeSigned
 	} else if isRequestPresignedSignatureV4(r) {
@@ -108,6 +128,9 @@ func checkRequestAuthType(r *http.Request, bucket, policyAction, region string)
 	reqAuthType := getRequestAuthType(r)
 
 	switch reqAuthType {
+	case authTypeClientCert:
+		// Client certificate is already verified during TLS handshake.
+		return ErrNone
 	case authTypePresignedV2, authTypeSignedV2:
 		// Signature V2 validation.
 		s3Error := isReqAuthenticatedV2(r)
@@ -125,7 +148,10 @@ func checkRequestAuthType(r *http.Request, bucket, policyAction, region string)
 
 	if reqAuthType == authTypeAnonymous && policyAction != "" {
 		// http://docs.aws.amazon.com/AmazonS3/latest/dev/using-with-s3-actions.html
-		return enforceBucketPolicy(bucket, policyAction, r.URL)
+		// The object is resolved from the request, virtual host style
+		// requests carry only the object in their path.
+		_, object := getRequestBucketObject(r)
+		return enforceBucketPolicy(bucket, object, policyAction, r.URL.Query())
 	}
 
 	// By default return ErrAccessDenied
@@ -163,7 +189,7 @@ func isReqAuthenticated(r *http.Request, region string) (s3Error APIErrorCode) {
 	payload, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		errorIf(err, "Unable to read request body for signature verification")
-		return ErrInternalError
+		return toAPIErrorCode(err)
 	}
 	// Verify Content-Md5, if payload is set.
 	if r.Header.Get("Content-Md5") != "" {
@@ -207,6 +233,7 @@ var supportedS3AuthTypes = map[authType]struct{}{
 	authTypeSignedV2:        {},
 	authTypePostPolicy:      {},
 	authTypeStreamingSigned: {},
+	authTypeClientCert:      {},
 }
 
 // Validate if the authType is valid and supported.
@@ -218,6 +245,9 @@ func isSupportedS3AuthType(aType authType) bool {
 // handler for validating incoming authorization headers.
 func (a authHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	aType := getRequestAuthType(r)
+	if aType == authTypeClientCert {
+		infoContext(fields{"principal": getClientCertPrincipal(r)}, "%s %s", r.Method, r.URL.Path)
+	}
 	if isSupportedS3AuthType(aType) {
 		// Let top level caller validate for anonymous and known signed requests.
 		a.handler.ServeHTTP(w, r)
@@ -18,10 +18,19 @@ package cmd
 
 import (
 	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"io"
+	"io/ioutil"
+	"math/big"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 )
 
 // Test get request auth type.
@@ -107,6 +116,25 @@ func TestGetRequestAuthType(t *testing.T) {
 			},
 			authT: authTypePostPolicy,
 		},
+		// Test case - 6
+		// Check for verified client certificate.
+		{
+			req: &http.Request{
+				URL: &url.URL{
+					Host:   "localhost:9000",
+					Scheme: "https",
+					Path:   "/",
+				},
+				Header: http.Header{
+					"Authorization": []string{"AWS4-HMAC-SHA256 <cred_string>"},
+				},
+				TLS: &tls.ConnectionState{
+					VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "alice"}}}},
+				},
+				Method: "GET",
+			},
+			authT: authTypeClientCert,
+		},
 	}
 
 	// .. Tests all request auth type.
@@ -171,9 +199,14 @@ func TestS3SupportedAuthType(t *testing.T) {
 			authT: authTypeUnknown,
 			pass:  false,
 		},
-		// Test 10 - some new auth type is not supported s3 type.
+		// Test 10 - supported s3 type with client certificate.
+		{
+			authT: authTypeClientCert,
+			pass:  true,
+		},
+		// Test 11 - some new auth type is not supported s3 type.
 		{
-			authT: authType(9),
+			authT: authType(10),
 			pass:  false,
 		},
 	}
@@ -342,3 +375,142 @@ func TestIsReqAuthenticated(t *testing.T) {
 		}
 	}
 }
+
+// newTestCA - self signed CA certificate for signing test client certificates.
+func newTestCA(commonName string) (*x509.Certificate, *rsa.PrivateKey, error) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{"Minio Test CA"}, CommonName: commonName},
+		NotBefore:             time.Now().UTC().Add(-time.Minute),
+		NotAfter:              time.Now().UTC().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(derBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, priv, nil
+}
+
+// newTestClientCert - client certificate for commonName signed by ca.
+func newTestClientCert(commonName string, ca *x509.Certificate, caKey *rsa.PrivateKey) (tls.Certificate, error) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{Organization: []string{"Minio Test Client"}, CommonName: commonName},
+		NotBefore:    time.Now().UTC().Add(-time.Minute),
+		NotAfter:     time.Now().UTC().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, ca, &priv.PublicKey, caKey)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{derBytes}, PrivateKey: priv}, nil
+}
+
+// Wrapper for calling client certificate authentication tests for both XL multiple disks and single node setup.
+func TestAPIClientCertAuth(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPIClientCertAuth, []string{"PutObject", "GetObject"})
+}
+
+// Tests that requests over mutual TLS are authenticated by the client
+// certificate alone, and that clients without a certificate signed by
+// the client CA are rejected during the TLS handshake.
+func testAPIClientCertAuth(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	ca, caKey, err := newTestCA("Minio Client CA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCA, otherCAKey, err := newTestCA("Other CA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	validCert, err := newTestClientCert("alice", ca, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invalidCert, err := newTestClientCert("mallory", otherCA, otherCAKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	server := httptest.NewUnstartedServer(apiRouter)
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  pool,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	newClient := func(certs ...tls.Certificate) *http.Client {
+		return &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true, Certificates: certs},
+		}}
+	}
+
+	objectName := "mtls-object"
+	data := []byte("hello, mutual tls")
+
+	// Unsigned requests with a valid client certificate are authorized.
+	client := newClient(validCert)
+	req, err := newTestRequest("PUT", getPutObjectURL(server.URL, bucketName, objectName),
+		int64(len(data)), bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("%s: Unable to put object over mutual TLS: %v", instanceType, err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, res.StatusCode)
+	}
+
+	req, err = newTestRequest("GET", getGetObjectURL(server.URL, bucketName, objectName), 0, nil)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+	}
+	res, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("%s: Unable to get object over mutual TLS: %v", instanceType, err)
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusOK || !bytes.Equal(body, data) {
+		t.Fatalf("%s: Expected `%d` with object data, got `%d` with %q", instanceType, http.StatusOK, res.StatusCode, body)
+	}
+
+	// Certificates signed by another CA and missing certificates are rejected.
+	for i, client := range []*http.Client{newClient(invalidCert), newClient()} {
+		req, err = newTestRequest("GET", getGetObjectURL(server.URL, bucketName, objectName), 0, nil)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+		}
+		if res, err = client.Do(req); err == nil {
+			res.Body.Close()
+			t.Errorf("%s: Test %d: Expected TLS handshake to fail, got `%d`", instanceType, i+1, res.StatusCode)
+		}
+	}
+}
@@ -63,6 +63,38 @@ type RPCLoginReply struct {
 	ServerVersion string
 }
 
+// Codes of the errors returned by the RPC server, connection errors
+// are returned as is.
+const (
+	rpcErrServer = 1 // The server failed the call.
+	rpcErrAuth   = 2 // The server rejected the credentials or token.
+)
+
+// RPCError - error returned by the RPC server for a call, as opposed
+// to errors reaching the server.
+type RPCError struct {
+	Code    int
+	Message string
+}
+
+func (e RPCError) Error() string {
+	return e.Message
+}
+
+// toRPCError - converts the errors returned by the RPC server to
+// RPCError, all other errors are returned as is.
+func toRPCError(err error) error {
+	serverErr, ok := err.(rpc.ServerError)
+	if !ok {
+		return err
+	}
+	switch string(serverErr) {
+	case errInvalidToken.Error(), errInvalidAccessKeyID.Error(), errAuthentication.Error():
+		return RPCError{Code: rpcErrAuth, Message: string(serverErr)}
+	}
+	return RPCError{Code: rpcErrServer, Message: string(serverErr)}
+}
+
 // Validates if incoming token is valid.
 func isRPCTokenValid(tokenStr string) bool {
 	jwt, err := newJWT(defaultInterNodeJWTExpiry, serverConfig.GetCredential())
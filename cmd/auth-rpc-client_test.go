@@ -16,7 +16,15 @@
 
 package cmd
 
-import "testing"
+import (
+	"net"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	router "github.com/gorilla/mux"
+)
 
 // Tests authorized RPC client.
 func TestAuthRPCClient(t *testing.T) {
@@ -49,3 +57,69 @@ func TestAuthRPCClient(t *testing.T) {
 		t.Fatalf("Unexpected node value %s, but expected %s", authRPC.RPCPath(), authCfg.path)
 	}
 }
+
+// Tests that errors returned by the RPC server are told apart from
+// errors reaching the server.
+func TestToRPCError(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	muxRouter := router.NewRouter()
+	if err = registerAdminRPCRouter(muxRouter); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(muxRouter)
+	defer server.Close()
+
+	creds := serverConfig.GetCredential()
+	authConf := &authConfig{
+		address:     server.Listener.Addr().String(),
+		accessKey:   creds.AccessKeyID,
+		secretKey:   creds.SecretAccessKey,
+		path:        path.Join(reservedBucket, adminPath),
+		loginMethod: "Admin.LoginHandler",
+	}
+	client := newAuthClient(authConf)
+	defer client.Close()
+
+	// Failed call.
+	err = toRPCError(client.Call("Admin.SetLogLevel", &SetLogLevelArgs{Level: "unknown"}, &GenericReply{}))
+	if rpcErr, ok := err.(RPCError); !ok || rpcErr.Code != rpcErrServer {
+		t.Fatalf("Expected a server error, got %#v", err)
+	}
+
+	// Invalid token.
+	rclient := newClient(authConf.address, authConf.path, false)
+	defer rclient.Close()
+	args := GenericArgs{Token: "garbage", Timestamp: time.Now().UTC()}
+	err = toRPCError(rclient.Call("Admin.SysInfo", &args, &SysInfoReply{}))
+	if rpcErr, ok := err.(RPCError); !ok || rpcErr.Code != rpcErrAuth || rpcErr.Message != errInvalidToken.Error() {
+		t.Fatalf("Expected an auth error, got %#v", err)
+	}
+
+	// Invalid credentials.
+	badConf := *authConf
+	badConf.secretKey = "wrongsecretkey"
+	badClient := newAuthClient(&badConf)
+	defer badClient.Close()
+	err = toRPCError(badClient.Call("Admin.SysInfo", &GenericArgs{}, &SysInfoReply{}))
+	if rpcErr, ok := err.(RPCError); !ok || rpcErr.Code != rpcErrAuth {
+		t.Fatalf("Expected an auth error, got %#v", err)
+	}
+
+	// Connection refused.
+	server.Close()
+	closedClient := newAuthClient(authConf)
+	defer closedClient.Close()
+	err = toRPCError(closedClient.Call("Admin.SysInfo", &GenericArgs{}, &SysInfoReply{}))
+	if _, ok := err.(*net.OpError); !ok {
+		t.Fatalf("Expected a connection error, got %#v", err)
+	}
+
+	if err = toRPCError(nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,362 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+var benchmarkFlags = []cli.Flag{
+	cli.IntFlag{
+		Name:  "objects",
+		Value: 16,
+		Usage: "Number of objects to write and read back.",
+	},
+	cli.StringFlag{
+		Name:  "size",
+		Value: "64MiB",
+		Usage: "Size of each object.",
+	},
+	cli.IntFlag{
+		Name:  "concurrent",
+		Value: 4,
+		Usage: "Number of concurrent operations.",
+	},
+	cli.DurationFlag{
+		Name:  "duration",
+		Value: 10 * time.Second,
+		Usage: "Duration of the 4KiB random read test.",
+	},
+	cli.BoolFlag{
+		Name:  "json",
+		Usage: "Print the results as JSON.",
+	},
+}
+
+var benchmarkCmd = cli.Command{
+	Name:   "benchmark",
+	Usage:  "Measure the throughput of local disks.",
+	Flags:  append(benchmarkFlags, globalFlags...),
+	Action: mainBenchmark,
+	CustomHelpTemplate: `NAME:
+  minio {{.Name}} - {{.Usage}}
+
+USAGE:
+  minio {{.Name}} [FLAGS] PATH [PATH...]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Benchmark a single disk with the default settings.
+      $ minio {{.Name}} /mnt/export1/
+
+  2. Benchmark a 4 disks erasure coded setup with 32 objects of 128MiB, 8 at a time.
+      $ minio {{.Name}} --objects 32 --size 128MiB --concurrent 8 /mnt/export1/ /mnt/export2/ \
+          /mnt/export3/ /mnt/export4/
+
+`,
+}
+
+// Size of the reads of the random read test.
+const benchmarkRandReadSize = 4 * humanize.KiByte
+
+// benchmarkOpts - settings of a benchmark run.
+type benchmarkOpts struct {
+	Objects    int
+	Size       int64
+	Concurrent int
+	Duration   time.Duration
+}
+
+// benchmarkStat - results of one benchmarked operation.
+type benchmarkStat struct {
+	Operation  string        `json:"operation"`
+	Count      int           `json:"count"`
+	Bytes      int64         `json:"bytes"`
+	Elapsed    time.Duration `json:"elapsed"`
+	Throughput float64       `json:"throughputMBps"`
+	IOPS       float64       `json:"iops"`
+	P50        time.Duration `json:"p50"`
+	P95        time.Duration `json:"p95"`
+	P99        time.Duration `json:"p99"`
+}
+
+// newBenchmarkStat - summarizes the latencies of count operations
+// transferring size bytes in elapsed time.
+func newBenchmarkStat(operation string, latencies []time.Duration, size int64, elapsed time.Duration) benchmarkStat {
+	stat := benchmarkStat{
+		Operation: operation,
+		Count:     len(latencies),
+		Bytes:     int64(len(latencies)) * size,
+		Elapsed:   elapsed,
+	}
+	if elapsed > 0 {
+		stat.Throughput = float64(stat.Bytes) / humanize.MByte / elapsed.Seconds()
+		stat.IOPS = float64(stat.Count) / elapsed.Seconds()
+	}
+	sort.Sort(byDuration(latencies))
+	stat.P50 = percentile(latencies, 50)
+	stat.P95 = percentile(latencies, 95)
+	stat.P99 = percentile(latencies, 99)
+	return stat
+}
+
+// byDuration is a collection satisfying sort.Interface.
+type byDuration []time.Duration
+
+func (d byDuration) Len() int           { return len(d) }
+func (d byDuration) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+func (d byDuration) Less(i, j int) bool { return d[i] < d[j] }
+
+// percentile - returns the p-th percentile of sorted latencies.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	// Nearest rank.
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Reader which repeats the same byte forever, benchmarked objects are
+// generated on the fly to not measure memory allocations.
+type benchmarkReader byte
+
+func (r benchmarkReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(r)
+	}
+	return len(p), nil
+}
+
+// runConcurrent - calls op with ids 0 to n-1 on concurrent go-routines
+// until done, doneCh is closed or an error occurs. Returns the latency
+// of each successful call.
+func runConcurrent(n, concurrent int, doneCh <-chan struct{}, op func(id int) error) ([]time.Duration, error) {
+	var mu sync.Mutex
+	var latencies []time.Duration
+	var opErr error
+	next := 0
+	// Returns the id of the next call, false if there is nothing left to do.
+	nextID := func() (int, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if opErr != nil || (n > 0 && next >= n) {
+			return 0, false
+		}
+		select {
+		case <-doneCh:
+			return 0, false
+		default:
+		}
+		next++
+		return next - 1, true
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrent; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				id, ok := nextID()
+				if !ok {
+					return
+				}
+				start := time.Now()
+				err := op(id)
+				latency := time.Since(start)
+				mu.Lock()
+				if err != nil && opErr == nil {
+					opErr = err
+				} else if err == nil {
+					latencies = append(latencies, latency)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return latencies, opErr
+}
+
+// runBenchmark - writes, reads back and randomly reads objects in a
+// new bucket of objAPI, until done or stopCh is closed. Returns the
+// stats of each operation and a function removing the bucket.
+func runBenchmark(objAPI ObjectLayer, opts benchmarkOpts, stopCh <-chan struct{}) (stats []benchmarkStat, cleanup func(), err error) {
+	if opts.Objects <= 0 || opts.Size <= 0 || opts.Concurrent <= 0 {
+		return nil, func() {}, errors.New("--objects, --size and --concurrent should be greater than zero")
+	}
+
+	bucket := "minio-benchmark-" + mustGetUUID()[:8]
+	if err = objAPI.MakeBucket(bucket); err != nil {
+		return nil, func() {}, err
+	}
+	objectName := func(id int) string {
+		return "object-" + strconv.Itoa(id)
+	}
+	written := make([]bool, opts.Objects)
+	cleanup = func() {
+		for id, ok := range written {
+			if ok {
+				errorIf(objAPI.DeleteObject(bucket, objectName(id)), "Unable to remove benchmark object %s.", objectName(id))
+			}
+		}
+		errorIf(objAPI.DeleteBucket(bucket), "Unable to remove benchmark bucket %s.", bucket)
+	}
+
+	// Write all the objects.
+	start := time.Now()
+	latencies, err := runConcurrent(opts.Objects, opts.Concurrent, stopCh, func(id int) error {
+		reader := io.LimitReader(benchmarkReader('a'+id%26), opts.Size)
+		if _, pErr := objAPI.PutObject(bucket, objectName(id), opts.Size, reader, nil, ""); pErr != nil {
+			return pErr
+		}
+		written[id] = true
+		return nil
+	})
+	stats = append(stats, newBenchmarkStat("PUT", latencies, opts.Size, time.Since(start)))
+	if err != nil || len(latencies) < opts.Objects {
+		return stats, cleanup, err
+	}
+
+	// Read them back.
+	start = time.Now()
+	latencies, err = runConcurrent(opts.Objects, opts.Concurrent, stopCh, func(id int) error {
+		return objAPI.GetObject(bucket, objectName(id), 0, opts.Size, ioutil.Discard)
+	})
+	stats = append(stats, newBenchmarkStat("GET", latencies, opts.Size, time.Since(start)))
+	if err != nil || len(latencies) < opts.Objects {
+		return stats, cleanup, err
+	}
+
+	// Read 4KiB at random offsets until the duration elapses.
+	readSize := int64(benchmarkRandReadSize)
+	if readSize > opts.Size {
+		readSize = opts.Size
+	}
+	offsets := opts.Size/readSize - 1
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		select {
+		case <-time.After(opts.Duration):
+		case <-stopCh:
+		}
+	}()
+	start = time.Now()
+	latencies, err = runConcurrent(0, opts.Concurrent, doneCh, func(id int) error {
+		offset := rand.Int63n(offsets+1) * readSize
+		return objAPI.GetObject(bucket, objectName(rand.Intn(opts.Objects)), offset, readSize, ioutil.Discard)
+	})
+	stats = append(stats, newBenchmarkStat("GET "+humanize.IBytes(uint64(readSize))+" random", latencies, readSize, time.Since(start)))
+	return stats, cleanup, err
+}
+
+// printBenchmarkStats - prints the stats as a table or as JSON.
+func printBenchmarkStats(stats []benchmarkStat, jsonOutput bool) {
+	if jsonOutput {
+		statsJSON, err := json.Marshal(stats)
+		if err != nil {
+			console.Fatalln("Unable to marshal benchmark results.", err)
+		}
+		console.Println(string(statsJSON))
+		return
+	}
+	console.Printf("%-18s %8s %14s %10s %12s %12s %12s\n", "Operation", "Count", "Throughput", "IOPS", "p50", "p95", "p99")
+	for _, stat := range stats {
+		console.Printf("%-18s %8d %14s %10.1f %12s %12s %12s\n", stat.Operation, stat.Count,
+			fmt.Sprintf("%.1f MB/s", stat.Throughput), stat.IOPS, stat.P50, stat.P95, stat.P99)
+	}
+}
+
+// mainBenchmark handler called for 'minio benchmark' command.
+func mainBenchmark(c *cli.Context) {
+	if !c.Args().Present() || c.Args().First() == "help" {
+		cli.ShowCommandHelpAndExit(c, "benchmark", 1)
+	}
+
+	// Set global variables after parsing passed arguments
+	setGlobalsFromContext(c)
+
+	// Initialization routine, such as config loading, enable logging, ..
+	minioInit()
+
+	size, err := humanize.ParseBytes(c.String("size"))
+	fatalIf(err, "Invalid object size %s.", c.String("size"))
+	opts := benchmarkOpts{
+		Objects:    c.Int("objects"),
+		Size:       int64(size),
+		Concurrent: c.Int("concurrent"),
+		Duration:   c.Duration("duration"),
+	}
+
+	disks := c.Args()
+	endpoints, err := parseStorageEndpoints(disks)
+	fatalIf(err, "Unable to parse storage endpoints %s", disks)
+	checkEndpointsSyntax(endpoints, disks)
+	if len(endpoints) > 1 {
+		fatalIf(checkSufficientDisks(endpoints), "Storage endpoint error.")
+	}
+	if isDistributedSetup(endpoints) {
+		fatalIf(errInvalidArgument, "Benchmarks are only supported on local disks.")
+	}
+
+	// Initialize name space lock.
+	initNSLock(false)
+
+	storageDisks, err := initStorageDisks(endpoints)
+	fatalIf(err, "Unable to initialize storage disk(s).")
+	formattedDisks, err := waitForFormatDisks(true, endpoints, storageDisks)
+	fatalIf(err, "Unable to format storage disk(s).")
+	objAPI, err := newObjectLayer(formattedDisks)
+	fatalIf(err, "Unable to initialize object layer.")
+
+	// Stop on Ctrl+C, benchmark objects are removed in any case.
+	stopCh := make(chan struct{})
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		close(stopCh)
+	}()
+
+	stats, cleanup, err := runBenchmark(objAPI, opts, stopCh)
+	cleanup()
+	signal.Stop(sigCh)
+	printBenchmarkStats(stats, c.Bool("json"))
+	fatalIf(err, "Benchmark failed.")
+}
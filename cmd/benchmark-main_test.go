@@ -0,0 +1,103 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Tests nearest rank percentiles.
+func TestPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 200; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	testCases := []struct {
+		latencies []time.Duration
+		p         int
+		expected  time.Duration
+	}{
+		{nil, 50, 0},
+		{latencies[:1], 99, time.Millisecond},
+		{latencies, 50, 100 * time.Millisecond},
+		{latencies, 95, 190 * time.Millisecond},
+		{latencies, 99, 198 * time.Millisecond},
+		{latencies, 100, 200 * time.Millisecond},
+	}
+	for i, testCase := range testCases {
+		if got := percentile(testCase.latencies, testCase.p); got != testCase.expected {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expected, got)
+		}
+	}
+}
+
+// Tests a benchmark run on both FS and XL and that its objects are removed.
+func TestRunBenchmark(t *testing.T) {
+	ExecObjectLayerTest(t, testRunBenchmark)
+}
+
+func testRunBenchmark(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	opts := benchmarkOpts{
+		Objects:    3,
+		Size:       64 * humanize.KiByte,
+		Concurrent: 2,
+		Duration:   50 * time.Millisecond,
+	}
+	stats, cleanup, err := runBenchmark(obj, opts, nil)
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if len(stats) != 3 {
+		t.Fatalf("%s: Expected 3 stats, got %d", instanceType, len(stats))
+	}
+	for _, stat := range stats[:2] {
+		if stat.Count != opts.Objects || stat.Bytes != int64(opts.Objects)*opts.Size {
+			t.Errorf("%s: Unexpected %s stat %#v", instanceType, stat.Operation, stat)
+		}
+	}
+	if stats[2].Count == 0 || stats[2].P99 < stats[2].P50 {
+		t.Errorf("%s: Unexpected random read stat %#v", instanceType, stats[2])
+	}
+
+	cleanup()
+	buckets, err := obj.ListBuckets()
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if len(buckets) != 0 {
+		t.Errorf("%s: Expected benchmark bucket to be removed, found %v", instanceType, buckets)
+	}
+
+	// A closed stop channel ends the benchmark right away.
+	stopCh := make(chan struct{})
+	close(stopCh)
+	stats, cleanup, err = runBenchmark(obj, opts, stopCh)
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if len(stats) != 1 || stats[0].Count != 0 {
+		t.Errorf("%s: Expected no object to be written, got %#v", instanceType, stats)
+	}
+	cleanup()
+
+	// Invalid options.
+	if _, _, err = runBenchmark(obj, benchmarkOpts{}, nil); err == nil {
+		t.Errorf("%s: Expected error for invalid options", instanceType)
+	}
+}
@@ -106,7 +106,7 @@ func updateCredsOnPeers(creds credential) map[string]error {
 				accessKey:   serverConfig.GetCredential().AccessKeyID,
 				secretKey:   serverConfig.GetCredential().SecretAccessKey,
 				address:     peers[ix],
-				secureConn:  isSSL(),
+				secureConn:  isServerTLS(),
 				path:        path.Join(reservedBucket, browserPeerPath),
 				loginMethod: "Browser.LoginHandler",
 			})
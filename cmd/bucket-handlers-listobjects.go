@@ -17,6 +17,12 @@
 package cmd
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
 	"net/http"
 	"strings"
 
@@ -26,9 +32,8 @@ import (
 // Validate all the ListObjects query arguments, returns an APIErrorCode
 // if one of the args do not meet the required conditions.
 // Special conditions required by Minio server are as below
-// - delimiter if set should be equal to '/', otherwise the request is rejected.
-// - marker if set should have a common prefix with 'prefix' param, otherwise
-//   the request is rejected.
+//   - marker if set should have a common prefix with 'prefix' param, otherwise
+//     the request is rejected.
 func validateListObjectsArgs(prefix, marker, delimiter string, maxKeys int) APIErrorCode {
 	// Max keys cannot be negative.
 	if maxKeys < 0 {
@@ -37,10 +42,9 @@ func validateListObjectsArgs(prefix, marker, delimiter string, maxKeys int) APIE
 
 	/// Minio special conditions for ListObjects.
 
-	// Verify if delimiter is anything other than '/', which we do not support.
-	if delimiter != "" && delimiter != "/" {
-		return ErrNotImplemented
-	}
+	// Delimiters other than '/' are verified by the object layer, only
+	// FS supports them.
+
 	// Marker is set validate pre-condition.
 	if marker != "" {
 		// Marker not common with prefix is not implemented.
@@ -52,6 +56,59 @@ func validateListObjectsArgs(prefix, marker, delimiter string, maxKeys int) APIE
 	return ErrNone
 }
 
+// errInvalidContinuationToken - continuation token was not issued by
+// this server or was modified.
+var errInvalidContinuationToken = errors.New("Invalid continuation token")
+
+// Purpose of the key continuation tokens are encrypted with, the
+// secret key itself is never used as a cipher key.
+const continuationTokenKeyPurpose = "minio-list-v2-continuation"
+
+// continuationTokenKey - AES-256 key of continuation tokens, derived
+// from the secret key as HMAC-SHA256(secretKey, purpose).
+func continuationTokenKey(secretKey string) []byte {
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(continuationTokenKeyPurpose))
+	return mac.Sum(nil)
+}
+
+// encodeContinuationToken - encodes the marker of the next page of a
+// listing as a continuation token. The marker is encrypted and
+// authenticated with AES-256-GCM under a key derived from secretKey,
+// so that any server sharing the credentials can resume the listing
+// from it while clients cannot read or forge it.
+func encodeContinuationToken(marker, secretKey string) (string, error) {
+	if marker == "" {
+		return "", nil
+	}
+	aead, err := newAESGCM(continuationTokenKey(secretKey))
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(marker), nil)), nil
+}
+
+// decodeContinuationToken - returns the marker encoded in token.
+func decodeContinuationToken(token, secretKey string) (string, error) {
+	aead, err := newAESGCM(continuationTokenKey(secretKey))
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errInvalidContinuationToken
+	}
+	marker, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", errInvalidContinuationToken
+	}
+	return string(marker), nil
+}
+
 // ListObjectsV2Handler - GET Bucket (List Objects) Version 2.
 // --------------------------
 // This implementation of the GET operation returns some or all (up to 1000)
@@ -76,14 +133,17 @@ func (api objectAPIHandlers) ListObjectsV2Handler(w http.ResponseWriter, r *http
 	}
 
 	// Extract all the listObjectsV2 query params to their native values.
-	prefix, token, startAfter, delimiter, fetchOwner, maxKeys, _ := getListObjectsV2Args(r.URL.Query())
-
-	// In ListObjectsV2 'continuation-token' is the marker.
-	marker := token
-	// Check if 'continuation-token' is empty.
-	if token == "" {
-		// Then we need to use 'start-after' as marker instead.
-		marker = startAfter
+	prefix, token, startAfter, delimiter, fetchOwner, maxKeys, encodingType := getListObjectsV2Args(r.URL.Query())
+
+	// In ListObjectsV2 'continuation-token' carries the marker.
+	marker := startAfter
+	// Check if 'continuation-token' is set, use 'start-after' otherwise.
+	if token != "" {
+		var err error
+		if marker, err = decodeContinuationToken(token, serverConfig.GetCredential().SecretAccessKey); err != nil {
+			writeErrorResponse(w, r, ErrInvalidContinuationToken, r.URL.Path)
+			return
+		}
 	}
 	// Validate the query params before beginning to serve the request.
 	// fetch-owner is not validated since it is a boolean
@@ -101,7 +161,15 @@ func (api objectAPIHandlers) ListObjectsV2Handler(w http.ResponseWriter, r *http
 		return
 	}
 
-	response := generateListObjectsV2Response(bucket, prefix, token, startAfter, delimiter, fetchOwner, maxKeys, listObjectsInfo)
+	response := generateListObjectsV2Response(bucket, prefix, token, startAfter, delimiter, encodingType, fetchOwner, maxKeys, listObjectsInfo)
+	// Next marker is handed out only as a continuation token, listing can
+	// be resumed without any state kept by the server.
+	response.NextContinuationToken, err = encodeContinuationToken(listObjectsInfo.NextMarker, serverConfig.GetCredential().SecretAccessKey)
+	if err != nil {
+		errorIf(err, "Unable to encode the continuation token.")
+		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+		return
+	}
 	// Write headers
 	setCommonHeaders(w)
 	// Write success response.
@@ -130,7 +198,7 @@ func (api objectAPIHandlers) ListObjectsV1Handler(w http.ResponseWriter, r *http
 	}
 
 	// Extract all the litsObjectsV1 query params to their native values.
-	prefix, marker, delimiter, maxKeys, _ := getListObjectsV1Args(r.URL.Query())
+	prefix, marker, delimiter, maxKeys, encodingType := getListObjectsV1Args(r.URL.Query())
 
 	// Validate all the query params before beginning to serve the request.
 	if s3Error := validateListObjectsArgs(prefix, marker, delimiter, maxKeys); s3Error != ErrNone {
@@ -147,7 +215,7 @@ func (api objectAPIHandlers) ListObjectsV1Handler(w http.ResponseWriter, r *http
 		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
 		return
 	}
-	response := generateListObjectsV1Response(bucket, prefix, marker, delimiter, maxKeys, listObjectsInfo)
+	response := generateListObjectsV1Response(bucket, prefix, marker, delimiter, encodingType, maxKeys, listObjectsInfo)
 	// Write headers
 	setCommonHeaders(w)
 	// Write success response.
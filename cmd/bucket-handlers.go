@@ -31,7 +31,7 @@ import (
 
 // http://docs.aws.amazon.com/AmazonS3/latest/dev/using-with-s3-actions.html
 // Enforces bucket policies for a bucket for a given tatusaction.
-func enforceBucketPolicy(bucket string, action string, reqURL *url.URL) (s3Error APIErrorCode) {
+func enforceBucketPolicy(bucket, object, action string, queryParams url.Values) (s3Error APIErrorCode) {
 	// Verify if bucket actually exists
 	if err := isBucketExist(bucket, newObjectLayerFn()); err != nil {
 		err = errorCause(err)
@@ -43,7 +43,7 @@ func enforceBucketPolicy(bucket string, action string, reqURL *url.URL) (s3Error
 			// For no bucket found we return NoSuchBucket instead.
 			return ErrNoSuchBucket
 		}
-		errorIf(err, "Unable to read bucket policy.")
+		errorIfContext(err, fields{"bucket": bucket}, "Unable to read bucket policy.")
 		// Return internal error for any other errors so that we can investigate.
 		return ErrInternalError
 	}
@@ -55,12 +55,12 @@ func enforceBucketPolicy(bucket string, action string, reqURL *url.URL) (s3Error
 	}
 
 	// Construct resource in 'arn:aws:s3:::examplebucket/object' format.
-	resource := AWSResourcePrefix + strings.TrimSuffix(strings.TrimPrefix(reqURL.Path, "/"), "/")
+	resource := AWSResourcePrefix + strings.TrimSuffix(bucket+"/"+object, "/")
 
 	// Get conditions for policy verification.
 	conditionKeyMap := make(map[string]set.StringSet)
-	for queryParam := range reqURL.Query() {
-		conditionKeyMap[queryParam] = set.CreateStringSet(reqURL.Query().Get(queryParam))
+	for queryParam := range queryParams {
+		conditionKeyMap[queryParam] = set.CreateStringSet(queryParams.Get(queryParam))
 	}
 
 	// Validate action, resource and conditions with current policy statements.
@@ -89,7 +89,7 @@ func (api objectAPIHandlers) GetBucketLocationHandler(w http.ResponseWriter, r *
 	}
 
 	if _, err := objectAPI.GetBucketInfo(bucket); err != nil {
-		errorIf(err, "Unable to fetch bucket info.")
+		errorIfContext(err, fields{"bucket": bucket}, "Unable to fetch bucket info.")
 		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
 		return
 	}
@@ -145,7 +145,7 @@ func (api objectAPIHandlers) ListMultipartUploadsHandler(w http.ResponseWriter,
 
 	listMultipartsInfo, err := objectAPI.ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
 	if err != nil {
-		errorIf(err, "Unable to list multipart uploads.")
+		errorIfContext(err, fields{"bucket": bucket}, "Unable to list multipart uploads.")
 		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
 		return
 	}
@@ -227,7 +227,7 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 
 	// Read incoming body XML bytes.
 	if _, err := io.ReadFull(r.Body, deleteXMLBytes); err != nil {
-		errorIf(err, "Unable to read HTTP body.")
+		errorIfContext(err, fields{"bucket": bucket}, "Unable to read HTTP body.")
 		writeErrorResponse(w, r, ErrInternalError, r.URL.Path)
 		return
 	}
@@ -235,7 +235,7 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 	// Unmarshal list of keys to be deleted.
 	deleteObjects := &DeleteObjectsRequest{}
 	if err := xml.Unmarshal(deleteXMLBytes, deleteObjects); err != nil {
-		errorIf(err, "Unable to unmarshal delete objects request XML.")
+		errorIfContext(err, fields{"bucket": bucket}, "Unable to unmarshal delete objects request XML.")
 		writeErrorResponse(w, r, ErrMalformedXML, r.URL.Path)
 		return
 	}
@@ -255,6 +255,7 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 		}(index, object)
 	}
 	wg.Wait()
+	globalBucketQuotaCache.invalidate(bucket)
 
 	// Collect deleted objects and errors if any.
 	var deletedObjects []ObjectIdentifier
@@ -272,7 +273,7 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 			deletedObjects = append(deletedObjects, object)
 			continue
 		}
-		errorIf(err, "Unable to delete object. %s", object.ObjectName)
+		errorIfContext(err, fields{"bucket": bucket, "object": object.ObjectName}, "Unable to delete object.")
 		// Error during delete should be collected separately.
 		deleteErrors = append(deleteErrors, DeleteError{
 			Code:    errorCodeResponse[toAPIErrorCode(err)].Code,
@@ -333,12 +334,25 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 	// Proceed to creating a bucket.
 	err := objectAPI.MakeBucket(bucket)
 	if err != nil {
-		errorIf(err, "Unable to create a bucket.")
+		errorIfContext(err, fields{"bucket": bucket}, "Unable to create a bucket.")
 		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
 		return
 	}
+
+	// Enable object lock if requested, object lock can only be enabled
+	// when the bucket is created.
+	if strings.EqualFold(r.Header.Get(amzBucketObjectLockEnabled), "true") {
+		cfg := &objectLockConfiguration{ObjectLockEnabled: objectLockEnabled}
+		if err = writeBucketObjectLock(bucket, objectAPI, cfg); err != nil {
+			// Remove the bucket, it could not be created as requested.
+			_ = objectAPI.DeleteBucket(bucket)
+			writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+			return
+		}
+	}
+
 	// Make sure to add Location information here only for bucket
-	w.Header().Set("Location", getLocation(r))
+	w.Header().Set("Location", sanitizeHeaderValue(getLocation(r)))
 	writeSuccessResponse(w, nil)
 }
 
@@ -352,23 +366,23 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 		writeErrorResponse(w, r, ErrServerNotInitialized, r.URL.Path)
 		return
 	}
+	bucket := mux.Vars(r)["bucket"]
 
 	// Here the parameter is the size of the form data that should
 	// be loaded in memory, the remaining being put in temporary files.
 	reader, err := r.MultipartReader()
 	if err != nil {
-		errorIf(err, "Unable to initialize multipart reader.")
+		errorIfContext(err, fields{"bucket": bucket}, "Unable to initialize multipart reader.")
 		writeErrorResponse(w, r, ErrMalformedPOSTRequest, r.URL.Path)
 		return
 	}
 
 	fileBody, fileName, formValues, err := extractPostPolicyFormValues(reader)
 	if err != nil {
-		errorIf(err, "Unable to parse form values.")
+		errorIfContext(err, fields{"bucket": bucket}, "Unable to parse form values.")
 		writeErrorResponse(w, r, ErrMalformedPOSTRequest, r.URL.Path)
 		return
 	}
-	bucket := mux.Vars(r)["bucket"]
 	formValues["Bucket"] = bucket
 	object := formValues["Key"]
 
@@ -429,12 +443,12 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 
 	objInfo, err := objectAPI.PutObject(bucket, object, -1, fileBody, metadata, sha256sum)
 	if err != nil {
-		errorIf(err, "Unable to create object.")
+		errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to create object.")
 		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
 		return
 	}
 	w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
-	w.Header().Set("Location", getObjectLocation(bucket, object))
+	w.Header().Set("Location", sanitizeHeaderValue(getObjectLocation(bucket, object)))
 
 	// Set common headers.
 	setCommonHeaders(w)
@@ -475,10 +489,15 @@ func (api objectAPIHandlers) HeadBucketHandler(w http.ResponseWriter, r *http.Re
 	}
 
 	if _, err := objectAPI.GetBucketInfo(bucket); err != nil {
-		errorIf(err, "Unable to fetch bucket info.")
+		errorIfContext(err, fields{"bucket": bucket}, "Unable to fetch bucket info.")
 		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
 		return
 	}
+	// SDKs detect the region of the bucket before signing requests, all
+	// the buckets are served in the region of the server whichever region
+	// they were created in.
+	w.Header().Set("X-Amz-Bucket-Region", serverConfig.GetRegion())
+	w.Header().Set("Content-Length", "0")
 	writeSuccessResponse(w, nil)
 }
 
@@ -501,7 +520,7 @@ func (api objectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.
 
 	// Attempt to delete bucket.
 	if err := objectAPI.DeleteBucket(bucket); err != nil {
-		errorIf(err, "Unable to delete a bucket.")
+		errorIfContext(err, fields{"bucket": bucket}, "Unable to delete a bucket.")
 		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
 		return
 	}
@@ -515,6 +534,15 @@ func (api objectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.
 	// Delete listener config, if present - ignore any errors.
 	_ = removeListenerConfig(bucket, objectAPI)
 
+	// Delete bucket quota, if present - ignore any errors.
+	_ = removeBucketQuota(bucket, objectAPI)
+
+	// Delete bucket lifecycle, if present - ignore any errors.
+	_ = removeBucketLifecycle(bucket, objectAPI)
+
+	// Delete bucket object lock, if present - ignore any errors.
+	_ = removeBucketObjectLock(bucket, objectAPI)
+
 	// Write success response.
 	writeSuccessNoContent(w)
 }
@@ -18,12 +18,19 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/xml"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"reflect"
 	"strconv"
 	"testing"
+
+	humanize "github.com/dustin/go-humanize"
 )
 
 // Wrapper for calling GetBucketPolicy HTTP handler tests for both XL multiple disks and single node setup.
@@ -248,6 +255,33 @@ func testHeadBucketHandler(obj ObjectLayer, instanceType, bucketName string, api
 
 	}
 
+	// The region of the server is returned, requests are signed with it
+	// whichever region the bucket was created in.
+	serverRegion := serverConfig.GetRegion()
+	serverConfig.SetRegion("us-west-2")
+	westBucket := getRandomBucketName()
+	err := obj.MakeBucket(westBucket)
+	serverConfig.SetRegion(serverRegion)
+	if err != nil {
+		t.Fatalf("%s: Unable to create bucket: %v", instanceType, err)
+	}
+	expectedRegion := serverRegion
+	rec := httptest.NewRecorder()
+	req, err := newTestSignedRequestV4("HEAD", getHEADBucketURL("", westBucket), 0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for HeadBucketHandler: <ERROR> %v", instanceType, err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	if region := rec.Header().Get("X-Amz-Bucket-Region"); region != expectedRegion {
+		t.Errorf("%s: Expected bucket region %s, got %s", instanceType, expectedRegion, region)
+	}
+	if rec.Header().Get("Content-Length") != "0" || rec.Header().Get("X-Amz-Request-Id") == "" {
+		t.Errorf("%s: Unexpected response headers %v", instanceType, rec.Header())
+	}
+
 	// Test for Anonymous/unsigned http request.
 	anonReq, err := newTestRequest("HEAD", getHEADBucketURL("", bucketName), 0, nil)
 
@@ -516,6 +550,55 @@ func testListMultipartUploadsHandler(obj ObjectLayer, instanceType, bucketName s
 	ExecObjectLayerAPINilTest(t, nilBucket, "", instanceType, apiRouter, nilReq)
 }
 
+// Tests that max-uploads=0 lists the default of 1000 uploads. Only run
+// on FS, creating 1500 uploads on XL is too slow for a unit test.
+func TestListMultipartUploadsDefaultMaxUploads(t *testing.T) {
+	rootPath, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+
+	bucketName, apiRouter, err := initAPIHandlerTest(obj, []string{"ListMultipartUploads"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentials := serverConfig.GetCredential()
+
+	for i := 0; i < 1500; i++ {
+		if _, err = obj.NewMultipartUpload(bucketName, fmt.Sprintf("object-%04d", i), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, maxUploads := range []string{"0", "1500"} {
+		rec := httptest.NewRecorder()
+		u := getListMultipartUploadsURLWithParams("", bucketName, "", "", "", "", maxUploads)
+		req, err := newTestSignedRequestV4("GET", u, 0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("max-uploads=%s: Expected the response status to be `%d`, but instead found `%d`", maxUploads, http.StatusOK, rec.Code)
+		}
+		var resp ListMultipartUploadsResponse
+		if err = xml.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Uploads) != 1000 || resp.MaxUploads != 1000 || !resp.IsTruncated {
+			t.Fatalf("max-uploads=%s: Expected 1000 truncated uploads, got %d (MaxUploads %d, IsTruncated %t)",
+				maxUploads, len(resp.Uploads), resp.MaxUploads, resp.IsTruncated)
+		}
+	}
+}
+
 // Wrapper for calling TestListBucketsHandler tests for both XL multiple disks and single node setup.
 func TestListBucketsHandler(t *testing.T) {
 	ExecObjectLayerAPITest(t, testListBucketsHandler, []string{"ListBuckets"})
@@ -771,3 +854,343 @@ func testAPIDeleteMultipleObjectsHandler(obj ObjectLayer, instanceType, bucketNa
 	// `ExecObjectLayerAPINilTest` manages the operation.
 	ExecObjectLayerAPINilTest(t, nilBucket, nilObject, instanceType, apiRouter, nilReq)
 }
+
+// Wrapper for calling PutBucket HTTP handler tests for both XL multiple disks and single node setup.
+func TestPutBucketHandlerLocationConstraint(t *testing.T) {
+	ExecObjectLayerAPITest(t, testPutBucketHandlerLocationConstraint, []string{"PutBucket"})
+}
+
+func testPutBucketHandlerLocationConstraint(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	testCases := []struct {
+		bucketName         string
+		location           string
+		expectedRespStatus int
+		expectedErrCode    string
+	}{
+		// Location matches the server region.
+		{"bucket-us-east-1", "us-east-1", http.StatusOK, ""},
+		// Empty location defaults to "us-east-1".
+		{"bucket-default", "", http.StatusOK, ""},
+		// Location different from the server region.
+		{"bucket-eu-west-1", "eu-west-1", http.StatusBadRequest, "IllegalLocationConstraintException"},
+	}
+	for i, testCase := range testCases {
+		configBytes, err := xml.Marshal(createBucketLocationConfiguration{Location: testCase.location})
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to marshal bucket configuration: <ERROR> %v", i+1, instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("PUT", getMakeBucketURL("", testCase.bucketName),
+			int64(len(configBytes)), bytes.NewReader(configBytes), credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for PutBucketHandler: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+		_, err = obj.GetBucketInfo(testCase.bucketName)
+		if testCase.expectedErrCode == "" {
+			if err != nil {
+				t.Errorf("Test %d: %s: Expected bucket to be created, got %v", i+1, instanceType, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("Test %d: %s: Expected bucket not to be created", i+1, instanceType)
+		}
+		errorResponse := APIErrorResponse{}
+		if err = xml.Unmarshal(rec.Body.Bytes(), &errorResponse); err != nil {
+			t.Fatalf("Test %d: %s: Unable to unmarshal response body %s", i+1, instanceType, rec.Body.String())
+		}
+		if errorResponse.Code != testCase.expectedErrCode {
+			t.Errorf("Test %d: %s: Expected the error code to be `%s`, but instead found `%s`", i+1, instanceType, testCase.expectedErrCode, errorResponse.Code)
+		}
+	}
+}
+
+// countingReader - counts the bytes read from the underlying reader.
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Wrapper for calling PutBucket HTTP handler tests with a body larger than
+// allowed for both XL multiple disks and single node setup.
+func TestPutBucketHandlerBodyTooLarge(t *testing.T) {
+	ExecObjectLayerAPITest(t, testPutBucketHandlerBodyTooLarge, []string{"PutBucket"})
+}
+
+func testPutBucketHandlerBodyTooLarge(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	body := bytes.Repeat([]byte("a"), 10*humanize.MiByte)
+	rec := httptest.NewRecorder()
+	req, err := newTestSignedRequestV4("PUT", getMakeBucketURL("", "bucket-too-large"),
+		int64(len(body)), bytes.NewReader(body), credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for PutBucketHandler: <ERROR> %v", instanceType, err)
+	}
+	reader := &countingReader{reader: bytes.NewReader(body)}
+	req.Body = ioutil.NopCloser(reader)
+
+	setRequestSizeLimitHandler(apiRouter).ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+	errorResponse := APIErrorResponse{}
+	if err = xml.Unmarshal(rec.Body.Bytes(), &errorResponse); err != nil {
+		t.Fatalf("%s: Unable to unmarshal response body %s", instanceType, rec.Body.String())
+	}
+	if errorResponse.Code != "EntityTooLarge" {
+		t.Errorf("%s: Expected the error code to be `EntityTooLarge`, but instead found `%s`", instanceType, errorResponse.Code)
+	}
+	if reader.n > 2*humanize.MiByte {
+		t.Errorf("%s: Expected the body not to be read fully, %d bytes were read", instanceType, reader.n)
+	}
+	if _, err = obj.GetBucketInfo("bucket-too-large"); err == nil {
+		t.Errorf("%s: Expected bucket not to be created", instanceType)
+	}
+}
+
+// Wrapper for calling ListObjectsV2 continuation token tests for both XL multiple disks and single node setup.
+func TestListObjectsV2ContinuationToken(t *testing.T) {
+	ExecObjectLayerAPITest(t, testListObjectsV2ContinuationToken, []string{"ListObjectsV2"})
+}
+
+// Tests that a listing is resumed from its continuation token alone, by
+// restarting the object layer between pages.
+func testListObjectsV2ContinuationToken(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	nObjects := 1000
+	for i := 0; i < nObjects; i++ {
+		if _, err := obj.PutObject(bucketName, fmt.Sprintf("object-%04d", i), 0, bytes.NewReader(nil), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// restart - returns a router serving a new instance of the object
+	// layer on the same disks, without any listing state of the others.
+	restart := func() http.Handler {
+		var newObj ObjectLayer
+		var err error
+		switch instanceType {
+		case FSTestStr:
+			newObj, err = newFSObjects(obj.(fsObjects).storage)
+		case XLTestStr:
+			newObj, err = newXLObjects(obj.(*xlObjects).storageDisks)
+		}
+		if err != nil {
+			t.Fatalf("%s: Unable to restart object layer: %v", instanceType, err)
+		}
+		return initTestAPIEndPoints(newObj, []string{"ListObjectsV2"})
+	}
+
+	listPage := func(router http.Handler, token string) *httptest.ResponseRecorder {
+		queryValues := url.Values{}
+		queryValues.Set("list-type", "2")
+		queryValues.Set("max-keys", "100")
+		if token != "" {
+			queryValues.Set("continuation-token", token)
+		}
+		req, err := newTestSignedRequestV4("GET", makeTestTargetURL("", bucketName, "", queryValues),
+			0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for ListObjectsV2: <ERROR> %v", instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	seen := make(map[string]int)
+	token := ""
+	for page := 0; ; page++ {
+		rec := listPage(restart(), token)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Page %d: Expected the response status to be `%d`, but instead found `%d`", instanceType, page+1, http.StatusOK, rec.Code)
+		}
+		var resp ListObjectsV2Response
+		if err := xml.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: Page %d: Unable to parse the response: %v", instanceType, page+1, err)
+		}
+		if resp.ContinuationToken != token {
+			t.Errorf("%s: Page %d: Expected continuation token %q, got %q", instanceType, page+1, token, resp.ContinuationToken)
+		}
+		for _, object := range resp.Contents {
+			seen[object.Key]++
+		}
+		if !resp.IsTruncated {
+			break
+		}
+		if resp.NextContinuationToken == "" || resp.NextContinuationToken == resp.Contents[len(resp.Contents)-1].Key {
+			t.Fatalf("%s: Page %d: Expected an encoded continuation token, got %q", instanceType, page+1, resp.NextContinuationToken)
+		}
+		// The marker is encrypted, it can not be read back from the token.
+		if raw, err := base64.RawURLEncoding.DecodeString(resp.NextContinuationToken); err != nil || bytes.Contains(raw, []byte(resp.Contents[len(resp.Contents)-1].Key)) {
+			t.Fatalf("%s: Page %d: Expected an encrypted continuation token, got %q", instanceType, page+1, resp.NextContinuationToken)
+		}
+		token = resp.NextContinuationToken
+	}
+	if len(seen) != nObjects {
+		t.Errorf("%s: Expected %d objects to be listed, got %d", instanceType, nObjects, len(seen))
+	}
+	for name, count := range seen {
+		if count != 1 {
+			t.Errorf("%s: Expected %s to be listed once, listed %d times", instanceType, name, count)
+		}
+	}
+
+	// Tokens which were not issued by the server are rejected.
+	foreignToken, err := encodeContinuationToken("object-0500", "other-secret-key")
+	if err != nil {
+		t.Fatalf("%s: Unable to encode a continuation token: <ERROR> %s", instanceType, err)
+	}
+	for i, token := range []string{"object-0500", foreignToken, "!"} {
+		if rec := listPage(apiRouter, token); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: Test %d: Expected the response status to be `%d`, but instead found `%d`", instanceType, i+1, http.StatusBadRequest, rec.Code)
+		}
+	}
+}
+
+// Wrapper for calling ListObjectsV2 start-after tests for both XL multiple disks and single node setup.
+func TestListObjectsV2StartAfter(t *testing.T) {
+	ExecObjectLayerAPITest(t, testListObjectsV2StartAfter, []string{"ListObjectsV2"})
+}
+
+// Tests that start-after and continuation tokens are exclusive, the key
+// they name is never listed again.
+func testListObjectsV2StartAfter(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	for _, object := range []string{"a", "dir/x", "dir/y", "object-1", "object-2", "object-3", "z"} {
+		if _, err := obj.PutObject(bucketName, object, 0, bytes.NewReader(nil), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	listObjects := func(queryValues url.Values) ListObjectsV2Response {
+		queryValues.Set("list-type", "2")
+		req, err := newTestSignedRequestV4("GET", makeTestTargetURL("", bucketName, "", queryValues),
+			0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for ListObjectsV2: <ERROR> %v", instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+		}
+		var resp ListObjectsV2Response
+		if err = xml.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: Unable to parse the response: %v", instanceType, err)
+		}
+		return resp
+	}
+	keysOf := func(resp ListObjectsV2Response) (keys []string) {
+		for _, object := range resp.Contents {
+			keys = append(keys, object.Key)
+		}
+		for _, prefix := range resp.CommonPrefixes {
+			keys = append(keys, prefix.Prefix)
+		}
+		return keys
+	}
+
+	// start-after naming an existing object excludes it.
+	resp := listObjects(url.Values{"start-after": []string{"object-2"}})
+	if keys := keysOf(resp); !reflect.DeepEqual(keys, []string{"object-3", "z"}) {
+		t.Errorf("%s: Expected [object-3 z] after object-2, got %v", instanceType, keys)
+	}
+	if resp.StartAfter != "object-2" {
+		t.Errorf("%s: Expected StartAfter object-2, got %q", instanceType, resp.StartAfter)
+	}
+
+	// Pages resumed from a continuation token start after its last key.
+	var keys []string
+	queryValues := url.Values{"delimiter": []string{"/"}, "max-keys": []string{"2"}}
+	for page := 0; page < 10; page++ {
+		resp = listObjects(queryValues)
+		keys = append(keys, keysOf(resp)...)
+		if !resp.IsTruncated {
+			break
+		}
+		queryValues.Set("continuation-token", resp.NextContinuationToken)
+	}
+	if expected := []string{"a", "dir/", "object-1", "object-2", "object-3", "z"}; !reflect.DeepEqual(keys, expected) {
+		t.Errorf("%s: Expected pages to list %v, got %v", instanceType, expected, keys)
+	}
+}
+
+// Wrapper for calling ListObjectsV1 encoding type tests for both XL multiple disks and single node setup.
+func TestListObjectsV1EncodingType(t *testing.T) {
+	ExecObjectLayerAPITest(t, testListObjectsV1EncodingType, []string{"ListObjectsV1"})
+}
+
+// Tests that object names with XML special characters are listed in a
+// well formed response, and encoded when encoding-type=url is requested.
+func testListObjectsV1EncodingType(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	objectNames := []string{`a&b<c>d"e'f`, "dir name/obj+1", "ünï"}
+	for _, objectName := range objectNames {
+		if _, err := obj.PutObject(bucketName, objectName, 0, bytes.NewReader(nil), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := []struct {
+		encodingType     string
+		delimiter        string
+		expectedKeys     []string
+		expectedPrefixes []string
+	}{
+		{"", "", objectNames, nil},
+		// Slashes are not encoded, like S3 does.
+		{"url", "", []string{"a%26b%3Cc%3Ed%22e%27f", "dir%20name/obj%2B1", "%C3%BCn%C3%AF"}, nil},
+		{"url", "/", []string{"a%26b%3Cc%3Ed%22e%27f", "%C3%BCn%C3%AF"}, []string{"dir%20name/"}},
+	}
+	for i, testCase := range testCases {
+		queryValues := url.Values{}
+		if testCase.encodingType != "" {
+			queryValues.Set("encoding-type", testCase.encodingType)
+		}
+		if testCase.delimiter != "" {
+			queryValues.Set("delimiter", testCase.delimiter)
+		}
+		req, err := newTestSignedRequestV4("GET", makeTestTargetURL("", bucketName, "", queryValues),
+			0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for ListObjectsV1: <ERROR> %v", instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Test %d: Expected the response status to be `%d`, but instead found `%d`", instanceType, i+1, http.StatusOK, rec.Code)
+		}
+		var resp ListObjectsResponse
+		if err = xml.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: Test %d: Unable to parse the response: %v", instanceType, i+1, err)
+		}
+		if resp.EncodingType != testCase.encodingType {
+			t.Errorf("%s: Test %d: Expected encoding type %q, got %q", instanceType, i+1, testCase.encodingType, resp.EncodingType)
+		}
+		var keys, prefixes []string
+		for _, object := range resp.Contents {
+			keys = append(keys, object.Key)
+		}
+		for _, prefix := range resp.CommonPrefixes {
+			prefixes = append(prefixes, prefix.Prefix)
+		}
+		if !reflect.DeepEqual(keys, testCase.expectedKeys) {
+			t.Errorf("%s: Test %d: Expected keys %q, got %q", instanceType, i+1, testCase.expectedKeys, keys)
+		}
+		if !reflect.DeepEqual(prefixes, testCase.expectedPrefixes) {
+			t.Errorf("%s: Test %d: Expected prefixes %q, got %q", instanceType, i+1, testCase.expectedPrefixes, prefixes)
+		}
+	}
+}
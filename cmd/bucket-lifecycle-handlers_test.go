@@ -0,0 +1,130 @@
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Tests objects matching a lifecycle expiration rule carry the x-amz-expiration header.
+func TestAPIBucketLifecycleHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPIBucketLifecycleHandlers, []string{"PutBucketLifecycle", "GetBucketLifecycle",
+		"DeleteBucketLifecycle", "GetObject", "HeadObject"})
+}
+
+func testAPIBucketLifecycleHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	// Helper to send signed requests.
+	sendRequest := func(method, urlStr string, data []byte) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(method, urlStr, int64(len(data)), bytes.NewReader(data),
+			credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s %s: <ERROR> %v", instanceType, method, urlStr, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	data := []byte("hello, world")
+	for _, object := range []string{"logs/object", "object"} {
+		if _, err := obj.PutObject(bucketName, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatalf("%s: <ERROR> %s", instanceType, err)
+		}
+	}
+
+	// No lifecycle is set initially.
+	if rec := sendRequest("GET", getBucketLifecycleURL("", bucketName), nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+
+	// Invalid lifecycles are rejected.
+	invalidLifecycles := []struct {
+		lifecycle  string
+		statusCode int
+	}{
+		{"<LifecycleConfiguration>", http.StatusBadRequest},
+		{"<LifecycleConfiguration></LifecycleConfiguration>", http.StatusBadRequest},
+		{"<LifecycleConfiguration><Rule><Status>Enabled</Status></Rule></LifecycleConfiguration>", http.StatusBadRequest},
+	}
+	for i, testCase := range invalidLifecycles {
+		if rec := sendRequest("PUT", getBucketLifecycleURL("", bucketName), []byte(testCase.lifecycle)); rec.Code != testCase.statusCode {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.statusCode, rec.Code)
+		}
+	}
+
+	// Expire objects in "logs/" 30 days after their creation.
+	lifecycle := `<LifecycleConfiguration><Rule><ID>expire-logs</ID><Prefix>logs/</Prefix><Status>Enabled</Status>` +
+		`<Expiration><Days>30</Days></Expiration></Rule></LifecycleConfiguration>`
+	if rec := sendRequest("PUT", getBucketLifecycleURL("", bucketName), []byte(lifecycle)); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	rec := sendRequest("GET", getBucketLifecycleURL("", bucketName), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	lcfg := lifecycleConfiguration{}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &lcfg); err != nil {
+		t.Fatalf("%s: Failed to parse Get Bucket Lifecycle response: <ERROR> %v", instanceType, err)
+	}
+	if len(lcfg.Rules) != 1 || lcfg.Rules[0].ID != "expire-logs" || lcfg.Rules[0].Expiration.Days != 30 {
+		t.Fatalf("%s: Unexpected lifecycle %#v", instanceType, lcfg)
+	}
+
+	objInfo, err := obj.GetObjectInfo(bucketName, "logs/object")
+	if err != nil {
+		t.Fatalf("%s: <ERROR> %s", instanceType, err)
+	}
+	// Expiry is 30 days after the creation, rounded up to the next midnight UTC.
+	expiryDate := objInfo.ModTime.UTC().AddDate(0, 0, 30).Truncate(24 * time.Hour).Add(24 * time.Hour)
+	expectedExpiration := fmt.Sprintf("expiry-date=\"%s\", rule-id=\"expire-logs\"", expiryDate.Format(http.TimeFormat))
+
+	testCases := []struct {
+		objectName string
+		expiration string
+	}{
+		{"logs/object", expectedExpiration},
+		// Object not matching the rule prefix.
+		{"object", ""},
+	}
+	for i, testCase := range testCases {
+		for _, method := range []string{"GET", "HEAD"} {
+			rec = sendRequest(method, getGetObjectURL("", bucketName, testCase.objectName), nil)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Test %d: %s: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, method, http.StatusOK, rec.Code)
+			}
+			if expiration := rec.Header().Get("x-amz-expiration"); expiration != testCase.expiration {
+				t.Errorf("Test %d: %s: %s: Expected x-amz-expiration `%s`, got `%s`", i+1, instanceType, method, testCase.expiration, expiration)
+			}
+		}
+	}
+
+	// Objects do not expire once the lifecycle is removed.
+	if rec = sendRequest("DELETE", getBucketLifecycleURL("", bucketName), nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNoContent, rec.Code)
+	}
+	rec = sendRequest("HEAD", getGetObjectURL("", bucketName, "logs/object"), nil)
+	if expiration := rec.Header().Get("x-amz-expiration"); expiration != "" {
+		t.Errorf("%s: Expected no x-amz-expiration, got `%s`", instanceType, expiration)
+	}
+}
@@ -0,0 +1,276 @@
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Bucket lifecycle config file, saved along with other bucket configs.
+	bucketLifecycleConfig = "lifecycle.xml"
+
+	// Maximum size of a bucket lifecycle config.
+	maxBucketLifecycleConfigSize = 20 * 1024
+
+	// Maximum number of rules in a bucket lifecycle config.
+	maxBucketLifecycleRules = 1000
+
+	// Maximum length of a lifecycle rule id.
+	maxLifecycleRuleIDLength = 255
+
+	// Cached bucket lifecycle configs are reloaded after this duration.
+	bucketLifecycleCacheExpiry = 5 * time.Minute
+)
+
+// Lifecycle rule status.
+const (
+	lifecycleRuleEnabled  = "Enabled"
+	lifecycleRuleDisabled = "Disabled"
+)
+
+// lifecycleExpiration - expiration action of a lifecycle rule, objects
+// expire either a number of days after their creation or at a date.
+type lifecycleExpiration struct {
+	Days int        `xml:"Days,omitempty"`
+	Date *time.Time `xml:"Date,omitempty"`
+}
+
+// lifecycleRule - lifecycle rule applied to objects with a key prefix.
+type lifecycleRule struct {
+	ID         string               `xml:"ID,omitempty"`
+	Prefix     string               `xml:"Prefix"`
+	Status     string               `xml:"Status"`
+	Expiration *lifecycleExpiration `xml:"Expiration,omitempty"`
+}
+
+// lifecycleConfiguration - bucket lifecycle configuration.
+type lifecycleConfiguration struct {
+	XMLName xml.Name        `xml:"LifecycleConfiguration"`
+	Rules   []lifecycleRule `xml:"Rule"`
+}
+
+var (
+	errLifecycleNoRules       = errors.New("Lifecycle configuration should have at least one rule")
+	errLifecycleTooManyRules  = errors.New("Lifecycle configuration allows a maximum of 1000 rules")
+	errLifecycleInvalidID     = errors.New("Lifecycle rule ID should be unique and up to 255 characters")
+	errLifecycleInvalidStatus = errors.New("Lifecycle rule status should be Enabled or Disabled")
+	errLifecycleNoAction      = errors.New("Lifecycle rule should have an Expiration")
+	errLifecycleInvalidExpiry = errors.New("Lifecycle rule Expiration should have either positive Days or a Date")
+)
+
+// validateLifecycleConfig - validates a lifecycle configuration.
+func validateLifecycleConfig(lcfg *lifecycleConfiguration) error {
+	if len(lcfg.Rules) == 0 {
+		return errLifecycleNoRules
+	}
+	if len(lcfg.Rules) > maxBucketLifecycleRules {
+		return errLifecycleTooManyRules
+	}
+	ids := make(map[string]bool)
+	for _, rule := range lcfg.Rules {
+		if len(rule.ID) > maxLifecycleRuleIDLength || (rule.ID != "" && ids[rule.ID]) {
+			return errLifecycleInvalidID
+		}
+		ids[rule.ID] = true
+		if rule.Status != lifecycleRuleEnabled && rule.Status != lifecycleRuleDisabled {
+			return errLifecycleInvalidStatus
+		}
+		if rule.Expiration == nil {
+			return errLifecycleNoAction
+		}
+		hasDays := rule.Expiration.Days > 0
+		hasDate := rule.Expiration.Date != nil
+		if hasDays == hasDate || rule.Expiration.Days < 0 {
+			return errLifecycleInvalidExpiry
+		}
+	}
+	return nil
+}
+
+// readBucketLifecycle - reads bucket lifecycle for an input bucket, returns
+// BucketLifecycleNotFound if bucket lifecycle is not found.
+func readBucketLifecycle(bucket string, objAPI ObjectLayer) (*lifecycleConfiguration, error) {
+	lifecyclePath := pathJoin(bucketConfigPrefix, bucket, bucketLifecycleConfig)
+	objInfo, err := objAPI.GetObjectInfo(minioMetaBucket, lifecyclePath)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return nil, BucketLifecycleNotFound{Bucket: bucket}
+		}
+		errorIf(err, "Unable to load lifecycle for the bucket %s.", bucket)
+		return nil, errorCause(err)
+	}
+	var buffer bytes.Buffer
+	err = objAPI.GetObject(minioMetaBucket, lifecyclePath, 0, objInfo.Size, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return nil, BucketLifecycleNotFound{Bucket: bucket}
+		}
+		errorIf(err, "Unable to load lifecycle for the bucket %s.", bucket)
+		return nil, errorCause(err)
+	}
+	lcfg := &lifecycleConfiguration{}
+	if err = xml.Unmarshal(buffer.Bytes(), lcfg); err != nil {
+		errorIf(err, "Unable to parse lifecycle for the bucket %s.", bucket)
+		return nil, err
+	}
+	return lcfg, nil
+}
+
+// writeBucketLifecycle - save a bucket lifecycle that is assumed to be validated.
+func writeBucketLifecycle(bucket string, objAPI ObjectLayer, lcfg *lifecycleConfiguration) error {
+	buf, err := xml.Marshal(lcfg)
+	if err != nil {
+		errorIf(err, "Unable to marshal bucket lifecycle '%v' to XML", *lcfg)
+		return err
+	}
+	lifecyclePath := pathJoin(bucketConfigPrefix, bucket, bucketLifecycleConfig)
+	if _, err = objAPI.PutObject(minioMetaBucket, lifecyclePath, int64(len(buf)), bytes.NewReader(buf), nil, ""); err != nil {
+		errorIf(err, "Unable to set lifecycle for the bucket %s", bucket)
+		return errorCause(err)
+	}
+	globalBucketLifecycleCache.invalidate(bucket)
+	return nil
+}
+
+// removeBucketLifecycle - removes any previously written bucket lifecycle.
+// Returns BucketLifecycleNotFound if no lifecycle is found.
+func removeBucketLifecycle(bucket string, objAPI ObjectLayer) error {
+	globalBucketLifecycleCache.invalidate(bucket)
+	lifecyclePath := pathJoin(bucketConfigPrefix, bucket, bucketLifecycleConfig)
+	if err := objAPI.DeleteObject(minioMetaBucket, lifecyclePath); err != nil {
+		err = errorCause(err)
+		if _, ok := err.(ObjectNotFound); ok {
+			return BucketLifecycleNotFound{Bucket: bucket}
+		}
+		errorIf(err, "Unable to remove lifecycle on bucket %s.", bucket)
+		return err
+	}
+	return nil
+}
+
+// bucketLifecycleEntry - cached lifecycle of a bucket, lcfg is nil if
+// the bucket has no lifecycle.
+type bucketLifecycleEntry struct {
+	lcfg      *lifecycleConfiguration
+	updatedAt time.Time
+}
+
+// bucketLifecycleCache - caches lifecycle of buckets to avoid reading the
+// lifecycle config for every GetObject and HeadObject. Each server drops
+// the lifecycles it changes only, in distributed setups changes made
+// through other servers are seen after expiry.
+type bucketLifecycleCache struct {
+	mu      sync.Mutex
+	buckets map[string]bucketLifecycleEntry
+	expiry  time.Duration
+}
+
+func newBucketLifecycleCache(expiry time.Duration) *bucketLifecycleCache {
+	return &bucketLifecycleCache{
+		buckets: make(map[string]bucketLifecycleEntry),
+		expiry:  expiry,
+	}
+}
+
+// Global cache of bucket lifecycles.
+var globalBucketLifecycleCache = newBucketLifecycleCache(bucketLifecycleCacheExpiry)
+
+// get - returns lifecycle of a bucket, reloaded if expired.
+func (c *bucketLifecycleCache) get(bucket string, objAPI ObjectLayer) (*lifecycleConfiguration, error) {
+	c.mu.Lock()
+	entry, ok := c.buckets[bucket]
+	c.mu.Unlock()
+	if ok && time.Since(entry.updatedAt) < c.expiry {
+		return entry.lcfg, nil
+	}
+
+	lcfg, err := readBucketLifecycle(bucket, objAPI)
+	if err != nil && !isErrBucketLifecycleNotFound(err) {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.buckets[bucket] = bucketLifecycleEntry{lcfg: lcfg, updatedAt: time.Now().UTC()}
+	c.mu.Unlock()
+	return lcfg, nil
+}
+
+// invalidate - drops cached lifecycle of a bucket.
+func (c *bucketLifecycleCache) invalidate(bucket string) {
+	c.mu.Lock()
+	delete(c.buckets, bucket)
+	c.mu.Unlock()
+}
+
+// expiryDate - returns the date at which an object modified at modTime
+// expires by this rule. Like S3, expiry is rounded up to the next
+// midnight UTC.
+func (e lifecycleExpiration) expiryDate(modTime time.Time) time.Time {
+	if e.Date != nil {
+		return e.Date.UTC()
+	}
+	expiry := modTime.UTC().AddDate(0, 0, e.Days)
+	return expiry.Truncate(24 * time.Hour).Add(24 * time.Hour)
+}
+
+// getObjectExpiration - returns the value of the x-amz-expiration header
+// for an object, an empty string if no enabled lifecycle rule expires it.
+// The earliest expiry is used when more than one rule applies.
+func getObjectExpiration(lcfg *lifecycleConfiguration, objInfo ObjectInfo) string {
+	if lcfg == nil {
+		return ""
+	}
+	var expiry time.Time
+	var ruleID string
+	for _, rule := range lcfg.Rules {
+		if rule.Status != lifecycleRuleEnabled || rule.Expiration == nil {
+			continue
+		}
+		if !strings.HasPrefix(objInfo.Name, rule.Prefix) {
+			continue
+		}
+		ruleExpiry := rule.Expiration.expiryDate(objInfo.ModTime)
+		if expiry.IsZero() || ruleExpiry.Before(expiry) {
+			expiry, ruleID = ruleExpiry, rule.ID
+		}
+	}
+	if expiry.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("expiry-date=\"%s\", rule-id=\"%s\"", expiry.Format(http.TimeFormat), ruleID)
+}
+
+// setExpirationHeader - sets x-amz-expiration header if the object
+// expires by the lifecycle of its bucket.
+func setExpirationHeader(w http.ResponseWriter, objInfo ObjectInfo, objAPI ObjectLayer) {
+	lcfg, err := globalBucketLifecycleCache.get(objInfo.Bucket, objAPI)
+	if err != nil {
+		errorIf(err, "Unable to load lifecycle for the bucket %s.", objInfo.Bucket)
+		return
+	}
+	if expiration := getObjectExpiration(lcfg, objInfo); expiration != "" {
+		w.Header().Set("x-amz-expiration", sanitizeHeaderValue(expiration))
+	}
+}
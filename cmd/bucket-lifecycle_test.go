@@ -0,0 +1,90 @@
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Tests validation of lifecycle configurations.
+func TestValidateLifecycleConfig(t *testing.T) {
+	date := time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		rules       []lifecycleRule
+		expectedErr error
+	}{
+		// Valid rules.
+		{[]lifecycleRule{{ID: "logs", Prefix: "logs/", Status: lifecycleRuleEnabled, Expiration: &lifecycleExpiration{Days: 30}}}, nil},
+		{[]lifecycleRule{{Status: lifecycleRuleDisabled, Expiration: &lifecycleExpiration{Date: &date}}}, nil},
+		// No rules.
+		{nil, errLifecycleNoRules},
+		// Duplicate and too long rule ids.
+		{[]lifecycleRule{
+			{ID: "id", Status: lifecycleRuleEnabled, Expiration: &lifecycleExpiration{Days: 1}},
+			{ID: "id", Status: lifecycleRuleEnabled, Expiration: &lifecycleExpiration{Days: 2}},
+		}, errLifecycleInvalidID},
+		{[]lifecycleRule{{ID: strings.Repeat("a", 256), Status: lifecycleRuleEnabled, Expiration: &lifecycleExpiration{Days: 1}}}, errLifecycleInvalidID},
+		// Invalid status.
+		{[]lifecycleRule{{Status: "enabled", Expiration: &lifecycleExpiration{Days: 1}}}, errLifecycleInvalidStatus},
+		// Missing or invalid expiration.
+		{[]lifecycleRule{{Status: lifecycleRuleEnabled}}, errLifecycleNoAction},
+		{[]lifecycleRule{{Status: lifecycleRuleEnabled, Expiration: &lifecycleExpiration{}}}, errLifecycleInvalidExpiry},
+		{[]lifecycleRule{{Status: lifecycleRuleEnabled, Expiration: &lifecycleExpiration{Days: -1}}}, errLifecycleInvalidExpiry},
+		{[]lifecycleRule{{Status: lifecycleRuleEnabled, Expiration: &lifecycleExpiration{Days: 1, Date: &date}}}, errLifecycleInvalidExpiry},
+	}
+	for i, testCase := range testCases {
+		if err := validateLifecycleConfig(&lifecycleConfiguration{Rules: testCase.rules}); err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expectedErr, err)
+		}
+	}
+}
+
+// Tests the x-amz-expiration header value computed for objects.
+func TestGetObjectExpiration(t *testing.T) {
+	modTime := time.Date(2016, time.November, 10, 15, 4, 5, 0, time.UTC)
+	date := time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC)
+	lcfg := &lifecycleConfiguration{Rules: []lifecycleRule{
+		{ID: "logs", Prefix: "logs/", Status: lifecycleRuleEnabled, Expiration: &lifecycleExpiration{Days: 30}},
+		{ID: "old-logs", Prefix: "logs/old/", Status: lifecycleRuleEnabled, Expiration: &lifecycleExpiration{Days: 1}},
+		{ID: "tmp", Prefix: "tmp/", Status: lifecycleRuleEnabled, Expiration: &lifecycleExpiration{Date: &date}},
+		{ID: "disabled", Prefix: "", Status: lifecycleRuleDisabled, Expiration: &lifecycleExpiration{Days: 1}},
+	}}
+	testCases := []struct {
+		lcfg       *lifecycleConfiguration
+		objectName string
+		expiration string
+	}{
+		// Expiry is rounded up to the next midnight UTC.
+		{lcfg, "logs/today", `expiry-date="Sun, 11 Dec 2016 00:00:00 GMT", rule-id="logs"`},
+		// Earliest expiry wins.
+		{lcfg, "logs/old/today", `expiry-date="Sat, 12 Nov 2016 00:00:00 GMT", rule-id="old-logs"`},
+		// Expiry at a date.
+		{lcfg, "tmp/file", `expiry-date="Sun, 01 Jan 2017 00:00:00 GMT", rule-id="tmp"`},
+		// Disabled rules and non matching prefixes are ignored.
+		{lcfg, "photos/pic.jpg", ""},
+		// No lifecycle.
+		{nil, "logs/today", ""},
+	}
+	for i, testCase := range testCases {
+		objInfo := ObjectInfo{Name: testCase.objectName, ModTime: modTime}
+		if expiration := getObjectExpiration(testCase.lcfg, objInfo); expiration != testCase.expiration {
+			t.Errorf("Test %d: Expected %q, got %q", i+1, testCase.expiration, expiration)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// PutBucketObjectLockConfigHandler - PUT Bucket object lock configuration
+// -----------------
+// This operation uses the object-lock subresource to set the default
+// retention of new objects. Object lock can only be configured on buckets
+// created with x-amz-bucket-object-lock-enabled.
+func (api objectAPIHandlers) PutBucketObjectLockConfigHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, r, ErrServerNotInitialized, r.URL.Path)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+		return
+	}
+
+	// Object lock cannot be enabled on existing buckets.
+	if _, err = readBucketObjectLock(bucket, objAPI); err != nil {
+		if isErrBucketObjectLockNotFound(err) {
+			writeErrorResponse(w, r, ErrObjectLockConfigurationNotAllowed, r.URL.Path)
+			return
+		}
+		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+		return
+	}
+
+	// Read object lock config up to maxBucketObjectLockConfigSize.
+	configBytes, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBucketObjectLockConfigSize))
+	if err != nil {
+		errorIf(err, "Unable to read from client.")
+		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+		return
+	}
+	cfg := &objectLockConfiguration{}
+	if err = xml.Unmarshal(configBytes, cfg); err != nil {
+		errorIf(err, "Unable to parse bucket object lock XML.")
+		writeErrorResponse(w, r, ErrMalformedXML, r.URL.Path)
+		return
+	}
+	if err = validateObjectLockConfig(cfg); err != nil {
+		writeErrorResponse(w, r, ErrInvalidObjectLockConfiguration, r.URL.Path)
+		return
+	}
+
+	if err = writeBucketObjectLock(bucket, objAPI, cfg); err != nil {
+		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+		return
+	}
+
+	// Success.
+	writeSuccessResponse(w, nil)
+}
+
+// GetBucketObjectLockConfigHandler - GET Bucket object lock configuration
+// -----------------
+// This operation uses the object-lock subresource to return the object
+// lock configuration of a bucket.
+func (api objectAPIHandlers) GetBucketObjectLockConfigHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, r, ErrServerNotInitialized, r.URL.Path)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+		return
+	}
+
+	cfg, err := readBucketObjectLock(bucket, objAPI)
+	if err != nil {
+		switch err.(type) {
+		case BucketObjectLockNotFound:
+			writeErrorResponse(w, r, ErrNoSuchObjectLockConfiguration, r.URL.Path)
+		default:
+			writeErrorResponse(w, r, ErrInternalError, r.URL.Path)
+		}
+		return
+	}
+
+	// Write to client.
+	writeSuccessResponse(w, encodeResponse(cfg))
+}
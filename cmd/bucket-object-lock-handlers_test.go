@@ -0,0 +1,229 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Tests locked objects cannot be overwritten until their retention expires.
+func TestAPIBucketObjectLockHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPIBucketObjectLockHandlers, []string{"PutBucketObjectLockConfig",
+		"GetBucketObjectLockConfig", "CopyObject", "PutObject", "PutBucket", "DeleteObject",
+		"DeleteMultipleObjects", "PostPolicy"})
+}
+
+func testAPIBucketObjectLockHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	// Helper to send signed requests with additional headers.
+	sendRequest := func(method, urlStr string, data []byte, headers map[string]string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestRequest(method, urlStr, int64(len(data)), bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s %s: <ERROR> %v", instanceType, method, urlStr, err)
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		if err = signRequestV4(req, credentials.AccessKeyID, credentials.SecretAccessKey); err != nil {
+			t.Fatalf("%s: Failed to sign HTTP request for %s %s: <ERROR> %v", instanceType, method, urlStr, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	expectStatus := func(rec *httptest.ResponseRecorder, statusCode int, desc string) {
+		if rec.Code != statusCode {
+			t.Fatalf("%s: %s: Expected the response status to be `%d`, but instead found `%d`", instanceType, desc, statusCode, rec.Code)
+		}
+	}
+
+	data := []byte("hello, world")
+	retainUntil := time.Now().UTC().Add(time.Hour).Format(time.RFC3339)
+	compliance := map[string]string{
+		amzObjectLockMode:            objectLockModeCompliance,
+		amzObjectLockRetainUntilDate: retainUntil,
+	}
+
+	// Object lock is not enabled on the bucket.
+	expectStatus(sendRequest("PUT", getPutObjectURL("", bucketName, "object"), data, compliance),
+		http.StatusBadRequest, "Lock on a bucket without object lock")
+	expectStatus(sendRequest("GET", getBucketObjectLockURL("", bucketName), nil, nil),
+		http.StatusNotFound, "Get object lock of a bucket without object lock")
+	defaultRetention := `<ObjectLockConfiguration><ObjectLockEnabled>Enabled</ObjectLockEnabled>` +
+		`<Rule><DefaultRetention><Mode>GOVERNANCE</Mode><Days>1</Days></DefaultRetention></Rule></ObjectLockConfiguration>`
+	expectStatus(sendRequest("PUT", getBucketObjectLockURL("", bucketName), []byte(defaultRetention), nil),
+		http.StatusConflict, "Enable object lock on an existing bucket")
+
+	// Create a bucket with object lock enabled.
+	lockedBucket := getRandomBucketName()
+	expectStatus(sendRequest("PUT", getMakeBucketURL("", lockedBucket), nil, map[string]string{amzBucketObjectLockEnabled: "true"}),
+		http.StatusOK, "Create bucket with object lock")
+
+	// Objects locked in compliance mode cannot be overwritten.
+	expectStatus(sendRequest("PUT", getPutObjectURL("", lockedBucket, "object"), data, compliance),
+		http.StatusOK, "Put locked object")
+	objInfo, err := obj.GetObjectInfo(lockedBucket, "object")
+	if err != nil {
+		t.Fatalf("%s: <ERROR> %s", instanceType, err)
+	}
+	if objInfo.UserDefined[amzObjectLockMode] != objectLockModeCompliance || objInfo.UserDefined[amzObjectLockRetainUntilDate] != retainUntil {
+		t.Fatalf("%s: Unexpected object lock metadata %v", instanceType, objInfo.UserDefined)
+	}
+	expectStatus(sendRequest("PUT", getPutObjectURL("", lockedBucket, "object"), data, nil),
+		http.StatusForbidden, "Overwrite locked object")
+
+	// Requests are authenticated before the lock is checked, so the lock
+	// of an object is not revealed to unauthenticated callers.
+	badReq, err := newTestRequest("PUT", getPutObjectURL("", lockedBucket, "object"), int64(len(data)), bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("%s: <ERROR> %s", instanceType, err)
+	}
+	if err = signRequestV4(badReq, credentials.AccessKeyID, credentials.SecretAccessKey); err != nil {
+		t.Fatalf("%s: <ERROR> %s", instanceType, err)
+	}
+	badReq.Header.Set("Authorization", badReq.Header.Get("Authorization")+"a")
+	badRec := httptest.NewRecorder()
+	apiRouter.ServeHTTP(badRec, badReq)
+	errResponse := APIErrorResponse{}
+	if err = xml.Unmarshal(badRec.Body.Bytes(), &errResponse); err != nil {
+		t.Fatalf("%s: <ERROR> %s", instanceType, err)
+	}
+	if errResponse.Code != "SignatureDoesNotMatch" {
+		t.Fatalf("%s: Expected error code `SignatureDoesNotMatch` overwriting locked object with a bad signature, got `%s`", instanceType, errResponse.Code)
+	}
+
+	// Invalid lock headers are rejected.
+	invalidHeaders := []map[string]string{
+		{amzObjectLockMode: "INVALID", amzObjectLockRetainUntilDate: retainUntil},
+		{amzObjectLockMode: objectLockModeCompliance, amzObjectLockRetainUntilDate: "tomorrow"},
+		{amzObjectLockMode: objectLockModeCompliance, amzObjectLockRetainUntilDate: time.Now().UTC().Add(-time.Hour).Format(time.RFC3339)},
+		// No default retention to take the retain until date from.
+		{amzObjectLockMode: objectLockModeCompliance},
+	}
+	for i, headers := range invalidHeaders {
+		if rec := sendRequest("PUT", getPutObjectURL("", lockedBucket, "invalid"), data, headers); rec.Code != http.StatusBadRequest {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, http.StatusBadRequest, rec.Code)
+		}
+	}
+
+	// Objects without a lock can be overwritten.
+	for i := 0; i < 2; i++ {
+		expectStatus(sendRequest("PUT", getPutObjectURL("", lockedBucket, "unlocked"), data, nil),
+			http.StatusOK, "Put object without lock")
+	}
+
+	// The default retention of the bucket applies to new objects.
+	expectStatus(sendRequest("PUT", getBucketObjectLockURL("", lockedBucket), []byte("<ObjectLockConfiguration>"), nil),
+		http.StatusBadRequest, "Set malformed object lock")
+	expectStatus(sendRequest("PUT", getBucketObjectLockURL("", lockedBucket), []byte(defaultRetention), nil),
+		http.StatusOK, "Set default retention")
+	rec := sendRequest("GET", getBucketObjectLockURL("", lockedBucket), nil, nil)
+	expectStatus(rec, http.StatusOK, "Get object lock")
+	cfg := objectLockConfiguration{}
+	if err = xml.Unmarshal(rec.Body.Bytes(), &cfg); err != nil {
+		t.Fatalf("%s: <ERROR> %s", instanceType, err)
+	}
+	if cfg.Rule == nil || cfg.Rule.DefaultRetention.Mode != objectLockModeGovernance || cfg.Rule.DefaultRetention.Days != 1 {
+		t.Fatalf("%s: Unexpected object lock configuration %#v", instanceType, cfg)
+	}
+	expectStatus(sendRequest("PUT", getPutObjectURL("", lockedBucket, "default"), data, nil),
+		http.StatusOK, "Put object with default retention")
+	objInfo, err = obj.GetObjectInfo(lockedBucket, "default")
+	if err != nil {
+		t.Fatalf("%s: <ERROR> %s", instanceType, err)
+	}
+	if objInfo.UserDefined[amzObjectLockMode] != objectLockModeGovernance {
+		t.Fatalf("%s: Expected default retention mode, found %v", instanceType, objInfo.UserDefined)
+	}
+	expectStatus(sendRequest("PUT", getPutObjectURL("", lockedBucket, "default"), data, nil),
+		http.StatusForbidden, "Overwrite object with default retention")
+
+	// Locked objects cannot be deleted or overwritten through any other path.
+	expectStatus(sendRequest("DELETE", getDeleteObjectURL("", lockedBucket, "object"), nil, nil),
+		http.StatusForbidden, "Delete locked object")
+	deleteObjects := DeleteObjectsRequest{Objects: []ObjectIdentifier{{ObjectName: "object"}}}
+	deleteBytes, err := xml.Marshal(deleteObjects)
+	if err != nil {
+		t.Fatalf("%s: <ERROR> %s", instanceType, err)
+	}
+	rec = sendRequest("POST", getMultiDeleteObjectURL("", lockedBucket), deleteBytes, nil)
+	expectStatus(rec, http.StatusOK, "Delete multiple locked objects")
+	deleteResp := DeleteObjectsResponse{}
+	if err = xml.Unmarshal(rec.Body.Bytes(), &deleteResp); err != nil {
+		t.Fatalf("%s: <ERROR> %s", instanceType, err)
+	}
+	if len(deleteResp.DeletedObjects) != 0 || len(deleteResp.Errors) != 1 || deleteResp.Errors[0].Code != "AccessDenied" {
+		t.Fatalf("%s: Expected locked object not to be deleted, got %#v", instanceType, deleteResp)
+	}
+	copySource := map[string]string{"X-Amz-Copy-Source": "/" + lockedBucket + "/unlocked"}
+	expectStatus(sendRequest("PUT", getCopyObjectURL("", lockedBucket, "object"), nil, copySource),
+		http.StatusForbidden, "Copy onto locked object")
+	selfCopy := map[string]string{"X-Amz-Copy-Source": "/" + lockedBucket + "/object", "X-Amz-Metadata-Directive": "REPLACE"}
+	expectStatus(sendRequest("PUT", getCopyObjectURL("", lockedBucket, "object"), nil, selfCopy),
+		http.StatusForbidden, "Replace metadata of locked object")
+	postObject := func(object string) *httptest.ResponseRecorder {
+		req, pErr := newPostRequestV4("", lockedBucket, object, data, credentials.AccessKeyID, credentials.SecretAccessKey)
+		if pErr != nil {
+			t.Fatalf("%s: <ERROR> %s", instanceType, pErr)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	expectStatus(postObject("posted"), http.StatusNoContent, "Post policy upload")
+	expectStatus(postObject("object"), http.StatusForbidden, "Post policy upload onto locked object")
+	uploadID, err := obj.NewMultipartUpload(lockedBucket, "object", nil)
+	if err != nil {
+		t.Fatalf("%s: <ERROR> %s", instanceType, err)
+	}
+	md5Hex, err := obj.PutObjectPart(lockedBucket, "object", uploadID, 1, int64(len(data)), bytes.NewReader(data), "", "")
+	if err != nil {
+		t.Fatalf("%s: <ERROR> %s", instanceType, err)
+	}
+	_, err = obj.CompleteMultipartUpload(lockedBucket, "object", uploadID, []completePart{{PartNumber: 1, ETag: md5Hex}})
+	if _, ok := errorCause(err).(ObjectLocked); !ok {
+		t.Fatalf("%s: Expected multipart upload onto locked object to fail with ObjectLocked, got %v", instanceType, err)
+	}
+
+	// The locked object is left as is.
+	objInfo, err = obj.GetObjectInfo(lockedBucket, "object")
+	if err != nil {
+		t.Fatalf("%s: <ERROR> %s", instanceType, err)
+	}
+	if objInfo.UserDefined[amzObjectLockMode] != objectLockModeCompliance || objInfo.UserDefined[amzObjectLockRetainUntilDate] != retainUntil {
+		t.Fatalf("%s: Unexpected object lock metadata %v", instanceType, objInfo.UserDefined)
+	}
+
+	// Objects can be deleted once their retention expires.
+	expired := map[string]string{
+		amzObjectLockMode:            objectLockModeCompliance,
+		amzObjectLockRetainUntilDate: time.Now().UTC().Add(-time.Hour).Format(time.RFC3339),
+	}
+	if _, err = obj.PutObject(lockedBucket, "expired", int64(len(data)), bytes.NewReader(data), expired, ""); err != nil {
+		t.Fatalf("%s: <ERROR> %s", instanceType, err)
+	}
+	expectStatus(sendRequest("DELETE", getDeleteObjectURL("", lockedBucket, "expired"), nil, nil),
+		http.StatusNoContent, "Delete object with expired retention")
+	if _, err = obj.GetObjectInfo(lockedBucket, "expired"); !isErrObjectNotFound(err) {
+		t.Fatalf("%s: Expected object with expired retention to be deleted, got %v", instanceType, err)
+	}
+}
@@ -0,0 +1,303 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// Bucket object lock config file, saved along with other bucket configs.
+	bucketObjectLockConfig = "object-lock.xml"
+
+	// Maximum size of a bucket object lock config.
+	maxBucketObjectLockConfigSize = 1024
+
+	// Cached bucket object lock configs are reloaded after this duration.
+	bucketObjectLockCacheExpiry = 5 * time.Minute
+)
+
+// Object lock headers.
+const (
+	amzBucketObjectLockEnabled   = "X-Amz-Bucket-Object-Lock-Enabled"
+	amzObjectLockMode            = "X-Amz-Object-Lock-Mode"
+	amzObjectLockRetainUntilDate = "X-Amz-Object-Lock-Retain-Until-Date"
+)
+
+// Object lock retention modes.
+const (
+	objectLockModeGovernance = "GOVERNANCE"
+	objectLockModeCompliance = "COMPLIANCE"
+)
+
+// Value of ObjectLockEnabled in an object lock configuration.
+const objectLockEnabled = "Enabled"
+
+// objectLockRetention - default retention applied to new objects of a
+// bucket, either a number of days or years.
+type objectLockRetention struct {
+	Mode  string `xml:"Mode"`
+	Days  int    `xml:"Days,omitempty"`
+	Years int    `xml:"Years,omitempty"`
+}
+
+// objectLockRule - default retention rule of a bucket.
+type objectLockRule struct {
+	DefaultRetention objectLockRetention `xml:"DefaultRetention"`
+}
+
+// objectLockConfiguration - bucket object lock configuration.
+type objectLockConfiguration struct {
+	XMLName           xml.Name        `xml:"ObjectLockConfiguration"`
+	ObjectLockEnabled string          `xml:"ObjectLockEnabled"`
+	Rule              *objectLockRule `xml:"Rule,omitempty"`
+}
+
+var (
+	errObjectLockNotEnabled      = errors.New("Object lock configuration should have ObjectLockEnabled set to Enabled")
+	errObjectLockInvalidMode     = errors.New("Object lock default retention mode should be GOVERNANCE or COMPLIANCE")
+	errObjectLockInvalidDuration = errors.New("Object lock default retention should have either positive Days or Years")
+)
+
+// isValidObjectLockMode - returns true if mode is a supported retention mode.
+func isValidObjectLockMode(mode string) bool {
+	return mode == objectLockModeGovernance || mode == objectLockModeCompliance
+}
+
+// validateObjectLockConfig - validates an object lock configuration.
+func validateObjectLockConfig(cfg *objectLockConfiguration) error {
+	if cfg.ObjectLockEnabled != objectLockEnabled {
+		return errObjectLockNotEnabled
+	}
+	if cfg.Rule == nil {
+		return nil
+	}
+	retention := cfg.Rule.DefaultRetention
+	if !isValidObjectLockMode(retention.Mode) {
+		return errObjectLockInvalidMode
+	}
+	hasDays := retention.Days > 0
+	hasYears := retention.Years > 0
+	if hasDays == hasYears || retention.Days < 0 || retention.Years < 0 {
+		return errObjectLockInvalidDuration
+	}
+	return nil
+}
+
+// readBucketObjectLock - reads bucket object lock config for an input bucket,
+// returns BucketObjectLockNotFound if object lock is not enabled.
+func readBucketObjectLock(bucket string, objAPI ObjectLayer) (*objectLockConfiguration, error) {
+	lockPath := pathJoin(bucketConfigPrefix, bucket, bucketObjectLockConfig)
+	objInfo, err := objAPI.GetObjectInfo(minioMetaBucket, lockPath)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return nil, BucketObjectLockNotFound{Bucket: bucket}
+		}
+		errorIf(err, "Unable to load object lock for the bucket %s.", bucket)
+		return nil, errorCause(err)
+	}
+	var buffer bytes.Buffer
+	err = objAPI.GetObject(minioMetaBucket, lockPath, 0, objInfo.Size, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return nil, BucketObjectLockNotFound{Bucket: bucket}
+		}
+		errorIf(err, "Unable to load object lock for the bucket %s.", bucket)
+		return nil, errorCause(err)
+	}
+	cfg := &objectLockConfiguration{}
+	if err = xml.Unmarshal(buffer.Bytes(), cfg); err != nil {
+		errorIf(err, "Unable to parse object lock for the bucket %s.", bucket)
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// writeBucketObjectLock - save a bucket object lock config that is assumed
+// to be validated.
+func writeBucketObjectLock(bucket string, objAPI ObjectLayer, cfg *objectLockConfiguration) error {
+	buf, err := xml.Marshal(cfg)
+	if err != nil {
+		errorIf(err, "Unable to marshal bucket object lock '%v' to XML", *cfg)
+		return err
+	}
+	lockPath := pathJoin(bucketConfigPrefix, bucket, bucketObjectLockConfig)
+	if _, err = objAPI.PutObject(minioMetaBucket, lockPath, int64(len(buf)), bytes.NewReader(buf), nil, ""); err != nil {
+		errorIf(err, "Unable to set object lock for the bucket %s", bucket)
+		return errorCause(err)
+	}
+	globalBucketObjectLockCache.invalidate(bucket)
+	return nil
+}
+
+// removeBucketObjectLock - removes any previously written bucket object
+// lock config. Returns BucketObjectLockNotFound if none is found.
+func removeBucketObjectLock(bucket string, objAPI ObjectLayer) error {
+	globalBucketObjectLockCache.invalidate(bucket)
+	lockPath := pathJoin(bucketConfigPrefix, bucket, bucketObjectLockConfig)
+	if err := objAPI.DeleteObject(minioMetaBucket, lockPath); err != nil {
+		err = errorCause(err)
+		if _, ok := err.(ObjectNotFound); ok {
+			return BucketObjectLockNotFound{Bucket: bucket}
+		}
+		errorIf(err, "Unable to remove object lock on bucket %s.", bucket)
+		return err
+	}
+	return nil
+}
+
+// bucketObjectLockEntry - cached object lock config of a bucket, cfg is
+// nil if object lock is not enabled on the bucket.
+type bucketObjectLockEntry struct {
+	cfg       *objectLockConfiguration
+	updatedAt time.Time
+}
+
+// bucketObjectLockCache - caches object lock config of buckets to avoid
+// reading the config for every PutObject.
+type bucketObjectLockCache struct {
+	mu      sync.Mutex
+	buckets map[string]bucketObjectLockEntry
+	expiry  time.Duration
+}
+
+func newBucketObjectLockCache(expiry time.Duration) *bucketObjectLockCache {
+	return &bucketObjectLockCache{
+		buckets: make(map[string]bucketObjectLockEntry),
+		expiry:  expiry,
+	}
+}
+
+// Global cache of bucket object lock configs.
+var globalBucketObjectLockCache = newBucketObjectLockCache(bucketObjectLockCacheExpiry)
+
+// get - returns object lock config of a bucket, reloaded if expired.
+func (c *bucketObjectLockCache) get(bucket string, objAPI ObjectLayer) (*objectLockConfiguration, error) {
+	c.mu.Lock()
+	entry, ok := c.buckets[bucket]
+	c.mu.Unlock()
+	if ok && time.Since(entry.updatedAt) < c.expiry {
+		return entry.cfg, nil
+	}
+
+	cfg, err := readBucketObjectLock(bucket, objAPI)
+	if err != nil && !isErrBucketObjectLockNotFound(err) {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.buckets[bucket] = bucketObjectLockEntry{cfg: cfg, updatedAt: time.Now().UTC()}
+	c.mu.Unlock()
+	return cfg, nil
+}
+
+// invalidate - drops cached object lock config of a bucket.
+func (c *bucketObjectLockCache) invalidate(bucket string) {
+	c.mu.Lock()
+	delete(c.buckets, bucket)
+	c.mu.Unlock()
+}
+
+// isObjectRetained - returns true if the lock saved in the metadata of an
+// object retains it, that is its retention has not expired. Such objects
+// cannot be overwritten or deleted, object layers check it with the object
+// locked. A retain until date which cannot be parsed retains the object.
+func isObjectRetained(metadata map[string]string) bool {
+	if !isValidObjectLockMode(metadata[amzObjectLockMode]) {
+		return false
+	}
+	until, err := time.Parse(time.RFC3339, metadata[amzObjectLockRetainUntilDate])
+	if err != nil {
+		return true
+	}
+	return time.Now().UTC().Before(until)
+}
+
+// getObjectLockMetadata - returns the lock metadata saved with a new
+// object, from the object lock headers or the default retention of the
+// bucket. A missing retain until date is taken from the bucket default.
+func getObjectLockMetadata(r *http.Request, cfg *objectLockConfiguration) (map[string]string, APIErrorCode) {
+	mode := r.Header.Get(amzObjectLockMode)
+	untilStr := r.Header.Get(amzObjectLockRetainUntilDate)
+	if cfg == nil {
+		if mode != "" || untilStr != "" {
+			return nil, ErrObjectLockNotEnabled
+		}
+		return nil, ErrNone
+	}
+
+	var defaultRetention *objectLockRetention
+	if cfg.Rule != nil {
+		defaultRetention = &cfg.Rule.DefaultRetention
+	}
+	if mode == "" && defaultRetention != nil {
+		mode = defaultRetention.Mode
+	}
+	if mode == "" && untilStr == "" {
+		// Object is not locked.
+		return nil, ErrNone
+	}
+	if !isValidObjectLockMode(mode) {
+		return nil, ErrInvalidObjectLockHeaders
+	}
+
+	var until time.Time
+	if untilStr != "" {
+		var err error
+		until, err = time.Parse(time.RFC3339, untilStr)
+		if err != nil || !until.After(time.Now().UTC()) {
+			return nil, ErrInvalidObjectLockHeaders
+		}
+	} else if defaultRetention != nil {
+		until = time.Now().UTC().AddDate(defaultRetention.Years, 0, defaultRetention.Days)
+	} else {
+		return nil, ErrInvalidObjectLockHeaders
+	}
+
+	return map[string]string{
+		amzObjectLockMode:            mode,
+		amzObjectLockRetainUntilDate: until.UTC().Format(time.RFC3339),
+	}, ErrNone
+}
+
+// checkPutObjectLock - verifies that a PutObject does not overwrite a
+// locked object, returns the lock metadata to be saved with the new
+// object. Governance mode retention cannot be bypassed. Object layers
+// verify it again when the object is committed, this only rejects such
+// uploads before their data is read.
+func checkPutObjectLock(bucket, object string, r *http.Request, objAPI ObjectLayer) (map[string]string, APIErrorCode) {
+	cfg, err := globalBucketObjectLockCache.get(bucket, objAPI)
+	if err != nil {
+		errorIf(err, "Unable to load object lock for the bucket %s.", bucket)
+		return nil, toAPIErrorCode(err)
+	}
+	if cfg != nil {
+		objInfo, err := objAPI.GetObjectInfo(bucket, object)
+		if err != nil && !isErrObjectNotFound(err) {
+			return nil, toAPIErrorCode(err)
+		}
+		if err == nil && isObjectRetained(objInfo.UserDefined) {
+			return nil, ErrAccessDenied
+		}
+	}
+	return getObjectLockMetadata(r, cfg)
+}
@@ -0,0 +1,51 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "testing"
+
+// Tests validation of bucket object lock configurations.
+func TestValidateObjectLockConfig(t *testing.T) {
+	testCases := []struct {
+		cfg         objectLockConfiguration
+		expectedErr error
+	}{
+		{objectLockConfiguration{ObjectLockEnabled: objectLockEnabled}, nil},
+		{objectLockConfiguration{}, errObjectLockNotEnabled},
+		{objectLockConfiguration{
+			ObjectLockEnabled: objectLockEnabled,
+			Rule:              &objectLockRule{objectLockRetention{Mode: objectLockModeCompliance, Years: 1}},
+		}, nil},
+		{objectLockConfiguration{
+			ObjectLockEnabled: objectLockEnabled,
+			Rule:              &objectLockRule{objectLockRetention{Mode: "INVALID", Days: 1}},
+		}, errObjectLockInvalidMode},
+		{objectLockConfiguration{
+			ObjectLockEnabled: objectLockEnabled,
+			Rule:              &objectLockRule{objectLockRetention{Mode: objectLockModeGovernance}},
+		}, errObjectLockInvalidDuration},
+		{objectLockConfiguration{
+			ObjectLockEnabled: objectLockEnabled,
+			Rule:              &objectLockRule{objectLockRetention{Mode: objectLockModeGovernance, Days: 1, Years: 1}},
+		}, errObjectLockInvalidDuration},
+	}
+	for i, testCase := range testCases {
+		if err := validateObjectLockConfig(&testCase.cfg); err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expectedErr, err)
+		}
+	}
+}
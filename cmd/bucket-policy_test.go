@@ -0,0 +1,89 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Tests bucket policies written concurrently are never partially saved.
+func TestWriteBucketPolicyConcurrent(t *testing.T) {
+	ExecObjectLayerTest(t, testWriteBucketPolicyConcurrent)
+}
+
+func testWriteBucketPolicyConcurrent(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucketName := getRandomBucketName()
+	if err := obj.MakeBucket(bucketName); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	// Buckets without a policy report it as not found.
+	if _, err := readBucketPolicy(bucketName, obj); err != (BucketPolicyNotFound{Bucket: bucketName}) {
+		t.Fatalf("%s: Expected BucketPolicyNotFound, got %v", instanceType, err)
+	}
+
+	// Policies of different sizes, a partial write would not parse or
+	// would match neither of them.
+	newPolicy := func(prefixes ...string) *bucketPolicy {
+		var statements []string
+		for _, prefix := range prefixes {
+			statements = append(statements, fmt.Sprintf(`{"Action":["s3:GetObject"],"Effect":"Allow","Principal":{"AWS":["*"]},"Resource":["arn:aws:s3:::%s/%s*"],"Sid":""}`, bucketName, prefix))
+		}
+		policy := &bucketPolicy{}
+		policyJSON := `{"Version":"2012-10-17","Statement":[` + strings.Join(statements, ",") + `]}`
+		if err := parseBucketPolicy(strings.NewReader(policyJSON), policy); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+		return policy
+	}
+	policies := []*bucketPolicy{
+		newPolicy("short"),
+		newPolicy("a-much-longer-prefix", "another-prefix", "yet-another-prefix"),
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(policies))
+	for i, policy := range policies {
+		wg.Add(1)
+		go func(i int, policy *bucketPolicy) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if err := writeBucketPolicy(bucketName, obj, policy); err != nil {
+					errs[i] = err
+					return
+				}
+			}
+		}(i, policy)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+
+	policy, err := readBucketPolicy(bucketName, obj)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if policy.String() != policies[0].String() && policy.String() != policies[1].String() {
+		t.Fatalf("%s: Unexpected bucket policy %s", instanceType, policy)
+	}
+}
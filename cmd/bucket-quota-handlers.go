@@ -0,0 +1,133 @@
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// PutBucketQuotaHandler - PUT Bucket quota
+// -----------------
+// This operation uses the quota subresource to set the maximum number
+// of bytes stored in a bucket, a quota of zero bytes removes the quota.
+func (api objectAPIHandlers) PutBucketQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, r, ErrServerNotInitialized, r.URL.Path)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+		return
+	}
+
+	// Read bucket quota up to maxBucketQuotaConfigSize.
+	quotaBytes, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBucketQuotaConfigSize))
+	if err != nil {
+		errorIf(err, "Unable to read from client.")
+		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+		return
+	}
+	quota := &bucketQuota{}
+	if err = json.Unmarshal(quotaBytes, quota); err != nil || quota.MaxBytes < 0 {
+		writeErrorResponse(w, r, ErrInvalidBucketQuota, r.URL.Path)
+		return
+	}
+
+	// Quota of zero bytes removes the quota.
+	if quota.MaxBytes == 0 {
+		err = removeBucketQuota(bucket, objAPI)
+		if isErrBucketQuotaNotFound(err) {
+			err = nil
+		}
+	} else {
+		err = writeBucketQuota(bucket, objAPI, quota)
+	}
+	if err != nil {
+		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+		return
+	}
+
+	// Success.
+	writeSuccessNoContent(w)
+}
+
+// GetBucketQuotaHandler - GET Bucket quota
+// -----------------
+// This operation uses the quota subresource to return the quota
+// of a specified bucket.
+func (api objectAPIHandlers) GetBucketQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, r, ErrServerNotInitialized, r.URL.Path)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+		return
+	}
+
+	quota, err := readBucketQuota(bucket, objAPI)
+	if err != nil {
+		switch err.(type) {
+		case BucketQuotaNotFound:
+			writeErrorResponse(w, r, ErrNoSuchBucketQuota, r.URL.Path)
+		default:
+			writeErrorResponse(w, r, ErrInternalError, r.URL.Path)
+		}
+		return
+	}
+
+	quotaBytes, err := json.Marshal(quota)
+	if err != nil {
+		errorIf(err, "Unable to marshal bucket quota.")
+		writeErrorResponse(w, r, ErrInternalError, r.URL.Path)
+		return
+	}
+
+	// Write to client.
+	w.Write(quotaBytes)
+}
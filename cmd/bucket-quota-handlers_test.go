@@ -0,0 +1,157 @@
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Tests uploads are rejected once the bucket quota is reached.
+func TestAPIBucketQuotaHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPIBucketQuotaHandlers, []string{"PutBucketQuota", "GetBucketQuota", "PutObject", "DeleteObject"})
+}
+
+func testAPIBucketQuotaHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	// Helper to send signed requests.
+	sendRequest := func(method, urlStr string, data []byte) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(method, urlStr, int64(len(data)), bytes.NewReader(data),
+			credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s %s: <ERROR> %v", instanceType, method, urlStr, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// No quota is set initially.
+	if rec := sendRequest("GET", getBucketQuotaURL("", bucketName), nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+
+	// Invalid quotas are rejected.
+	for i, quota := range []string{"", "{", `{"maxBytes": -1}`} {
+		if rec := sendRequest("PUT", getBucketQuotaURL("", bucketName), []byte(quota)); rec.Code != http.StatusBadRequest {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, http.StatusBadRequest, rec.Code)
+		}
+	}
+
+	// Set a quota of 1MiB.
+	quotaBytes, err := json.Marshal(bucketQuota{MaxBytes: humanize.MiByte})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := sendRequest("PUT", getBucketQuotaURL("", bucketName), quotaBytes); rec.Code != http.StatusNoContent {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNoContent, rec.Code)
+	}
+	rec := sendRequest("GET", getBucketQuotaURL("", bucketName), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	quota := bucketQuota{}
+	if err = json.Unmarshal(rec.Body.Bytes(), &quota); err != nil {
+		t.Fatalf("%s: Failed to parse Get Bucket Quota response: <ERROR> %v", instanceType, err)
+	}
+	if quota.MaxBytes != humanize.MiByte {
+		t.Fatalf("%s: Expected quota of %d bytes, got %d", instanceType, humanize.MiByte, quota.MaxBytes)
+	}
+
+	// The 1st MiB fits in the quota, the 2nd does not.
+	bytesData := generateBytesData(humanize.MiByte)
+	if rec = sendRequest("PUT", getPutObjectURL("", bucketName, "object-1"), bytesData); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	rec = sendRequest("PUT", getPutObjectURL("", bucketName, "object-2"), bytesData)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusForbidden, rec.Code)
+	}
+	errResponse := APIErrorResponse{}
+	if err = xml.Unmarshal(rec.Body.Bytes(), &errResponse); err != nil {
+		t.Fatalf("%s: Failed to parse error response: <ERROR> %v", instanceType, err)
+	}
+	if errResponse.Code != "QuotaExceeded" {
+		t.Fatalf("%s: Expected error code `QuotaExceeded`, got `%s`", instanceType, errResponse.Code)
+	}
+	if _, err = obj.GetObjectInfo(bucketName, "object-2"); err == nil {
+		t.Fatalf("%s: Object exceeding the quota should not be created", instanceType)
+	}
+
+	// Deleting the object frees up the quota.
+	if rec = sendRequest("DELETE", getDeleteObjectURL("", bucketName, "object-1"), nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNoContent, rec.Code)
+	}
+	if rec = sendRequest("PUT", getPutObjectURL("", bucketName, "object-2"), bytesData); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+
+	// Overwrites free the size of the object they replace.
+	if rec = sendRequest("PUT", getPutObjectURL("", bucketName, "object-2"), bytesData); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+
+	// Anonymous uploads are denied before the quota is verified.
+	anonReq, err := newTestRequest("PUT", getPutObjectURL("", bucketName, "object-3"), int64(len(bytesData)), bytes.NewReader(bytesData))
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+	}
+	rec = httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, anonReq)
+	errResponse = APIErrorResponse{}
+	if err = xml.Unmarshal(rec.Body.Bytes(), &errResponse); err != nil {
+		t.Fatalf("%s: Failed to parse error response: <ERROR> %v", instanceType, err)
+	}
+	if errResponse.Code != "AccessDenied" {
+		t.Fatalf("%s: Expected error code `AccessDenied`, got `%s`", instanceType, errResponse.Code)
+	}
+
+	// Uploads of unknown size are stopped once they cross the quota.
+	chunkedReq, err := newTestSignedRequestV4("PUT", getPutObjectURL("", bucketName, "object-3"), int64(len(bytesData)), bytes.NewReader(bytesData),
+		credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+	}
+	chunkedReq.ContentLength = -1
+	chunkedReq.TransferEncoding = []string{"chunked"}
+	rec = httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, chunkedReq)
+	errResponse = APIErrorResponse{}
+	if err = xml.Unmarshal(rec.Body.Bytes(), &errResponse); err != nil {
+		t.Fatalf("%s: Failed to parse error response: <ERROR> %v", instanceType, err)
+	}
+	if errResponse.Code != "QuotaExceeded" {
+		t.Fatalf("%s: Expected error code `QuotaExceeded`, got `%s`", instanceType, errResponse.Code)
+	}
+	if _, err = obj.GetObjectInfo(bucketName, "object-3"); err == nil {
+		t.Fatalf("%s: Object exceeding the quota should not be created", instanceType)
+	}
+
+	// Quota of zero bytes removes the quota.
+	if rec = sendRequest("PUT", getBucketQuotaURL("", bucketName), []byte(`{"maxBytes": 0}`)); rec.Code != http.StatusNoContent {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNoContent, rec.Code)
+	}
+	if rec = sendRequest("PUT", getPutObjectURL("", bucketName, "object-3"), bytesData); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+}
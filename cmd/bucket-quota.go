@@ -0,0 +1,282 @@
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// Bucket quota config file, saved along with other bucket configs.
+	bucketQuotaConfig = "quota.json"
+
+	// Maximum size of a bucket quota config.
+	maxBucketQuotaConfigSize = 1024
+
+	// Cached bucket quota and usage are refreshed after this duration.
+	bucketQuotaCacheExpiry = 5 * time.Minute
+
+	// Maximum number of objects listed in one pass while computing
+	// bucket usage.
+	bucketUsageListMaxKeys = 1000
+)
+
+// bucketQuota - bucket quota configuration.
+type bucketQuota struct {
+	// Maximum number of bytes stored in a bucket, zero
+	// for no quota.
+	MaxBytes int64 `json:"maxBytes"`
+}
+
+// readBucketQuota - reads bucket quota for an input bucket, returns
+// BucketQuotaNotFound if bucket quota is not found.
+func readBucketQuota(bucket string, objAPI ObjectLayer) (*bucketQuota, error) {
+	quotaPath := pathJoin(bucketConfigPrefix, bucket, bucketQuotaConfig)
+	objInfo, err := objAPI.GetObjectInfo(minioMetaBucket, quotaPath)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return nil, BucketQuotaNotFound{Bucket: bucket}
+		}
+		errorIf(err, "Unable to load quota for the bucket %s.", bucket)
+		return nil, errorCause(err)
+	}
+	var buffer bytes.Buffer
+	err = objAPI.GetObject(minioMetaBucket, quotaPath, 0, objInfo.Size, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return nil, BucketQuotaNotFound{Bucket: bucket}
+		}
+		errorIf(err, "Unable to load quota for the bucket %s.", bucket)
+		return nil, errorCause(err)
+	}
+	quota := &bucketQuota{}
+	if err = json.Unmarshal(buffer.Bytes(), quota); err != nil {
+		errorIf(err, "Unable to parse quota for the bucket %s.", bucket)
+		return nil, err
+	}
+	return quota, nil
+}
+
+// writeBucketQuota - save a bucket quota that is assumed to be validated.
+func writeBucketQuota(bucket string, objAPI ObjectLayer, quota *bucketQuota) error {
+	buf, err := json.Marshal(quota)
+	if err != nil {
+		errorIf(err, "Unable to marshal bucket quota '%v' to JSON", *quota)
+		return err
+	}
+	quotaPath := pathJoin(bucketConfigPrefix, bucket, bucketQuotaConfig)
+	if _, err = objAPI.PutObject(minioMetaBucket, quotaPath, int64(len(buf)), bytes.NewReader(buf), nil, ""); err != nil {
+		errorIf(err, "Unable to set quota for the bucket %s", bucket)
+		return errorCause(err)
+	}
+	globalBucketQuotaCache.invalidate(bucket)
+	return nil
+}
+
+// removeBucketQuota - removes any previously written bucket quota. Returns
+// BucketQuotaNotFound if no quota is found.
+func removeBucketQuota(bucket string, objAPI ObjectLayer) error {
+	globalBucketQuotaCache.invalidate(bucket)
+	quotaPath := pathJoin(bucketConfigPrefix, bucket, bucketQuotaConfig)
+	if err := objAPI.DeleteObject(minioMetaBucket, quotaPath); err != nil {
+		err = errorCause(err)
+		if _, ok := err.(ObjectNotFound); ok {
+			return BucketQuotaNotFound{Bucket: bucket}
+		}
+		errorIf(err, "Unable to remove quota on bucket %s.", bucket)
+		return err
+	}
+	return nil
+}
+
+// getBucketUsage - returns the total size of all the objects in a bucket.
+func getBucketUsage(bucket string, objAPI ObjectLayer) (int64, error) {
+	var usage int64
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(bucket, "", marker, "", bucketUsageListMaxKeys)
+		if err != nil {
+			return 0, err
+		}
+		for _, objInfo := range result.Objects {
+			usage += objInfo.Size
+		}
+		if !result.IsTruncated {
+			return usage, nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// bucketQuotaUsage - quota and usage of a bucket at a point in time.
+type bucketQuotaUsage struct {
+	maxBytes  int64 // Zero if bucket has no quota.
+	usage     int64 // Computed only if bucket has a quota.
+	updatedAt time.Time
+}
+
+// bucketQuotaCache - caches quota and usage of buckets to avoid reading
+// the quota config and listing the whole bucket for every upload. Each
+// server caches the uploads and deletes it serves only, in distributed
+// setups changes made through other servers are seen after expiry.
+type bucketQuotaCache struct {
+	mu      sync.Mutex
+	buckets map[string]bucketQuotaUsage
+	expiry  time.Duration
+}
+
+func newBucketQuotaCache(expiry time.Duration) *bucketQuotaCache {
+	return &bucketQuotaCache{
+		buckets: make(map[string]bucketQuotaUsage),
+		expiry:  expiry,
+	}
+}
+
+// Global cache of bucket quota and usage.
+var globalBucketQuotaCache = newBucketQuotaCache(bucketQuotaCacheExpiry)
+
+// get - returns quota and usage of a bucket, refreshed if expired.
+func (c *bucketQuotaCache) get(bucket string, objAPI ObjectLayer) (bucketQuotaUsage, error) {
+	c.mu.Lock()
+	entry, ok := c.buckets[bucket]
+	c.mu.Unlock()
+	if ok && time.Since(entry.updatedAt) < c.expiry {
+		return entry, nil
+	}
+
+	entry = bucketQuotaUsage{updatedAt: time.Now().UTC()}
+	quota, err := readBucketQuota(bucket, objAPI)
+	if err != nil && !isErrBucketQuotaNotFound(err) {
+		return bucketQuotaUsage{}, err
+	}
+	if quota != nil && quota.MaxBytes > 0 {
+		entry.maxBytes = quota.MaxBytes
+		entry.usage, err = getBucketUsage(bucket, objAPI)
+		if err != nil {
+			return bucketQuotaUsage{}, err
+		}
+	}
+
+	c.mu.Lock()
+	c.buckets[bucket] = entry
+	c.mu.Unlock()
+	return entry, nil
+}
+
+// addUsage - accounts size bytes written to a bucket, negative when an
+// overwrite shrinks an object. Parts of multipart uploads are accounted
+// as they are uploaded, usage might be over estimated until the next
+// refresh.
+func (c *bucketQuotaCache) addUsage(bucket string, size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.buckets[bucket]; ok {
+		entry.usage += size
+		c.buckets[bucket] = entry
+	}
+}
+
+// invalidate - drops cached quota and usage of a bucket, called when the
+// quota changes or when objects or uploads are deleted since the size they
+// free is not known.
+func (c *bucketQuotaCache) invalidate(bucket string) {
+	c.mu.Lock()
+	delete(c.buckets, bucket)
+	c.mu.Unlock()
+}
+
+// errQuotaExceeded - upload of unknown size crossed the bucket quota.
+var errQuotaExceeded = errors.New("Bucket quota exceeded")
+
+// bucketQuotaUpload - quota left for an upload to a bucket.
+type bucketQuotaUpload struct {
+	bucket string
+	// Bytes which can still be written, negative if the bucket
+	// has no quota.
+	left int64
+	// Size of the object being replaced, freed once the upload
+	// succeeds.
+	replacedSize int64
+}
+
+// limitReader - returns a reader failing with errQuotaExceeded once
+// more than the bytes left are read from r, for uploads whose size is
+// not known in advance.
+func (q bucketQuotaUpload) limitReader(r io.Reader) io.Reader {
+	if q.left < 0 {
+		return r
+	}
+	return &quotaLimitReader{reader: r, left: q.left}
+}
+
+// commit - accounts an upload of size bytes which completed.
+func (q bucketQuotaUpload) commit(size int64) {
+	globalBucketQuotaCache.addUsage(q.bucket, size-q.replacedSize)
+}
+
+// quotaLimitReader - fails reads beyond the bytes left in the quota.
+type quotaLimitReader struct {
+	reader io.Reader
+	left   int64
+}
+
+func (q *quotaLimitReader) Read(p []byte) (n int, err error) {
+	n, err = q.reader.Read(p)
+	q.left -= int64(n)
+	if q.left < 0 {
+		return n, errQuotaExceeded
+	}
+	return n, err
+}
+
+// checkBucketQuota - verifies if size bytes can be written to a bucket
+// without exceeding its quota. Object is the name of the object the
+// upload replaces, its size does not count towards the quota, empty
+// for parts of multipart uploads. Size is -1 for uploads of unknown
+// size, they are limited while they are read through limitReader.
+func checkBucketQuota(bucket, object string, size int64, objAPI ObjectLayer) (bucketQuotaUpload, APIErrorCode) {
+	q := bucketQuotaUpload{bucket: bucket, left: -1}
+	entry, err := globalBucketQuotaCache.get(bucket, objAPI)
+	if err != nil {
+		errorIf(err, "Unable to verify quota for the bucket %s.", bucket)
+		return q, toAPIErrorCode(err)
+	}
+	if entry.maxBytes <= 0 {
+		return q, ErrNone
+	}
+	if object != "" {
+		objInfo, err := objAPI.GetObjectInfo(bucket, object)
+		if err != nil && !isErrObjectNotFound(err) {
+			errorIf(err, "Unable to verify quota for the bucket %s.", bucket)
+			return q, toAPIErrorCode(err)
+		}
+		q.replacedSize = objInfo.Size
+	}
+	q.left = entry.maxBytes - entry.usage + q.replacedSize
+	if q.left < 0 {
+		q.left = 0
+	}
+	if size > q.left {
+		return q, ErrQuotaExceeded
+	}
+	return q, ErrNone
+}
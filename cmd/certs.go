@@ -56,13 +56,21 @@ func mustGetCertsPath() string {
 	return certsPath
 }
 
-// mustGetCertFile must get cert file.
+// mustGetCertFile must get cert file, --cert-file takes precedence
+// over the certs directory.
 func mustGetCertFile() string {
+	if globalCertFile != "" {
+		return globalCertFile
+	}
 	return filepath.Join(mustGetCertsPath(), globalMinioCertFile)
 }
 
-// mustGetKeyFile must get key file.
+// mustGetKeyFile must get key file, --key-file takes precedence
+// over the certs directory.
 func mustGetKeyFile() string {
+	if globalKeyFile != "" {
+		return globalKeyFile
+	}
 	return filepath.Join(mustGetCertsPath(), globalMinioKeyFile)
 }
 
@@ -88,7 +96,7 @@ func mustGetSystemCertPool() *x509.CertPool {
 // isCertFileExists verifies if cert file exists, returns true if
 // found, false otherwise.
 func isCertFileExists() bool {
-	st, e := os.Stat(filepath.Join(mustGetCertsPath(), globalMinioCertFile))
+	st, e := os.Stat(mustGetCertFile())
 	// If file exists and is regular return true.
 	if e == nil && st.Mode().IsRegular() {
 		return true
@@ -99,7 +107,7 @@ func isCertFileExists() bool {
 // isKeyFileExists verifies if key file exists, returns true if found,
 // false otherwise.
 func isKeyFileExists() bool {
-	st, e := os.Stat(filepath.Join(mustGetCertsPath(), globalMinioKeyFile))
+	st, e := os.Stat(mustGetKeyFile())
 	// If file exists and is regular return true.
 	if e == nil && st.Mode().IsRegular() {
 		return true
@@ -112,15 +120,42 @@ func isSSL() bool {
 	return isCertFileExists() && isKeyFileExists()
 }
 
-// Reads certificated file and returns a list of parsed certificates.
-func readCertificateChain() ([]*x509.Certificate, error) {
-	bytes, err := ioutil.ReadFile(mustGetCertFile())
-	if err != nil {
-		return nil, err
+// isServerTLS - returns true if the server serves TLS with any of its
+// certificates, i.e. the default one or the wildcard one.
+func isServerTLS() bool {
+	return len(getServerCertFiles()) > 0
+}
+
+// getServerCertFiles - returns the files of all the certificates served
+// by the server, the default certificate first.
+func getServerCertFiles() (certFiles []string) {
+	if isSSL() {
+		certFiles = append(certFiles, mustGetCertFile())
+	}
+	if globalWildcardCertFile != "" && globalWildcardKeyFile != "" {
+		certFiles = append(certFiles, globalWildcardCertFile)
 	}
+	return certFiles
+}
+
+// Reads the certificate files served by the server and returns a list
+// of parsed certificates.
+func readCertificateChain() ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for _, certFile := range getServerCertFiles() {
+		bytes, err := ioutil.ReadFile(certFile)
+		if err != nil {
+			return nil, err
+		}
 
-	// Proceed to parse the certificates.
-	return parseCertificateChain(bytes)
+		// Proceed to parse the certificates.
+		chain, err := parseCertificateChain(bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, chain...)
+	}
+	return certs, nil
 }
 
 // Parses certificate chain, returns a list of parsed certificates.
@@ -145,3 +180,21 @@ func parseCertificateChain(bytes []byte) ([]*x509.Certificate, error) {
 	}
 	return certs, nil
 }
+
+// loadClientCAs - reads the PEM encoded CA certificates in caFile into
+// a pool verifying client certificates.
+func loadClientCAs(caFile string) (*x509.CertPool, error) {
+	bytes, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	certs, err := parseCertificateChain(bytes)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	for _, cert := range certs {
+		pool.AddCert(cert)
+	}
+	return pool, nil
+}
@@ -0,0 +1,74 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// Time to wait for another process to release the config lock.
+const configLockTimeout = 5 * time.Second
+
+// Interval between attempts at acquiring the config lock.
+const configLockRetryInterval = 100 * time.Millisecond
+
+// errConfigLocked - config lock could not be acquired in time.
+var errConfigLocked = errors.New("Unable to lock config file, another minio server may be using the same config directory")
+
+// getConfigLockFile - returns the path of the lock file guarding config.json.
+func getConfigLockFile() (string, error) {
+	configFile, err := getConfigFile()
+	if err != nil {
+		return "", err
+	}
+	return configFile + ".lock", nil
+}
+
+// lockConfigFile - takes an exclusive advisory lock on the config lock
+// file, retrying until timeout. The returned function releases the lock.
+func lockConfigFile(timeout time.Duration) (unlock func(), err error) {
+	lockFile, err := getConfigLockFile()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(lockFile, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, errConfigLocked
+		}
+		time.Sleep(configLockRetryInterval)
+	}
+	return func() {
+		errorIf(unlockFile(f), "Unable to unlock config file.")
+		f.Close()
+	}, nil
+}
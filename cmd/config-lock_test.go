@@ -0,0 +1,146 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Tests that concurrent config saves never leave a corrupted config behind.
+func TestConfigLockConcurrentSave(t *testing.T) {
+	rootPath, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	// remove the root directory after the test ends.
+	defer removeAll(rootPath)
+
+	n := 10
+	creds := make(map[string]credential)
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		srvCfg := *serverConfig
+		srvCfg.rwMutex = &sync.RWMutex{}
+		srvCfg.Credential = mustGenAccessKeys()
+		creds[srvCfg.Credential.AccessKeyID] = srvCfg.Credential
+		wg.Add(1)
+		go func(i int, cfg serverConfigV10) {
+			defer wg.Done()
+			errs[i] = cfg.Save()
+		}(i, srvCfg)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("Save %d failed: %v", i, err)
+		}
+	}
+
+	// Config must load cleanly and hold one of the saved credentials.
+	if _, err = initConfig(); err != nil {
+		t.Fatalf("Unable to load config after concurrent saves: %v", err)
+	}
+	cred := serverConfig.GetCredential()
+	if creds[cred.AccessKeyID] != cred {
+		t.Fatalf("Loaded credential %#v does not match any saved credential", cred)
+	}
+}
+
+// Tests that the config lock fails with errConfigLocked while held elsewhere.
+func TestConfigLockTimeout(t *testing.T) {
+	rootPath, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	// remove the root directory after the test ends.
+	defer removeAll(rootPath)
+
+	unlock, err := lockConfigFile(configLockTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = lockConfigFile(200 * time.Millisecond); err != errConfigLocked {
+		t.Fatalf("Expected %v, got %v", errConfigLocked, err)
+	}
+	unlock()
+
+	// Lock is available again once released.
+	unlock, err = lockConfigFile(200 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+}
+
+// Tests that creating and migrating the config wait for the config lock.
+func TestConfigLockInitAndMigrate(t *testing.T) {
+	rootPath, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	// remove the root directory after the test ends.
+	defer removeAll(rootPath)
+
+	configFile, err := getConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Remove(configFile); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name string
+		fn   func() error
+	}{
+		{"initConfig", func() error {
+			created, err := initConfig()
+			if err == nil && !created {
+				err = errors.New("config was not created")
+			}
+			return err
+		}},
+		{"migrateConfig", migrateConfig},
+	}
+	for _, testCase := range testCases {
+		unlock, err := lockConfigFile(configLockTimeout)
+		if err != nil {
+			t.Fatal(err)
+		}
+		doneCh := make(chan error, 1)
+		go func(fn func() error) {
+			doneCh <- fn()
+		}(testCase.fn)
+		select {
+		case err = <-doneCh:
+			t.Fatalf("%s: Expected to wait for the config lock, returned %v", testCase.name, err)
+		case <-time.After(200 * time.Millisecond):
+		}
+		unlock()
+		if err = <-doneCh; err != nil {
+			t.Fatalf("%s: Unexpected error %v", testCase.name, err)
+		}
+	}
+	if !isConfigFileExists() {
+		t.Fatal("Expected the config to be created")
+	}
+}
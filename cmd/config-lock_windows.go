@@ -0,0 +1,63 @@
+// +build windows
+
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	modkernel32      = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = modkernel32.NewProc("LockFileEx")
+	procUnlockFileEx = modkernel32.NewProc("UnlockFileEx")
+)
+
+const (
+	lockfileFailImmediately = 0x00000001
+	lockfileExclusiveLock   = 0x00000002
+
+	errorLockViolation syscall.Errno = 0x21
+)
+
+// tryLockFile - attempts to take an exclusive lock on f without blocking,
+// returns false if the lock is held elsewhere.
+func tryLockFile(f *os.File) (bool, error) {
+	ol := new(syscall.Overlapped)
+	r1, _, e1 := procLockFileEx.Call(f.Fd(), uintptr(lockfileExclusiveLock|lockfileFailImmediately),
+		0, 1, 0, uintptr(unsafe.Pointer(ol)))
+	if r1 != 0 {
+		return true, nil
+	}
+	if e1 == errorLockViolation {
+		return false, nil
+	}
+	return false, e1
+}
+
+// unlockFile - releases the lock taken by tryLockFile.
+func unlockFile(f *os.File) error {
+	ol := new(syscall.Overlapped)
+	r1, _, e1 := procUnlockFileEx.Call(f.Fd(), 0, 1, 0, uintptr(unsafe.Pointer(ol)))
+	if r1 == 0 {
+		return e1
+	}
+	return nil
+}
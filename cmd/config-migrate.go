@@ -26,6 +26,23 @@ import (
 )
 
 func migrateConfig() error {
+	// Nothing to migrate without a config directory.
+	configPath, err := getConfigPath()
+	if err != nil {
+		return err
+	}
+	if _, err = os.Stat(configPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	// Hold the config lock, other servers sharing the config directory
+	// must not load or save a config being migrated.
+	unlock, err := lockConfigFile(configLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Purge all configs with version '1'.
 	if err := purgeV1(); err != nil {
 		return err
@@ -44,6 +44,19 @@ type serverConfigV10 struct {
 
 // initConfig - initialize server config and indicate if we are creating a new file or we are just loading
 func initConfig() (bool, error) {
+	// Create config path, the config lock file lives in it.
+	if err := createConfigPath(); err != nil {
+		return false, err
+	}
+
+	// Hold the config lock while loading to avoid reading a partial
+	// write, and while creating so that only one server creates it.
+	unlock, err := lockConfigFile(configLockTimeout)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
 	if !isConfigFileExists() {
 		// Initialize server config.
 		srvCfg := &serverConfigV10{}
@@ -70,17 +83,11 @@ func initConfig() (bool, error) {
 		srvCfg.Notify.PostgreSQL["1"] = postgreSQLNotify{}
 		srvCfg.rwMutex = &sync.RWMutex{}
 
-		// Create config path.
-		err := createConfigPath()
-		if err != nil {
-			return false, err
-		}
-
 		// Save the new config globally.
 		serverConfig = srvCfg
 
-		// Save config into file.
-		return true, serverConfig.Save()
+		// Save config into file, the config lock is already held.
+		return true, serverConfig.save()
 	}
 	configFile, err := getConfigFile()
 	if err != nil {
@@ -89,6 +96,7 @@ func initConfig() (bool, error) {
 	if _, err = os.Stat(configFile); err != nil {
 		return false, err
 	}
+
 	srvCfg := &serverConfigV10{}
 	srvCfg.Version = globalMinioConfigVersion
 	srvCfg.rwMutex = &sync.RWMutex{}
@@ -272,6 +280,18 @@ func (s serverConfigV10) GetCredential() credential {
 
 // Save config.
 func (s serverConfigV10) Save() error {
+	// Serialize writers across minio processes sharing this config.
+	unlock, err := lockConfigFile(configLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return s.save()
+}
+
+// save - saves config, the caller holds the config lock.
+func (s serverConfigV10) save() error {
 	s.rwMutex.RLock()
 	defer s.rwMutex.RUnlock()
 
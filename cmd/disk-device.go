@@ -0,0 +1,91 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Returned by getDeviceID on platforms where device numbers of paths
+// cannot be compared.
+var errDeviceIDUnsupported = errors.New("Device numbers are not supported on this platform")
+
+// Root of the filesystem the operating system runs from, a single
+// storage path should be on a different device.
+const rootFilesystemPath = "/"
+
+// getSameDeviceWarnings - returns a warning for every device more than
+// one of the storage paths are on, erasure coding does not protect
+// against the failure of such a device. A single storage path is
+// compared against the root filesystem instead, unless allowSameDevice
+// is set.
+func getSameDeviceWarnings(paths []string, allowSameDevice bool) ([]string, error) {
+	if len(paths) == 1 {
+		if allowSameDevice {
+			return nil, nil
+		}
+		devicePaths, err := groupPathsByDevice([]string{paths[0], rootFilesystemPath})
+		if err != nil || len(devicePaths) != 1 {
+			return nil, err
+		}
+		return []string{fmt.Sprintf("Storage path %s is on the same device as the root filesystem.", paths[0])}, nil
+	}
+
+	devicePaths, err := groupPathsByDevice(paths)
+	if err != nil {
+		return nil, err
+	}
+	var warnings []string
+	for _, group := range devicePaths {
+		if len(group) > 1 {
+			warnings = append(warnings, fmt.Sprintf("Storage paths %s are on the same device, redundancy is reduced.",
+				strings.Join(group, ", ")))
+		}
+	}
+	return warnings, nil
+}
+
+// groupPathsByDevice - groups paths by the device they are on, in the
+// order the devices are first seen. Paths which do not exist yet are
+// skipped, nothing is returned on platforms without device numbers.
+func groupPathsByDevice(paths []string) ([][]string, error) {
+	var groups [][]string
+	groupIndex := make(map[uint64]int)
+	for _, path := range paths {
+		dev, err := getDeviceID(path)
+		if err == errDeviceIDUnsupported {
+			return nil, nil
+		}
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		i, ok := groupIndex[dev]
+		if !ok {
+			i = len(groups)
+			groupIndex[dev] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], path)
+	}
+	return groups, nil
+}
@@ -0,0 +1,86 @@
+// +build linux darwin dragonfly freebsd netbsd openbsd
+
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Tests storage paths on the same device are warned about.
+func TestGetSameDeviceWarnings(t *testing.T) {
+	root, err := ioutil.TempDir("", "minio-device-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	disk1 := filepath.Join(root, "disk1")
+	disk2 := filepath.Join(root, "disk2")
+	for _, disk := range []string{disk1, disk2} {
+		if err = os.Mkdir(disk, 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Directories under the same temp root are on the same device.
+	warnings, err := getSameDeviceWarnings([]string{disk1, disk2}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], disk1+", "+disk2) {
+		t.Fatalf("Expected one warning about %s and %s, got %v", disk1, disk2, warnings)
+	}
+
+	// Paths which do not exist yet are skipped.
+	warnings, err = getSameDeviceWarnings([]string{disk1, filepath.Join(root, "disk3")}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("Expected no warnings, got %v", warnings)
+	}
+
+	// A single path is compared against the root filesystem.
+	rootDev, err := getDeviceID(rootFilesystemPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diskDev, err := getDeviceID(disk1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	warnings, err = getSameDeviceWarnings([]string{disk1}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := rootDev == diskDev; expected != (len(warnings) == 1) {
+		t.Fatalf("Expected root filesystem warning %v, got %v", expected, warnings)
+	}
+	warnings, err = getSameDeviceWarnings([]string{disk1}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("Expected no warnings with allowSameDevice, got %v", warnings)
+	}
+}
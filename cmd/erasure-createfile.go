@@ -21,18 +21,18 @@ import (
 	"hash"
 	"io"
 	"sync"
-
-	"github.com/klauspost/reedsolomon"
 )
 
 // erasureCreateFile - writes an entire stream by erasure coding to
 // all the disks, writes also calculate individual block's checksum
-// for future bit-rot protection.
-func erasureCreateFile(disks []StorageAPI, volume, path string, reader io.Reader, blockSize int64, dataBlocks int, parityBlocks int, algo string, writeQuorum int) (bytesWritten int64, checkSums []string, err error) {
+// for future bit-rot protection. Checksums of the whole file and of
+// each erasure block are returned per disk.
+func erasureCreateFile(disks []StorageAPI, volume, path string, reader io.Reader, blockSize int64, dataBlocks int, parityBlocks int, algo string, writeQuorum int) (bytesWritten int64, checkSums []string, blockSums [][]string, err error) {
 	// Allocated blockSized buffer for reading.
 	buf := make([]byte, blockSize)
 
 	hashWriters := newHashWriters(len(disks), algo)
+	blockSums = make([][]string, len(disks))
 
 	// Read until io.EOF, erasure codes data and writes to all disks.
 	for {
@@ -41,7 +41,7 @@ func erasureCreateFile(disks []StorageAPI, volume, path string, reader io.Reader
 		// FIXME: this is a bug in Golang, n == 0 and err ==
 		// io.ErrUnexpectedEOF for io.ReadFull function.
 		if n == 0 && rErr == io.ErrUnexpectedEOF {
-			return 0, nil, traceError(rErr)
+			return 0, nil, nil, traceError(rErr)
 		}
 		if rErr == io.EOF {
 			// We have reached EOF on the first byte read, io.Reader
@@ -49,28 +49,28 @@ func erasureCreateFile(disks []StorageAPI, volume, path string, reader io.Reader
 			// data. Will create a 0byte file instead.
 			if bytesWritten == 0 {
 				blocks = make([][]byte, len(disks))
-				rErr = appendFile(disks, volume, path, blocks, hashWriters, writeQuorum)
+				rErr = appendFile(disks, volume, path, blocks, hashWriters, blockSums, writeQuorum)
 				if rErr != nil {
-					return 0, nil, rErr
+					return 0, nil, nil, rErr
 				}
 			} // else we have reached EOF after few reads, no need to
 			// add an additional 0bytes at the end.
 			break
 		}
 		if rErr != nil && rErr != io.ErrUnexpectedEOF {
-			return 0, nil, traceError(rErr)
+			return 0, nil, nil, traceError(rErr)
 		}
 		if n > 0 {
 			// Returns encoded blocks.
 			var enErr error
 			blocks, enErr = encodeData(buf[0:n], dataBlocks, parityBlocks)
 			if enErr != nil {
-				return 0, nil, enErr
+				return 0, nil, nil, enErr
 			}
 
 			// Write to all disks.
-			if err = appendFile(disks, volume, path, blocks, hashWriters, writeQuorum); err != nil {
-				return 0, nil, err
+			if err = appendFile(disks, volume, path, blocks, hashWriters, blockSums, writeQuorum); err != nil {
+				return 0, nil, nil, err
 			}
 			bytesWritten += int64(n)
 		}
@@ -80,15 +80,15 @@ func erasureCreateFile(disks []StorageAPI, volume, path string, reader io.Reader
 	for i := range checkSums {
 		checkSums[i] = hex.EncodeToString(hashWriters[i].Sum(nil))
 	}
-	return bytesWritten, checkSums, nil
+	return bytesWritten, checkSums, blockSums, nil
 }
 
 // encodeData - encodes incoming data buffer into
 // dataBlocks+parityBlocks returns a 2 dimensional byte array.
 func encodeData(dataBuffer []byte, dataBlocks, parityBlocks int) ([][]byte, error) {
-	rs, err := reedsolomon.New(dataBlocks, parityBlocks)
+	rs, err := newReedSolomon(dataBlocks, parityBlocks)
 	if err != nil {
-		return nil, traceError(err)
+		return nil, err
 	}
 	// Split the input buffer into data and parity blocks.
 	var blocks [][]byte
@@ -107,8 +107,9 @@ func encodeData(dataBuffer []byte, dataBlocks, parityBlocks int) ([][]byte, erro
 	return blocks, nil
 }
 
-// appendFile - append data buffer at path.
-func appendFile(disks []StorageAPI, volume, path string, enBlocks [][]byte, hashWriters []hash.Hash, writeQuorum int) (err error) {
+// appendFile - append data buffer at path, the checksum of each
+// appended block is added to blockSums.
+func appendFile(disks []StorageAPI, volume, path string, enBlocks [][]byte, hashWriters []hash.Hash, blockSums [][]string, writeQuorum int) (err error) {
 	var wg = &sync.WaitGroup{}
 	var wErrs = make([]error, len(disks))
 	// Write encoded data to quorum disks in parallel.
@@ -128,6 +129,7 @@ func appendFile(disks []StorageAPI, volume, path string, enBlocks [][]byte, hash
 
 			// Calculate hash for each blocks.
 			hashWriters[index].Write(enBlocks[index])
+			blockSums[index] = append(blockSums[index], blockSum(enBlocks[index]))
 
 			// Successfully wrote.
 			wErrs[index] = nil
@@ -19,12 +19,13 @@ package cmd
 import "encoding/hex"
 
 // Heals the erasure coded file. reedsolomon.Reconstruct() is used to reconstruct the missing parts.
-func erasureHealFile(latestDisks []StorageAPI, outDatedDisks []StorageAPI, volume, path, healBucket, healPath string, size int64, blockSize int64, dataBlocks int, parityBlocks int, algo string) (checkSums []string, err error) {
+func erasureHealFile(latestDisks []StorageAPI, outDatedDisks []StorageAPI, volume, path, healBucket, healPath string, size int64, blockSize int64, dataBlocks int, parityBlocks int, algo string) (checkSums []string, blockSums [][]string, err error) {
 	var offset int64
 	remainingSize := size
 
 	// Hash for bitrot protection.
 	hashWriters := newHashWriters(len(outDatedDisks), bitRotAlgo)
+	blockSums = make([][]string, len(outDatedDisks))
 
 	for remainingSize > 0 {
 		curBlockSize := blockSize
@@ -45,8 +46,9 @@ func erasureHealFile(latestDisks []StorageAPI, outDatedDisks []StorageAPI, volum
 				continue
 			}
 			enBlocks[index] = make([]byte, curEncBlockSize)
-			_, err := disk.ReadFile(volume, path, offset, enBlocks[index])
-			if err != nil {
+			n, err := disk.ReadFile(volume, path, offset, enBlocks[index])
+			// A short read indicates a truncated shard, treat it as corrupted.
+			if err != nil || n != curEncBlockSize {
 				enBlocks[index] = nil
 			}
 		}
@@ -54,7 +56,7 @@ func erasureHealFile(latestDisks []StorageAPI, outDatedDisks []StorageAPI, volum
 		// Reconstruct missing data.
 		err := decodeData(enBlocks, dataBlocks, parityBlocks)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		// Write to the healPath file.
@@ -62,11 +64,16 @@ func erasureHealFile(latestDisks []StorageAPI, outDatedDisks []StorageAPI, volum
 			if disk == nil {
 				continue
 			}
+			// Reconstructed shard should be of the expected size.
+			if int64(len(enBlocks[index])) != curEncBlockSize {
+				return nil, nil, traceError(errUnexpected)
+			}
 			err := disk.AppendFile(healBucket, healPath, enBlocks[index])
 			if err != nil {
-				return nil, traceError(err)
+				return nil, nil, traceError(err)
 			}
 			hashWriters[index].Write(enBlocks[index])
+			blockSums[index] = append(blockSums[index], blockSum(enBlocks[index]))
 		}
 		remainingSize -= curBlockSize
 		offset += curEncBlockSize
@@ -80,5 +87,5 @@ func erasureHealFile(latestDisks []StorageAPI, outDatedDisks []StorageAPI, volum
 		}
 		checkSums[index] = hex.EncodeToString(hashWriters[index].Sum(nil))
 	}
-	return checkSums, nil
+	return checkSums, blockSums, nil
 }
@@ -22,7 +22,6 @@ import (
 	"io"
 	"sync"
 
-	"github.com/klauspost/reedsolomon"
 	"github.com/minio/minio/pkg/bpool"
 )
 
@@ -111,7 +110,8 @@ func getReadDisks(orderedDisks []StorageAPI, index int, dataBlocks int) (readDis
 }
 
 // parallelRead - reads chunks in parallel from the disks specified in []readDisks.
-func parallelRead(volume, path string, readDisks []StorageAPI, orderedDisks []StorageAPI, enBlocks [][]byte, blockOffset int64, curChunkSize int64, bitRotVerify func(diskIndex int) bool, pool *bpool.BytePool) {
+// A chunk failing blockVerify is corrupt and not used for decoding.
+func parallelRead(volume, path string, readDisks []StorageAPI, orderedDisks []StorageAPI, enBlocks [][]byte, blockOffset int64, curChunkSize int64, bitRotVerify func(diskIndex int) bool, blockVerify func(diskIndex int, buf []byte) bool, pool *bpool.BytePool) {
 	// WaitGroup to synchronise the read go-routines.
 	wg := &sync.WaitGroup{}
 
@@ -140,8 +140,14 @@ func parallelRead(volume, path string, readDisks []StorageAPI, orderedDisks []St
 			}
 			buf = buf[:curChunkSize]
 
-			_, err = readDisks[index].ReadFile(volume, path, blockOffset, buf)
-			if err != nil {
+			n, err := readDisks[index].ReadFile(volume, path, blockOffset, buf)
+			// A short read indicates a truncated shard, treat it as corrupted.
+			if err != nil || n != curChunkSize {
+				orderedDisks[index] = nil
+				return
+			}
+			if !blockVerify(index, buf) {
+				errorIf(errXLBlockCorrupted, "Checksum mismatch at offset %d of %s/%s on disk %s.", blockOffset, volume, path, readDisks[index])
 				orderedDisks[index] = nil
 				return
 			}
@@ -157,8 +163,10 @@ func parallelRead(volume, path string, readDisks []StorageAPI, orderedDisks []St
 // Erasure coded files are read block by block as per given erasureInfo and data chunks
 // are decoded into a data block. Data block is trimmed for given offset and length,
 // then written to given writer. This function also supports bit-rot detection by
-// verifying checksum of individual block's checksum.
-func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path string, offset int64, length int64, totalLength int64, blockSize int64, dataBlocks int, parityBlocks int, checkSums []string, algo string, pool *bpool.BytePool) (int64, error) {
+// verifying checksum of individual block's checksum. Disks with per block
+// checksums in blockSums are verified block by block as they are read,
+// others by the checksum of the whole file before the first read.
+func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path string, offset int64, length int64, totalLength int64, blockSize int64, dataBlocks int, parityBlocks int, checkSums []string, blockSums [][]string, algo string, pool *bpool.BytePool) (int64, error) {
 	// Offset and length cannot be negative.
 	if offset < 0 || length < 0 {
 		return 0, traceError(errUnexpected)
@@ -169,9 +177,19 @@ func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path s
 		return 0, traceError(errUnexpected)
 	}
 
+	// Chunk size is undefined without data blocks.
+	if dataBlocks <= 0 {
+		return 0, traceError(errUnexpected)
+	}
+
 	// chunkSize is the amount of data that needs to be read from each disk at a time.
 	chunkSize := getChunkSize(blockSize, dataBlocks)
 
+	// hasBlockSums - disk has checksums for each block.
+	hasBlockSums := func(diskIndex int) bool {
+		return diskIndex < len(blockSums) && len(blockSums[diskIndex]) > 0
+	}
+
 	// bitRotVerify verifies if the file on a particular disk doesn't have bitrot
 	// by verifying the hash of the contents of the file.
 	bitRotVerify := func() func(diskIndex int) bool {
@@ -184,6 +202,10 @@ func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path s
 				// Already validated.
 				return true
 			}
+			if hasBlockSums(diskIndex) {
+				// Validated block by block instead.
+				return true
+			}
 			// Is this a valid block?
 			isValid := isValidBlock(disks[diskIndex], volume, path, checkSums[diskIndex], algo)
 			verified[diskIndex] = isValid
@@ -231,6 +253,17 @@ func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path s
 		// should happen.
 		nextIndex := 0
 
+		// blockVerify verifies the chunk of the current block read
+		// from a disk against its checksum.
+		blockVerify := func(diskIndex int, buf []byte) bool {
+			if !hasBlockSums(diskIndex) {
+				// Whole file is already validated by bitRotVerify.
+				return true
+			}
+			sums := blockSums[diskIndex]
+			return block < int64(len(sums)) && blockSum(buf) == sums[block]
+		}
+
 		for {
 			// readDisks - disks from which we need to read in parallel.
 			var readDisks []StorageAPI
@@ -241,7 +274,7 @@ func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path s
 				return bytesWritten, err
 			}
 			// Issue a parallel read across the disks specified in readDisks.
-			parallelRead(volume, path, readDisks, disks, enBlocks, blockOffset, curChunkSize, bitRotVerify, pool)
+			parallelRead(volume, path, readDisks, disks, enBlocks, blockOffset, curChunkSize, bitRotVerify, blockVerify, pool)
 			if isSuccessDecodeBlocks(enBlocks, dataBlocks) {
 				// If enough blocks are available to do rs.Reconstruct()
 				break
@@ -323,9 +356,9 @@ func isValidBlock(disk StorageAPI, volume, path, checkSum, checkSumAlgo string)
 // decodeData - decode encoded blocks.
 func decodeData(enBlocks [][]byte, dataBlocks, parityBlocks int) error {
 	// Initialized reedsolomon.
-	rs, err := reedsolomon.New(dataBlocks, parityBlocks)
+	rs, err := newReedSolomon(dataBlocks, parityBlocks)
 	if err != nil {
-		return traceError(err)
+		return err
 	}
 
 	// Reconstruct encoded blocks.
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"io/ioutil"
 	"math/rand"
 	"testing"
 	"time"
@@ -271,7 +272,7 @@ func TestErasureReadFileDiskFail(t *testing.T) {
 	}
 
 	// Create a test file to read from.
-	size, checkSums, err := erasureCreateFile(disks, "testbucket", "testobject", bytes.NewReader(data), blockSize, dataBlocks, parityBlocks, bitRotAlgo, dataBlocks+1)
+	size, checkSums, _, err := erasureCreateFile(disks, "testbucket", "testobject", bytes.NewReader(data), blockSize, dataBlocks, parityBlocks, bitRotAlgo, dataBlocks+1)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -285,7 +286,7 @@ func TestErasureReadFileDiskFail(t *testing.T) {
 	pool := bpool.NewBytePool(chunkSize, len(disks))
 
 	buf := &bytes.Buffer{}
-	_, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool)
+	_, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, nil, bitRotAlgo, pool)
 	if err != nil {
 		t.Error(err)
 	}
@@ -298,7 +299,7 @@ func TestErasureReadFileDiskFail(t *testing.T) {
 	disks[5] = ReadDiskDown{disks[5].(*posix)}
 
 	buf.Reset()
-	_, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool)
+	_, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, nil, bitRotAlgo, pool)
 	if err != nil {
 		t.Error(err)
 	}
@@ -313,7 +314,7 @@ func TestErasureReadFileDiskFail(t *testing.T) {
 	disks[11] = ReadDiskDown{disks[11].(*posix)}
 
 	buf.Reset()
-	_, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool)
+	_, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, nil, bitRotAlgo, pool)
 	if err != nil {
 		t.Error(err)
 	}
@@ -325,12 +326,78 @@ func TestErasureReadFileDiskFail(t *testing.T) {
 	disks[12] = ReadDiskDown{disks[12].(*posix)}
 	disks[13] = ReadDiskDown{disks[13].(*posix)}
 	buf.Reset()
-	_, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool)
+	_, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, nil, bitRotAlgo, pool)
 	if errorCause(err) != errXLReadQuorum {
 		t.Fatal("expected errXLReadQuorum error")
 	}
 }
 
+// Test erasureReadFile() with a corrupted byte in one of the shards.
+func TestErasureReadFileBlockChecksum(t *testing.T) {
+	// Initialize environment needed for the test.
+	dataBlocks := 7
+	parityBlocks := 7
+	blockSize := int64(64 * humanize.KiByte)
+	setup, err := newErasureTestSetup(dataBlocks, parityBlocks, blockSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer setup.Remove()
+
+	disks := setup.disks
+
+	data := make([]byte, 1*humanize.MiByte)
+	length := int64(len(data))
+	if _, err = rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a test file to read from.
+	_, _, blockSums, err := erasureCreateFile(disks, "testbucket", "testobject", bytes.NewReader(data), blockSize, dataBlocks, parityBlocks, bitRotAlgo, dataBlocks+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	totalBlocks := int((length + blockSize - 1) / blockSize)
+	for index := range disks {
+		if len(blockSums[index]) != totalBlocks {
+			t.Fatalf("Disk %d: expected %d block checksums, got %d", index, totalBlocks, len(blockSums[index]))
+		}
+	}
+
+	// Flip a byte inside the 4th block of the first data shard.
+	chunkSize := getChunkSize(blockSize, dataBlocks)
+	corruptBlock := int64(3)
+	shardPath := pathJoin(setup.diskPaths[0], "testbucket", "testobject")
+	shard, err := ioutil.ReadFile(shardPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shard[corruptBlock*chunkSize+10] ^= 0xff
+	if err = ioutil.WriteFile(shardPath, shard, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Checksum of the block catches the corruption.
+	buf := make([]byte, chunkSize)
+	if _, err = disks[0].ReadFile("testbucket", "testobject", corruptBlock*chunkSize, buf); err != nil {
+		t.Fatal(err)
+	}
+	if blockSum(buf) == blockSums[0][corruptBlock] {
+		t.Fatal("Expected the checksum of the corrupted block to differ")
+	}
+
+	// Whole file checksums are not needed when block checksums are available.
+	checkSums := make([]string, len(disks))
+	pool := bpool.NewBytePool(chunkSize, len(disks))
+	output := &bytes.Buffer{}
+	if _, err = erasureReadFile(output, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, blockSums, bitRotAlgo, pool); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(output.Bytes(), data) {
+		t.Fatal("Contents of the reconstructed file differs")
+	}
+}
+
 func TestErasureReadFileOffsetLength(t *testing.T) {
 	// Initialize environment needed for the test.
 	dataBlocks := 7
@@ -354,7 +421,7 @@ func TestErasureReadFileOffsetLength(t *testing.T) {
 	}
 
 	// Create a test file to read from.
-	size, checkSums, err := erasureCreateFile(disks, "testbucket", "testobject", bytes.NewReader(data), blockSize, dataBlocks, parityBlocks, bitRotAlgo, dataBlocks+1)
+	size, checkSums, _, err := erasureCreateFile(disks, "testbucket", "testobject", bytes.NewReader(data), blockSize, dataBlocks, parityBlocks, bitRotAlgo, dataBlocks+1)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -390,7 +457,7 @@ func TestErasureReadFileOffsetLength(t *testing.T) {
 	for i, testCase := range testCases {
 		expected := data[testCase.offset:(testCase.offset + testCase.length)]
 		buf := &bytes.Buffer{}
-		_, err = erasureReadFile(buf, disks, "testbucket", "testobject", testCase.offset, testCase.length, length, blockSize, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool)
+		_, err = erasureReadFile(buf, disks, "testbucket", "testobject", testCase.offset, testCase.length, length, blockSize, dataBlocks, parityBlocks, checkSums, nil, bitRotAlgo, pool)
 		if err != nil {
 			t.Error(err)
 			continue
@@ -400,6 +467,12 @@ func TestErasureReadFileOffsetLength(t *testing.T) {
 			t.Errorf("Test %d : read data is different from what was expected", i+1)
 		}
 	}
+
+	// Zero data blocks must be rejected instead of dividing by zero.
+	_, err = erasureReadFile(&bytes.Buffer{}, disks, "testbucket", "testobject", 0, length, length, blockSize, 0, parityBlocks, checkSums, nil, bitRotAlgo, pool)
+	if errorCause(err) != errUnexpected {
+		t.Errorf("Expected %v for zero data blocks, got %v", errUnexpected, err)
+	}
 }
 
 // Test erasureReadFile with random offset and lengths.
@@ -433,7 +506,7 @@ func TestErasureReadFileRandomOffsetLength(t *testing.T) {
 	iterations := 10000
 
 	// Create a test file to read from.
-	size, checkSums, err := erasureCreateFile(disks, "testbucket", "testobject", bytes.NewReader(data), blockSize, dataBlocks, parityBlocks, bitRotAlgo, dataBlocks+1)
+	size, checkSums, _, err := erasureCreateFile(disks, "testbucket", "testobject", bytes.NewReader(data), blockSize, dataBlocks, parityBlocks, bitRotAlgo, dataBlocks+1)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -458,7 +531,7 @@ func TestErasureReadFileRandomOffsetLength(t *testing.T) {
 
 		expected := data[offset : offset+readLen]
 
-		_, err = erasureReadFile(buf, disks, "testbucket", "testobject", offset, readLen, length, blockSize, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool)
+		_, err = erasureReadFile(buf, disks, "testbucket", "testobject", offset, readLen, length, blockSize, dataBlocks, parityBlocks, checkSums, nil, bitRotAlgo, pool)
 		if err != nil {
 			t.Fatal(err, offset, readLen)
 		}
@@ -469,3 +542,93 @@ func TestErasureReadFileRandomOffsetLength(t *testing.T) {
 		buf.Reset()
 	}
 }
+
+// Simulates a disk returning one byte less than requested without an
+// error for ReadFile(), like a silently truncated shard.
+type ReadDiskShort struct {
+	StorageAPI
+}
+
+func (r ReadDiskShort) ReadFile(volume string, path string, offset int64, buf []byte) (n int64, err error) {
+	n, err = r.StorageAPI.ReadFile(volume, path, offset, buf)
+	if err != nil || n == 0 {
+		return n, err
+	}
+	return n - 1, nil
+}
+
+// Test erasureReadFile() with truncated shards, on in-memory disks.
+func TestErasureReadFileShortRead(t *testing.T) {
+	dataBlocks := 7
+	parityBlocks := 7
+	blockSize := int64(blockSizeV1)
+	memDisks := make([]*memDisk, dataBlocks+parityBlocks)
+	disks := make([]StorageAPI, len(memDisks))
+	for i := range disks {
+		memDisks[i] = newMemDisk()
+		disks[i] = memDisks[i]
+		if err := disks[i].MakeVol("testbucket"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The last block of the file is shorter than the block size.
+	data := make([]byte, 3*blockSizeV1+100)
+	length := int64(len(data))
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a test file to read from.
+	_, checkSums, blockSums, err := erasureCreateFile(disks, "testbucket", "testobject", bytes.NewReader(data), blockSize, dataBlocks, parityBlocks, bitRotAlgo, dataBlocks+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunkSize := getChunkSize(blockSize, dataBlocks)
+	pool := bpool.NewBytePool(chunkSize, len(disks))
+
+	// Truncate the first data shard by 1 byte. Blocks are verified by
+	// their own checksums, the whole file checksum is not checked.
+	shard := memDisks[0].vols["testbucket"].files["testobject"]
+	shard.data = shard.data[:len(shard.data)-1]
+
+	buf := &bytes.Buffer{}
+	if _, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, blockSums, bitRotAlgo, pool); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Error("Contents of the erasure coded file differs")
+	}
+
+	// The second data shard returns short reads without an error.
+	disks[1] = ReadDiskShort{disks[1]}
+	buf.Reset()
+	if _, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, blockSums, bitRotAlgo, pool); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Error("Contents of the erasure coded file differs")
+	}
+
+	// 5 more disks offline, only the data blocks needed are left. The
+	// short tail block is a valid read on the remaining disks.
+	for _, index := range []int{2, 3, 4, 5, 6} {
+		disks[index] = nil
+	}
+	buf.Reset()
+	if _, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, blockSums, bitRotAlgo, pool); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Error("Contents of the erasure coded file differs")
+	}
+
+	// One more short read, the data cannot be reconstructed.
+	disks[7] = ReadDiskShort{disks[7]}
+	buf.Reset()
+	_, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, blockSums, bitRotAlgo, pool)
+	if errorCause(err) != errXLReadQuorum {
+		t.Fatalf("Expected errXLReadQuorum, got %v", err)
+	}
+}
@@ -18,6 +18,8 @@ package cmd
 
 import (
 	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
 	"errors"
 	"hash"
 	"io"
@@ -26,6 +28,17 @@ import (
 	"github.com/minio/blake2b-simd"
 )
 
+// newReedSolomon - initializes the erasure coder for the given data and
+// parity blocks. The coder picks the fastest Galois field multiplication
+// the CPU supports (AVX2, SSSE3 or generic Go) at runtime.
+func newReedSolomon(dataBlocks, parityBlocks int) (reedsolomon.Encoder, error) {
+	rs, err := reedsolomon.New(dataBlocks, parityBlocks)
+	if err != nil {
+		return nil, traceError(err)
+	}
+	return rs, nil
+}
+
 // newHashWriters - inititialize a slice of hashes for the disk count.
 func newHashWriters(diskCount int, algo string) []hash.Hash {
 	hashWriters := make([]hash.Hash, diskCount)
@@ -47,6 +60,12 @@ func newHash(algo string) hash.Hash {
 	}
 }
 
+// blockSum - returns the SHA-512/256 checksum of an erasure block.
+func blockSum(block []byte) string {
+	sum := sha512.Sum512_256(block)
+	return hex.EncodeToString(sum[:])
+}
+
 // hashSum calculates the hash of the entire path and returns.
 func hashSum(disk StorageAPI, volume, path string, writer hash.Hash) ([]byte, error) {
 	// Allocate staging buffer of 128KiB for copyBuffer.
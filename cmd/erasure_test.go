@@ -19,6 +19,8 @@ package cmd
 import (
 	"bytes"
 	"testing"
+
+	humanize "github.com/dustin/go-humanize"
 )
 
 // mustEncodeData - encodes data slice and provides encoded 2 dimensional slice.
@@ -182,3 +184,33 @@ func newErasureTestSetup(dataBlocks int, parityBlocks int, blockSize int64) (*er
 	}
 	return &erasureTestSetup{dataBlocks, parityBlocks, blockSize, diskPaths, disks}, nil
 }
+
+// Benchmarks 8+4 erasure encoding of a 10MiB block.
+func BenchmarkErasureEncode10MiB(b *testing.B) {
+	data := bytes.Repeat([]byte("a"), 10*humanize.MiByte)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := encodeData(data, 8, 4); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Benchmarks 8+4 erasure decoding of a 10MiB block with four blocks missing.
+func BenchmarkErasureDecode10MiB(b *testing.B) {
+	data := bytes.Repeat([]byte("a"), 10*humanize.MiByte)
+	encoded := mustEncodeData(data, 8, 4)
+	enBlocks := make([][]byte, len(encoded))
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(enBlocks, encoded)
+		for _, missing := range []int{0, 3, 8, 11} {
+			enBlocks[missing] = nil
+		}
+		if err := decodeData(enBlocks, 8, 4); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
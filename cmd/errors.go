@@ -55,6 +55,11 @@ func (e Error) Error() string {
 	return e.e.Error()
 }
 
+// Unwrap - returns the cause error, for errors.As and errors.Is.
+func (e Error) Unwrap() error {
+	return e.e
+}
+
 // Trace - returns stack trace.
 func (e Error) Trace() []string {
 	var traceArr []string
@@ -101,12 +106,16 @@ func traceError(e error, errs ...error) error {
 	return err
 }
 
-// Returns the underlying cause error.
+// Returns the underlying cause error, errors traced more than once
+// are unwrapped down to the original typed error.
 func errorCause(err error) error {
-	if e, ok := err.(*Error); ok {
+	for {
+		e, ok := err.(*Error)
+		if !ok {
+			return err
+		}
 		err = e.e
 	}
-	return err
 }
 
 // Returns slice of underlying cause error.
@@ -0,0 +1,48 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "testing"
+
+// Tests the cause of traced errors is the original typed error, even
+// when traced more than once.
+func TestErrorCause(t *testing.T) {
+	bucketErr := BucketNotFound{Bucket: "bucket"}
+	testCases := []struct {
+		err   error
+		cause error
+	}{
+		{nil, nil},
+		{bucketErr, bucketErr},
+		{traceError(bucketErr), bucketErr},
+		{traceError(traceError(bucketErr)), bucketErr},
+		{traceError(traceError(traceError(errDiskNotFound))), errDiskNotFound},
+	}
+	for i, testCase := range testCases {
+		if cause := errorCause(testCase.err); cause != testCase.cause {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.cause, cause)
+		}
+	}
+
+	// Handlers map the cause to the API error.
+	if code := toAPIErrorCode(traceError(traceError(bucketErr))); code != ErrNoSuchBucket {
+		t.Errorf("Expected %v, got %v", ErrNoSuchBucket, code)
+	}
+	if code := toAPIErrorCode(traceError(toObjectErr(traceError(errVolumeNotFound), "bucket"))); code != ErrNoSuchBucket {
+		t.Errorf("Expected %v, got %v", ErrNoSuchBucket, code)
+	}
+}
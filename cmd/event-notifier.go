@@ -127,7 +127,7 @@ func newNotificationEvent(event eventData) NotificationEvent {
 	// For all other events we should set ETag and Size.
 	nEvent.S3.Object = objectMeta{
 		Key:       escapedObj,
-		ETag:      event.ObjInfo.MD5Sum,
+		ETag:      getObjectETag(event.ObjInfo),
 		Size:      event.ObjInfo.Size,
 		Sequencer: sequencer,
 	}
@@ -295,6 +295,11 @@ func eventNotify(event eventData) {
 
 	// Notify internal targets.
 	eventNotifyForBucketListeners(eventType, objectName, event.Bucket, notificationEvent)
+
+	// Replicate to the secondary server.
+	if globalReplicationWorker != nil {
+		globalReplicationWorker.enqueue(event)
+	}
 }
 
 // loads notification config if any for a given bucket, returns
@@ -158,6 +158,74 @@ func initFormatFS(storageDisk StorageAPI) error {
 	return saveFSFormatData(storageDisk, newFSFormatV1())
 }
 
+// Versions of the fs backend format, buckets have their metadata saved
+// since version 2.
+const (
+	fsFormatVersionV1 = "1"
+	fsFormatVersion   = "2"
+)
+
+// checkFormatFS - verifies the version of the fs backend format, older
+// versions are migrated to fsFormatVersion and newer versions written
+// by a later release are rejected.
+func checkFormatFS(storageDisk StorageAPI, format *formatConfigV1) error {
+	if !isFSFormat(format) || format.FS == nil {
+		return errFSDiskFormat
+	}
+	switch format.FS.Version {
+	case fsFormatVersion:
+		return nil
+	case fsFormatVersionV1:
+		return migrateFormatFSV1(storageDisk, format)
+	}
+	return fmt.Errorf("Unsupported fs backend format version %s found, this release supports up to version %s", format.FS.Version, fsFormatVersion)
+}
+
+// migrateFormatFSV1 - saves the metadata of buckets created before fs
+// format version 2, format.json is updated once all of them are saved.
+func migrateFormatFSV1(storageDisk StorageAPI, format *formatConfigV1) error {
+	vols, err := storageDisk.ListVols()
+	if err != nil {
+		return err
+	}
+	for _, vol := range vols {
+		if vol.Name == minioMetaBucket || !IsValidBucketName(vol.Name) {
+			continue
+		}
+		_, err = storageDisk.StatFile(minioMetaBucket, getFSBucketMetaPath(vol.Name))
+		if err == nil {
+			// Already saved by an interrupted migration.
+			continue
+		}
+		if err != errFileNotFound {
+			return err
+		}
+		bucketMeta := newFSBucketMetaV1()
+		bucketMeta.Created = vol.Created
+		if err = writeFSBucketMetadata(storageDisk, vol.Name, bucketMeta); err != nil {
+			return err
+		}
+	}
+
+	// Replace format.json, a crash until then restarts the migration.
+	migratedFormat := *format
+	migratedFormat.FS = &fsFormat{Version: fsFormatVersion}
+	formatBytes, err := json.Marshal(&migratedFormat)
+	if err != nil {
+		return err
+	}
+	tmpPath := mustGetUUID()
+	if err = storageDisk.AppendFile(minioMetaTmpBucket, tmpPath, formatBytes); err != nil {
+		return err
+	}
+	if err = storageDisk.RenameFile(minioMetaTmpBucket, tmpPath, minioMetaBucket, fsFormatJSONFile); err != nil {
+		storageDisk.DeleteFile(minioMetaTmpBucket, tmpPath)
+		return err
+	}
+	*format = migratedFormat
+	return nil
+}
+
 // loads format.json from minioMetaBucket if it exists.
 func loadFormatFS(storageDisk StorageAPI) (format *formatConfigV1, err error) {
 	return loadFormat(storageDisk)
@@ -0,0 +1,59 @@
+// +build linux
+
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"os"
+)
+
+// fsSendFile - writes length bytes at offset of the object straight from
+// its file when writer is an io.ReaderFrom, for a http response this
+// lets the kernel copy the data to the socket with sendfile(2). Returns
+// false if the object cannot be sent this way.
+func fsSendFile(disk StorageAPI, bucket, object string, offset, length int64, writer io.Writer) (bool, error) {
+	rf, ok := writer.(io.ReaderFrom)
+	if !ok || length == 0 {
+		return false, nil
+	}
+	// Only local disks expose the underlying file.
+	file, err := openLocalFile(disk, bucket, object)
+	if err == errNotLocalDisk {
+		return false, nil
+	}
+	if err != nil {
+		return true, traceError(err)
+	}
+	defer file.Close()
+
+	if _, err = file.Seek(offset, os.SEEK_SET); err != nil {
+		return true, traceError(err)
+	}
+
+	// io.LimitedReader over an *os.File is what net.TCPConn
+	// recognizes to use sendfile(2).
+	n, err := rf.ReadFrom(io.LimitReader(file, length))
+	if err != nil {
+		return true, traceError(err)
+	}
+	if n != length {
+		return true, traceError(io.ErrUnexpectedEOF)
+	}
+	return true, nil
+}
@@ -0,0 +1,175 @@
+// +build linux
+
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Tests sending objects through io.ReaderFrom on local disks.
+func TestFSSendFile(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+	fs := obj.(fsObjects)
+
+	bucket := getRandomBucketName()
+	object := "object"
+	data := []byte("hello, world")
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		object         string
+		offset, length int64
+		writer         io.Writer
+		expectedSent   bool
+		expectedErr    error
+		expectedData   []byte
+	}{
+		// Whole object to a writer implementing io.ReaderFrom.
+		{object, 0, int64(len(data)), new(bytes.Buffer), true, nil, data},
+		// Range of the object.
+		{object, 7, 5, new(bytes.Buffer), true, nil, data[7:]},
+		// Empty range is left to the generic code path.
+		{object, 0, 0, new(bytes.Buffer), false, nil, nil},
+		// Writer without io.ReaderFrom.
+		{object, 0, int64(len(data)), funcToWriter(ioutil.Discard.Write), false, nil, nil},
+		// Range past the end of the object.
+		{object, 7, 10, new(bytes.Buffer), true, io.ErrUnexpectedEOF, nil},
+		// Non existent object.
+		{"non-existent-object", 0, 1, new(bytes.Buffer), true, errFileNotFound, nil},
+	}
+	for i, testCase := range testCases {
+		sent, err := fsSendFile(fs.storage, bucket, testCase.object, testCase.offset, testCase.length, testCase.writer)
+		if sent != testCase.expectedSent {
+			t.Fatalf("Test %d: Expected sent to be %v, got %v", i+1, testCase.expectedSent, sent)
+		}
+		if errorCause(err) != testCase.expectedErr {
+			t.Fatalf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if testCase.expectedData == nil {
+			continue
+		}
+		if got := testCase.writer.(*bytes.Buffer).Bytes(); !bytes.Equal(got, testCase.expectedData) {
+			t.Fatalf("Test %d: Expected data %q, got %q", i+1, testCase.expectedData, got)
+		}
+	}
+
+	// Objects of disks not known to be local are left to the generic
+	// code path.
+	sent, err := fsSendFile(&freeSpaceDisk{StorageAPI: fs.storage}, bucket, object, 0, int64(len(data)), new(bytes.Buffer))
+	if sent || err != nil {
+		t.Fatalf("Expected the object of a remote disk not to be sent, got %v, %v", sent, err)
+	}
+}
+
+// Reader which repeats the same byte forever.
+type repeatByteReader byte
+
+func (r repeatByteReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(r)
+	}
+	return len(p), nil
+}
+
+// Benchmarks serving an object over a loopback http connection, writer
+// is wrapped to hide io.ReaderFrom if sendfile should not be used.
+func benchmarkGetObjectSendFile(b *testing.B, objSize int64, sendFile bool) {
+	rootPath, err := newTestConfig("us-east-1")
+	if err != nil {
+		b.Fatalf("Unable to initialize config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	obj, disks, err := prepareBenchmarkBackend(FSTestStr)
+	if err != nil {
+		b.Fatalf("Failed obtaining Temp Backend: <ERROR> %s", err)
+	}
+	defer removeRoots(disks)
+
+	bucket := getRandomBucketName()
+	object := "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		b.Fatal(err)
+	}
+	if _, err = obj.PutObject(bucket, object, objSize, io.LimitReader(repeatByteReader('a'), objSize), nil, ""); err != nil {
+		b.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var writer io.Writer = w
+		if !sendFile {
+			writer = funcToWriter(w.Write)
+		}
+		if gErr := obj.GetObject(bucket, object, 0, objSize, writer); gErr != nil {
+			b.Error(gErr)
+		}
+	}))
+	defer server.Close()
+
+	b.SetBytes(objSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			b.Fatal(err)
+		}
+		n, err := io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			b.Fatal(err)
+		}
+		if n != objSize {
+			b.Fatalf("Expected %d bytes, got %d", objSize, n)
+		}
+	}
+	b.StopTimer()
+}
+
+// BenchmarkGetObject1GbSendFileFS - GetObject of a 1GiB object with sendfile(2).
+func BenchmarkGetObject1GbSendFileFS(b *testing.B) {
+	benchmarkGetObjectSendFile(b, humanize.GiByte, true)
+}
+
+// BenchmarkGetObject1GbNoSendFileFS - GetObject of a 1GiB object through a staging buffer.
+func BenchmarkGetObject1GbNoSendFileFS(b *testing.B) {
+	benchmarkGetObjectSendFile(b, humanize.GiByte, false)
+}
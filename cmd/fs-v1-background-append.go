@@ -35,6 +35,17 @@ var errAppendPartsTimeout = errors.New("appendParts goroutine timeout")
 // Timeout value for the appendParts go-routine.
 var appendPartsTimeout = 24 * 60 * 60 * time.Second // 24 hours.
 
+// Suffix of the append files in the tmp volume. An append file is
+// removed by the appendParts go-routine of its upload when it ends, the
+// purge of stale temporary entries leaves them alone.
+const bgAppendFileSuffix = ".append"
+
+// getAppendFilePath - returns the path of the append file of an upload
+// in the tmp volume.
+func getAppendFilePath(bucket, uploadID string) string {
+	return getTmpObjectPath(bucket, uploadID+bgAppendFileSuffix)
+}
+
 // Holds a map of uploadID->appendParts go-routine
 type backgroundAppend struct {
 	infoMap map[string]bgAppendPartsInfo
@@ -128,12 +139,20 @@ func (b *backgroundAppend) abort(uploadID string) {
 func (b *backgroundAppend) appendParts(disk StorageAPI, bucket, object, uploadID string, info bgAppendPartsInfo) {
 	// Holds the list of parts that is already appended to the "append" file.
 	appendMeta := fsMetaV1{}
+	appendFile := getAppendFilePath(bucket, uploadID)
 	for {
 		select {
 		case input := <-info.inputCh:
 			// We receive on this channel when new part gets uploaded or when complete-multipart sends
 			// a value on this channel to confirm if all the required parts are appended.
 			meta := input.meta
+			// The append file may have been removed behind our back, start
+			// over instead of appending to a new file. Nothing else removes
+			// it while this go-routine runs, the check cannot race.
+			if !isAppendFileIntact(disk, appendFile, appendMeta) {
+				disk.DeleteFile(minioMetaTmpBucket, appendFile)
+				appendMeta.Parts = nil
+			}
 			for {
 				// Append should be done such a way that if part-3 and part-2 is uploaded before part-1, we
 				// wait till part-1 is uploaded after which we append part-2 and part-3 as well in this for-loop.
@@ -151,7 +170,7 @@ func (b *backgroundAppend) appendParts(disk StorageAPI, bucket, object, uploadID
 					break
 				}
 				if err := appendPart(disk, bucket, object, uploadID, part); err != nil {
-					disk.DeleteFile(minioMetaTmpBucket, uploadID)
+					disk.DeleteFile(minioMetaTmpBucket, appendFile)
 					appendMeta.Parts = nil
 					input.errCh <- err
 					break
@@ -160,7 +179,7 @@ func (b *backgroundAppend) appendParts(disk StorageAPI, bucket, object, uploadID
 			}
 		case <-info.abortCh:
 			// abort-multipart-upload closed abortCh to end the appendParts go-routine.
-			disk.DeleteFile(minioMetaTmpBucket, uploadID)
+			disk.DeleteFile(minioMetaTmpBucket, appendFile)
 			return
 		case <-info.completeCh:
 			// complete-multipart-upload closed completeCh to end the appendParts go-routine.
@@ -172,7 +191,7 @@ func (b *backgroundAppend) appendParts(disk StorageAPI, bucket, object, uploadID
 			delete(b.infoMap, uploadID)
 			b.Unlock()
 			// Delete the temporary append file as well.
-			disk.DeleteFile(minioMetaTmpBucket, uploadID)
+			disk.DeleteFile(minioMetaTmpBucket, appendFile)
 
 			close(info.timeoutCh)
 			return
@@ -180,6 +199,19 @@ func (b *backgroundAppend) appendParts(disk StorageAPI, bucket, object, uploadID
 	}
 }
 
+// Returns true if the append file has the size of the parts appended to it.
+func isAppendFileIntact(disk StorageAPI, appendFile string, appendMeta fsMetaV1) bool {
+	if len(appendMeta.Parts) == 0 {
+		return true
+	}
+	var size int64
+	for _, part := range appendMeta.Parts {
+		size += part.Size
+	}
+	fi, err := disk.StatFile(minioMetaTmpBucket, appendFile)
+	return err == nil && fi.Size == size
+}
+
 // Appends the "part" to the append-file inside "tmp/" that finally gets moved to the actual location
 // upon complete-multipart-upload.
 func appendPart(disk StorageAPI, bucket, object, uploadID string, part objectPartInfo) error {
@@ -201,7 +233,7 @@ func appendPart(disk StorageAPI, bucket, object, uploadID string, part objectPar
 			// hence considered as an error condition.
 			return err
 		}
-		if err = disk.AppendFile(minioMetaTmpBucket, uploadID, buf[:n]); err != nil {
+		if err = disk.AppendFile(minioMetaTmpBucket, getAppendFilePath(bucket, uploadID), buf[:n]); err != nil {
 			return err
 		}
 		offset += n
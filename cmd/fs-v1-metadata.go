@@ -18,14 +18,32 @@ package cmd
 
 import (
 	"encoding/json"
+	"path"
 	"sort"
+	"time"
 )
 
 const (
-	fsMetaJSONFile   = "fs.json"
-	fsFormatJSONFile = "format.json"
+	fsMetaJSONFile       = "fs.json"
+	fsFormatJSONFile     = "format.json"
+	fsBucketMetaJSONFile = "metadata.json"
 )
 
+// Version of the bucket metadata, buckets created before it was
+// introduced have no metadata at all.
+const fsBucketMetaVersion = "1"
+
+// A fsBucketMetaV1 represents the metadata saved when a bucket is
+// created, in `.minio.sys/buckets/<bucket>/metadata.json`.
+type fsBucketMetaV1 struct {
+	Version string    `json:"version"`
+	Format  string    `json:"format"`
+	Created time.Time `json:"created"`
+	Region  string    `json:"region,omitempty"`
+	Owner   string    `json:"owner,omitempty"`
+	ACL     string    `json:"acl"`
+}
+
 // A fsMetaV1 represents a metadata header mapping keys to sets of values.
 type fsMetaV1 struct {
 	Version string `json:"version"`
@@ -90,6 +108,15 @@ func readFSMetadata(disk StorageAPI, bucket, filePath string) (fsMeta fsMetaV1,
 	return fsMeta, nil
 }
 
+// read fs.json of an upload from the given disk and return its parts.
+func readFSMetaParts(disk StorageAPI, bucket string, object string) ([]objectPartInfo, error) {
+	fsMeta, err := readFSMetadata(disk, bucket, path.Join(object, fsMetaJSONFile))
+	if err != nil {
+		return nil, err
+	}
+	return fsMeta.Parts, nil
+}
+
 // Write fsMeta to fs.json or fs-append.json.
 func writeFSMetadata(disk StorageAPI, bucket, filePath string, fsMeta fsMetaV1) error {
 	tmpPath := mustGetUUID()
@@ -102,10 +129,10 @@ func writeFSMetadata(disk StorageAPI, bucket, filePath string, fsMeta fsMetaV1)
 	}
 	err = disk.RenameFile(minioMetaTmpBucket, tmpPath, bucket, filePath)
 	if err != nil {
-		err = disk.DeleteFile(minioMetaTmpBucket, tmpPath)
-		if err != nil {
-			return traceError(err)
+		if dErr := disk.DeleteFile(minioMetaTmpBucket, tmpPath); dErr != nil {
+			return traceError(dErr)
 		}
+		return traceError(err)
 	}
 	return nil
 }
@@ -119,13 +146,68 @@ func newFSMetaV1() (fsMeta fsMetaV1) {
 	return fsMeta
 }
 
+// newFSBucketMetaV1 - initializes new fsBucketMetaV1 of a private
+// bucket owned by the server credentials.
+func newFSBucketMetaV1() (bucketMeta fsBucketMetaV1) {
+	bucketMeta = fsBucketMetaV1{
+		Version: fsBucketMetaVersion,
+		Format:  "fs",
+		Created: time.Now().UTC(),
+		ACL:     "private",
+	}
+	if serverConfig != nil {
+		bucketMeta.Region = serverConfig.GetRegion()
+		bucketMeta.Owner = serverConfig.GetCredential().AccessKeyID
+	}
+	return bucketMeta
+}
+
+// getFSBucketMetaPath - returns the path of the metadata of a bucket
+// inside minioMetaBucket.
+func getFSBucketMetaPath(bucket string) string {
+	return path.Join(bucketMetaPrefix, bucket, fsBucketMetaJSONFile)
+}
+
+// readFSBucketMetadata - reads the metadata of a bucket, returns
+// errFileNotFound for legacy buckets created without metadata.
+func readFSBucketMetadata(disk StorageAPI, bucket string) (bucketMeta fsBucketMetaV1, err error) {
+	buf, err := disk.ReadAll(minioMetaBucket, getFSBucketMetaPath(bucket))
+	if err != nil {
+		return fsBucketMetaV1{}, traceError(err)
+	}
+	if err = json.Unmarshal(buf, &bucketMeta); err != nil {
+		return fsBucketMetaV1{}, traceError(err)
+	}
+	return bucketMeta, nil
+}
+
+// writeFSBucketMetadata - writes the metadata of a bucket.
+func writeFSBucketMetadata(disk StorageAPI, bucket string, bucketMeta fsBucketMetaV1) error {
+	tmpPath := mustGetUUID()
+	metadataBytes, err := json.Marshal(bucketMeta)
+	if err != nil {
+		return traceError(err)
+	}
+	if err = disk.AppendFile(minioMetaTmpBucket, tmpPath, metadataBytes); err != nil {
+		return traceError(err)
+	}
+	err = disk.RenameFile(minioMetaTmpBucket, tmpPath, minioMetaBucket, getFSBucketMetaPath(bucket))
+	if err != nil {
+		if dErr := disk.DeleteFile(minioMetaTmpBucket, tmpPath); dErr != nil {
+			return traceError(dErr)
+		}
+		return traceError(err)
+	}
+	return nil
+}
+
 // newFSFormatV1 - initializes new formatConfigV1 with FS format info.
 func newFSFormatV1() (format *formatConfigV1) {
 	return &formatConfigV1{
 		Version: "1",
 		Format:  "fs",
 		FS: &fsFormat{
-			Version: "1",
+			Version: fsFormatVersion,
 		},
 	}
 }
@@ -55,8 +55,9 @@ func (fs fsObjects) isUploadIDExists(bucket, object, uploadID string) bool {
 	return true
 }
 
-// updateUploadJSON - add or remove upload ID info in all `uploads.json`.
-func (fs fsObjects) updateUploadJSON(bucket, object, uploadID string, initiated time.Time, isRemove bool) error {
+// updateUploadJSON - applies the update to `uploads.json`, the file is
+// removed once it has no more upload IDs.
+func (fs fsObjects) updateUploadJSON(bucket, object string, update func(uploadsJSON *uploadsV1)) error {
 	uploadsPath := path.Join(bucket, object, uploadsJSONFile)
 	tmpUploadsPath := mustGetUUID()
 
@@ -71,13 +72,7 @@ func (fs fsObjects) updateUploadJSON(bucket, object, uploadID string, initiated
 	}
 
 	// update the uploadsJSON struct
-	if !isRemove {
-		// Add the uploadID
-		uploadsJSON.AddUploadID(uploadID, initiated)
-	} else {
-		// Remove the upload ID
-		uploadsJSON.RemoveUploadID(uploadID)
-	}
+	update(&uploadsJSON)
 
 	// update the file or delete it?
 	if len(uploadsJSON.Uploads) > 0 {
@@ -91,12 +86,17 @@ func (fs fsObjects) updateUploadJSON(bucket, object, uploadID string, initiated
 	return err
 }
 
-// addUploadID - add upload ID and its initiated time to 'uploads.json'.
-func (fs fsObjects) addUploadID(bucket, object string, uploadID string, initiated time.Time) error {
-	return fs.updateUploadJSON(bucket, object, uploadID, initiated, false)
+// addUploadID - add upload ID, its initiated time and the expected size
+// of the object to 'uploads.json'.
+func (fs fsObjects) addUploadID(bucket, object string, uploadID string, initiated time.Time, size int64) error {
+	return fs.updateUploadJSON(bucket, object, func(uploadsJSON *uploadsV1) {
+		uploadsJSON.AddUploadID(uploadID, initiated, size)
+	})
 }
 
 // removeUploadID - remove upload ID in 'uploads.json'.
 func (fs fsObjects) removeUploadID(bucket, object string, uploadID string) error {
-	return fs.updateUploadJSON(bucket, object, uploadID, time.Time{}, true)
+	return fs.updateUploadJSON(bucket, object, func(uploadsJSON *uploadsV1) {
+		uploadsJSON.RemoveUploadID(uploadID)
+	})
 }
@@ -62,7 +62,7 @@ func (fs fsObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 		keyMarkerLock := nsMutex.NewNSLock(minioMetaMultipartBucket,
 			pathJoin(bucket, keyMarker))
 		keyMarkerLock.RLock()
-		uploads, _, err = listMultipartUploadIDs(bucket, keyMarker, uploadIDMarker, maxUploads, fs.storage)
+		uploads, _, err = listMultipartUploadIDs(bucket, keyMarker, uploadIDMarker, maxUploads, fs.storage, readFSMetaParts)
 		keyMarkerLock.RUnlock()
 		if err != nil {
 			return ListMultipartsInfo{}, err
@@ -117,7 +117,7 @@ func (fs fsObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 			entryLock := nsMutex.NewNSLock(minioMetaMultipartBucket,
 				pathJoin(bucket, entry))
 			entryLock.RLock()
-			tmpUploads, end, err = listMultipartUploadIDs(bucket, entry, uploadIDMarker, maxUploads, fs.storage)
+			tmpUploads, end, err = listMultipartUploadIDs(bucket, entry, uploadIDMarker, maxUploads, fs.storage, readFSMetaParts)
 			entryLock.RUnlock()
 			if err != nil {
 				return ListMultipartsInfo{}, err
@@ -226,6 +226,9 @@ func (fs fsObjects) newMultipartUpload(bucket string, object string, meta map[st
 	// Initialize `fs.json` values.
 	fsMeta := newFSMetaV1()
 
+	// Expected size of the object is only saved in `uploads.json`.
+	size := getUploadSize(meta)
+
 	// Save additional metadata.
 	fsMeta.Meta = meta
 
@@ -239,7 +242,7 @@ func (fs fsObjects) newMultipartUpload(bucket string, object string, meta map[st
 	uploadID = mustGetUUID()
 	initiated := time.Now().UTC()
 	// Add upload ID to uploads.json
-	if err = fs.addUploadID(bucket, object, uploadID, initiated); err != nil {
+	if err = fs.addUploadID(bucket, object, uploadID, initiated, size); err != nil {
 		return "", err
 	}
 	uploadIDPath := path.Join(bucket, object, uploadID)
@@ -265,12 +268,27 @@ func (fs fsObjects) NewMultipartUpload(bucket, object string, meta map[string]st
 		return "", traceError(BucketNotFound{Bucket: bucket})
 	}
 	// Verify if object name is valid.
-	if !IsValidObjectName(object) {
+	if !IsValidObjectName(object) || isReservedObjectName(object) {
 		return "", traceError(ObjectNameInvalid{Bucket: bucket, Object: object})
 	}
+	// Parts are staged in the tmp directory, a disk without free
+	// inodes would only fail the upload of the first part.
+	if err := fs.probeTmpDir(); err != nil {
+		return "", toObjectErr(err, minioMetaTmpBucket)
+	}
 	return fs.newMultipartUpload(bucket, object, meta)
 }
 
+// probeTmpDir - creates and removes a file in the tmp directory, to
+// verify new files can be created there.
+func (fs fsObjects) probeTmpDir() error {
+	probeFile := mustGetUUID()
+	if err := fs.storage.AppendFile(minioMetaTmpBucket, probeFile, nil); err != nil {
+		return traceError(err)
+	}
+	return traceError(fs.storage.DeleteFile(minioMetaTmpBucket, probeFile))
+}
+
 // Returns if a new part can be appended to fsAppendDataFile.
 func partToAppend(fsMeta fsMetaV1, fsAppendMeta fsMetaV1) (part objectPartInfo, appendNeeded bool) {
 	if len(fsMeta.Parts) == 0 {
@@ -429,6 +447,33 @@ func (fs fsObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 	return newMD5Hex, nil
 }
 
+// CopyObjectPart - copies a byte range of an existing object as a part
+// of an ongoing multipart transaction, source data is streamed through
+// GetObject into PutObjectPart.
+//
+// Implements S3 compatible Upload Part Copy API.
+func (fs fsObjects) CopyObjectPart(srcBucket, srcObject, dstBucket, dstObject, uploadID string, partID int, startOffset int64, length int64) (string, error) {
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		if gerr := fs.GetObject(srcBucket, srcObject, startOffset, length, pipeWriter); gerr != nil {
+			errorIf(gerr, "Unable to read %s/%s.", srcBucket, srcObject)
+			pipeWriter.CloseWithError(gerr)
+			return
+		}
+		pipeWriter.Close() // Close writer explicitly signalling we wrote all data.
+	}()
+
+	md5Sum, err := fs.PutObjectPart(dstBucket, dstObject, uploadID, partID, length, pipeReader, "", "")
+	if err != nil {
+		// Unblock the reading go-routine upon error.
+		pipeReader.CloseWithError(err)
+		return "", err
+	}
+	// Explicitly close the reader.
+	pipeReader.Close()
+	return md5Sum, nil
+}
+
 // listObjectParts - wrapper scanning through
 // '.minio.sys/multipart/bucket/object/UPLOADID'. Lists all the parts
 // saved inside '.minio.sys/multipart/bucket/object/UPLOADID'.
@@ -584,15 +629,20 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 			appendFallback = false
 			objectLock.Lock()
 			defer objectLock.Unlock()
-			if err = fs.storage.RenameFile(minioMetaTmpBucket, uploadID, bucket, object); err != nil {
-				return "", toObjectErr(traceError(err), minioMetaTmpBucket, uploadID)
+			// Locked objects cannot be overwritten until their retention expires.
+			if err = fs.checkObjectRetention(bucket, object); err != nil {
+				return "", err
+			}
+			appendFile := getAppendFilePath(bucket, uploadID)
+			if err = fs.storage.RenameFile(minioMetaTmpBucket, appendFile, bucket, object); err != nil {
+				return "", toObjectErr(traceError(err), minioMetaTmpBucket, appendFile)
 			}
 		}
 	}
 
 	if appendFallback {
 		// background append could not do append all the required parts, hence we do it here.
-		tempObj := uploadID + "-" + "part.1"
+		tempObj := getTmpObjectPath(bucket, uploadID+"-"+"part.1")
 
 		// Allocate staging buffer.
 		var buf = make([]byte, readSizeV1)
@@ -660,6 +710,10 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 
 		objectLock.Lock()
 		defer objectLock.Unlock()
+		if err = fs.checkObjectRetention(bucket, object); err != nil {
+			fs.storage.DeleteFile(minioMetaTmpBucket, tempObj)
+			return "", err
+		}
 		// Rename the file back to original location, if not delete the temporary object.
 		err = fs.storage.RenameFile(minioMetaTmpBucket, tempObj, bucket, object)
 		if err != nil {
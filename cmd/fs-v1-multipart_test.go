@@ -22,6 +22,9 @@ import (
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
 )
 
 // TestNewMultipartUploadFaultyDisk - test NewMultipartUpload with faulty disks
@@ -57,6 +60,50 @@ func TestNewMultipartUploadFaultyDisk(t *testing.T) {
 	}
 }
 
+// TestNewMultipartUploadInodesFull - tests that NewMultipartUpload fails
+// if no files can be created in the tmp directory.
+func TestNewMultipartUploadInodesFull(t *testing.T) {
+	disk := filepath.Join(os.TempDir(), "minio-"+nextSuffix())
+	defer removeAll(disk)
+	obj := initFSObjects(disk, t)
+
+	fs := obj.(fsObjects)
+	bucketName := "bucket"
+	if err := obj.MakeBucket(bucketName); err != nil {
+		t.Fatal("Cannot create bucket, err: ", err)
+	}
+
+	// The probe file is created by the second storage call.
+	fsStorage := fs.storage.(*retryStorage)
+	for _, diskErr := range []error{errDiskInodesFull, errDiskFull} {
+		fs.storage = newNaughtyDisk(fsStorage, map[int]error{2: diskErr}, nil)
+		_, err := fs.NewMultipartUpload(bucketName, "object", nil)
+		switch diskErr {
+		case errDiskInodesFull:
+			if !isSameType(errorCause(err), InodeExhausted{}) {
+				t.Fatal("Expected InodeExhausted, got ", err)
+			}
+		case errDiskFull:
+			if !isSameType(errorCause(err), StorageFull{}) {
+				t.Fatal("Expected StorageFull, got ", err)
+			}
+		}
+	}
+	if getAPIError(toAPIErrorCode(InodeExhausted{})).Code != "XMinioStorageFull" {
+		t.Fatal("Expected InodeExhausted to be returned as XMinioStorageFull")
+	}
+
+	// No upload was started.
+	fs.storage = fsStorage
+	result, err := fs.ListMultipartUploads(bucketName, "", "", "", "", 1000)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+	if len(result.Uploads) != 0 {
+		t.Fatalf("Expected no uploads, found %d", len(result.Uploads))
+	}
+}
+
 // TestPutObjectPartFaultyDisk - test PutObjectPart with faulty disks
 func TestPutObjectPartFaultyDisk(t *testing.T) {
 	// Prepare for tests
@@ -210,3 +257,72 @@ func TestListMultipartUploadsFaultyDisk(t *testing.T) {
 		}
 	}
 }
+
+// TestCompleteMultipartUploadPurgedAppendFile - tests the background append
+// starts over when its append file was purged as a stale temporary file.
+func TestCompleteMultipartUploadPurgedAppendFile(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	disk := filepath.Join(os.TempDir(), "minio-"+nextSuffix())
+	defer removeAll(disk)
+	obj := initFSObjects(disk, t)
+	fs := obj.(fsObjects)
+	bucketName := "bucket"
+	objectName := "object"
+
+	if err = obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+	uploadID, err := obj.NewMultipartUpload(bucketName, objectName, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	part1 := bytes.Repeat([]byte("a"), 5*humanize.MiByte)
+	part2 := []byte("b")
+	if _, err = obj.PutObjectPart(bucketName, objectName, uploadID, 1, int64(len(part1)), bytes.NewReader(part1), "", ""); err != nil {
+		t.Fatal(err)
+	}
+	// waitForAppend - waits for the append file to reach size.
+	waitForAppend := func(size int64) {
+		for i := 0; ; i++ {
+			fi, sErr := fs.storage.StatFile(minioMetaTmpBucket, getAppendFilePath(bucketName, uploadID))
+			if sErr == nil && fi.Size == size {
+				return
+			}
+			if i == 100 {
+				t.Fatalf("Parts were not appended in the background, expected %d bytes", size)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+	// Purge the append file once the first part is appended.
+	waitForAppend(int64(len(part1)))
+	if err = fs.storage.DeleteFile(minioMetaTmpBucket, getAppendFilePath(bucketName, uploadID)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = obj.PutObjectPart(bucketName, objectName, uploadID, 2, int64(len(part2)), bytes.NewReader(part2), "", ""); err != nil {
+		t.Fatal(err)
+	}
+	// Both parts are appended again to a new append file.
+	waitForAppend(int64(len(part1) + len(part2)))
+
+	parts := []completePart{{PartNumber: 1, ETag: getMD5Hash(part1)}, {PartNumber: 2, ETag: getMD5Hash(part2)}}
+	if _, err = obj.CompleteMultipartUpload(bucketName, objectName, uploadID, parts); err != nil {
+		t.Fatal(err)
+	}
+
+	var buffer bytes.Buffer
+	expected := append(part1, part2...)
+	if err = obj.GetObject(bucketName, objectName, 0, int64(len(expected)), &buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buffer.Bytes(), expected) {
+		t.Fatalf("Expected %d bytes of the uploaded parts, got %d bytes which differ", len(expected), buffer.Len())
+	}
+}
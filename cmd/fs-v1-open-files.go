@@ -0,0 +1,88 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sync"
+	"time"
+)
+
+// Maximum time DeleteObject waits for the readers of an object to close it.
+const fsOpenFileWaitTimeout = 5 * time.Second
+
+// openFile - readers currently holding a file open.
+type openFile struct {
+	readers int
+	// Closed when the last reader closes the file.
+	doneCh chan struct{}
+}
+
+// openFileTracker - keeps track of the files being read by GetObject,
+// so that they are not removed underneath the readers on platforms
+// which do not allow removing an open file.
+type openFileTracker struct {
+	mu    sync.Mutex
+	files map[string]*openFile
+}
+
+func newOpenFileTracker() *openFileTracker {
+	return &openFileTracker{files: make(map[string]*openFile)}
+}
+
+// open - tracks a new reader of path.
+func (t *openFileTracker) open(path string) {
+	t.mu.Lock()
+	f, ok := t.files[path]
+	if !ok {
+		f = &openFile{doneCh: make(chan struct{})}
+		t.files[path] = f
+	}
+	f.readers++
+	t.mu.Unlock()
+}
+
+// close - stops tracking a reader of path.
+func (t *openFileTracker) close(path string) {
+	t.mu.Lock()
+	if f, ok := t.files[path]; ok {
+		f.readers--
+		if f.readers == 0 {
+			close(f.doneCh)
+			delete(t.files, path)
+		}
+	}
+	t.mu.Unlock()
+}
+
+// wait - waits up to timeout for all the readers of path to close it,
+// returns false if the file is still open.
+func (t *openFileTracker) wait(path string, timeout time.Duration) bool {
+	t.mu.Lock()
+	f, ok := t.files[path]
+	t.mu.Unlock()
+	if !ok {
+		return true
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-f.doneCh:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
@@ -0,0 +1,72 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+// Tests open file tracking of readers.
+func TestOpenFileTracker(t *testing.T) {
+	tracker := newOpenFileTracker()
+
+	// Files which are not open are not waited for.
+	if !tracker.wait("bucket/object", time.Millisecond) {
+		t.Fatal("Expected no wait for a file which is not open")
+	}
+
+	tracker.open("bucket/object")
+	tracker.open("bucket/object")
+	if tracker.wait("bucket/object", 10*time.Millisecond) {
+		t.Fatal("Expected wait to time out while the file is open")
+	}
+
+	// Wait returns once the last reader closes the file.
+	tracker.close("bucket/object")
+	doneCh := make(chan bool, 1)
+	go func() {
+		doneCh <- tracker.wait("bucket/object", time.Minute)
+	}()
+	select {
+	case <-doneCh:
+		t.Fatal("Expected wait to block while a reader remains")
+	case <-time.After(50 * time.Millisecond):
+	}
+	tracker.close("bucket/object")
+	select {
+	case ok := <-doneCh:
+		if !ok {
+			t.Fatal("Expected wait to succeed after the file is closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Wait did not return after the file was closed")
+	}
+	if len(tracker.files) != 0 {
+		t.Fatalf("Expected no tracked files, found %d", len(tracker.files))
+	}
+}
+
+// Benchmarks the tracking overhead added to every GetObject.
+func BenchmarkOpenFileTracker(b *testing.B) {
+	tracker := newOpenFileTracker()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tracker.open("bucket/object")
+		tracker.close("bucket/object")
+	}
+}
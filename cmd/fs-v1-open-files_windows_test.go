@@ -0,0 +1,82 @@
+// +build windows
+
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Tests that DeleteObject waits for an in progress GetObject on windows.
+func TestFSDeleteObjectConcurrentRead(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	disk := filepath.Join(os.TempDir(), "minio-"+nextSuffix())
+	defer removeAll(disk)
+
+	obj := initFSObjects(disk, t)
+	bucketName := "bucket"
+	if err = obj.MakeBucket(bucketName); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	data := []byte("hello, world")
+	if _, err = obj.PutObject(bucketName, "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	writer := blockingWriter{
+		startedCh: make(chan struct{}),
+		unblockCh: make(chan struct{}),
+		once:      &sync.Once{},
+	}
+	getErrCh := make(chan error, 1)
+	go func() {
+		getErrCh <- obj.GetObject(bucketName, "object", 0, int64(len(data)), writer)
+	}()
+	<-writer.startedCh
+	deleteErrCh := make(chan error, 1)
+	go func() {
+		deleteErrCh <- obj.DeleteObject(bucketName, "object")
+	}()
+	select {
+	case err = <-deleteErrCh:
+		t.Fatal("DeleteObject returned while the object was being read: ", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Both complete once the reader is done.
+	close(writer.unblockCh)
+	if err = <-getErrCh; err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if err = <-deleteErrCh; err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if _, err = obj.GetObjectInfo(bucketName, "object"); !isSameType(errorCause(err), ObjectNotFound{}) {
+		t.Fatal("Expected object to be deleted, got ", err)
+	}
+}
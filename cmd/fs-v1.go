@@ -22,11 +22,12 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"hash"
 	"io"
+	"os"
 	"path"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/minio/minio/pkg/mimedb"
 )
@@ -40,6 +41,12 @@ type fsObjects struct {
 
 	// To manage the appendRoutine go0routines
 	bgAppend *backgroundAppend
+
+	// Objects being read by GetObject.
+	openFiles *openFileTracker
+
+	// Bounds the number of concurrent tree walks of ListObjects.
+	listSemaphore listSemaphore
 }
 
 // list of all errors that can be ignored in tree walk operation in FS
@@ -55,7 +62,7 @@ func newFSObjects(storage StorageAPI) (ObjectLayer, error) {
 	}
 
 	// Load format and validate.
-	_, err := loadFormatFS(storage)
+	format, err := loadFormatFS(storage)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to recognize backend format, %s", err)
 	}
@@ -65,6 +72,13 @@ func newFSObjects(storage StorageAPI) (ObjectLayer, error) {
 		return nil, fmt.Errorf("Unable to initialize '.minio.sys' meta volume, %s", err)
 	}
 
+	// Verify the format version, migrating older versions.
+	if !globalSkipFormatCheck {
+		if err = checkFormatFS(storage, format); err != nil {
+			return nil, fmt.Errorf("Unable to verify backend format, %s", err)
+		}
+	}
+
 	// Initialize fs objects.
 	fs := fsObjects{
 		storage:  storage,
@@ -72,6 +86,8 @@ func newFSObjects(storage StorageAPI) (ObjectLayer, error) {
 		bgAppend: &backgroundAppend{
 			infoMap: make(map[string]bgAppendPartsInfo),
 		},
+		openFiles:     newOpenFileTracker(),
+		listSemaphore: newListSemaphore(globalMaxListGoroutines),
 	}
 
 	// Return successfully initialized object layer.
@@ -132,6 +148,9 @@ func (fs fsObjects) StorageInfo() StorageInfo {
 		Free:  info.Free,
 	}
 	storageInfo.Backend.Type = FS
+	if err == nil {
+		storageInfo.Disks = []DiskMetrics{newDiskMetrics(fs.storage.String(), info)}
+	}
 	return storageInfo
 }
 
@@ -140,12 +159,21 @@ func (fs fsObjects) StorageInfo() StorageInfo {
 // MakeBucket - make a bucket.
 func (fs fsObjects) MakeBucket(bucket string) error {
 	// Verify if bucket is valid.
-	if !IsValidBucketName(bucket) {
+	if !isValidNewBucketName(bucket) {
 		return traceError(BucketNameInvalid{Bucket: bucket})
 	}
+	// An existing directory is reported as BucketExists, including
+	// legacy buckets without metadata.
 	if err := fs.storage.MakeVol(bucket); err != nil {
 		return toObjectErr(traceError(err), bucket)
 	}
+	// Save the bucket metadata, the bucket is removed if it cannot be saved.
+	if err := writeFSBucketMetadata(fs.storage, bucket, newFSBucketMetaV1()); err != nil {
+		if dErr := fs.storage.DeleteVol(bucket); dErr != nil {
+			errorIf(dErr, "Unable to delete bucket %s.", bucket)
+		}
+		return toObjectErr(err, bucket)
+	}
 	return nil
 }
 
@@ -165,6 +193,18 @@ func (fs fsObjects) GetBucketInfo(bucket string) (BucketInfo, error) {
 	}, nil
 }
 
+// Directories in the export path which are never listed as buckets.
+var ignoredFSBucketDirs = map[string]bool{
+	"lost+found": true,
+}
+
+// isIgnoredFSBucketDir - returns true for directories in the export
+// path which are not buckets, hidden directories including the volume
+// special bucket and directories created by the filesystem.
+func isIgnoredFSBucketDir(name string) bool {
+	return strings.HasPrefix(name, ".") || ignoredFSBucketDirs[name]
+}
+
 // ListBuckets - list buckets.
 func (fs fsObjects) ListBuckets() ([]BucketInfo, error) {
 	var bucketInfos []BucketInfo
@@ -174,19 +214,32 @@ func (fs fsObjects) ListBuckets() ([]BucketInfo, error) {
 	}
 	var invalidBucketNames []string
 	for _, vol := range vols {
+		// Ignore the volume special bucket, hidden directories and
+		// directories created by the filesystem itself.
+		if isIgnoredFSBucketDir(vol.Name) {
+			continue
+		}
 		// StorageAPI can send volume names which are incompatible
 		// with buckets, handle it and skip them.
 		if !IsValidBucketName(vol.Name) {
 			invalidBucketNames = append(invalidBucketNames, vol.Name)
 			continue
 		}
-		// Ignore the volume special bucket.
-		if vol.Name == minioMetaBucket {
+		// Creation date is saved in the bucket metadata, legacy
+		// buckets without metadata use the modification time of
+		// their directory.
+		created := vol.Created
+		bucketMeta, mErr := readFSBucketMetadata(fs.storage, vol.Name)
+		if mErr == nil {
+			created = bucketMeta.Created
+		} else if errorCause(mErr) != errFileNotFound {
+			return nil, toObjectErr(mErr, vol.Name)
+		} else if globalStrictBucketMetadata {
 			continue
 		}
 		bucketInfos = append(bucketInfos, BucketInfo{
 			Name:    vol.Name,
-			Created: vol.Created,
+			Created: created,
 		})
 	}
 	// Print a user friendly message if we indeed skipped certain directories which are
@@ -194,7 +247,8 @@ func (fs fsObjects) ListBuckets() ([]BucketInfo, error) {
 	if len(invalidBucketNames) > 0 {
 		errorIf(errors.New("One or more invalid bucket names found"), "Skipping %s", invalidBucketNames)
 	}
-	sort.Sort(byBucketName(bucketInfos))
+	// Buckets are listed in the order they were created.
+	sort.Sort(byBucketCreated(bucketInfos))
 	return bucketInfos, nil
 }
 
@@ -204,10 +258,21 @@ func (fs fsObjects) DeleteBucket(bucket string) error {
 	if !IsValidBucketName(bucket) {
 		return traceError(BucketNameInvalid{Bucket: bucket})
 	}
+
+	// Lock the bucket so that no object operation is in progress while it
+	// is deleted, the bucket directory is removed only if it is empty.
+	bucketLock := nsMutex.NewNSLock(bucket, "")
+	bucketLock.Lock()
+	defer bucketLock.Unlock()
+
 	// Attempt to delete regular bucket.
 	if err := fs.storage.DeleteVol(bucket); err != nil {
 		return toObjectErr(traceError(err), bucket)
 	}
+	// Delete the bucket metadata, legacy buckets have none.
+	if err := fs.storage.DeleteFile(minioMetaBucket, getFSBucketMetaPath(bucket)); err != nil && err != errFileNotFound {
+		return toObjectErr(traceError(err), bucket)
+	}
 	// Cleanup all the previously incomplete multiparts.
 	if err := cleanupDir(fs.storage, minioMetaMultipartBucket, bucket); err != nil && errorCause(err) != errVolumeNotFound {
 		return toObjectErr(err, bucket)
@@ -236,6 +301,17 @@ func (fs fsObjects) GetObject(bucket, object string, offset int64, length int64,
 		return toObjectErr(traceError(errUnexpected), bucket, object)
 	}
 
+	// Hold the bucket only until the object is locked, DeleteBucket
+	// waits for it. A locked object cannot be deleted, the bucket is not
+	// empty until the object is read.
+	bucketLock := nsMutex.NewNSLock(bucket, "")
+	bucketLock.RLock()
+	// Lock the object before reading.
+	objectLock := nsMutex.NewNSLock(bucket, object)
+	objectLock.RLock()
+	defer objectLock.RUnlock()
+	bucketLock.RUnlock()
+
 	// Stat the file to get file size.
 	fi, err := fs.storage.StatFile(bucket, object)
 	if err != nil {
@@ -251,10 +327,15 @@ func (fs fsObjects) GetObject(bucket, object string, offset int64, length int64,
 		return traceError(InvalidRange{offset, length, fi.Size})
 	}
 
-	// Lock the object before reading.
-	objectLock := nsMutex.NewNSLock(bucket, object)
-	objectLock.RLock()
-	defer objectLock.RUnlock()
+	// Track the reader until the object is read, DeleteObject may wait for it.
+	objectPath := pathJoin(bucket, object)
+	fs.openFiles.open(objectPath)
+	defer fs.openFiles.close(objectPath)
+
+	// Send the object straight from its file if the writer supports it.
+	if ok, sErr := fsSendFile(fs.storage, bucket, object, offset, length, writer); ok {
+		return toObjectErr(sErr, bucket, object)
+	}
 
 	var totalLeft = length
 	bufSize := int64(readSizeV1)
@@ -350,6 +431,25 @@ func (fs fsObjects) getObjectInfo(bucket, object string) (ObjectInfo, error) {
 	return objInfo, nil
 }
 
+// checkObjectRetention - returns ObjectLocked if the object exists and is
+// retained by its object lock, the object has to be locked by the caller.
+func (fs fsObjects) checkObjectRetention(bucket, object string) error {
+	if bucket == minioMetaBucket {
+		return nil
+	}
+	objInfo, err := fs.getObjectInfo(bucket, object)
+	if err != nil {
+		if isErrObjectNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if isObjectRetained(objInfo.UserDefined) {
+		return traceError(ObjectLocked{Bucket: bucket, Object: object})
+	}
+	return nil
+}
+
 // GetObjectInfo - get object info.
 func (fs fsObjects) GetObjectInfo(bucket, object string) (ObjectInfo, error) {
 	// Verify if bucket is valid.
@@ -360,16 +460,33 @@ func (fs fsObjects) GetObjectInfo(bucket, object string) (ObjectInfo, error) {
 	if !IsValidObjectName(object) {
 		return ObjectInfo{}, traceError(ObjectNameInvalid{Bucket: bucket, Object: object})
 	}
+
+	// Lock the object so that metadata is not read mid-write.
+	objectLock := nsMutex.NewNSLock(bucket, object)
+	objectLock.RLock()
+	defer objectLock.RUnlock()
+
 	return fs.getObjectInfo(bucket, object)
 }
 
+// Separates the destination bucket from the name of a temporary file,
+// bucket names cannot contain it.
+const tmpBucketSeparator = "@"
+
+// getTmpObjectPath - returns the path of a temporary file in the tmp
+// volume which is renamed into bucket when committed, storage serving
+// buckets from many paths creates it on the path of the bucket.
+func getTmpObjectPath(bucket, name string) string {
+	return bucket + tmpBucketSeparator + name
+}
+
 // PutObject - create an object.
 func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (objInfo ObjectInfo, err error) {
 	// Verify if bucket is valid.
 	if !IsValidBucketName(bucket) {
 		return ObjectInfo{}, traceError(BucketNameInvalid{Bucket: bucket})
 	}
-	if !IsValidObjectName(object) {
+	if !IsValidObjectName(object) || isReservedObjectName(object) {
 		return ObjectInfo{}, traceError(ObjectNameInvalid{
 			Bucket: bucket,
 			Object: object,
@@ -380,23 +497,20 @@ func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.
 		metadata = make(map[string]string)
 	}
 
-	uniqueID := mustGetUUID()
-
 	// Uploaded object will first be written to the temporary location which will eventually
 	// be renamed to the actual location. It is first written to the temporary location
 	// so that cleaning it up will be easy if the server goes down.
-	tempObj := uniqueID
+	tempObj := getTmpObjectPath(bucket, mustGetUUID())
 
 	// Initialize md5 writer.
 	md5Writer := md5.New()
 
 	hashWriters := []io.Writer{md5Writer}
 
-	var sha256Writer hash.Hash
-	if sha256sum != "" {
-		sha256Writer = sha256.New()
-		hashWriters = append(hashWriters, sha256Writer)
-	}
+	// SHA-256 of object data is always computed, it is saved as the
+	// object checksum.
+	sha256Writer := sha256.New()
+	hashWriters = append(hashWriters, sha256Writer)
 	multiWriter := io.MultiWriter(hashWriters...)
 
 	// Limit the reader to its provided size if specified.
@@ -422,6 +536,7 @@ func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.
 		if size > 0 {
 			err = fs.storage.PrepareFile(minioMetaTmpBucket, tempObj, size)
 			if err != nil {
+				fs.storage.DeleteFile(minioMetaTmpBucket, tempObj)
 				return ObjectInfo{}, toObjectErr(err, bucket, object)
 			}
 		}
@@ -475,10 +590,36 @@ func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.
 		}
 	}
 
-	// Lock the object before committing the object.
+	// Verify the checksum requested by the client if any and save it.
+	if err = setObjectChecksum(metadata, sha256Writer.Sum(nil)); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	// Hold the bucket while committing the object, the data is read
+	// before so that a concurrent DeleteBucket does not wait for slow
+	// uploads.
+	bucketLock := nsMutex.NewNSLock(bucket, "")
+	bucketLock.RLock()
+	defer bucketLock.RUnlock()
+
+	// The bucket may have been deleted while the object was written,
+	// renaming would create it again.
+	if !fs.isBucketExist(bucket) {
+		return ObjectInfo{}, traceError(BucketNotFound{Bucket: bucket})
+	}
+
+	// Lock the object before committing the object, concurrent uploads
+	// of the same object must rename the data and write `fs.json` as a
+	// unit, else the data of one upload ends up with the metadata of
+	// another.
 	objectLock := nsMutex.NewNSLock(bucket, object)
-	objectLock.RLock()
-	defer objectLock.RUnlock()
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	// Locked objects cannot be overwritten until their retention expires.
+	if err = fs.checkObjectRetention(bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
 
 	// Entire object was written to the temp location, now it's safe to rename it to the actual location.
 	err = fs.storage.RenameFile(minioMetaTmpBucket, tempObj, bucket, object)
@@ -502,6 +643,84 @@ func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.
 	return fs.getObjectInfo(bucket, object)
 }
 
+// SetObjectMetadata - replaces the metadata of an existing object, only
+// `fs.json` is rewritten and the object data is left untouched.
+func (fs fsObjects) SetObjectMetadata(bucket, object string, metadata map[string]string) (ObjectInfo, error) {
+	// Verify if bucket is valid.
+	if !IsValidBucketName(bucket) {
+		return ObjectInfo{}, traceError(BucketNameInvalid{Bucket: bucket})
+	}
+	if !IsValidObjectName(object) {
+		return ObjectInfo{}, traceError(ObjectNameInvalid{Bucket: bucket, Object: object})
+	}
+
+	// Lock the object before updating its metadata.
+	objectLock := nsMutex.NewNSLock(bucket, object)
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	// Validate object exists.
+	if _, err := fs.storage.StatFile(bucket, object); err != nil {
+		return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
+	}
+	// Metadata of locked objects cannot be replaced.
+	if err := fs.checkObjectRetention(bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	fsMetaPath := path.Join(bucketMetaPrefix, bucket, object, fsMetaJSONFile)
+	fsMeta, err := readFSMetadata(fs.storage, minioMetaBucket, fsMetaPath)
+	if err != nil {
+		// Objects without `fs.json` start off with a fresh metadata.
+		if errorCause(err) != errFileNotFound {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+		fsMeta = newFSMetaV1()
+	}
+	fsMeta.Meta = replaceObjectMetadata(fsMeta.Meta, metadata)
+
+	// Guess content-type from the extension if possible.
+	if fsMeta.Meta["content-type"] == "" {
+		if objectExt := path.Ext(object); objectExt != "" {
+			if content, ok := mimedb.DB[strings.ToLower(strings.TrimPrefix(objectExt, "."))]; ok {
+				fsMeta.Meta["content-type"] = content.ContentType
+			}
+		}
+	}
+
+	// Save the new metadata, `fs.json` is replaced atomically.
+	if err = writeFSMetadata(fs.storage, minioMetaBucket, fsMetaPath, fsMeta); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	// Metadata change is reflected as the new modification time.
+	if err = fsTouchObject(fs.storage, bucket, object, time.Now().UTC()); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	return fs.getObjectInfo(bucket, object)
+}
+
+// fsTouchObject - sets the modification time of the object file, which
+// is what GetObjectInfo reports. Only local disks expose the underlying
+// file, objects on remote disks keep their modification time.
+func fsTouchObject(disk StorageAPI, bucket, object string, modTime time.Time) error {
+	file, err := openLocalFile(disk, bucket, object)
+	if err == errNotLocalDisk {
+		return nil
+	}
+	if err != nil {
+		return traceError(err)
+	}
+	filePath := file.Name()
+	file.Close()
+
+	if err = os.Chtimes(filePath, modTime, modTime); err != nil {
+		return traceError(err)
+	}
+	return nil
+}
+
 // DeleteObject - deletes an object from a bucket, this operation is destructive
 // and there are no rollbacks supported.
 func (fs fsObjects) DeleteObject(bucket, object string) error {
@@ -513,12 +732,27 @@ func (fs fsObjects) DeleteObject(bucket, object string) error {
 		return traceError(ObjectNameInvalid{Bucket: bucket, Object: object})
 	}
 
+	// Hold the bucket so that it is not deleted at the same time.
+	bucketLock := nsMutex.NewNSLock(bucket, "")
+	bucketLock.RLock()
+	defer bucketLock.RUnlock()
+
 	// Lock the object before deleting so that an in progress GetObject does not return
 	// corrupt data or there is no race with a PutObject.
 	objectLock := nsMutex.NewNSLock(bucket, object)
 	objectLock.RLock()
 	defer objectLock.RUnlock()
 
+	// Locked objects cannot be deleted until their retention expires.
+	if err := fs.checkObjectRetention(bucket, object); err != nil {
+		return err
+	}
+
+	// Windows cannot remove an open file, wait for the readers to close it.
+	if fsWaitForReadersOnDelete && !fs.openFiles.wait(pathJoin(bucket, object), fsOpenFileWaitTimeout) {
+		return traceError(ObjectInUse{Bucket: bucket, Object: object})
+	}
+
 	if bucket != minioMetaBucket {
 		// We don't store fs.json for minio-S3-layer created files like policy.json,
 		// hence we don't try to delete fs.json for such files.
@@ -533,8 +767,19 @@ func (fs fsObjects) DeleteObject(bucket, object string) error {
 	return nil
 }
 
-// ListObjects - list all objects at prefix upto maxKeys., optionally delimited by '/'. Maintains the list pool
-// state for future re-entrant list requests.
+// acquireTreeWalk - takes a tree walk slot for a new listing. When all
+// slots are taken the oldest walk parked in the list pool is ended first,
+// so that abandoned paginated listings cannot starve new ones.
+func (fs fsObjects) acquireTreeWalk() bool {
+	if fs.listSemaphore.acquire(0) {
+		return true
+	}
+	fs.listPool.EvictOldest()
+	return fs.listSemaphore.acquire(globalListSemaphoreTimeout)
+}
+
+// ListObjects - list all objects at prefix upto maxKeys., optionally delimited by '/' or any other
+// delimiter. Maintains the list pool state for future re-entrant list requests delimited by '/'.
 func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
 	// Convert entry to ObjectInfo
 	entryToObjectInfo := func(entry string) (objInfo ObjectInfo, err error) {
@@ -554,6 +799,12 @@ func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKey
 	if !IsValidBucketName(bucket) {
 		return ListObjectsInfo{}, traceError(BucketNameInvalid{Bucket: bucket})
 	}
+
+	// Hold the bucket while listing, DeleteBucket waits for it.
+	bucketLock := nsMutex.NewNSLock(bucket, "")
+	bucketLock.RLock()
+	defer bucketLock.RUnlock()
+
 	// Verify if bucket exists.
 	if !fs.isBucketExist(bucket) {
 		return ListObjectsInfo{}, traceError(BucketNotFound{Bucket: bucket})
@@ -561,12 +812,6 @@ func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKey
 	if !IsValidObjectPrefix(prefix) {
 		return ListObjectsInfo{}, traceError(ObjectNameInvalid{Bucket: bucket, Object: prefix})
 	}
-	// Verify if delimiter is anything other than '/', which we do not support.
-	if delimiter != "" && delimiter != slashSeparator {
-		return ListObjectsInfo{}, traceError(UnsupportedDelimiter{
-			Delimiter: delimiter,
-		})
-	}
 	// Verify if marker has prefix.
 	if marker != "" {
 		if !strings.HasPrefix(marker, prefix) {
@@ -582,6 +827,15 @@ func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKey
 		return ListObjectsInfo{}, nil
 	}
 
+	// Delimiters other than '/' do not match the directories of the
+	// backend, all the objects under the prefix are walked.
+	if delimiter != "" && delimiter != slashSeparator {
+		if maxKeys < 0 || maxKeys > maxObjectList {
+			maxKeys = maxObjectList
+		}
+		return fs.listObjectsDelimited(bucket, prefix, marker, delimiter, maxKeys)
+	}
+
 	// For delimiter and prefix as '/' we do not list anything at all
 	// since according to s3 spec we stop at the 'delimiter'
 	// along // with the prefix. On a flat namespace with 'prefix'
@@ -613,7 +867,12 @@ func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKey
 			return !strings.HasSuffix(object, slashSeparator)
 		}
 		listDir := listDirFactory(isLeaf, fsTreeWalkIgnoredErrs, fs.storage)
-		walkResultCh = startTreeWalk(bucket, prefix, marker, recursive, listDir, isLeaf, endWalkCh)
+		// The slot is held until the walk ends, including while it
+		// waits in the list pool for the next page.
+		if !fs.acquireTreeWalk() {
+			return ListObjectsInfo{}, traceError(ListingBusy{})
+		}
+		walkResultCh = startTreeWalkRelease(bucket, prefix, marker, recursive, listDir, isLeaf, endWalkCh, fs.listSemaphore.release)
 	}
 	var objInfos []ObjectInfo
 	var eof bool
@@ -662,6 +921,66 @@ func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKey
 	return result, nil
 }
 
+// listObjectsDelimited - lists the objects at prefix, objects with the
+// delimiter after the prefix are grouped in common prefixes ending at
+// the first delimiter. The walk is not kept in the list pool, the next
+// page walks again from the marker.
+func (fs fsObjects) listObjectsDelimited(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+	isLeaf := func(bucket, object string) bool {
+		return !strings.HasSuffix(object, slashSeparator)
+	}
+	listDir := listDirFactory(isLeaf, fsTreeWalkIgnoredErrs, fs.storage)
+	if !fs.acquireTreeWalk() {
+		return ListObjectsInfo{}, traceError(ListingBusy{})
+	}
+	endWalkCh := make(chan struct{})
+	defer close(endWalkCh)
+	walkResultCh := startTreeWalkRelease(bucket, prefix, marker, true, listDir, isLeaf, endWalkCh, fs.listSemaphore.release)
+
+	var result ListObjectsInfo
+	for {
+		walkResult, ok := <-walkResultCh
+		if !ok {
+			break
+		}
+		// For any walk error return right away.
+		if walkResult.err != nil {
+			// File not found is a valid case.
+			if errorCause(walkResult.err) == errFileNotFound {
+				return ListObjectsInfo{}, nil
+			}
+			return ListObjectsInfo{}, toObjectErr(walkResult.err, bucket, prefix)
+		}
+		entry := walkResult.entry
+		if index := strings.Index(entry[len(prefix):], delimiter); index >= 0 {
+			// Keys sharing a prefix are listed one after the other,
+			// the common prefix is only compared with the last one.
+			commonPrefix := entry[:len(prefix)+index+len(delimiter)]
+			if commonPrefix == marker || (len(result.Prefixes) > 0 && result.Prefixes[len(result.Prefixes)-1] == commonPrefix) {
+				continue
+			}
+			if len(result.Objects)+len(result.Prefixes) == maxKeys {
+				result.IsTruncated = true
+				break
+			}
+			result.Prefixes = append(result.Prefixes, commonPrefix)
+			result.NextMarker = commonPrefix
+			continue
+		}
+		if len(result.Objects)+len(result.Prefixes) == maxKeys {
+			result.IsTruncated = true
+			break
+		}
+		objInfo, err := fs.getObjectInfo(bucket, entry)
+		if err != nil {
+			return ListObjectsInfo{}, nil
+		}
+		result.Objects = append(result.Objects, objInfo)
+		result.NextMarker = entry
+	}
+	return result, nil
+}
+
 // HealObject - no-op for fs. Valid only for XL.
 func (fs fsObjects) HealObject(bucket, object string) error {
 	return traceError(NotImplemented{})
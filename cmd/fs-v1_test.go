@@ -18,9 +18,20 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 // TestNewFS - tests initialization of all input disks
@@ -169,6 +180,80 @@ func TestFSLoadFormatFS(t *testing.T) {
 	}
 }
 
+// TestFSFormatMigration - tests newFSObjects migrates fs format version 1
+// and rejects newer versions.
+func TestFSFormatMigration(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	disk := filepath.Join(os.TempDir(), "minio-"+nextSuffix())
+	defer removeAll(disk)
+
+	obj := initFSObjects(disk, t)
+	fs := obj.(fsObjects)
+	if err = obj.MakeBucket("bucket"); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	// Downgrade to a version 1 layout, buckets are only directories.
+	writeFormat := func(version string) {
+		format := newFSFormatV1()
+		format.FS.Version = version
+		formatBytes, wErr := json.Marshal(format)
+		if wErr != nil {
+			t.Fatal(wErr)
+		}
+		if wErr = ioutil.WriteFile(filepath.Join(disk, minioMetaBucket, fsFormatJSONFile), formatBytes, 0644); wErr != nil {
+			t.Fatal(wErr)
+		}
+	}
+	writeFormat(fsFormatVersionV1)
+	if err = os.Mkdir(filepath.Join(disk, "legacy-bucket"), 0755); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	// Version 1 is migrated, existing metadata is kept.
+	metadataBytes, err := ioutil.ReadFile(filepath.Join(disk, minioMetaBucket, bucketMetaPrefix, "bucket", fsBucketMetaJSONFile))
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if _, err = newFSObjects(fs.storage); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	format, err := loadFormatFS(fs.storage)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if format.FS.Version != fsFormatVersion {
+		t.Fatalf("Expected fs format version %s, got %s", fsFormatVersion, format.FS.Version)
+	}
+	if _, err = fs.storage.StatFile(minioMetaBucket, getFSBucketMetaPath("legacy-bucket")); err != nil {
+		t.Fatal("Expected legacy bucket metadata to be saved, got ", err)
+	}
+	migratedBytes, err := ioutil.ReadFile(filepath.Join(disk, minioMetaBucket, bucketMetaPrefix, "bucket", fsBucketMetaJSONFile))
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if !bytes.Equal(metadataBytes, migratedBytes) {
+		t.Fatalf("Expected bucket metadata %s to be kept, got %s", metadataBytes, migratedBytes)
+	}
+
+	// Newer versions are rejected unless the check is skipped.
+	writeFormat("3")
+	_, err = newFSObjects(fs.storage)
+	if err == nil || !strings.Contains(err.Error(), "version 3") || !strings.Contains(err.Error(), "version "+fsFormatVersion) {
+		t.Fatal("Expected an unsupported version error, got ", err)
+	}
+	globalSkipFormatCheck = true
+	defer func() { globalSkipFormatCheck = false }()
+	if _, err = newFSObjects(fs.storage); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+}
+
 // TestFSGetBucketInfo - test GetBucketInfo with healty and faulty disks
 func TestFSGetBucketInfo(t *testing.T) {
 	// Prepare for testing
@@ -206,6 +291,289 @@ func TestFSGetBucketInfo(t *testing.T) {
 
 }
 
+// TestFSGetObjectInfo - tests all metadata fields are populated, with and
+// without the fs.json metadata file.
+func TestFSGetObjectInfo(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	// Prepare for testing
+	disk := filepath.Join(os.TempDir(), "minio-"+nextSuffix())
+	defer removeAll(disk)
+
+	obj := initFSObjects(disk, t)
+	fs := obj.(fsObjects)
+	bucketName := "bucket"
+	objectName := "object.txt"
+	data := []byte("hello, world")
+
+	if err = obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+	metadata := map[string]string{
+		"content-type":     "application/json",
+		"content-encoding": "gzip",
+		"X-Amz-Meta-Key":   "value",
+	}
+	if _, err = obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), metadata, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := fs.GetObjectInfo(bucketName, objectName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Bucket != bucketName || info.Name != objectName {
+		t.Fatalf("wrong object %s/%s", info.Bucket, info.Name)
+	}
+	if info.Size != int64(len(data)) || info.ModTime.IsZero() || info.IsDir {
+		t.Fatalf("wrong stat info %#v", info)
+	}
+	if info.MD5Sum != getMD5Hash(data) {
+		t.Fatalf("wrong md5Sum, expected: %s, found: %s", getMD5Hash(data), info.MD5Sum)
+	}
+	if info.ContentType != "application/json" || info.ContentEncoding != "gzip" {
+		t.Fatalf("wrong content headers %#v", info)
+	}
+	if info.UserDefined["X-Amz-Meta-Key"] != "value" {
+		t.Fatalf("user metadata not returned %#v", info.UserDefined)
+	}
+	if _, ok := info.UserDefined["md5Sum"]; ok {
+		t.Fatal("md5Sum must not be returned as user metadata")
+	}
+
+	// Objects written without metadata fall back to the file stat.
+	if err = fs.storage.DeleteFile(minioMetaBucket, path.Join(bucketMetaPrefix, bucketName, objectName, fsMetaJSONFile)); err != nil {
+		t.Fatal(err)
+	}
+	info, err = fs.GetObjectInfo(bucketName, objectName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size != int64(len(data)) || info.ModTime.IsZero() || info.MD5Sum != "" {
+		t.Fatalf("wrong stat info without metadata %#v", info)
+	}
+	if info.ContentType != "text/plain" {
+		t.Fatalf("wrong guessed content type, expected: text/plain, found: %s", info.ContentType)
+	}
+}
+
+// TestFSConcurrentPutObject - tests concurrent uploads of the same object
+// leave the data and the metadata of the same upload behind.
+func TestFSConcurrentPutObject(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	// Prepare for testing
+	disk := filepath.Join(os.TempDir(), "minio-"+nextSuffix())
+	defer removeAll(disk)
+
+	obj := initFSObjects(disk, t)
+	bucketName := "bucket"
+	objectName := "object"
+	if err = obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			data := []byte(fmt.Sprintf("data-%d", i))
+			metadata := map[string]string{"X-Amz-Meta-Upload": fmt.Sprint(i)}
+			_, errs[i] = obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), metadata, "")
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("Upload %d: %v", i, err)
+		}
+	}
+
+	info, err := obj.GetObjectInfo(bucketName, objectName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buffer bytes.Buffer
+	if err = obj.GetObject(bucketName, objectName, 0, info.Size, &buffer); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "data-" + info.UserDefined["X-Amz-Meta-Upload"]; buffer.String() != expected {
+		t.Fatalf("Expected data %q matching the metadata, got %q", expected, buffer.String())
+	}
+}
+
+// TestFSUnicodeObjectNames - tests object names are stored byte for byte,
+// the NFC and NFD forms of the same name are different objects as they
+// are for S3.
+func TestFSUnicodeObjectNames(t *testing.T) {
+	if runtime.GOOS == "darwin" {
+		t.Skip("HFS+ normalizes file names to NFD")
+	}
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	// Prepare for testing
+	disk := filepath.Join(os.TempDir(), "minio-"+nextSuffix())
+	defer removeAll(disk)
+
+	obj := initFSObjects(disk, t)
+	bucketName := "bucket"
+	if err = obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+
+	// "café" in NFC and NFD form.
+	objectNames := []string{"caf\u00e9", "cafe\u0301"}
+	for _, objectName := range objectNames {
+		data := []byte(objectName)
+		if _, err = obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, objectName := range objectNames {
+		var buffer bytes.Buffer
+		if err = obj.GetObject(bucketName, objectName, 0, int64(len(objectName)), &buffer); err != nil {
+			t.Fatal(err)
+		}
+		if buffer.String() != objectName {
+			t.Fatalf("Object %q: expected data %q, got %q", objectName, objectName, buffer.String())
+		}
+	}
+	result, err := obj.ListObjects(bucketName, "", "", "", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var listed []string
+	for _, object := range result.Objects {
+		listed = append(listed, object.Name)
+	}
+	sort.Strings(listed)
+	sort.Strings(objectNames)
+	if !reflect.DeepEqual(listed, objectNames) {
+		t.Fatalf("Expected objects %q, got %q", objectNames, listed)
+	}
+}
+
+// TestFSPutObjectCleanup - tests the temporary file is removed when an
+// upload fails a checksum or the body is cut short.
+func TestFSPutObjectCleanup(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	// Prepare for testing
+	disk := filepath.Join(os.TempDir(), "minio-"+nextSuffix())
+	defer removeAll(disk)
+
+	obj := initFSObjects(disk, t)
+	fs := obj.(fsObjects)
+	bucketName := "bucket"
+	data := []byte("hello, world")
+
+	if err = obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+
+	// brokenReader - fails after sending part of the data.
+	brokenReader := func() io.Reader {
+		pipeReader, pipeWriter := io.Pipe()
+		go func() {
+			pipeWriter.Write(data[:5])
+			pipeWriter.CloseWithError(errUnexpected)
+		}()
+		return pipeReader
+	}
+
+	testCases := []struct {
+		reader    io.Reader
+		size      int64
+		md5Sum    string
+		sha256sum string
+		expected  error
+	}{
+		// Test case - 1.
+		// Content-MD5 mismatch.
+		{bytes.NewReader(data), int64(len(data)), getMD5Hash([]byte("other")), "", BadDigest{}},
+		// Test case - 2.
+		// x-amz-content-sha256 mismatch.
+		{bytes.NewReader(data), int64(len(data)), "", getSHA256Hash([]byte("other")), SHA256Mismatch{}},
+		// Test case - 3.
+		// Body shorter than its size.
+		{bytes.NewReader(data[:5]), int64(len(data)), "", "", IncompleteBody{}},
+		// Test case - 4.
+		// Body fails mid-stream.
+		{brokenReader(), int64(len(data)), "", "", errUnexpected},
+	}
+	for i, testCase := range testCases {
+		metadata := make(map[string]string)
+		if testCase.md5Sum != "" {
+			metadata["md5Sum"] = testCase.md5Sum
+		}
+		_, err = obj.PutObject(bucketName, "object", testCase.size, testCase.reader, metadata, testCase.sha256sum)
+		if !isSameType(errorCause(err), testCase.expected) {
+			t.Fatalf("Test %d: expected %T, got %v", i+1, testCase.expected, err)
+		}
+		entries, lErr := fs.storage.ListDir(minioMetaTmpBucket, "")
+		if lErr != nil {
+			t.Fatalf("Test %d: %v", i+1, lErr)
+		}
+		if len(entries) != 0 {
+			t.Fatalf("Test %d: temporary files left behind %v", i+1, entries)
+		}
+		if _, err = obj.GetObjectInfo(bucketName, "object"); !isErrObjectNotFound(err) {
+			t.Fatalf("Test %d: expected no object, got %v", i+1, err)
+		}
+	}
+}
+
+// TestFSGetObjectPathTraversal - tests object names cannot escape the bucket.
+func TestFSGetObjectPathTraversal(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	disk := filepath.Join(os.TempDir(), "minio-"+nextSuffix())
+	defer removeAll(disk)
+
+	obj := initFSObjects(disk, t)
+	bucketName := "bucket"
+	if err = obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+
+	// A file next to the bucket, outside of it.
+	if err = ioutil.WriteFile(filepath.Join(disk, "server-main.go"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, objectName := range []string{"../server-main.go", "../../server-main.go", "a/../../server-main.go", "/server-main.go"} {
+		var buffer bytes.Buffer
+		err = obj.GetObject(bucketName, objectName, 0, int64(len("secret")), &buffer)
+		if !isSameType(errorCause(err), ObjectNameInvalid{}) {
+			t.Errorf("Object %s: expected ObjectNameInvalid, got %v", objectName, err)
+		}
+		if buffer.Len() != 0 {
+			t.Errorf("Object %s: expected no data, got %q", objectName, buffer.String())
+		}
+	}
+}
+
 // TestFSDeleteObject - test fs.DeleteObject() with healthy and corrupted disks
 func TestFSDeleteObject(t *testing.T) {
 	// Prepare for tests
@@ -247,6 +615,117 @@ func TestFSDeleteObject(t *testing.T) {
 
 }
 
+// TestFSMakeBucket - tests for fs MakeBucket
+// TestFSListBucketsOrder - tests fs ListBuckets lists buckets in order of creation.
+func TestFSListBucketsOrder(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	disk := filepath.Join(os.TempDir(), "minio-"+nextSuffix())
+	defer removeAll(disk)
+
+	obj := initFSObjects(disk, t)
+	fs := obj.(fsObjects)
+
+	buckets := []string{"bucket-e", "bucket-c", "bucket-a", "bucket-d", "bucket-b"}
+	for _, bucket := range buckets {
+		if err = fs.MakeBucket(bucket); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Legacy buckets without metadata are listed by the time their
+	// directory was modified.
+	if err = fs.storage.MakeVol("bucket-legacy"); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	buckets = append(buckets, "bucket-legacy")
+
+	bucketInfos, err := fs.ListBuckets()
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if len(bucketInfos) != len(buckets) {
+		t.Fatalf("Expected %d buckets, found %d", len(buckets), len(bucketInfos))
+	}
+	for i, bucketInfo := range bucketInfos {
+		if bucketInfo.Name != buckets[i] {
+			t.Fatalf("Expected bucket %d to be %s, found %s", i, buckets[i], bucketInfo.Name)
+		}
+	}
+}
+
+func TestFSMakeBucket(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	disk := filepath.Join(os.TempDir(), "minio-"+nextSuffix())
+	defer removeAll(disk)
+
+	obj := initFSObjects(disk, t)
+	fs := obj.(fsObjects)
+
+	// Invalid bucket names are rejected before touching the disk.
+	for _, bucket := range []string{"ab", "Bucket", "my..bucket", "my--bucket", "my.-bucket", "192.168.1.1"} {
+		if err = fs.MakeBucket(bucket); !isSameType(errorCause(err), BucketNameInvalid{}) {
+			t.Fatalf("%s: unexpected error %v", bucket, err)
+		}
+		if _, err = os.Stat(filepath.Join(disk, bucket)); !os.IsNotExist(err) {
+			t.Fatalf("%s: expected bucket directory to be absent, got %v", bucket, err)
+		}
+	}
+
+	// A new bucket has its metadata saved.
+	if err = fs.MakeBucket("bucket"); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	metadataBytes, err := ioutil.ReadFile(filepath.Join(disk, minioMetaBucket, bucketMetaPrefix, "bucket", fsBucketMetaJSONFile))
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	var bucketMeta fsBucketMetaV1
+	if err = json.Unmarshal(metadataBytes, &bucketMeta); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if bucketMeta.Version != fsBucketMetaVersion || bucketMeta.Format != "fs" || bucketMeta.ACL != "private" {
+		t.Fatalf("Unexpected bucket metadata %#v", bucketMeta)
+	}
+	if bucketMeta.Region != "us-east-1" || bucketMeta.Owner != serverConfig.GetCredential().AccessKeyID {
+		t.Fatalf("Unexpected bucket metadata %#v", bucketMeta)
+	}
+	if bucketMeta.Created.IsZero() {
+		t.Fatal("Expected creation time to be set")
+	}
+	if err = fs.MakeBucket("bucket"); !isSameType(errorCause(err), BucketExists{}) {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	// A legacy bucket is only a directory, it exists anyway.
+	if err = os.Mkdir(filepath.Join(disk, "legacy-bucket"), 0755); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if err = fs.MakeBucket("legacy-bucket"); !isSameType(errorCause(err), BucketExists{}) {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	// Deleting the bucket deletes its metadata.
+	if err = fs.DeleteBucket("bucket"); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if _, err = fs.storage.StatFile(minioMetaBucket, getFSBucketMetaPath("bucket")); err != errFileNotFound {
+		t.Fatal("Expected bucket metadata to be deleted, got ", err)
+	}
+	if err = fs.DeleteBucket("legacy-bucket"); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+}
+
 // TestFSDeleteBucket - tests for fs DeleteBucket
 func TestFSDeleteBucket(t *testing.T) {
 	// Prepare for testing
@@ -288,6 +767,171 @@ func TestFSDeleteBucket(t *testing.T) {
 
 }
 
+// blockingWriter - blocks the first write until unblockCh is closed,
+// startedCh is closed when the first write begins.
+type blockingWriter struct {
+	startedCh chan struct{}
+	unblockCh chan struct{}
+	once      *sync.Once
+}
+
+func (w blockingWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() {
+		close(w.startedCh)
+		<-w.unblockCh
+	})
+	return len(p), nil
+}
+
+// TestFSDeleteBucketConcurrent - tests fs DeleteBucket with concurrent
+// object operations on the same bucket.
+func TestFSDeleteBucketConcurrent(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	disk := filepath.Join(os.TempDir(), "minio-"+nextSuffix())
+	defer removeAll(disk)
+
+	obj := initFSObjects(disk, t)
+	bucketName := "bucket"
+	if err := obj.MakeBucket(bucketName); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	data := []byte("hello, world")
+	if _, err := obj.PutObject(bucketName, "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	// DeleteBucket does not wait for an in progress GetObject, the
+	// bucket is not empty.
+	writer := blockingWriter{
+		startedCh: make(chan struct{}),
+		unblockCh: make(chan struct{}),
+		once:      &sync.Once{},
+	}
+	getErrCh := make(chan error, 1)
+	go func() {
+		getErrCh <- obj.GetObject(bucketName, "object", 0, int64(len(data)), writer)
+	}()
+	<-writer.startedCh
+	deleteErrCh := make(chan error, 1)
+	go func() {
+		deleteErrCh <- obj.DeleteBucket(bucketName)
+	}()
+	select {
+	case err := <-deleteErrCh:
+		if !isSameType(errorCause(err), BucketNotEmpty{}) {
+			t.Fatal("Unexpected error: ", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("DeleteBucket waited for GetObject")
+	}
+	close(writer.unblockCh)
+	if err := <-getErrCh; err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	// DeleteBucket of a non empty bucket racing with PutObject fails
+	// and leaves all the objects in place.
+	var wg sync.WaitGroup
+	putErrs := make([]error, 10)
+	for i := range putErrs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			objectName := fmt.Sprintf("dir/object-%d", i)
+			_, putErrs[i] = obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), nil, "")
+		}(i)
+	}
+	if err := obj.DeleteBucket(bucketName); !isSameType(errorCause(err), BucketNotEmpty{}) {
+		t.Fatal("Unexpected error: ", err)
+	}
+	wg.Wait()
+	for i, err := range putErrs {
+		if err != nil {
+			t.Fatalf("PutObject %d: unexpected error %v", i, err)
+		}
+	}
+	result, err := obj.ListObjects(bucketName, "", "", "", 1000)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if len(result.Objects) != len(putErrs)+1 {
+		t.Fatalf("Expected %d objects, got %d", len(putErrs)+1, len(result.Objects))
+	}
+}
+
+// TestFSDeleteEmptyBucketRace - tests that deleting an empty bucket
+// racing with PutObject either fails with BucketNotEmpty or deletes the
+// bucket entirely, the bucket emptiness is never checked separately.
+func TestFSDeleteEmptyBucketRace(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	disk := filepath.Join(os.TempDir(), "minio-"+nextSuffix())
+	defer removeAll(disk)
+
+	obj := initFSObjects(disk, t)
+	fs := obj.(fsObjects)
+	data := []byte("hello, world")
+
+	// PutObject completing just before DeleteBucket.
+	if err = obj.MakeBucket("bucket"); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if _, err = obj.PutObject("bucket", "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if err = obj.DeleteBucket("bucket"); !isSameType(errorCause(err), BucketNotEmpty{}) {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if _, err = obj.GetObjectInfo("bucket", "object"); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		bucketName := fmt.Sprintf("race-bucket-%d", i)
+		if err = obj.MakeBucket(bucketName); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		putErrCh := make(chan error, 1)
+		go func() {
+			_, pErr := obj.PutObject(bucketName, "object", int64(len(data)), bytes.NewReader(data), nil, "")
+			putErrCh <- pErr
+		}()
+		deleteErr := obj.DeleteBucket(bucketName)
+		putErr := <-putErrCh
+
+		switch {
+		case deleteErr == nil:
+			// The bucket was deleted first, the object must not have
+			// created the bucket again.
+			if !isErrBucketNotFound(putErr) {
+				t.Fatalf("Test %d: expected BucketNotFound after DeleteBucket, got %v", i, putErr)
+			}
+			if _, err = fs.storage.StatVol(bucketName); err != errVolumeNotFound {
+				t.Fatalf("Test %d: expected the bucket to be deleted, got %v", i, err)
+			}
+		case isSameType(errorCause(deleteErr), BucketNotEmpty{}):
+			// The object was written first and is left untouched.
+			if putErr != nil {
+				t.Fatalf("Test %d: unexpected error %v", i, putErr)
+			}
+			if _, err = obj.GetObjectInfo(bucketName, "object"); err != nil {
+				t.Fatalf("Test %d: unexpected error %v", i, err)
+			}
+		default:
+			t.Fatalf("Test %d: unexpected error %v", i, deleteErr)
+		}
+	}
+}
+
 // TestFSListBuckets - tests for fs ListBuckets
 func TestFSListBuckets(t *testing.T) {
 	// Prepare for tests
@@ -316,6 +960,34 @@ func TestFSListBuckets(t *testing.T) {
 		t.Fatal("ListBuckets not working properly")
 	}
 
+	// Directories which are not buckets and a legacy bucket without
+	// bucket metadata.
+	for _, dir := range []string{"lost+found", ".hidden", "legacy"} {
+		if err = os.Mkdir(filepath.Join(disk, dir), 0777); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, strict := range []bool{false, true} {
+		globalStrictBucketMetadata = strict
+		buckets, err = fs.ListBuckets()
+		globalStrictBucketMetadata = false
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		var names []string
+		for _, bucket := range buckets {
+			names = append(names, bucket.Name)
+		}
+		sort.Strings(names)
+		expected := []string{"bucket", "legacy"}
+		if strict {
+			expected = []string{"bucket"}
+		}
+		if !reflect.DeepEqual(names, expected) {
+			t.Fatalf("Strict %t: expected buckets %v, got %v", strict, expected, names)
+		}
+	}
+
 	// Test ListBuckets with faulty disks
 	fsStorage := fs.storage.(*retryStorage)
 	for i := 1; i <= 2; i++ {
@@ -327,6 +999,120 @@ func TestFSListBuckets(t *testing.T) {
 
 }
 
+// TestFSListObjectsDelimiter - tests listing objects with a delimiter
+// other than '/'.
+func TestFSListObjectsDelimiter(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	disk := filepath.Join(os.TempDir(), "minio-"+nextSuffix())
+	defer removeAll(disk)
+
+	obj := initFSObjects(disk, t)
+	bucketName := "bucket"
+	if err = obj.MakeBucket(bucketName); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	for _, objectName := range []string{"com.example.a", "com.example.b", "com.other", "org.x/y", "plain"} {
+		if _, err = obj.PutObject(bucketName, objectName, int64(len("data")), bytes.NewReader([]byte("data")), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := []struct {
+		prefix      string
+		marker      string
+		maxKeys     int
+		objects     []string
+		prefixes    []string
+		isTruncated bool
+		nextMarker  string
+	}{
+		{"", "", 1000, []string{"plain"}, []string{"com.", "org."}, false, "plain"},
+		{"com.", "", 1000, []string{"com.other"}, []string{"com.example."}, false, "com.other"},
+		{"com.example.", "", 1000, []string{"com.example.a", "com.example.b"}, nil, false, "com.example.b"},
+		// Paginated listing.
+		{"", "", 1, nil, []string{"com."}, true, "com."},
+		{"", "com.", 1, nil, []string{"org."}, true, "org."},
+		{"", "org.", 1, []string{"plain"}, nil, false, "plain"},
+	}
+	for i, testCase := range testCases {
+		result, err := obj.ListObjects(bucketName, testCase.prefix, testCase.marker, ".", testCase.maxKeys)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		var objects []string
+		for _, objInfo := range result.Objects {
+			objects = append(objects, objInfo.Name)
+		}
+		if !reflect.DeepEqual(objects, testCase.objects) || !reflect.DeepEqual(result.Prefixes, testCase.prefixes) {
+			t.Errorf("Test %d: Expected objects %v and prefixes %v, got %v and %v", i+1, testCase.objects, testCase.prefixes, objects, result.Prefixes)
+		}
+		if result.IsTruncated != testCase.isTruncated || result.NextMarker != testCase.nextMarker {
+			t.Errorf("Test %d: Expected truncated %t at %q, got %t at %q", i+1, testCase.isTruncated, testCase.nextMarker, result.IsTruncated, result.NextMarker)
+		}
+	}
+}
+
+// TestFSListObjectsReservedEntries - tests entries in the reserved "$"
+// namespace are not listed as objects.
+func TestFSListObjectsReservedEntries(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	disk := filepath.Join(os.TempDir(), "minio-"+nextSuffix())
+	defer removeAll(disk)
+
+	obj := initFSObjects(disk, t)
+	bucketName := "bucket"
+	if err := obj.MakeBucket(bucketName); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	data := []byte("hello")
+	for i := 0; i < 5; i++ {
+		if _, err := obj.PutObject(bucketName, fmt.Sprintf("object-%d", i), int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		if err := os.MkdirAll(filepath.Join(disk, bucketName, fmt.Sprintf("$multiparts-%d", i), "part"), 0777); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(disk, bucketName, "$multiparts"), 0777); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(disk, bucketName, "$tmpobject"), data, 0666); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	result, err := obj.ListObjects(bucketName, "", "", "", 1000)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if len(result.Objects) != 5 || len(result.Prefixes) != 0 {
+		t.Fatalf("Expected 5 objects, got %d objects and %d prefixes", len(result.Objects), len(result.Prefixes))
+	}
+	for i, objInfo := range result.Objects {
+		if objInfo.Name != fmt.Sprintf("object-%d", i) {
+			t.Errorf("Expected object-%d, got %s", i, objInfo.Name)
+		}
+	}
+	result, err = obj.ListObjects(bucketName, "", "", slashSeparator, 1000)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if len(result.Objects) != 5 || len(result.Prefixes) != 0 {
+		t.Fatalf("Expected 5 objects, got %d objects and %d prefixes", len(result.Objects), len(result.Prefixes))
+	}
+}
+
 // TestFSHealObject - tests for fs HealObject
 func TestFSHealObject(t *testing.T) {
 	disk := filepath.Join(os.TempDir(), "minio-"+nextSuffix())
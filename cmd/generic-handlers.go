@@ -17,6 +17,10 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"path"
 	"regexp"
@@ -50,18 +54,72 @@ const requestFormDataSize = 64 * humanize.MiByte
 // where, 5GiB is the maximum allowed object size for object upload.
 const requestMaxBodySize = 5*humanize.GiByte + requestFormDataSize
 
+// For bucket management requests i.e bucket creation, policy, lifecycle
+// configuration, request body should be not more than 1MiB.
+const requestMaxBucketBodySize = 1 * humanize.MiByte
+
 type requestSizeLimitHandler struct {
-	handler     http.Handler
-	maxBodySize int64
+	handler           http.Handler
+	maxBodySize       int64
+	maxBucketBodySize int64
 }
 
 func setRequestSizeLimitHandler(h http.Handler) http.Handler {
-	return requestSizeLimitHandler{handler: h, maxBodySize: requestMaxBodySize}
+	return requestSizeLimitHandler{
+		handler:           h,
+		maxBodySize:       requestMaxBodySize,
+		maxBucketBodySize: requestMaxBucketBodySize,
+	}
+}
+
+// errRequestBodyTooLarge - request body crossed the limit set by
+// requestSizeLimitHandler.
+var errRequestBodyTooLarge = errors.New("request body too large")
+
+// requestBodyLimitReader - reads at most n bytes of the request body,
+// reading past them fails with errRequestBodyTooLarge.
+type requestBodyLimitReader struct {
+	io.ReadCloser
+	n   int64
+	err error
+}
+
+func (l *requestBodyLimitReader) Read(p []byte) (n int, err error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	// Read one byte more than allowed to find out if the body is
+	// larger than the limit.
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err = l.ReadCloser.Read(p)
+	if int64(n) <= l.n {
+		l.n -= int64(n)
+		l.err = err
+		return n, err
+	}
+	n = int(l.n)
+	l.n = 0
+	l.err = errRequestBodyTooLarge
+	return n, l.err
 }
 
 func (h requestSizeLimitHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	maxBodySize := h.maxBodySize
+	// All bucket level PUT requests carry only small XML or JSON
+	// documents, restrict them further so that they are never read
+	// fully into memory.
+	if r.Method == "PUT" && !strings.HasPrefix(r.URL.Path, reservedBucket+"/") {
+		if _, object := getRequestBucketObject(r); object == "" {
+			maxBodySize = h.maxBucketBodySize
+		}
+	}
 	// Restricting read data to a given maximum length
-	r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+	r.Body = &requestBodyLimitReader{ReadCloser: r.Body, n: maxBodySize}
 	h.handler.ServeHTTP(w, r)
 }
 
@@ -82,8 +140,8 @@ func setBrowserRedirectHandler(h http.Handler) http.Handler {
 
 func (h redirectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	aType := getRequestAuthType(r)
-	// Re-direct only for JWT and anonymous requests coming from web-browser.
-	if aType == authTypeJWT || aType == authTypeAnonymous {
+	// Re-direct only for JWT, anonymous and client certificate requests coming from web-browser.
+	if aType == authTypeJWT || aType == authTypeAnonymous || aType == authTypeClientCert {
 		// Re-direction handled specifically for browsers.
 		if strings.Contains(r.Header.Get("User-Agent"), "Mozilla") {
 			switch r.URL.Path {
@@ -135,6 +193,28 @@ func (h cacheControlHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.handler.ServeHTTP(w, r)
 }
 
+// Adds security headers to all responses over TLS, browsers are told
+// to use HTTPS for the next globalHSTSMaxAge seconds.
+type hstsHandler struct {
+	handler http.Handler
+	maxAge  int
+}
+
+func setHSTSHandler(h http.Handler) http.Handler {
+	return hstsHandler{handler: h, maxAge: globalHSTSMaxAge}
+}
+
+func (h hstsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.TLS != nil {
+		if h.maxAge > 0 {
+			w.Header().Set("Strict-Transport-Security", fmt.Sprintf("max-age=%d; includeSubDomains", h.maxAge))
+		}
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("X-Frame-Options", "DENY")
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
 // Adds verification for incoming paths.
 type minioPrivateBucketHandler struct {
 	handler        http.Handler
@@ -147,13 +227,117 @@ func setPrivateBucketHandler(h http.Handler) http.Handler {
 
 func (h minioPrivateBucketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// For all non browser requests, reject access to 'reservedBucket'.
-	if !strings.Contains(r.Header.Get("User-Agent"), "Mozilla") && path.Clean(r.URL.Path) == reservedBucket {
+	bucket, object := getRequestBucketObject(r)
+	isReservedBucket := path.Clean(r.URL.Path) == reservedBucket || ("/"+bucket == reservedBucket && object == "")
+	if !strings.Contains(r.Header.Get("User-Agent"), "Mozilla") && isReservedBucket {
 		writeErrorResponse(w, r, ErrAllAccessDisabled, r.URL.Path)
 		return
 	}
 	h.handler.ServeHTTP(w, r)
 }
 
+// Adds verification of Content-Type for incoming object uploads.
+type contentTypeHandler struct {
+	handler http.Handler
+}
+
+func setValidContentTypeHandler(h http.Handler) http.Handler {
+	return contentTypeHandler{h}
+}
+
+// getMediaType - returns the lower cased media type of a Content-Type
+// header value without its parameters, i.e "text/plain; charset=utf-8"
+// returns "text/plain".
+func getMediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		// Ignore malformed parameters, only the media type matters.
+		mediaType = strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+	}
+	return mediaType
+}
+
+func (h contentTypeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Object uploads with form url encoded Content-Type are almost always
+	// client bugs, i.e data uploaded with curl without setting a Content-Type,
+	// reject them instead of saving the object with a wrong Content-Type.
+	// Browser form uploads are POST requests and are not affected.
+	if r.Method == "PUT" && !strings.HasPrefix(r.URL.Path, reservedBucket+"/") {
+		bucket, object := getRequestBucketObject(r)
+		isObjectPath := bucket != "" && object != ""
+		if isObjectPath && getMediaType(r.Header.Get("Content-Type")) == "application/x-www-form-urlencoded" {
+			writeErrorResponse(w, r, ErrUnsupportedContentType, r.URL.Path)
+			return
+		}
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
+// Maximum size of the user metadata of an object, the sum of the
+// lengths of all x-amz-meta-* keys, without the prefix, and values.
+const maxUserMetadataSize = 2 * humanize.KiByte
+
+// Adds verification of the user metadata of incoming object uploads.
+type metadataSizeHandler struct {
+	handler http.Handler
+}
+
+func setMetadataSizeHandler(h http.Handler) http.Handler {
+	return metadataSizeHandler{h}
+}
+
+// isPrintableASCII - returns true if s only contains printable ASCII
+// characters, space excluded.
+func isPrintableASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] <= ' ' || s[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// checkUserMetadata - validates the x-amz-meta-* headers of a request.
+func checkUserMetadata(header http.Header) APIErrorCode {
+	size := 0
+	for key, values := range header {
+		cKey := http.CanonicalHeaderKey(key)
+		if !strings.HasPrefix(cKey, "X-Amz-Meta-") {
+			continue
+		}
+		if !isPrintableASCII(key) {
+			return ErrInvalidMetadataKey
+		}
+		size += len(cKey) - len("X-Amz-Meta-")
+		for _, value := range values {
+			size += len(value)
+		}
+	}
+	if size > maxUserMetadataSize {
+		return ErrMetadataTooLarge
+	}
+	return ErrNone
+}
+
+func (h metadataSizeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Object uploads, copies and new multipart uploads carry the user
+	// metadata of the object, reject them before the body is read.
+	isMetadataRequest := r.Method == "PUT"
+	if r.Method == "POST" {
+		_, isMetadataRequest = r.URL.Query()["uploads"]
+	}
+	if isMetadataRequest && !strings.HasPrefix(r.URL.Path, reservedBucket+"/") {
+		bucket, object := getRequestBucketObject(r)
+		if bucket != "" && object != "" {
+			if apiErr := checkUserMetadata(r.Header); apiErr != ErrNone {
+				writeErrorResponse(w, r, apiErr, r.URL.Path)
+				return
+			}
+		}
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
 // Supported Amz date formats.
 var amzDateFormats = []string{
 	time.RFC1123,
@@ -228,13 +412,28 @@ type resourceHandler struct {
 	handler http.Handler
 }
 
-// setCorsHandler handler for CORS (Cross Origin Resource Sharing)
+// Duration in seconds browsers may cache the result of a CORS preflight.
+const corsMaxAge = 3600
+
+// setCorsHandler handler for CORS (Cross Origin Resource Sharing),
+// preflight requests are answered with the requested method and headers
+// when allowed. Responses carry the origin of the request instead of
+// "*", credentials are not allowed.
 func setCorsHandler(h http.Handler) http.Handler {
 	c := cors.New(cors.Options{
 		AllowedOrigins: []string{"*"},
-		AllowedMethods: []string{"GET", "HEAD", "POST", "PUT"},
+		AllowedMethods: []string{"GET", "HEAD", "POST", "PUT", "DELETE"},
 		AllowedHeaders: []string{"*"},
-		ExposedHeaders: []string{"ETag"},
+		ExposedHeaders: []string{
+			"ETag",
+			"Content-Range",
+			"Accept-Ranges",
+			"X-Amz-Request-Id",
+			objectChecksumMetaKey,
+			sseAlgorithmMetaKey,
+			"x-amz-expiration",
+		},
+		MaxAge: corsMaxAge,
 	})
 	return c.Handler(h)
 }
@@ -249,18 +448,8 @@ func setIgnoreResourcesHandler(h http.Handler) http.Handler {
 
 // Resource handler ServeHTTP() wrapper
 func (h resourceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// Skip the first element which is usually '/' and split the rest.
-	splits := strings.SplitN(r.URL.Path[1:], "/", 2)
-
-	// Save bucketName and objectName extracted from url Path.
-	var bucketName, objectName string
-	if len(splits) == 1 {
-		bucketName = splits[0]
-	}
-	if len(splits) == 2 {
-		bucketName = splits[0]
-		objectName = splits[1]
-	}
+	// Save bucketName and objectName extracted from the request.
+	bucketName, objectName := getRequestBucketObject(r)
 
 	// If bucketName is present and not objectName check for bucket level resource queries.
 	if bucketName != "" && objectName == "" {
@@ -277,7 +466,7 @@ func (h resourceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	// A put method on path "/" doesn't make sense, ignore it.
-	if r.Method == "PUT" && r.URL.Path == "/" {
+	if r.Method == "PUT" && bucketName == "" {
 		writeErrorResponse(w, r, ErrNotImplemented, r.URL.Path)
 		return
 	}
@@ -312,7 +501,6 @@ func ignoreNotImplementedObjectResources(req *http.Request) bool {
 var notimplementedBucketResourceNames = map[string]bool{
 	"acl":            true,
 	"cors":           true,
-	"lifecycle":      true,
 	"logging":        true,
 	"replication":    true,
 	"tagging":        true,
@@ -325,6 +513,5 @@ var notimplementedBucketResourceNames = map[string]bool{
 // List of not implemented object queries
 var notimplementedObjectResourceNames = map[string]bool{
 	"torrent": true,
-	"acl":     true,
 	"policy":  true,
 }
@@ -0,0 +1,353 @@
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Tests getMediaType strips Content-Type parameters.
+func TestGetMediaType(t *testing.T) {
+	testCases := []struct {
+		contentType string
+		mediaType   string
+	}{
+		{"", ""},
+		{"application/octet-stream", "application/octet-stream"},
+		{"text/plain; charset=utf-8", "text/plain"},
+		{"Application/X-WWW-Form-Urlencoded;charset=UTF-8", "application/x-www-form-urlencoded"},
+		// Malformed parameters.
+		{"application/x-www-form-urlencoded; charset", "application/x-www-form-urlencoded"},
+	}
+	for i, testCase := range testCases {
+		if mediaType := getMediaType(testCase.contentType); mediaType != testCase.mediaType {
+			t.Errorf("Test %d: Expected %q, got %q", i+1, testCase.mediaType, mediaType)
+		}
+	}
+}
+
+// Tests object uploads with form url encoded Content-Type are rejected.
+func TestValidContentTypeHandler(t *testing.T) {
+	var contentType string
+	handler := setValidContentTypeHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+	}))
+
+	testCases := []struct {
+		method      string
+		path        string
+		contentType string
+		statusCode  int
+	}{
+		// Object uploads with form url encoded Content-Type.
+		{"PUT", "/bucket/object", "application/x-www-form-urlencoded", http.StatusBadRequest},
+		{"PUT", "/bucket/dir/object", "application/x-www-form-urlencoded; charset=utf-8", http.StatusBadRequest},
+		// Other object uploads.
+		{"PUT", "/bucket/object", "", http.StatusOK},
+		{"PUT", "/bucket/object", "text/plain; charset=utf-8", http.StatusOK},
+		{"PUT", "/bucket/object", "multipart/form-data; boundary=foo", http.StatusOK},
+		// Browser form uploads.
+		{"POST", "/bucket", "multipart/form-data; boundary=foo", http.StatusOK},
+		{"POST", "/bucket/object", "application/x-www-form-urlencoded", http.StatusOK},
+		// Not an object upload.
+		{"PUT", "/bucket", "application/x-www-form-urlencoded", http.StatusOK},
+		{"PUT", "/bucket/", "application/x-www-form-urlencoded", http.StatusOK},
+		{"PUT", reservedBucket + "/upload/bucket/object", "application/x-www-form-urlencoded", http.StatusOK},
+	}
+	for i, testCase := range testCases {
+		contentType = ""
+		req, err := http.NewRequest(testCase.method, "http://localhost:9000"+testCase.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if testCase.contentType != "" {
+			req.Header.Set("Content-Type", testCase.contentType)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != testCase.statusCode {
+			t.Errorf("Test %d: Expected status %d, got %d", i+1, testCase.statusCode, rec.Code)
+		}
+		// Content-Type is passed on unmodified.
+		if rec.Code == http.StatusOK && contentType != testCase.contentType {
+			t.Errorf("Test %d: Expected Content-Type %q, got %q", i+1, testCase.contentType, contentType)
+		}
+	}
+}
+
+// Tests object uploads with too large user metadata are rejected before
+// the object is written.
+func TestMetadataSizeHandler(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+
+	bucket := getRandomBucketName()
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	handler := setMetadataSizeHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		object := strings.TrimPrefix(r.URL.Path, "/"+bucket+"/")
+		if _, err := obj.PutObject(bucket, object, -1, r.Body, extractMetadataFromHeader(r.Header), ""); err != nil {
+			t.Fatal(err)
+		}
+	}))
+
+	testCases := []struct {
+		method     string
+		query      string
+		object     string
+		metaSize   int
+		statusCode int
+	}{
+		// Metadata of exactly the maximum size.
+		{"PUT", "", "object1", maxUserMetadataSize, http.StatusOK},
+		// One byte more than the maximum size.
+		{"PUT", "", "object2", maxUserMetadataSize + 1, http.StatusBadRequest},
+		// New multipart uploads.
+		{"POST", "?uploads", "object3", maxUserMetadataSize + 1, http.StatusBadRequest},
+	}
+	for i, testCase := range testCases {
+		req, err := http.NewRequest(testCase.method, "http://localhost:9000/"+bucket+"/"+testCase.object+testCase.query, bytes.NewReader([]byte("hello")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Key "a" and "b" without the prefix are one byte each.
+		req.Header.Set("X-Amz-Meta-A", strings.Repeat("a", testCase.metaSize/2-1))
+		req.Header.Set("X-Amz-Meta-B", strings.Repeat("b", testCase.metaSize-testCase.metaSize/2-1))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != testCase.statusCode {
+			t.Errorf("Test %d: Expected status %d, got %d", i+1, testCase.statusCode, rec.Code)
+		}
+		if rec.Code == http.StatusBadRequest && !strings.Contains(rec.Body.String(), "<Code>MetadataTooLarge</Code>") {
+			t.Errorf("Test %d: Expected MetadataTooLarge, got %s", i+1, rec.Body.String())
+		}
+		_, err = obj.GetObjectInfo(bucket, testCase.object)
+		if testCase.statusCode == http.StatusOK && err != nil {
+			t.Errorf("Test %d: Expected object to be written, got %v", i+1, err)
+		}
+		if testCase.statusCode != http.StatusOK && err == nil {
+			t.Errorf("Test %d: Expected object not to be written", i+1)
+		}
+	}
+
+	// Metadata keys with non printable characters.
+	if apiErr := checkUserMetadata(http.Header{"X-Amz-Meta-\x01": []string{"v"}}); apiErr != ErrInvalidMetadataKey {
+		t.Errorf("Expected ErrInvalidMetadataKey, got %d", apiErr)
+	}
+	if apiErr := checkUserMetadata(http.Header{"X-Amz-Meta-Key": []string{"v"}, "Content-Type": []string{"text/plain"}}); apiErr != ErrNone {
+		t.Errorf("Expected ErrNone, got %d", apiErr)
+	}
+}
+
+// Tests CORS preflight requests are answered with the requested method
+// and headers, and actual requests with the origin of the request.
+func TestCorsHandler(t *testing.T) {
+	var served bool
+	handler := setCorsHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = true
+		w.Header().Set("ETag", "\"etag\"")
+	}))
+	origin := "https://example.com"
+
+	testCases := []struct {
+		method       string
+		headers      string
+		allowed      bool
+		allowHeaders string
+	}{
+		{"PUT", "content-type, x-amz-date, authorization, x-amz-content-sha256", true, "Content-Type, X-Amz-Date, Authorization, X-Amz-Content-Sha256"},
+		{"GET", "range, x-amz-date", true, "Range, X-Amz-Date"},
+		{"DELETE", "", true, ""},
+		{"HEAD", "", true, ""},
+		// Not supported by the S3 API.
+		{"PATCH", "", false, ""},
+	}
+	for i, testCase := range testCases {
+		served = false
+		req := httptest.NewRequest("OPTIONS", "/bucket/object", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", testCase.method)
+		if testCase.headers != "" {
+			req.Header.Set("Access-Control-Request-Headers", testCase.headers)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != http.StatusOK || w.Body.Len() != 0 || served {
+			t.Fatalf("Test %d: Expected an empty 200 preflight response, got %d %q", i+1, w.Code, w.Body.String())
+		}
+		if !testCase.allowed {
+			if v := w.Header().Get("Access-Control-Allow-Origin"); v != "" {
+				t.Errorf("Test %d: Expected no Access-Control-Allow-Origin, got %q", i+1, v)
+			}
+			continue
+		}
+		if v := w.Header().Get("Access-Control-Allow-Origin"); v != origin {
+			t.Errorf("Test %d: Expected Access-Control-Allow-Origin %q, got %q", i+1, origin, v)
+		}
+		if v := w.Header().Get("Access-Control-Allow-Methods"); v != testCase.method {
+			t.Errorf("Test %d: Expected Access-Control-Allow-Methods %q, got %q", i+1, testCase.method, v)
+		}
+		if v := w.Header().Get("Access-Control-Allow-Headers"); v != testCase.allowHeaders {
+			t.Errorf("Test %d: Expected Access-Control-Allow-Headers %q, got %q", i+1, testCase.allowHeaders, v)
+		}
+		if v := w.Header().Get("Access-Control-Max-Age"); v != "3600" {
+			t.Errorf("Test %d: Expected Access-Control-Max-Age 3600, got %q", i+1, v)
+		}
+		if v := w.Header().Get("Access-Control-Allow-Credentials"); v != "" {
+			t.Errorf("Test %d: Expected no Access-Control-Allow-Credentials, got %q", i+1, v)
+		}
+	}
+
+	// Actual requests.
+	req := httptest.NewRequest("GET", "/bucket/object", nil)
+	req.Header.Set("Origin", origin)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if !served {
+		t.Fatal("Expected the request to be served")
+	}
+	if v := w.Header().Get("Access-Control-Allow-Origin"); v != origin {
+		t.Errorf("Expected Access-Control-Allow-Origin %q, got %q", origin, v)
+	}
+	if v := w.Header().Get("Access-Control-Expose-Headers"); !strings.Contains(v, "Etag") || !strings.Contains(v, "X-Amz-Request-Id") {
+		t.Errorf("Expected ETag and X-Amz-Request-Id to be exposed, got %q", v)
+	}
+}
+
+// Tests security headers are set on all responses over TLS, including
+// error responses, and are not set over plain HTTP.
+func TestHSTSHandler(t *testing.T) {
+	defer func() { globalHSTSMaxAge = globalMinioDefaultHSTSMaxAge }()
+
+	testCases := []struct {
+		tls    bool
+		maxAge int
+		hsts   string
+	}{
+		{true, globalMinioDefaultHSTSMaxAge, "max-age=31536000; includeSubDomains"},
+		{true, 600, "max-age=600; includeSubDomains"},
+		// HSTS is disabled.
+		{true, 0, ""},
+		// No security headers over plain HTTP.
+		{false, globalMinioDefaultHSTSMaxAge, ""},
+	}
+	for i, testCase := range testCases {
+		globalHSTSMaxAge = testCase.maxAge
+		testServer := UnstartedTestServer(t, "FS")
+		if testCase.tls {
+			testServer.Server.StartTLS()
+		} else {
+			testServer.Server.Start()
+		}
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}}
+
+		signedReq, err := newTestSignedRequestV4("GET", testServer.Server.URL+"/", 0, nil, testServer.AccessKey, testServer.SecretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Anonymous request for a bucket which doesn't exist.
+		anonReq, err := newTestRequest("GET", testServer.Server.URL+"/non-existent-bucket", 0, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Not implemented resource, rejected by a generic handler.
+		notImplementedReq, err := newTestRequest("GET", testServer.Server.URL+"/bucket?website", 0, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for j, req := range []*http.Request{signedReq, anonReq, notImplementedReq} {
+			res, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("Test %d.%d: %v", i+1, j+1, err)
+			}
+			res.Body.Close()
+			if j == 0 && res.StatusCode != http.StatusOK {
+				t.Fatalf("Test %d.%d: Expected %d, got %d", i+1, j+1, http.StatusOK, res.StatusCode)
+			}
+			if j > 0 && res.StatusCode < http.StatusBadRequest {
+				t.Fatalf("Test %d.%d: Expected an error response, got %d", i+1, j+1, res.StatusCode)
+			}
+			if hsts := res.Header.Get("Strict-Transport-Security"); hsts != testCase.hsts {
+				t.Errorf("Test %d.%d: Expected Strict-Transport-Security %q, got %q", i+1, j+1, testCase.hsts, hsts)
+			}
+			nosniff, frameOptions := "", ""
+			if testCase.tls {
+				nosniff, frameOptions = "nosniff", "DENY"
+			}
+			if v := res.Header.Get("X-Content-Type-Options"); v != nosniff {
+				t.Errorf("Test %d.%d: Expected X-Content-Type-Options %q, got %q", i+1, j+1, nosniff, v)
+			}
+			if v := res.Header.Get("X-Frame-Options"); v != frameOptions {
+				t.Errorf("Test %d.%d: Expected X-Frame-Options %q, got %q", i+1, j+1, frameOptions, v)
+			}
+		}
+		testServer.Stop()
+	}
+}
+
+// Tests request bodies are read up to the limit, and fail with
+// errRequestBodyTooLarge past it.
+func TestRequestBodyLimitReader(t *testing.T) {
+	testCases := []struct {
+		body        string
+		limit       int64
+		expected    string
+		expectedErr error
+	}{
+		{"", 4, "", nil},
+		{"abc", 4, "abc", nil},
+		{"abcd", 4, "abcd", nil},
+		{"abcde", 4, "abcd", errRequestBodyTooLarge},
+		{"a", 0, "", errRequestBodyTooLarge},
+	}
+	for i, testCase := range testCases {
+		body := &requestBodyLimitReader{ReadCloser: ioutil.NopCloser(strings.NewReader(testCase.body)), n: testCase.limit}
+		data, err := ioutil.ReadAll(body)
+		if err != testCase.expectedErr {
+			t.Fatalf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if string(data) != testCase.expected {
+			t.Fatalf("Test %d: Expected %q, got %q", i+1, testCase.expected, data)
+		}
+		// The error is sticky.
+		if _, err = body.Read(make([]byte, 1)); testCase.expectedErr != nil && err != testCase.expectedErr {
+			t.Fatalf("Test %d: Expected error %v on the next read, got %v", i+1, testCase.expectedErr, err)
+		}
+	}
+	if toAPIErrorCode(traceError(errRequestBodyTooLarge)) != ErrEntityTooLarge {
+		t.Fatal("Expected errRequestBodyTooLarge to map to ErrEntityTooLarge")
+	}
+}
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"crypto/x509"
+	"io"
 	"time"
 
 	humanize "github.com/dustin/go-humanize"
@@ -42,6 +43,8 @@ const (
 	globalMinioKeyFile            = "private.key"
 	globalMinioConfigFile         = "config.json"
 	globalMinioCertExpireWarnDays = time.Hour * 24 * 30 // 30 days.
+	globalMinioDefaultPort        = "9000"
+	globalMinioDefaultHSTSMaxAge  = 31536000 // 1 year in seconds.
 	// Add new global values here.
 )
 
@@ -56,6 +59,7 @@ const (
 
 var (
 	globalQuiet     = false               // quiet flag set via command line.
+	globalJSONLog   = false               // json log format set via command line.
 	globalConfigDir = mustGetConfigPath() // config-dir flag set via command line
 	// Add new global flags here.
 
@@ -68,15 +72,69 @@ var (
 	// Minio local server address (in `host:port` format)
 	globalMinioAddr = ""
 	// Minio default port, can be changed through command line.
-	globalMinioPort = "9000"
+	globalMinioPort = globalMinioDefaultPort
+	// Erasure block size for new objects, can be changed through command line.
+	globalErasureBlockSize = int64(blockSizeV1)
+	// Timeouts of S3 API requests, can be changed through command line.
+	globalAPITimeouts = defaultAPITimeouts
+	// Minimum transfer rate of object data in bytes/second, disabled when zero.
+	globalMinTransferRate = int64(0)
+	// Master key sealing the keys of encrypted objects, set using
+	// MINIO_SSE_MASTER_KEY or --sse-master-key-file, server side
+	// encryption is disabled when nil.
+	globalSSEMasterKey []byte
+	// Max age in seconds of the Strict-Transport-Security header of
+	// responses over TLS, set using --hsts-max-age, disabled when zero.
+	globalHSTSMaxAge = globalMinioDefaultHSTSMaxAge
+	// Compresses objects of compressible content types at rest, set using
+	// --enable-compression.
+	globalCompression = false
+	// Maximum number of in-progress multipart uploads of a bucket, set
+	// using --max-multipart-uploads-per-bucket, disabled when zero.
+	globalMaxMultipartUploadsPerBucket = globalDefaultMaxMultipartUploadsPerBucket
+	// Skips verifying and migrating the backend format version, set
+	// using --skip-format-check for recovery.
+	globalSkipFormatCheck = false
+	// Serves the Go profiling handlers, set using --enable-pprof.
+	globalPprofEnabled = false
+	// Maximum number of concurrent tree walks of ListObjects, set using
+	// --max-list-goroutines, disabled when zero.
+	globalMaxListGoroutines = globalDefaultMaxListGoroutines
+	// Time ListObjects waits for a tree walk slot, set using
+	// --list-semaphore-timeout.
+	globalListSemaphoreTimeout = globalDefaultListSemaphoreTimeout
+	// Temporary entries older than this are purged while the server runs,
+	// set using --tmp-cleanup-age.
+	globalTmpCleanupAge = globalDefaultTmpCleanupAge
+	// Interval between purges of temporary entries, set using
+	// --tmp-cleanup-interval.
+	globalTmpCleanupInterval = globalDefaultTmpCleanupInterval
+	// FS buckets without bucket metadata are not listed, set using
+	// --strict-bucket-metadata.
+	globalStrictBucketMetadata = false
+	// Access log of served requests set using --access-log, disabled when nil.
+	globalAccessLog io.Writer
+	// Format of the access log entries, set using --access-log-format.
+	globalAccessLogFormat = accessLogFormatJSON
 	// Holds the host that was passed using --address
 	globalMinioHost = ""
+	// TLS certificate and key, set using --cert-file and --key-file
+	globalCertFile = ""
+	globalKeyFile  = ""
+	// Wildcard certificate and key, set using --wildcard-cert and --wildcard-key
+	globalWildcardCertFile = ""
+	globalWildcardKeyFile  = ""
+	// Domain for virtual host style requests, set using --domain
+	globalMinioDomain = ""
 	// Peer communication struct
 	globalS3Peers = s3Peers{}
 
 	// CA root certificates, a nil value means system certs pool will be used
 	globalRootCAs *x509.CertPool
 
+	// Time the server was started at, reset when serverMain starts.
+	globalServerStartTime = time.Now().UTC()
+
 	// Add new variable global values here.
 )
 
@@ -108,4 +166,13 @@ func setGlobalsFromContext(c *cli.Context) {
 	}
 	// Set global quiet flag.
 	globalQuiet = c.Bool("quiet") || c.GlobalBool("quiet")
+	// Set global log format.
+	switch logFormat := c.String("log-format"); logFormat {
+	case "", "text":
+		globalJSONLog = false
+	case "json":
+		globalJSONLog = true
+	default:
+		console.Fatalf("Unknown log format %s, supported formats are text and json.", logFormat)
+	}
 }
@@ -17,9 +17,12 @@
 package cmd
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
 	"io"
 	"io/ioutil"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"strings"
 )
@@ -43,19 +46,104 @@ func isValidLocationConstraint(r *http.Request) (s3Error APIErrorCode) {
 			// in accordance with protocol.
 			incomingRegion = "us-east-1"
 		}
-		// Return errInvalidRegion if location constraint does not match
-		// with configured region.
+		// Return ErrInvalidLocationConstraint if location constraint
+		// does not match with configured region.
 		s3Error = ErrNone
 		if serverRegion != incomingRegion {
-			s3Error = ErrInvalidRegion
+			s3Error = ErrInvalidLocationConstraint
 		}
 		return s3Error
 	}
+	if err == errRequestBodyTooLarge {
+		return ErrEntityTooLarge
+	}
 	errorIf(err, "Unable to xml decode location constraint")
 	// Treat all other failures as XML parsing errors.
 	return ErrMalformedXML
 }
 
+// Supported canned ACLs.
+const (
+	cannedACLPrivate           = "private"
+	cannedACLPublicRead        = "public-read"
+	cannedACLPublicReadWrite   = "public-read-write"
+	cannedACLAuthenticatedRead = "authenticated-read"
+)
+
+// Metadata key under which per-object canned ACL is saved.
+const objectACLMetaKey = "x-amz-acl"
+
+// getACLType - returns the canned ACL requested through the "x-amz-acl"
+// header, an empty string is returned if no ACL was requested.
+func getACLType(r *http.Request) (acl string, s3Error APIErrorCode) {
+	acl = r.Header.Get("X-Amz-Acl")
+	switch acl {
+	case "", cannedACLPrivate, cannedACLPublicRead, cannedACLPublicReadWrite, cannedACLAuthenticatedRead:
+		return acl, ErrNone
+	}
+	return "", ErrInvalidCannedACL
+}
+
+// getChecksumSHA256 - returns the checksum of object data requested
+// through the "x-amz-checksum-sha256" header, an empty string is returned
+// if no checksum was requested.
+func getChecksumSHA256(r *http.Request) (checksum string, s3Error APIErrorCode) {
+	checksum = r.Header.Get("X-Amz-Checksum-Sha256")
+	if checksum == "" {
+		return "", ErrNone
+	}
+	sum, err := base64.StdEncoding.DecodeString(checksum)
+	if err != nil || len(sum) != sha256.Size {
+		return "", ErrInvalidDigest
+	}
+	return checksum, ErrNone
+}
+
+// Supported storage classes, objects are stored the same way whatever
+// their storage class.
+const (
+	storageClassStandard          = "STANDARD"
+	storageClassReducedRedundancy = "REDUCED_REDUNDANCY"
+)
+
+// Metadata key under which the storage class of an object is saved.
+const storageClassMetaKey = "X-Amz-Storage-Class"
+
+// getStorageClass - returns the storage class requested through the
+// "x-amz-storage-class" header, an empty string is returned if no
+// storage class was requested.
+func getStorageClass(r *http.Request) (storageClass string, s3Error APIErrorCode) {
+	storageClass = r.Header.Get(storageClassMetaKey)
+	switch storageClass {
+	case "", storageClassStandard, storageClassReducedRedundancy:
+		return storageClass, ErrNone
+	}
+	return "", ErrInvalidStorageClass
+}
+
+// getObjectStorageClass - returns the storage class of an object,
+// objects saved without one are of the STANDARD storage class.
+func getObjectStorageClass(objInfo ObjectInfo) string {
+	if storageClass := objInfo.UserDefined[storageClassMetaKey]; storageClass != "" {
+		return storageClass
+	}
+	return storageClassStandard
+}
+
+// checkObjectACL - validates anonymous read access against the canned ACL
+// saved with the object. Objects without an ACL are governed only by
+// the bucket policy, which has already been verified by the caller.
+func checkObjectACL(r *http.Request, objInfo ObjectInfo) (s3Error APIErrorCode) {
+	if getRequestAuthType(r) != authTypeAnonymous {
+		return ErrNone
+	}
+	switch objInfo.UserDefined[objectACLMetaKey] {
+	case "", cannedACLPublicRead, cannedACLPublicReadWrite:
+		return ErrNone
+	}
+	return ErrAccessDenied
+}
+
 // Supported headers that needs to be extracted.
 var supportedHeaders = []string{
 	"content-type",
@@ -123,3 +211,25 @@ func extractPostPolicyFormValues(reader *multipart.Reader) (filePart io.Reader,
 	}
 	return filePart, fileName, formValues, nil
 }
+
+// getRequestBucketObject - extracts bucket and object names of a path
+// style or virtual host style request.
+func getRequestBucketObject(r *http.Request) (bucket, object string) {
+	urlPath := strings.TrimPrefix(r.URL.Path, "/")
+	if globalMinioDomain != "" {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		host = strings.ToLower(host)
+		if strings.HasSuffix(host, "."+globalMinioDomain) {
+			return strings.TrimSuffix(host, "."+globalMinioDomain), urlPath
+		}
+	}
+	splits := strings.SplitN(urlPath, "/", 2)
+	bucket = splits[0]
+	if len(splits) == 2 {
+		object = splits[1]
+	}
+	return bucket, object
+}
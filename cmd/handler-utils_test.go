@@ -68,7 +68,7 @@ func TestIsValidLocationContraint(t *testing.T) {
 		// In case of empty request body ErrNone is returned.
 		{"", "us-east-1", ErrNone},
 		// Test case - 3.
-		{"eu-central-1", "us-east-1", ErrInvalidRegion},
+		{"eu-central-1", "us-east-1", ErrInvalidLocationConstraint},
 	}
 	for i, testCase := range testCases {
 		inputRequest, e := createExpectedRequest(&http.Request{}, testCase.locationForInputRequest)
@@ -0,0 +1,245 @@
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+var healFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "bucket",
+		Usage: "Heal only the specified bucket.",
+	},
+	cli.StringFlag{
+		Name:  "object",
+		Usage: "Heal only the specified object, requires --bucket.",
+	},
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Report needed repairs without writing to disks.",
+	},
+	cli.BoolFlag{
+		Name:  "recursive",
+		Usage: "Heal all buckets when --bucket is not specified.",
+	},
+}
+
+var healCmd = cli.Command{
+	Name:   "heal",
+	Usage:  "Heal buckets and objects on erasure coded disks.",
+	Flags:  append(healFlags, globalFlags...),
+	Action: mainHeal,
+	CustomHelpTemplate: `NAME:
+  minio {{.Name}} - {{.Usage}}
+
+USAGE:
+  minio {{.Name}} [FLAGS] PATH [PATH...]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Heal all buckets and objects on a 4 disks setup.
+      $ minio {{.Name}} --recursive /mnt/export1/ /mnt/export2/ /mnt/export3/ /mnt/export4/
+
+  2. Report objects in bucket "photos" which need healing, without repairing them.
+      $ minio {{.Name}} --dry-run --bucket photos /mnt/export1/ /mnt/export2/ /mnt/export3/ /mnt/export4/
+
+  3. Heal a single object.
+      $ minio {{.Name}} --bucket photos --object 2016/march/pic.jpg /mnt/export1/ /mnt/export2/ \
+          /mnt/export3/ /mnt/export4/
+
+`,
+}
+
+// Maximum number of objects listed in one pass while healing.
+const healListMaxKeys = 1000
+
+// healReport - summary of a heal run.
+type healReport struct {
+	DryRun         bool
+	BucketsScanned int
+	BucketsHealed  int
+	ObjectsHealed  int
+	ObjectsFailed  int
+	TimeElapsed    time.Duration
+}
+
+// String - printable heal report.
+func (r healReport) String() string {
+	healed := "healed"
+	if r.DryRun {
+		healed = "need healing"
+	}
+	return fmt.Sprintf("Buckets scanned: %d\nBuckets %s: %d\nObjects %s: %d\nObjects failed: %d\nTime elapsed: %s",
+		r.BucketsScanned, healed, r.BucketsHealed, healed, r.ObjectsHealed, r.ObjectsFailed, r.TimeElapsed)
+}
+
+// healObjectEntry - heals an object if needed and updates the report,
+// with dryRun only the need for healing is reported.
+func healObjectEntry(xl xlObjects, bucket, object string, dryRun bool, report *healReport) {
+	shouldHeal, err := xlShouldHealObject(xl, bucket, object)
+	if err != nil {
+		errorIf(err, "Unable to verify %s/%s.", bucket, object)
+		report.ObjectsFailed++
+		return
+	}
+	if !shouldHeal {
+		return
+	}
+	if !dryRun {
+		if err = xl.HealObject(bucket, object); err != nil {
+			errorIf(err, "Unable to heal %s/%s.", bucket, object)
+			report.ObjectsFailed++
+			return
+		}
+	}
+	report.ObjectsHealed++
+}
+
+// healBucketEntry - heals a bucket and all the objects in it if needed
+// and updates the report, with dryRun only the need for healing is reported.
+func healBucketEntry(xl xlObjects, bucket string, dryRun bool, report *healReport) error {
+	report.BucketsScanned++
+	if xlShouldHealBucket(xl.storageDisks, bucket) {
+		if !dryRun {
+			if err := xl.HealBucket(bucket); err != nil {
+				return err
+			}
+		}
+		report.BucketsHealed++
+	}
+
+	// List all the objects which need healing.
+	marker := ""
+	for {
+		result, err := xl.ListObjectsHeal(bucket, "", marker, "", healListMaxKeys)
+		if err != nil {
+			return err
+		}
+		for _, objInfo := range result.Objects {
+			healObjectEntry(xl, bucket, objInfo.Name, dryRun, report)
+		}
+		if !result.IsTruncated {
+			return nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// healXL - heals the requested scope on the given erasure coded object layer.
+func healXL(xl xlObjects, bucket, object string, dryRun, recursive bool) (report healReport, err error) {
+	report.DryRun = dryRun
+	startTime := time.Now().UTC()
+	defer func() {
+		report.TimeElapsed = time.Since(startTime)
+	}()
+
+	if object != "" {
+		if bucket == "" {
+			return report, errors.New("--object requires --bucket")
+		}
+		report.BucketsScanned++
+		healObjectEntry(xl, bucket, object, dryRun, &report)
+		return report, nil
+	}
+
+	if bucket != "" {
+		err = healBucketEntry(xl, bucket, dryRun, &report)
+		return report, err
+	}
+
+	if !recursive {
+		return report, errors.New("either --bucket or --recursive should be specified")
+	}
+
+	// List all bucket names from all disks to heal.
+	bucketNames, err := listBucketNames(xl.storageDisks)
+	if err != nil {
+		return report, err
+	}
+	for bucketName := range bucketNames {
+		if err = healBucketEntry(xl, bucketName, dryRun, &report); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// mainHeal handler called for 'minio heal' command.
+func mainHeal(c *cli.Context) {
+	if !c.Args().Present() || c.Args().First() == "help" {
+		cli.ShowCommandHelpAndExit(c, "heal", 1)
+	}
+
+	// Set global variables after parsing passed arguments
+	setGlobalsFromContext(c)
+
+	// Initialization routine, such as config loading, enable logging, ..
+	minioInit()
+
+	disks := c.Args()
+	endpoints, err := parseStorageEndpoints(disks)
+	fatalIf(err, "Unable to parse storage endpoints %s", disks)
+	checkEndpointsSyntax(endpoints, disks)
+
+	if len(endpoints) == 1 {
+		fatalIf(errInvalidArgument, "Healing is only supported on erasure coded disks.")
+	}
+	fatalIf(checkSufficientDisks(endpoints), "Storage endpoint error.")
+	if isDistributedSetup(endpoints) {
+		fatalIf(errInvalidArgument, "Healing is only supported on local disks.")
+	}
+
+	storageDisks, err := initStorageDisks(endpoints)
+	fatalIf(err, "Unable to initialize storage disk(s).")
+
+	// Print disk enumeration results.
+	console.Println("Disks:")
+	for i, disk := range storageDisks {
+		status := "online"
+		if disk == nil {
+			status = "offline"
+		} else if _, err = disk.DiskInfo(); err != nil {
+			status = "offline (" + err.Error() + ")"
+		}
+		console.Printf("  %s: %s\n", disks[i], status)
+	}
+
+	// Initialize name space lock.
+	initNSLock(false)
+
+	xl, err := initXLObjects(storageDisks)
+	fatalIf(err, "Unable to initialize erasure coded disks %s.", strings.Join(disks, " "))
+
+	report, err := healXL(*xl, c.String("bucket"), c.String("object"), c.Bool("dry-run"), c.Bool("recursive"))
+	console.Println(report)
+	fatalIf(err, "Unable to heal.")
+
+	// Not all the objects could be repaired.
+	if report.ObjectsFailed > 0 {
+		os.Exit(1)
+	}
+}
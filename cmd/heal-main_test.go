@@ -0,0 +1,126 @@
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"path"
+	"testing"
+)
+
+// Tests healing of buckets and objects through healXL.
+func TestHealXL(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	nDisks := 16
+	fsDirs, err := getRandomDisks(nDisks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	endpoints, err := parseStorageEndpoints(fsDirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj, _, err := initObjectLayer(endpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xl := obj.(*xlObjects)
+
+	bucket := getRandomBucketName()
+	emptyBucket := getRandomBucketName()
+	object := "object"
+	for _, b := range []string{bucket, emptyBucket} {
+		if err = obj.MakeBucket(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data := []byte("hello, world")
+	if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Remove the empty bucket on the first disk and the object on the second disk.
+	if err = xl.storageDisks[0].DeleteVol(emptyBucket); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"part.1", xlMetaJSONFile} {
+		if err = xl.storageDisks[1].DeleteFile(bucket, path.Join(object, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Dry run should only report.
+	report, err := healXL(*xl, "", "", true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.BucketsScanned != 2 || report.BucketsHealed != 1 || report.ObjectsHealed != 1 || report.ObjectsFailed != 0 {
+		t.Fatalf("Unexpected dry run report %#v", report)
+	}
+	if _, err = xl.storageDisks[0].StatVol(emptyBucket); err != errVolumeNotFound {
+		t.Fatalf("Expected bucket to be missing after dry run, got %v", err)
+	}
+
+	// Heal the bucket and the object.
+	report, err = healXL(*xl, "", "", false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.BucketsScanned != 2 || report.BucketsHealed != 1 || report.ObjectsHealed != 1 || report.ObjectsFailed != 0 {
+		t.Fatalf("Unexpected heal report %#v", report)
+	}
+	if _, err = xl.storageDisks[0].StatVol(emptyBucket); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = xl.storageDisks[1].StatFile(bucket, path.Join(object, xlMetaJSONFile)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing left to heal.
+	report, err = healXL(*xl, bucket, object, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.ObjectsHealed != 0 || report.ObjectsFailed != 0 {
+		t.Fatalf("Unexpected report after healing %#v", report)
+	}
+
+	// Non existent object cannot be healed.
+	report, err = healXL(*xl, bucket, "non-existent-object", false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.ObjectsFailed != 1 {
+		t.Fatalf("Expected one failed object, got %#v", report)
+	}
+
+	// Invalid scopes.
+	if _, err = healXL(*xl, "", object, false, false); err == nil {
+		t.Fatal("Expected error when --object is used without --bucket")
+	}
+	if _, err = healXL(*xl, "", "", false, false); err == nil {
+		t.Fatal("Expected error when neither --bucket nor --recursive is used")
+	}
+}
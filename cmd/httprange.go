@@ -132,3 +132,29 @@ func parseRequestRange(rangeString string, resourceSize int64) (hrange *httpRang
 
 	return &httpRange{offsetBegin, offsetEnd, resourceSize}, nil
 }
+
+// parseCopyPartRange - parses x-amz-copy-source-range of an upload part
+// copy request, unlike regular ranges both first and last byte positions
+// are mandatory and should be within the source object. eg. "bytes=0-1048575"
+func parseCopyPartRange(rangeString string, resourceSize int64) (hrange *httpRange, err error) {
+	byteRangeString := strings.TrimPrefix(rangeString, byteRangePrefix)
+	if byteRangeString == rangeString {
+		return nil, fmt.Errorf("'%s' does not start with '%s'", rangeString, byteRangePrefix)
+	}
+
+	sepIndex := strings.Index(byteRangeString, "-")
+	if sepIndex == -1 || !validBytePos.MatchString(byteRangeString[:sepIndex]) ||
+		!validBytePos.MatchString(byteRangeString[sepIndex+1:]) {
+		return nil, fmt.Errorf("'%s' does not have a valid range value", rangeString)
+	}
+	offsetEnd, err := strconv.ParseInt(byteRangeString[sepIndex+1:], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' does not have a valid last byte position value", rangeString)
+	}
+
+	// Regular ranges are truncated to the resource size, copy ranges are not.
+	if offsetEnd >= resourceSize {
+		return nil, errInvalidRange
+	}
+	return parseRequestRange(rangeString, resourceSize)
+}
@@ -0,0 +1,71 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "time"
+
+const (
+	// Default maximum number of concurrent tree walks of ListObjects.
+	globalDefaultMaxListGoroutines = 50
+
+	// Default time ListObjects waits for a tree walk to finish when
+	// the maximum number of tree walks is reached.
+	globalDefaultListSemaphoreTimeout = 10 * time.Second
+)
+
+// listSemaphore - bounds the number of concurrent tree walk go-routines,
+// each walk holds a directory open while it lists. A nil listSemaphore
+// does not limit walks.
+type listSemaphore chan struct{}
+
+// newListSemaphore - returns a semaphore allowing n concurrent walks,
+// the limit is disabled when n is zero.
+func newListSemaphore(n int) listSemaphore {
+	if n <= 0 {
+		return nil
+	}
+	return make(listSemaphore, n)
+}
+
+// acquire - takes a slot, waiting at most timeout for one to be
+// released. Returns false if no slot became available.
+func (s listSemaphore) acquire(timeout time.Duration) bool {
+	if s == nil {
+		return true
+	}
+	select {
+	case s <- struct{}{}:
+		return true
+	default:
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case s <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// release - gives back a slot taken by acquire.
+func (s listSemaphore) release() {
+	if s == nil {
+		return
+	}
+	<-s
+}
@@ -0,0 +1,119 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Tests that exactly the default number of walks proceed while the next blocks.
+func TestListSemaphore(t *testing.T) {
+	s := newListSemaphore(globalDefaultMaxListGoroutines)
+	for i := 0; i < globalDefaultMaxListGoroutines; i++ {
+		if !s.acquire(0) {
+			t.Fatalf("Walk %d: expected to proceed", i+1)
+		}
+	}
+	if s.acquire(50 * time.Millisecond) {
+		t.Fatalf("Walk %d: expected to block", globalDefaultMaxListGoroutines+1)
+	}
+
+	// A released slot unblocks a waiting walk.
+	acquired := make(chan bool)
+	go func() {
+		acquired <- s.acquire(time.Second)
+	}()
+	s.release()
+	if !<-acquired {
+		t.Fatal("Expected the waiting walk to proceed after a release")
+	}
+
+	// Disabled limit never blocks.
+	s = newListSemaphore(0)
+	for i := 0; i < 2*globalDefaultMaxListGoroutines; i++ {
+		if !s.acquire(0) {
+			t.Fatal("Expected a disabled semaphore to never block")
+		}
+		s.release()
+	}
+}
+
+// Tests that fs ListObjects fails with ListingBusy when all walks are taken,
+// and ends walks parked in the list pool to make room.
+func TestFSListObjectsSemaphore(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	defer func(timeout time.Duration) {
+		globalListSemaphoreTimeout = timeout
+	}(globalListSemaphoreTimeout)
+	globalListSemaphoreTimeout = 50 * time.Millisecond
+
+	disk := filepath.Join(os.TempDir(), "minio-"+nextSuffix())
+	defer removeAll(disk)
+
+	obj := initFSObjects(disk, t)
+	fs := obj.(fsObjects)
+	fs.listSemaphore = newListSemaphore(1)
+
+	bucketName := "bucket"
+	if err = obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("hello")
+	if _, err = obj.PutObject(bucketName, "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Slot taken by a walk outside the pool.
+	fs.listSemaphore.acquire(0)
+	if _, err = fs.ListObjects(bucketName, "", "", "", 1000); !isSameType(errorCause(err), ListingBusy{}) {
+		t.Fatalf("Expected ListingBusy, got %v", err)
+	}
+	if toAPIErrorCode(err) != ErrSlowDown {
+		t.Fatalf("Expected ListingBusy to map to SlowDown, got %v", toAPIErrorCode(err))
+	}
+	fs.listSemaphore.release()
+
+	// Slot taken by a walk parked in the list pool, it is ended.
+	fs.listSemaphore.acquire(0)
+	endWalkCh := make(chan struct{})
+	go func() {
+		<-endWalkCh
+		fs.listSemaphore.release()
+	}()
+	fs.listPool.Set(listParams{bucket: bucketName, marker: "parked"}, make(chan treeWalkResult), endWalkCh)
+	result, err := fs.ListObjects(bucketName, "", "", "", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 1 {
+		t.Fatalf("Expected 1 object, got %d", len(result.Objects))
+	}
+	select {
+	case <-endWalkCh:
+	default:
+		t.Fatal("Expected the parked walk to be ended")
+	}
+}
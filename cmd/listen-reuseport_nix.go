@@ -0,0 +1,95 @@
+// +build linux darwin freebsd
+
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+// listenReusePort - listens on the TCP address with SO_REUSEPORT set,
+// other processes listening with SO_REUSEPORT can bind the same port,
+// i.e a new server process started while the old one drains its
+// connections.
+func listenReusePort(network, address string) (net.Listener, error) {
+	tcpAddr, err := net.ResolveTCPAddr(network, address)
+	if err != nil {
+		return nil, err
+	}
+	if tcpAddr.IP == nil {
+		// Listen on all the addresses of both families if supported.
+		listener, err := listenReusePortTCP(syscall.AF_INET6, tcpAddr)
+		if err == nil {
+			return listener, nil
+		}
+		return listenReusePortTCP(syscall.AF_INET, tcpAddr)
+	}
+	if tcpAddr.IP.To4() != nil {
+		return listenReusePortTCP(syscall.AF_INET, tcpAddr)
+	}
+	return listenReusePortTCP(syscall.AF_INET6, tcpAddr)
+}
+
+// listenReusePortTCP - binds a listening socket of family to tcpAddr.
+func listenReusePortTCP(family int, tcpAddr *net.TCPAddr) (net.Listener, error) {
+	var sockaddr syscall.Sockaddr
+	if family == syscall.AF_INET {
+		sa := &syscall.SockaddrInet4{Port: tcpAddr.Port}
+		if tcpAddr.IP != nil {
+			copy(sa.Addr[:], tcpAddr.IP.To4())
+		}
+		sockaddr = sa
+	} else {
+		sa := &syscall.SockaddrInet6{Port: tcpAddr.Port}
+		if tcpAddr.IP != nil {
+			copy(sa.Addr[:], tcpAddr.IP.To16())
+		}
+		sockaddr = sa
+	}
+
+	fd, err := syscall.Socket(family, syscall.SOCK_STREAM, syscall.IPPROTO_TCP)
+	if err != nil {
+		return nil, os.NewSyscallError("socket", err)
+	}
+	// net.FileListener duplicates the socket, the file is always closed.
+	file := os.NewFile(uintptr(fd), tcpAddr.String())
+	defer file.Close()
+	syscall.CloseOnExec(fd)
+
+	if err = syscall.SetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_REUSEADDR, 1); err != nil {
+		return nil, os.NewSyscallError("setsockopt", err)
+	}
+	if err = syscall.SetsockoptInt(fd, syscall.SOL_SOCKET, soReusePort, 1); err != nil {
+		return nil, os.NewSyscallError("setsockopt", err)
+	}
+	if family == syscall.AF_INET6 && tcpAddr.IP == nil {
+		// Accept IPv4 connections as well, like net.Listen.
+		if err = syscall.SetsockoptInt(fd, syscall.IPPROTO_IPV6, syscall.IPV6_V6ONLY, 0); err != nil {
+			return nil, os.NewSyscallError("setsockopt", err)
+		}
+	}
+	if err = syscall.Bind(fd, sockaddr); err != nil {
+		return nil, os.NewSyscallError("bind", err)
+	}
+	if err = syscall.Listen(fd, syscall.SOMAXCONN); err != nil {
+		return nil, os.NewSyscallError("listen", err)
+	}
+	return net.FileListener(file)
+}
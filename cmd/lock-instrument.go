@@ -19,6 +19,7 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 )
 
@@ -126,14 +127,15 @@ var errLockNotInitialized = errors.New("Debug lockMap not initialized")
 
 // Initialize lock info volume path.
 func (n *nsLockMap) initLockInfoForVolumePath(param nsParam) {
-	n.debugLockMap[param] = newDebugLockInfoPerVolumePath()
+	n.getShard(param).debugLockMap[param] = newDebugLockInfoPerVolumePath()
 }
 
 // Change the state of the lock from Blocked to Running.
 func (n *nsLockMap) statusBlockedToRunning(param nsParam, lockSource, opsID string, readLock bool) error {
-	// This operation is not executed under the scope nsLockMap.mutex.Lock(), lock has to be explicitly held here.
-	n.lockMapMutex.Lock()
-	defer n.lockMapMutex.Unlock()
+	// This operation is not executed under the scope of the shard mutex, lock has to be explicitly held here.
+	shard := n.getShard(param)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
 	// new state info to be set for the lock.
 	newLockInfo := debugLockInfo{
 		lockSource: lockSource,
@@ -149,7 +151,7 @@ func (n *nsLockMap) statusBlockedToRunning(param nsParam, lockSource, opsID stri
 	}
 
 	// Check whether the lock info entry for <volume, path> pair already exists and its not `nil`.
-	debugLockMap, ok := n.debugLockMap[param]
+	debugLockMap, ok := shard.debugLockMap[param]
 	if !ok {
 		// The lock state info foe given <volume, path> pair should already exist.
 		// If not return `LockInfoVolPathMissing`.
@@ -159,7 +161,7 @@ func (n *nsLockMap) statusBlockedToRunning(param nsParam, lockSource, opsID stri
 	if debugLockMap == nil {
 		return traceError(errLockNotInitialized)
 	}
-	lockInfo, ok := n.debugLockMap[param].lockInfo[opsID]
+	lockInfo, ok := shard.debugLockMap[param].lockInfo[opsID]
 	if !ok {
 		// The lock info entry for given `opsID` should already exist for given <volume, path> pair.
 		// If not return `LockInfoOpsIDNotFound`.
@@ -174,19 +176,21 @@ func (n *nsLockMap) statusBlockedToRunning(param nsParam, lockSource, opsID stri
 		return traceError(LockInfoStateNotBlocked{param.volume, param.path, opsID})
 	}
 	// All checks finished. Changing the status of the operation from blocked to running and updating the time.
-	n.debugLockMap[param].lockInfo[opsID] = newLockInfo
+	shard.debugLockMap[param].lockInfo[opsID] = newLockInfo
 
 	// After locking unblocks decrease the blocked counter.
-	n.blockedCounter--
+	atomic.AddInt64(&n.blockedCounter, -1)
 	// Increase the running counter.
-	n.runningLockCounter++
-	n.debugLockMap[param].blocked--
-	n.debugLockMap[param].running++
+	atomic.AddInt64(&n.runningLockCounter, 1)
+	shard.debugLockMap[param].blocked--
+	shard.debugLockMap[param].running++
 	return nil
 }
 
-// Change the state of the lock from Ready to Blocked.
+// Change the state of the lock from Ready to Blocked, called with the
+// shard mutex held.
 func (n *nsLockMap) statusNoneToBlocked(param nsParam, lockSource, opsID string, readLock bool) error {
+	shard := n.getShard(param)
 	newLockInfo := debugLockInfo{
 		lockSource: lockSource,
 		status:     blockedStatus,
@@ -198,7 +202,7 @@ func (n *nsLockMap) statusNoneToBlocked(param nsParam, lockSource, opsID string,
 		newLockInfo.lType = debugWLockStr
 	}
 
-	lockInfo, ok := n.debugLockMap[param]
+	lockInfo, ok := shard.debugLockMap[param]
 	if !ok {
 		// State info entry for the given <volume, pair> doesn't exist, initializing it.
 		n.initLockInfoForVolumePath(param)
@@ -209,31 +213,32 @@ func (n *nsLockMap) statusNoneToBlocked(param nsParam, lockSource, opsID string,
 	}
 
 	// lockInfo is a map[string]debugLockInfo, which holds map[OperationID]{status,time, origin} of the lock.
-	if n.debugLockMap[param].lockInfo == nil {
-		n.debugLockMap[param].lockInfo = make(map[string]debugLockInfo)
+	if shard.debugLockMap[param].lockInfo == nil {
+		shard.debugLockMap[param].lockInfo = make(map[string]debugLockInfo)
 	}
 	// The status of the operation with the given operation ID is marked blocked till its gets unblocked from the lock.
-	n.debugLockMap[param].lockInfo[opsID] = newLockInfo
+	shard.debugLockMap[param].lockInfo[opsID] = newLockInfo
 	// Increment the Global lock counter.
-	n.globalLockCounter++
+	atomic.AddInt64(&n.globalLockCounter, 1)
 	// Increment the counter for number of blocked opertions, decrement it after the locking unblocks.
-	n.blockedCounter++
+	atomic.AddInt64(&n.blockedCounter, 1)
 	// increment the reference of the lock for the given <volume,path> pair.
-	n.debugLockMap[param].ref++
+	shard.debugLockMap[param].ref++
 	// increment the blocked counter for the given <volume, path> pair.
-	n.debugLockMap[param].blocked++
+	shard.debugLockMap[param].blocked++
 	return nil
 }
 
 // deleteLockInfoEntry - Deletes the lock state information for given
 // <volume, path> pair. Called when nsLk.ref count is 0.
 func (n *nsLockMap) deleteLockInfoEntryForVolumePath(param nsParam) error {
+	shard := n.getShard(param)
 	// delete the lock info for the given operation.
-	if _, found := n.debugLockMap[param]; !found {
+	if _, found := shard.debugLockMap[param]; !found {
 		return traceError(LockInfoVolPathMissing{param.volume, param.path})
 	}
 	// Remove from the map if there are no more references for the given (volume,path) pair.
-	delete(n.debugLockMap, param)
+	delete(shard.debugLockMap, param)
 	return nil
 }
 
@@ -242,7 +247,7 @@ func (n *nsLockMap) deleteLockInfoEntryForVolumePath(param nsParam) error {
 // <volume, path> pair is not 0.
 func (n *nsLockMap) deleteLockInfoEntryForOps(param nsParam, opsID string) error {
 	// delete the lock info for the given operation.
-	infoMap, found := n.debugLockMap[param]
+	infoMap, found := n.getShard(param).debugLockMap[param]
 	if !found {
 		return traceError(LockInfoVolPathMissing{param.volume, param.path})
 	}
@@ -254,8 +259,8 @@ func (n *nsLockMap) deleteLockInfoEntryForOps(param nsParam, opsID string) error
 		return traceError(LockInfoOpsIDNotFound{param.volume, param.path, opsID})
 	}
 	// Decrease the global running and lock reference counter.
-	n.runningLockCounter--
-	n.globalLockCounter--
+	atomic.AddInt64(&n.runningLockCounter, -1)
+	atomic.AddInt64(&n.globalLockCounter, -1)
 	// Decrease the lock referee counter for the lock info for given <volume,path> pair.
 	// Decrease the running operation number. Its assumed that the operation is over
 	// once an attempt to release the lock is made.
@@ -297,7 +297,7 @@ func (l *lockServer) lockMaintenance(interval time.Duration) {
 	// Validate if long lived locks are indeed clean.
 	for _, nlrip := range nlripLongLived {
 		// Initialize client based on the long live locks.
-		c := newClient(nlrip.lri.node, nlrip.lri.rpcPath, isSSL())
+		c := newClient(nlrip.lri.node, nlrip.lri.rpcPath, isServerTLS())
 
 		var expired bool
 
@@ -16,7 +16,10 @@
 
 package cmd
 
-import "time"
+import (
+	"sync/atomic"
+	"time"
+)
 
 // SystemLockState - Structure to fill the lock state of entire object storage.
 // That is the total locks held, total calls blocked on locks and state of all the locks for the entire system.
@@ -61,16 +64,25 @@ type OpsLockState struct {
 
 // Read entire state of the locks in the system and return.
 func getSystemLockState() (SystemLockState, error) {
-	nsMutex.lockMapMutex.Lock()
-	defer nsMutex.lockMapMutex.Unlock()
-
 	lockState := SystemLockState{}
 
-	lockState.TotalBlockedLocks = nsMutex.blockedCounter
-	lockState.TotalLocks = nsMutex.globalLockCounter
-	lockState.TotalAcquiredLocks = nsMutex.runningLockCounter
+	lockState.TotalBlockedLocks = atomic.LoadInt64(&nsMutex.blockedCounter)
+	lockState.TotalLocks = atomic.LoadInt64(&nsMutex.globalLockCounter)
+	lockState.TotalAcquiredLocks = atomic.LoadInt64(&nsMutex.runningLockCounter)
 
-	for param, debugLock := range nsMutex.debugLockMap {
+	for i := range nsMutex.shards {
+		lockState.LocksInfoPerObject = append(lockState.LocksInfoPerObject,
+			getShardLockState(&nsMutex.shards[i])...)
+	}
+	return lockState, nil
+}
+
+// Read the state of the locks of a shard of the namespace lock map.
+func getShardLockState(shard *nsLockShard) (locksInfoPerObject []VolumeLockInfo) {
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	for param, debugLock := range shard.debugLockMap {
 		volLockInfo := VolumeLockInfo{}
 		volLockInfo.Bucket = param.volume
 		volLockInfo.Object = param.path
@@ -87,7 +99,7 @@ func getSystemLockState() (SystemLockState, error) {
 				Duration:    time.Now().UTC().Sub(lockInfo.since),
 			})
 		}
-		lockState.LocksInfoPerObject = append(lockState.LocksInfoPerObject, volLockInfo)
+		locksInfoPerObject = append(locksInfoPerObject, volLockInfo)
 	}
-	return lockState, nil
+	return locksInfoPerObject
 }
@@ -39,7 +39,11 @@ func enableConsoleLogger() {
 	fatalIf(err, "Unknown log level found in the config file.")
 
 	consoleLogger.Level = lvl
-	consoleLogger.Formatter = new(logrus.TextFormatter)
+	if globalJSONLog {
+		consoleLogger.Formatter = new(logrus.JSONFormatter)
+	} else {
+		consoleLogger.Formatter = new(logrus.TextFormatter)
+	}
 	log.mu.Lock()
 	log.loggers = append(log.loggers, consoleLogger)
 	log.mu.Unlock()
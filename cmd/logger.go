@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"path"
 	"runtime"
@@ -57,22 +58,82 @@ func callerSource() string {
 	return fmt.Sprintf("[%s:%d:%s()]", file, line, name)
 }
 
+// sanitizeForLog - escapes all but the printable ASCII characters of s
+// as \xNN, user supplied names may carry terminal escape sequences.
+func sanitizeForLog(s string) string {
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c >= 0x20 && c <= 0x7e {
+			buf.WriteByte(c)
+		} else {
+			fmt.Fprintf(&buf, "\\x%02x", c)
+		}
+	}
+	return buf.String()
+}
+
+// logMessage - formats a log message, sanitized for the log output.
+func logMessage(msg string, data ...interface{}) string {
+	return sanitizeForLog(fmt.Sprintf(msg, data...))
+}
+
+// logFields - log fields of ctx, string values are sanitized.
+func logFields(ctx fields) logrus.Fields {
+	ctxFields := make(logrus.Fields, len(ctx))
+	for key, value := range ctx {
+		if s, ok := value.(string); ok {
+			value = sanitizeForLog(s)
+		}
+		ctxFields[key] = value
+	}
+	return ctxFields
+}
+
+// errorFields - log fields for err logged at source, ctx carries
+// additional context such as bucket and object names.
+func errorFields(err error, source string, ctx fields) logrus.Fields {
+	errFields := logrus.Fields{
+		"source": source,
+		"cause":  sanitizeForLog(err.Error()),
+	}
+	if e, ok := err.(*Error); ok {
+		errFields["stack"] = strings.Join(e.Trace(), " ")
+	}
+	for key, value := range logFields(ctx) {
+		if _, ok := errFields[key]; !ok {
+			errFields[key] = value
+		}
+	}
+	return errFields
+}
+
 // errorIf synonymous with fatalIf but doesn't exit on error != nil
 func errorIf(err error, msg string, data ...interface{}) {
 	if err == nil || !isErrLogged(err) {
 		return
 	}
-	source := callerSource()
-	fields := logrus.Fields{
-		"source": source,
-		"cause":  err.Error(),
+	errFields := errorFields(err, callerSource(), nil)
+	for _, log := range log.loggers {
+		log.WithFields(errFields).Error(logMessage(msg, data...))
 	}
-	if e, ok := err.(*Error); ok {
-		fields["stack"] = strings.Join(e.Trace(), " ")
+}
+
+// errorIfContext is errorIf which additionally logs ctx, i.e the
+// bucket and object names the error is about.
+func errorIfContext(err error, ctx fields, msg string, data ...interface{}) {
+	if err == nil || !isErrLogged(err) {
+		return
+	}
+	errFields := errorFields(err, callerSource(), ctx)
+	for _, log := range log.loggers {
+		log.WithFields(errFields).Error(logMessage(msg, data...))
 	}
+}
 
+// infoContext logs an informational message along with ctx.
+func infoContext(ctx fields, msg string, data ...interface{}) {
 	for _, log := range log.loggers {
-		log.WithFields(fields).Errorf(msg, data...)
+		log.WithFields(logFields(ctx)).Info(logMessage(msg, data...))
 	}
 }
 
@@ -81,17 +142,24 @@ func fatalIf(err error, msg string, data ...interface{}) {
 	if err == nil || !isErrLogged(err) {
 		return
 	}
-	source := callerSource()
-	fields := logrus.Fields{
-		"source": source,
-		"cause":  err.Error(),
+	errFields := errorFields(err, callerSource(), nil)
+	for _, log := range log.loggers {
+		log.WithFields(errFields).Fatal(logMessage(msg, data...))
 	}
-	if e, ok := err.(*Error); ok {
-		fields["stack"] = strings.Join(e.Trace(), " ")
+}
+
+// setLogLevel - changes the minimum level of all the registered loggers.
+func setLogLevel(level string) error {
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
 	}
-	for _, log := range log.loggers {
-		log.WithFields(fields).Fatalf(msg, data...)
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	for _, logger := range log.loggers {
+		logger.Level = lvl
 	}
+	return nil
 }
 
 // returns false if error is not supposed to be logged.
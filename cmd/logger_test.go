@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/Sirupsen/logrus"
@@ -29,7 +30,7 @@ import (
 func TestCallerSource(t *testing.T) {
 	currentSource := func() string { return callerSource() }
 	gotSource := currentSource()
-	expectedSource := "[logger_test.go:31:TestCallerSource()]"
+	expectedSource := "[logger_test.go:32:TestCallerSource()]"
 	if gotSource != expectedSource {
 		t.Errorf("expected : %s, got : %s", expectedSource, gotSource)
 	}
@@ -62,3 +63,76 @@ func TestLogger(t *testing.T) {
 		t.Fatal("Cause field has unexpected message", msg)
 	}
 }
+
+// Tests error logger with bucket and object context.
+func TestLoggerContext(t *testing.T) {
+	var buffer bytes.Buffer
+	var logFields logrus.Fields
+	testLog := logrus.New()
+	testLog.Out = &buffer
+	testLog.Formatter = new(logrus.JSONFormatter)
+	log.mu.Lock()
+	savedLoggers := log.loggers
+	log.loggers = []*logrus.Logger{testLog}
+	log.mu.Unlock()
+	defer func() {
+		log.mu.Lock()
+		log.loggers = savedLoggers
+		log.mu.Unlock()
+	}()
+
+	errorIfContext(traceError(errors.New("Fake error")), fields{"bucket": "bucket", "object": "object", "cause": "overridden"}, "Failed with error.")
+	if err := json.Unmarshal(buffer.Bytes(), &logFields); err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range map[string]string{"bucket": "bucket", "object": "object", "cause": "Fake error"} {
+		if logFields[key] != value {
+			t.Errorf("Expected %s field to be %s, got %v", key, value, logFields[key])
+		}
+	}
+	if _, ok := logFields["stack"]; !ok {
+		t.Error("Stack field missing")
+	}
+}
+
+// Tests that user supplied names are sanitized in the log output.
+func TestLoggerSanitize(t *testing.T) {
+	var buffer bytes.Buffer
+	testLog := logrus.New()
+	testLog.Out = &buffer
+	testLog.Formatter = &logrus.TextFormatter{DisableColors: true}
+	log.mu.Lock()
+	savedLoggers := log.loggers
+	log.loggers = []*logrus.Logger{testLog}
+	log.mu.Unlock()
+	defer func() {
+		log.mu.Lock()
+		log.loggers = savedLoggers
+		log.mu.Unlock()
+	}()
+
+	object := "object\033[H\033[2J\n"
+	errorIfContext(errors.New("Fake error "+object), fields{"bucket": "bucket", "object": object}, "Failed with %s.", object)
+	output := buffer.String()
+	if strings.ContainsAny(strings.TrimSuffix(output, "\n"), "\033\n") {
+		t.Fatalf("Expected sanitized log output, got %q", output)
+	}
+	// Values are quoted by the text formatter, escaping backslashes.
+	if !strings.Contains(output, `object="object\\x1b[H\\x1b[2J\\x0a"`) {
+		t.Fatalf("Expected escaped object name in log output, got %q", output)
+	}
+
+	testCases := []struct {
+		input, output string
+	}{
+		{"", ""},
+		{"bucket/object name~", "bucket/object name~"},
+		{"\x00\x1f\x7f", `\x00\x1f\x7f`},
+		{"é", `\xc3\xa9`},
+	}
+	for i, testCase := range testCases {
+		if got := sanitizeForLog(testCase.input); got != testCase.output {
+			t.Errorf("Test %d: Expected %q, got %q", i+1, testCase.output, got)
+		}
+	}
+}
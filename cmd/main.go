@@ -19,6 +19,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"sort"
 	"time"
 
@@ -112,6 +113,9 @@ func registerApp() *cli.App {
 	registerCommand(serverCmd)
 	registerCommand(versionCmd)
 	registerCommand(updateCmd)
+	registerCommand(healCmd)
+	registerCommand(adminCmd)
+	registerCommand(benchmarkCmd)
 
 	// Set up app.
 	app := cli.NewApp()
@@ -163,6 +167,28 @@ func checkUpdate() {
 	}
 }
 
+// Valid region names, AWS regions as well as custom ones like "home-1".
+var validRegionName = regexp.MustCompile(`^[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*$`)
+
+// isValidRegionName - validates a region name for the config.
+func isValidRegionName(region string) bool {
+	return validRegionName.MatchString(region)
+}
+
+// setRegionFromEnv - overrides the region in config with MINIO_REGION
+// environment variable if set.
+func setRegionFromEnv() error {
+	region := os.Getenv("MINIO_REGION")
+	if region == "" {
+		return nil
+	}
+	if !isValidRegionName(region) {
+		return errInvalidArgument
+	}
+	serverConfig.SetRegion(region)
+	return nil
+}
+
 // Generic Minio initialization to create/load config, prepare loggers, etc..
 func minioInit() {
 	// Sets new config directory.
@@ -193,6 +219,8 @@ func minioInit() {
 			SecretAccessKey: secretKey,
 		})
 	}
+	// Fetch region from environment variable and update the config.
+	fatalIf(setRegionFromEnv(), "Invalid region %s.", os.Getenv("MINIO_REGION"))
 	if !isValidAccessKey(serverConfig.GetCredential().AccessKeyID) {
 		fatalIf(errInvalidArgument, "Invalid access key. Accept only a string starting with a alphabetic and containing from 5 to 20 characters.")
 	}
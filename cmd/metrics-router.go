@@ -0,0 +1,82 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	router "github.com/gorilla/mux"
+)
+
+const (
+	metricsPath = "/prometheus/metrics"
+)
+
+// registerMetricsRouter - registers the metrics in the Prometheus text
+// format, scraped with the RPC token of 'minio admin' as bearer token.
+func registerMetricsRouter(mux *router.Router) {
+	metricsRouter := mux.NewRoute().PathPrefix(reservedBucket).Subrouter()
+	metricsRouter.Methods("GET").Path(metricsPath).Handler(rpcTokenAuthHandler(http.HandlerFunc(metricsHandler)))
+}
+
+// metricsHandler - writes all the metrics of the server.
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if globalReplicationWorker != nil {
+		fmt.Fprintln(w, "# HELP minio_replication_lag_seconds Time from queueing the last replicated event to its acknowledgement by the replication target.")
+		fmt.Fprintln(w, "# TYPE minio_replication_lag_seconds gauge")
+		fmt.Fprintf(w, "minio_replication_lag_seconds %g\n", globalReplicationWorker.getLag().Seconds())
+	}
+	if objAPI := newObjectLayerFn(); objAPI != nil {
+		writeDiskMetrics(w, objAPI.StorageInfo().Disks)
+	}
+}
+
+// writeDiskMetrics - writes the capacity gauges of each disk.
+func writeDiskMetrics(w io.Writer, disks []DiskMetrics) {
+	if len(disks) == 0 {
+		return
+	}
+	gauges := []struct {
+		name, help string
+		value      func(DiskMetrics) int64
+	}{
+		{"minio_disk_total_bytes", "Total capacity of the disk.", func(d DiskMetrics) int64 { return d.TotalBytes }},
+		{"minio_disk_used_bytes", "Used capacity of the disk.", func(d DiskMetrics) int64 { return d.UsedBytes }},
+		{"minio_disk_free_bytes", "Free capacity of the disk.", func(d DiskMetrics) int64 { return d.FreeBytes }},
+	}
+	for _, gauge := range gauges {
+		fmt.Fprintf(w, "# HELP %s %s\n", gauge.name, gauge.help)
+		fmt.Fprintf(w, "# TYPE %s gauge\n", gauge.name)
+		for _, d := range disks {
+			fmt.Fprintf(w, "%s{path=\"%s\"} %d\n", gauge.name, escapeMetricsLabelValue(d.Path), gauge.value(d))
+		}
+	}
+}
+
+// Escapes of label values in the Prometheus text format, all the other
+// characters are written as is.
+var metricsLabelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeMetricsLabelValue - escapes a label value for the Prometheus
+// text format.
+func escapeMetricsLabelValue(value string) string {
+	return metricsLabelValueReplacer.Replace(value)
+}
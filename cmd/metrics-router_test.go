@@ -0,0 +1,111 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// Tests the Prometheus text format of the disk gauges.
+func TestWriteDiskMetrics(t *testing.T) {
+	disks := []DiskMetrics{
+		{Path: "/mnt/disk1", TotalBytes: 100, UsedBytes: 40, FreeBytes: 60},
+		{Path: "/mnt/\"disk2\"\\\n\tdisk", TotalBytes: 200, UsedBytes: 50, FreeBytes: 150},
+	}
+	var buf bytes.Buffer
+	writeDiskMetrics(&buf, disks)
+	expected := `# HELP minio_disk_total_bytes Total capacity of the disk.
+# TYPE minio_disk_total_bytes gauge
+minio_disk_total_bytes{path="/mnt/disk1"} 100
+minio_disk_total_bytes{path="/mnt/\"disk2\"\\\n	disk"} 200
+# HELP minio_disk_used_bytes Used capacity of the disk.
+# TYPE minio_disk_used_bytes gauge
+minio_disk_used_bytes{path="/mnt/disk1"} 40
+minio_disk_used_bytes{path="/mnt/\"disk2\"\\\n	disk"} 50
+# HELP minio_disk_free_bytes Free capacity of the disk.
+# TYPE minio_disk_free_bytes gauge
+minio_disk_free_bytes{path="/mnt/disk1"} 60
+minio_disk_free_bytes{path="/mnt/\"disk2\"\\\n	disk"} 150
+`
+	if buf.String() != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, buf.String())
+	}
+
+	// Nothing is written without disks.
+	buf.Reset()
+	writeDiskMetrics(&buf, nil)
+	if buf.Len() != 0 {
+		t.Fatalf("Expected no metrics, got %s", buf.String())
+	}
+}
+
+// Tests the metrics are only served to requests with the RPC token.
+func TestMetricsRouter(t *testing.T) {
+	// The test server changes the address globals.
+	defer restoreMinioAddrGlobals(globalMinioAddr, globalMinioHost, globalMinioPort)
+
+	testServer := StartTestServer(t, "FS")
+	defer testServer.Stop()
+
+	jwt, err := newJWT(defaultInterNodeJWTExpiry, serverConfig.GetCredential())
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.GenerateToken(testServer.AccessKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		authorization string
+		status        int
+		contains      string
+	}{
+		{jwtAlgorithm + " " + token, http.StatusOK, "minio_disk_total_bytes"},
+		// Requests without a valid token are rejected.
+		{"", http.StatusForbidden, "AccessDenied"},
+		{jwtAlgorithm + " garbage", http.StatusUnauthorized, ""},
+	}
+	for i, testCase := range testCases {
+		req, err := http.NewRequest("GET", testServer.Server.URL+reservedBucket+metricsPath, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if testCase.authorization != "" {
+			req.Header.Set("Authorization", testCase.authorization)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != testCase.status {
+			t.Fatalf("Test %d: Expected status %d, got %d", i+1, testCase.status, resp.StatusCode)
+		}
+		if !strings.Contains(string(body), testCase.contains) {
+			t.Errorf("Test %d: Expected response to contain %q, got %s", i+1, testCase.contains, body)
+		}
+	}
+}
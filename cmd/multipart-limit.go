@@ -0,0 +1,131 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// Default maximum number of in-progress multipart uploads of a bucket.
+	globalDefaultMaxMultipartUploadsPerBucket = 1000
+
+	// Cached counts of multipart uploads are refreshed after this duration.
+	multipartUploadsCacheExpiry = 5 * time.Second
+)
+
+// getMultipartUploadsCount - returns the number of in-progress multipart
+// uploads of a bucket, counting stops once limit uploads are found.
+func getMultipartUploadsCount(bucket string, limit int, objAPI ObjectLayer) (int, error) {
+	var count int
+	keyMarker, uploadIDMarker := "", ""
+	for {
+		result, err := objAPI.ListMultipartUploads(bucket, "", keyMarker, uploadIDMarker, "", maxUploadsList)
+		if err != nil {
+			return 0, err
+		}
+		count += len(result.Uploads)
+		if !result.IsTruncated || count >= limit {
+			return count, nil
+		}
+		keyMarker, uploadIDMarker = result.NextKeyMarker, result.NextUploadIDMarker
+	}
+}
+
+// multipartUploadsCount - number of multipart uploads of a bucket at a
+// point in time.
+type multipartUploadsCount struct {
+	count     int
+	updatedAt time.Time
+}
+
+// multipartUploadsCache - caches the number of in-progress multipart
+// uploads of buckets to avoid listing them for every new upload. Counts
+// are incremented on new uploads and dropped when uploads are aborted
+// or completed, they are refreshed after expiry.
+type multipartUploadsCache struct {
+	mu      sync.Mutex
+	buckets map[string]multipartUploadsCount
+	expiry  time.Duration
+}
+
+func newMultipartUploadsCache(expiry time.Duration) *multipartUploadsCache {
+	return &multipartUploadsCache{
+		buckets: make(map[string]multipartUploadsCount),
+		expiry:  expiry,
+	}
+}
+
+// Global cache of the number of multipart uploads of buckets.
+var globalMultipartUploadsCache = newMultipartUploadsCache(multipartUploadsCacheExpiry)
+
+// get - returns the number of multipart uploads of a bucket, refreshed
+// if expired.
+func (c *multipartUploadsCache) get(bucket string, limit int, objAPI ObjectLayer) (int, error) {
+	c.mu.Lock()
+	entry, ok := c.buckets[bucket]
+	c.mu.Unlock()
+	if ok && time.Since(entry.updatedAt) < c.expiry {
+		return entry.count, nil
+	}
+
+	count, err := getMultipartUploadsCount(bucket, limit, objAPI)
+	if err != nil {
+		return 0, err
+	}
+	c.mu.Lock()
+	c.buckets[bucket] = multipartUploadsCount{count: count, updatedAt: time.Now().UTC()}
+	c.mu.Unlock()
+	return count, nil
+}
+
+// add - accounts a new multipart upload of a bucket.
+func (c *multipartUploadsCache) add(bucket string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.buckets[bucket]; ok {
+		entry.count++
+		c.buckets[bucket] = entry
+	}
+}
+
+// invalidate - drops the cached number of multipart uploads of a bucket.
+func (c *multipartUploadsCache) invalidate(bucket string) {
+	c.mu.Lock()
+	delete(c.buckets, bucket)
+	c.mu.Unlock()
+}
+
+// checkMultipartUploadsLimit - verifies if a new multipart upload can be
+// initiated on a bucket without exceeding the configured limit.
+func checkMultipartUploadsLimit(bucket string, objAPI ObjectLayer) APIErrorCode {
+	limit := globalMaxMultipartUploadsPerBucket
+	if limit <= 0 {
+		// Limit is disabled.
+		return ErrNone
+	}
+	count, err := globalMultipartUploadsCache.get(bucket, limit, objAPI)
+	if err != nil {
+		errorIf(err, "Unable to count multipart uploads of the bucket %s.", bucket)
+		return toAPIErrorCode(err)
+	}
+	if count >= limit {
+		return ErrTooManyMultipartUploads
+	}
+	return ErrNone
+}
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"errors"
+	"hash/fnv"
 	"net/url"
 	pathutil "path"
 	"sync"
@@ -43,7 +44,7 @@ func initDsyncNodes(eps []*url.URL) error {
 			accessKey: cred.AccessKeyID,
 			secretKey: cred.SecretAccessKey,
 			// Construct a new dsync server addr.
-			secureConn: isSSL(),
+			secureConn: isServerTLS(),
 			address:    ep.Host,
 			// Construct a new rpc path for the endpoint.
 			path:        pathutil.Join(lockRPCPath, getPath(ep)),
@@ -58,14 +59,21 @@ func initDsyncNodes(eps []*url.URL) error {
 
 // initNSLock - initialize name space lock map.
 func initNSLock(isDist bool) {
-	nsMutex = &nsLockMap{
-		isDist:  isDist,
-		lockMap: make(map[nsParam]*nsLock),
-	}
+	nsMutex = newNSLockMap(isDist)
+}
 
-	// Initialize nsLockMap with entry for instrumentation information.
-	// Entries of <volume,path> -> stateInfo of locks
-	nsMutex.debugLockMap = make(map[nsParam]*debugLockInfoPerVolumePath)
+// newNSLockMap - initializes an empty name space lock map.
+func newNSLockMap(isDist bool) *nsLockMap {
+	n := &nsLockMap{
+		isDist: isDist,
+	}
+	for i := range n.shards {
+		n.shards[i].lockMap = make(map[nsParam]*nsLock)
+		// Initialize the shard with entry for instrumentation information.
+		// Entries of <volume,path> -> stateInfo of locks
+		n.shards[i].debugLockMap = make(map[nsParam]*debugLockInfoPerVolumePath)
+	}
+	return n
 }
 
 // RWLocker - interface that any read-write locking library should implement.
@@ -87,29 +95,50 @@ type nsLock struct {
 	ref uint
 }
 
+// Number of shards of the namespace lock map.
+const nsLockShards = 256
+
+// nsLockShard - a shard of the namespace lock map, resources are spread
+// over the shards by the hash of their name so that locking different
+// resources does not contend on the same mutex. The instrumentation of
+// the locks of a shard is guarded by the same mutex.
+type nsLockShard struct {
+	mutex        sync.Mutex
+	lockMap      map[nsParam]*nsLock
+	debugLockMap map[nsParam]*debugLockInfoPerVolumePath // Info for instrumentation on locks.
+}
+
 // nsLockMap - namespace lock map, provides primitives to Lock,
 // Unlock, RLock and RUnlock.
 type nsLockMap struct {
-	// Lock counter used for lock debugging.
-	globalLockCounter  int64                                   // Total locks held.
-	blockedCounter     int64                                   // Total operations blocked waiting for locks.
-	runningLockCounter int64                                   // Total locks held but not released yet.
-	debugLockMap       map[nsParam]*debugLockInfoPerVolumePath // Info for instrumentation on locks.
+	// Lock counter used for lock debugging, updated atomically.
+	globalLockCounter  int64 // Total locks held.
+	blockedCounter     int64 // Total operations blocked waiting for locks.
+	runningLockCounter int64 // Total locks held but not released yet.
 
 	// Indicates whether the locking service is part
 	// of a distributed setup or not.
-	isDist       bool
-	lockMap      map[nsParam]*nsLock
-	lockMapMutex sync.Mutex
+	isDist bool
+	shards [nsLockShards]nsLockShard
+}
+
+// getShard - returns the shard of a namespace resource.
+func (n *nsLockMap) getShard(param nsParam) *nsLockShard {
+	h := fnv.New32a()
+	h.Write([]byte(param.volume))
+	h.Write([]byte(slashSeparator))
+	h.Write([]byte(param.path))
+	return &n.shards[h.Sum32()%nsLockShards]
 }
 
 // Lock the namespace resource.
 func (n *nsLockMap) lock(volume, path string, lockSource, opsID string, readLock bool) {
 	var nsLk *nsLock
-	n.lockMapMutex.Lock()
-
 	param := nsParam{volume, path}
-	nsLk, found := n.lockMap[param]
+	shard := n.getShard(param)
+	shard.mutex.Lock()
+
+	nsLk, found := shard.lockMap[param]
 	if !found {
 		nsLk = &nsLock{
 			RWLocker: func() RWLocker {
@@ -120,20 +149,19 @@ func (n *nsLockMap) lock(volume, path string, lockSource, opsID string, readLock
 			}(),
 			ref: 0,
 		}
-		n.lockMap[param] = nsLk
+		shard.lockMap[param] = nsLk
 	}
 	nsLk.ref++ // Update ref count here to avoid multiple races.
 
 	// Change the state of the lock to be blocked for the given
 	// pair of <volume, path> and <OperationID> till the lock
-	// unblocks. The lock for accessing `nsMutex` is held inside
-	// the function itself.
+	// unblocks.
 	if err := n.statusNoneToBlocked(param, lockSource, opsID, readLock); err != nil {
 		errorIf(err, "Failed to set lock state to blocked")
 	}
 
 	// Unlock map before Locking NS which might block.
-	n.lockMapMutex.Unlock()
+	shard.mutex.Unlock()
 
 	// Locking here can block.
 	if readLock {
@@ -154,11 +182,12 @@ func (n *nsLockMap) lock(volume, path string, lockSource, opsID string, readLock
 func (n *nsLockMap) unlock(volume, path, opsID string, readLock bool) {
 	// nsLk.Unlock() will not block, hence locking the map for the
 	// entire function is fine.
-	n.lockMapMutex.Lock()
-	defer n.lockMapMutex.Unlock()
-
 	param := nsParam{volume, path}
-	if nsLk, found := n.lockMap[param]; found {
+	shard := n.getShard(param)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	if nsLk, found := shard.lockMap[param]; found {
 		if readLock {
 			nsLk.RUnlock()
 		} else {
@@ -179,7 +208,7 @@ func (n *nsLockMap) unlock(volume, path, opsID string, readLock bool) {
 		}
 		if nsLk.ref == 0 {
 			// Remove from the map if there are no more references.
-			delete(n.lockMap, param)
+			delete(shard.lockMap, param)
 
 			// delete the lock state entry for given
 			// <volume, path> pair.
@@ -222,8 +251,10 @@ func (n *nsLockMap) RUnlock(volume, path, opsID string) {
 
 // ForceUnlock - forcefully unlock a lock based on name.
 func (n *nsLockMap) ForceUnlock(volume, path string) {
-	n.lockMapMutex.Lock()
-	defer n.lockMapMutex.Unlock()
+	param := nsParam{volume, path}
+	shard := n.getShard(param)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
 
 	// Clarification on operation:
 	// - In case of FS or XL we call ForceUnlock on the local nsMutex
@@ -243,10 +274,9 @@ func (n *nsLockMap) ForceUnlock(volume, path string) {
 		dsync.NewDRWMutex(pathutil.Join(volume, path)).ForceUnlock()
 	}
 
-	param := nsParam{volume, path}
-	if _, found := n.lockMap[param]; found {
+	if _, found := shard.lockMap[param]; found {
 		// Remove lock from the map.
-		delete(n.lockMap, param)
+		delete(shard.lockMap, param)
 
 		// delete the lock state entry for given
 		// <volume, path> pair.
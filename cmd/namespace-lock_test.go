@@ -17,8 +17,10 @@
 package cmd
 
 import (
+	"runtime"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -64,7 +66,7 @@ func TestNamespaceLockTest(t *testing.T) {
 	// Write lock tests.
 	testCase := testCases[0]
 	testCase.lk("a", "b", "c") // lock once.
-	nsLk, ok := nsMutex.lockMap[nsParam{"a", "b"}]
+	nsLk, ok := nsMutex.getShard(nsParam{"a", "b"}).lockMap[nsParam{"a", "b"}]
 	if !ok && testCase.shouldPass {
 		t.Errorf("Lock in map missing.")
 	}
@@ -76,7 +78,7 @@ func TestNamespaceLockTest(t *testing.T) {
 	if testCase.unlockedRefCount != nsLk.ref && testCase.shouldPass {
 		t.Errorf("Test %d fails, expected to pass. Wanted ref count is %d, got %d", 1, testCase.unlockedRefCount, nsLk.ref)
 	}
-	_, ok = nsMutex.lockMap[nsParam{"a", "b"}]
+	_, ok = nsMutex.getShard(nsParam{"a", "b"}).lockMap[nsParam{"a", "b"}]
 	if ok && !testCase.shouldPass {
 		t.Errorf("Lock map found after unlock.")
 	}
@@ -87,7 +89,7 @@ func TestNamespaceLockTest(t *testing.T) {
 	testCase.rlk("a", "b", "c") // lock second time.
 	testCase.rlk("a", "b", "c") // lock third time.
 	testCase.rlk("a", "b", "c") // lock fourth time.
-	nsLk, ok = nsMutex.lockMap[nsParam{"a", "b"}]
+	nsLk, ok = nsMutex.getShard(nsParam{"a", "b"}).lockMap[nsParam{"a", "b"}]
 	if !ok && testCase.shouldPass {
 		t.Errorf("Lock in map missing.")
 	}
@@ -101,7 +103,7 @@ func TestNamespaceLockTest(t *testing.T) {
 	if testCase.unlockedRefCount != nsLk.ref && testCase.shouldPass {
 		t.Errorf("Test %d fails, expected to pass. Wanted ref count is %d, got %d", 2, testCase.unlockedRefCount, nsLk.ref)
 	}
-	_, ok = nsMutex.lockMap[nsParam{"a", "b"}]
+	_, ok = nsMutex.getShard(nsParam{"a", "b"}).lockMap[nsParam{"a", "b"}]
 	if !ok && testCase.shouldPass {
 		t.Errorf("Lock map not found.")
 	}
@@ -110,7 +112,7 @@ func TestNamespaceLockTest(t *testing.T) {
 	testCase = testCases[2]
 	testCase.rlk("a", "c", "d") // lock once.
 
-	nsLk, ok = nsMutex.lockMap[nsParam{"a", "c"}]
+	nsLk, ok = nsMutex.getShard(nsParam{"a", "c"}).lockMap[nsParam{"a", "c"}]
 	if !ok && testCase.shouldPass {
 		t.Errorf("Lock in map missing.")
 	}
@@ -122,7 +124,7 @@ func TestNamespaceLockTest(t *testing.T) {
 	if testCase.unlockedRefCount != nsLk.ref && testCase.shouldPass {
 		t.Errorf("Test %d fails, expected to pass. Wanted ref count is %d, got %d", 3, testCase.unlockedRefCount, nsLk.ref)
 	}
-	_, ok = nsMutex.lockMap[nsParam{"a", "c"}]
+	_, ok = nsMutex.getShard(nsParam{"a", "c"}).lockMap[nsParam{"a", "c"}]
 	if ok && !testCase.shouldPass {
 		t.Errorf("Lock map not found.")
 	}
@@ -414,3 +416,64 @@ func TestNamespaceForceUnlockTest(t *testing.T) {
 	// Clean up lock.
 	nsMutex.ForceUnlock("bucket", "object")
 }
+
+// Tests that a read lock blocks a write lock on the same resource and
+// not on a resource in another shard.
+func TestNamespaceLockShards(t *testing.T) {
+	rlock := nsMutex.NewNSLock("bucket", "object")
+	rlock.RLock()
+
+	locked := make(chan struct{})
+	go func() {
+		wlock := nsMutex.NewNSLock("bucket", "object")
+		wlock.Lock()
+		close(locked)
+		wlock.Unlock()
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("Write lock acquired while read lock is held.")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// A lock on another resource should not block.
+	other := nsMutex.NewNSLock("bucket", "other-object")
+	otherLocked := make(chan struct{})
+	go func() {
+		other.Lock()
+		close(otherLocked)
+		other.Unlock()
+	}()
+	select {
+	case <-otherLocked:
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Lock on another resource blocked.")
+	}
+
+	rlock.RUnlock()
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Fatal("Write lock not acquired after read lock is released.")
+	}
+}
+
+// Benchmarks locking different resources from 100 goroutines.
+func BenchmarkLockNS(b *testing.B) {
+	n := newNSLockMap(false)
+	var counter uint64
+	parallelism := 100 / runtime.GOMAXPROCS(0)
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	b.SetParallelism(parallelism)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		object := "object-" + strconv.FormatUint(atomic.AddUint64(&counter, 1), 10)
+		for pb.Next() {
+			n.Lock("bucket", object, "")
+			n.Unlock("bucket", object, "")
+		}
+	})
+}
@@ -431,7 +431,8 @@ func testListObjects(obj ObjectLayer, instanceType string, t TestErrHandler) {
 		{"volatile-bucket-2", "", "", "", 0, ListObjectsInfo{}, BucketNotFound{Bucket: "volatile-bucket-2"}, false},
 		{"volatile-bucket-3", "", "", "", 0, ListObjectsInfo{}, BucketNotFound{Bucket: "volatile-bucket-3"}, false},
 		// Valid, existing bucket, but sending invalid delimeter values (9-10).
-		// Empty string < "" > and forward slash < / > are the ony two valid arguments for delimeter.
+		// Empty string < "" > and forward slash < / > are the ony two valid arguments for delimeter,
+		// except for FS which supports any delimiter.
 		{"test-bucket-list-object", "", "", "*", 0, ListObjectsInfo{}, fmt.Errorf("delimiter '%s' is not supported", "*"), false},
 		{"test-bucket-list-object", "", "", "-", 0, ListObjectsInfo{}, fmt.Errorf("delimiter '%s' is not supported", "-"), false},
 		// Testing for failure cases with both perfix and marker (11).
@@ -520,6 +521,9 @@ func testListObjects(obj ObjectLayer, instanceType string, t TestErrHandler) {
 	}
 
 	for i, testCase := range testCases {
+		if instanceType == FSTestStr && testCase.delimeter != "" && testCase.delimeter != slashSeparator {
+			testCase.shouldPass = true
+		}
 		result, err := obj.ListObjects(testCase.bucketName, testCase.prefix, testCase.marker, testCase.delimeter, testCase.maxKeys)
 		if err != nil && testCase.shouldPass {
 			t.Errorf("Test %d: %s:  Expected to pass, but failed with: <ERROR> %s", i+1, instanceType, err.Error())
@@ -569,6 +573,113 @@ func testListObjects(obj ObjectLayer, instanceType string, t TestErrHandler) {
 	}
 }
 
+// Wrapper for calling ListObjects truncation tests for both XL multiple disks and single node setup.
+func TestListObjectsTruncated(t *testing.T) {
+	ExecObjectLayerTest(t, testListObjectsTruncated)
+}
+
+// Tests the last page is not truncated, also when it is exactly maxKeys long.
+func testListObjectsTruncated(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	for i := 0; i < 10; i++ {
+		object := fmt.Sprintf("obj-%d", i)
+		if _, err := obj.PutObject(bucket, object, int64(len(object)), bytes.NewBufferString(object), nil, ""); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+
+	testCases := []struct {
+		marker      string
+		delimiter   string
+		maxKeys     int
+		objects     int
+		isTruncated bool
+	}{
+		{"", "", 10, 10, false},
+		{"", "/", 10, 10, false},
+		{"", "", 11, 10, false},
+		{"", "", 9, 9, true},
+		{"obj-8", "", 1, 1, false},
+		{"obj-4", "/", 5, 5, false},
+		{"obj-4", "/", 4, 4, true},
+	}
+	for i, testCase := range testCases {
+		result, err := obj.ListObjects(bucket, "", testCase.marker, testCase.delimiter, testCase.maxKeys)
+		if err != nil {
+			t.Fatalf("%s: Test %d: %v", instanceType, i+1, err)
+		}
+		if len(result.Objects) != testCase.objects {
+			t.Errorf("%s: Test %d: Expected %d objects, got %d", instanceType, i+1, testCase.objects, len(result.Objects))
+		}
+		if result.IsTruncated != testCase.isTruncated {
+			t.Errorf("%s: Test %d: Expected IsTruncated %v, got %v", instanceType, i+1, testCase.isTruncated, result.IsTruncated)
+		}
+	}
+}
+
+// Wrapper for calling ListObjects common prefixes tests for both XL multiple disks and single node setup.
+func TestListObjectsCommonPrefixes(t *testing.T) {
+	ExecObjectLayerTest(t, testListObjectsCommonPrefixes)
+}
+
+// Tests every common prefix is listed once, also across pages.
+func testListObjectsCommonPrefixes(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	// 100 objects sharing 10 common prefixes.
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 10; j++ {
+			object := fmt.Sprintf("a/dir-%d/obj-%d.txt", i, j)
+			if _, err := obj.PutObject(bucket, object, int64(len(object)), bytes.NewBufferString(object), nil, ""); err != nil {
+				t.Fatalf("%s: %v", instanceType, err)
+			}
+		}
+	}
+
+	result, err := obj.ListObjects(bucket, "a/", "", "/", 1000)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if len(result.Prefixes) != 10 || len(result.Objects) != 0 {
+		t.Fatalf("%s: Expected 10 prefixes, got %d prefixes and %d objects", instanceType, len(result.Prefixes), len(result.Objects))
+	}
+	for i, prefix := range result.Prefixes {
+		if expected := fmt.Sprintf("a/dir-%d/", i); prefix != expected {
+			t.Errorf("%s: Expected prefix %s, got %s", instanceType, expected, prefix)
+		}
+	}
+
+	// Paged listing.
+	var prefixes []string
+	marker := ""
+	for {
+		result, err = obj.ListObjects(bucket, "a/", marker, "/", 3)
+		if err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+		prefixes = append(prefixes, result.Prefixes...)
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextMarker
+	}
+	seen := make(map[string]bool)
+	for _, prefix := range prefixes {
+		if seen[prefix] {
+			t.Fatalf("%s: Prefix %s listed twice", instanceType, prefix)
+		}
+		seen[prefix] = true
+	}
+	if len(prefixes) != 10 {
+		t.Fatalf("%s: Expected 10 prefixes over all pages, got %d", instanceType, len(prefixes))
+	}
+}
+
 // Initialize FS backend for the benchmark.
 func initFSObjectsB(disk string, t *testing.B) (obj ObjectLayer) {
 	endPoints, err := parseStorageEndpoints([]string{disk})
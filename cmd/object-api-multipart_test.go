@@ -19,6 +19,7 @@ package cmd
 import (
 	"bytes"
 	"fmt"
+	"path"
 	"strings"
 	"testing"
 
@@ -63,6 +64,11 @@ func testObjectNewMultipartUpload(obj ObjectLayer, instanceType string, t TestEr
 		t.Fatalf("%s: Expected to fail since object name is invalid.", instanceType)
 	}
 
+	_, err = obj.NewMultipartUpload(bucket, "dir/$multiparts", nil)
+	if _, ok := errorCause(err).(ObjectNameInvalid); !ok {
+		t.Fatalf("%s: Expected to fail since object name is reserved, got %v.", instanceType, err)
+	}
+
 	uploadID, err := obj.NewMultipartUpload(bucket, object, nil)
 	if err != nil {
 		t.Fatalf("%s : %s", instanceType, err.Error())
@@ -126,6 +132,154 @@ func testObjectAbortMultipartUpload(obj ObjectLayer, instanceType string, t Test
 	}
 }
 
+// Wrapper for calling multipart upload progress tests for both XL multiple disks and single node setup.
+func TestMultipartUploadProgress(t *testing.T) {
+	ExecObjectLayerTest(t, testMultipartUploadProgress)
+}
+
+// Tests the committed size of a multipart upload grows with each part.
+func testMultipartUploadProgress(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "minio-bucket"
+	object := "minio-object"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	partSizes := []int64{5 * 1024, 3 * 1024, 1024}
+	uploadID, err := obj.NewMultipartUpload(bucket, object, map[string]string{uploadSizeMetaKey: "9216"})
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+
+	upload, err := getMultipartUpload(obj, bucket, object, uploadID)
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	if upload.Size != 9216 || upload.CommittedSize != 0 || upload.Parts != 0 {
+		t.Fatalf("%s: Unexpected progress of a new upload %+v", instanceType, upload)
+	}
+
+	var committed int64
+	for i, size := range partSizes {
+		data := bytes.Repeat([]byte("a"), int(size))
+		if _, err = obj.PutObjectPart(bucket, object, uploadID, i+1, size, bytes.NewReader(data), "", ""); err != nil {
+			t.Fatalf("%s : %s", instanceType, err.Error())
+		}
+		committed += size
+		upload, err = getMultipartUpload(obj, bucket, object, uploadID)
+		if err != nil {
+			t.Fatalf("%s : %s", instanceType, err.Error())
+		}
+		if upload.CommittedSize != committed || upload.Parts != i+1 || upload.Size != 9216 {
+			t.Errorf("%s: Part %d: Expected %d bytes in %d parts, got %+v", instanceType, i+1, committed, i+1, upload)
+		}
+	}
+
+	// Committed size is listed with the uploads.
+	result, err := obj.ListMultipartUploads(bucket, "", "", "", "", maxUploadsList)
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	if len(result.Uploads) != 1 || result.Uploads[0].CommittedSize != committed {
+		t.Errorf("%s: Expected one upload with %d bytes committed, got %+v", instanceType, committed, result.Uploads)
+	}
+
+	if _, err = getMultipartUpload(obj, bucket, object, "non-existent-upload-id"); !isSameType(errorCause(err), InvalidUploadID{}) {
+		t.Errorf("%s: Expected InvalidUploadID, got %v", instanceType, err)
+	}
+}
+
+// Wrapper for calling multipart upload recovery tests for both XL multiple disks and single node setup.
+func TestMultipartUploadRecovery(t *testing.T) {
+	ExecObjectLayerTest(t, testMultipartUploadRecovery)
+}
+
+// Tests multipart uploads left incomplete by a crash are listed after a
+// restart and can be aborted, partially written parts are purged.
+func testMultipartUploadRecovery(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "minio-bucket"
+	object := "minio-object"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	uploadID, err := obj.NewMultipartUpload(bucket, object, nil)
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	data := bytes.Repeat([]byte("a"), 1024)
+	if _, err = obj.PutObjectPart(bucket, object, uploadID, 1, int64(len(data)), bytes.NewReader(data), "", ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+
+	var storageDisks []StorageAPI
+	switch instanceType {
+	case FSTestStr:
+		storageDisks = []StorageAPI{obj.(fsObjects).storage}
+	case XLTestStr:
+		storageDisks = obj.(*xlObjects).storageDisks
+	}
+
+	// Crash while the second part is written, parts are staged in the
+	// tmp volume before they are renamed into the upload.
+	partialPart := "partial-part.2"
+	for _, disk := range storageDisks {
+		if err = disk.AppendFile(minioMetaTmpBucket, partialPart, data[:100]); err != nil {
+			t.Fatalf("%s : %s", instanceType, err.Error())
+		}
+	}
+
+	// Restart.
+	if err = houseKeeping(storageDisks); err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	switch instanceType {
+	case FSTestStr:
+		obj, err = newFSObjects(storageDisks[0])
+	case XLTestStr:
+		obj, err = newXLObjects(storageDisks)
+	}
+	if err != nil {
+		t.Fatalf("%s : Unable to restart object layer: %s", instanceType, err.Error())
+	}
+	for _, disk := range storageDisks {
+		if _, err = disk.StatFile(minioMetaTmpBucket, partialPart); err != errFileNotFound {
+			t.Fatalf("%s : Expected partial part to be purged, got %v", instanceType, err)
+		}
+	}
+
+	// The upload and its complete part are listed.
+	uploads, err := obj.ListMultipartUploads(bucket, "", "", "", "", 1000)
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	if len(uploads.Uploads) != 1 || uploads.Uploads[0].UploadID != uploadID || uploads.Uploads[0].Object != object {
+		t.Fatalf("%s : Expected upload %s to be listed, got %#v", instanceType, uploadID, uploads.Uploads)
+	}
+	parts, err := obj.ListObjectParts(bucket, object, uploadID, 0, 1000)
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	if len(parts.Parts) != 1 || parts.Parts[0].PartNumber != 1 || parts.Parts[0].Size != int64(len(data)) {
+		t.Fatalf("%s : Unexpected parts %#v", instanceType, parts.Parts)
+	}
+
+	// Aborting cleans up the upload.
+	if err = obj.AbortMultipartUpload(bucket, object, uploadID); err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	uploads, err = obj.ListMultipartUploads(bucket, "", "", "", "", 1000)
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	if len(uploads.Uploads) != 0 {
+		t.Fatalf("%s : Expected no uploads after abort, got %#v", instanceType, uploads.Uploads)
+	}
+	for _, disk := range storageDisks {
+		if _, err = disk.ListDir(minioMetaMultipartBucket, path.Join(bucket, object, uploadID)); err != errFileNotFound {
+			t.Fatalf("%s : Expected upload to be removed from disk, got %v", instanceType, err)
+		}
+	}
+}
+
 // Wrapper for calling isUploadIDExists tests for both XL multiple disks and single node setup.
 func TestObjectAPIIsUploadIDExists(t *testing.T) {
 	ExecObjectLayerTest(t, testObjectAPIIsUploadIDExists)
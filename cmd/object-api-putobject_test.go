@@ -20,6 +20,8 @@ import (
 	"bytes"
 	"crypto/md5"
 	"encoding/hex"
+	"errors"
+	"io"
 	"io/ioutil"
 	"os"
 	"path"
@@ -152,6 +154,15 @@ func testObjectAPIPutObject(obj ObjectLayer, instanceType string, t TestErrHandl
 		// Test case 30
 		// valid data with X-Amz-Meta- meta
 		{bucket, object, data, map[string]string{"X-Amz-Meta-AppID": "a42"}, "", int64(len(data)), getMD5Hash(data), nil},
+
+		// Test case 31-32.
+		// Object names in the reserved "$" namespace.
+		{bucket, "$tmpobject", data, nil, "", int64(len(data)), "", ObjectNameInvalid{Bucket: bucket, Object: "$tmpobject"}},
+		{bucket, "dir/$x/obj", data, nil, "", int64(len(data)), "", ObjectNameInvalid{Bucket: bucket, Object: "dir/$x/obj"}},
+
+		// Test case 33.
+		// "$" only reserved as a prefix of an element.
+		{bucket, "dir/a$b", data, nil, "", int64(len(data)), getMD5Hash(data), nil},
 	}
 
 	for i, testCase := range testCases {
@@ -327,6 +338,65 @@ func testObjectAPIPutObjectStaleFiles(obj ObjectLayer, instanceType string, disk
 	}
 }
 
+// crashReader - fails every read, as if the writer crashed.
+type crashReader struct{}
+
+func (crashReader) Read(p []byte) (int, error) {
+	return 0, errors.New("simulated crash")
+}
+
+// Wrapper for calling interrupted PutObject tests for both XL multiple disks and single node setup.
+func TestObjectAPIPutObjectInterrupted(t *testing.T) {
+	ExecObjectLayerStaleFilesTest(t, testObjectAPIPutObjectInterrupted)
+}
+
+// Tests an upload interrupted mid-write is never visible, neither as a
+// new object nor over an existing one.
+func testObjectAPIPutObjectInterrupted(obj ObjectLayer, instanceType string, disks []string, t *testing.T) {
+	bucket := "minio-bucket"
+	object := "minio-object"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+
+	data := bytes.Repeat([]byte("a"), 2*1024*1024)
+	interrupted := func() io.Reader {
+		return io.MultiReader(bytes.NewReader(data[:len(data)/2]), crashReader{})
+	}
+
+	// New object.
+	if _, err := obj.PutObject(bucket, object, int64(len(data)), interrupted(), nil, ""); err == nil {
+		t.Fatalf("%s: Expected interrupted upload to fail", instanceType)
+	}
+	if _, err := obj.GetObjectInfo(bucket, object); !isSameType(errorCause(err), ObjectNotFound{}) {
+		t.Fatalf("%s: Expected ObjectNotFound after interrupted upload, got %v", instanceType, err)
+	}
+
+	// Existing object.
+	oldData := []byte("hello, world")
+	if _, err := obj.PutObject(bucket, object, int64(len(oldData)), bytes.NewReader(oldData), nil, ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	if _, err := obj.PutObject(bucket, object, int64(len(data)), interrupted(), nil, ""); err == nil {
+		t.Fatalf("%s: Expected interrupted upload to fail", instanceType)
+	}
+	var buffer bytes.Buffer
+	if err := obj.GetObject(bucket, object, 0, int64(len(oldData)), &buffer); err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	if !bytes.Equal(buffer.Bytes(), oldData) {
+		t.Errorf("%s: Expected the existing object to be intact, got %q", instanceType, buffer.Bytes())
+	}
+
+	// Partially written data is not left behind.
+	for _, disk := range disks {
+		tmpMetaDir := path.Join(disk, minioMetaTmpBucket)
+		if !isDirEmpty(tmpMetaDir) {
+			t.Fatalf("%s: expected: empty, got: non-empty", minioMetaTmpBucket)
+		}
+	}
+}
+
 // Wrapper for calling Multipart PutObject tests for both XL multiple disks and single node setup.
 func TestObjectAPIMultipartPutObjectStaleFiles(t *testing.T) {
 	if runtime.GOOS == "windows" {
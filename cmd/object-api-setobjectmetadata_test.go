@@ -0,0 +1,131 @@
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Wrapper for calling SetObjectMetadata tests for both XL multiple disks and single node setup.
+func TestSetObjectMetadata(t *testing.T) {
+	ExecObjectLayerTest(t, testSetObjectMetadata)
+}
+
+// Testing SetObjectMetadata().
+func testSetObjectMetadata(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucketName := "test-setobjectmetadata"
+	objectName := "object"
+	// This bucket is used for testing SetObjectMetadata operations.
+	err := obj.MakeBucket(bucketName)
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	data := []byte("setobjectmetadata")
+	metadata := map[string]string{
+		"content-type":     "application/octet-stream",
+		"X-Amz-Meta-Hello": "world",
+	}
+	sha256sum := ""
+	objInfo, err := obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), metadata, sha256sum)
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+
+	testCases := []struct {
+		bucketName string
+		objectName string
+		err        error
+	}{
+		// Test case with invalid bucket name (Test number 1).
+		{".test", objectName, BucketNameInvalid{Bucket: ".test"}},
+		// Test case with non-existing bucket name (Test number 2).
+		{"abcdefgh", objectName, BucketNotFound{Bucket: "abcdefgh"}},
+		// Test case with invalid object name (Test number 3).
+		{bucketName, "", ObjectNameInvalid{Bucket: bucketName, Object: ""}},
+		// Test case with non-existing object name (Test number 4).
+		{bucketName, "Africa", ObjectNotFound{Bucket: bucketName, Object: "Africa"}},
+	}
+	for i, testCase := range testCases {
+		_, err = obj.SetObjectMetadata(testCase.bucketName, testCase.objectName, nil)
+		if err == nil {
+			t.Fatalf("Test %d: %s: Expected to fail with \"%s\", but passed instead", i+1, instanceType, testCase.err)
+		}
+		if err.Error() != testCase.err.Error() {
+			t.Errorf("Test %d: %s: Expected to fail with error \"%s\", but instead failed with error \"%s\" instead", i+1, instanceType, testCase.err, err)
+		}
+	}
+
+	newMetadata := map[string]string{
+		"content-type":        "text/plain",
+		"cache-control":       "no-cache",
+		"content-disposition": "attachment",
+		"X-Amz-Meta-Foo":      "bar",
+	}
+	newObjInfo, err := obj.SetObjectMetadata(bucketName, objectName, newMetadata)
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	if newObjInfo.MD5Sum != objInfo.MD5Sum {
+		t.Errorf("%s: Expected md5sum to be \"%s\", but found \"%s\"", instanceType, objInfo.MD5Sum, newObjInfo.MD5Sum)
+	}
+	if newObjInfo.Size != objInfo.Size {
+		t.Errorf("%s: Expected size to be %d, but found %d", instanceType, objInfo.Size, newObjInfo.Size)
+	}
+
+	// Verify the metadata is replaced as seen by GetObjectInfo.
+	newObjInfo, err = obj.GetObjectInfo(bucketName, objectName)
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	if newObjInfo.ContentType != "text/plain" {
+		t.Errorf("%s: Expected content-type to be \"text/plain\", but found \"%s\"", instanceType, newObjInfo.ContentType)
+	}
+	for k, v := range newMetadata {
+		if newObjInfo.UserDefined[k] != v {
+			t.Errorf("%s: Expected metadata \"%s\" to be \"%s\", but found \"%s\"", instanceType, k, v, newObjInfo.UserDefined[k])
+		}
+	}
+	if _, ok := newObjInfo.UserDefined["X-Amz-Meta-Hello"]; ok {
+		t.Errorf("%s: Expected metadata \"X-Amz-Meta-Hello\" to be removed", instanceType)
+	}
+	if !newObjInfo.ModTime.After(objInfo.ModTime) {
+		t.Errorf("%s: Expected modification time to be after \"%s\", but found \"%s\"", instanceType, objInfo.ModTime, newObjInfo.ModTime)
+	}
+
+	// Verify the object data is untouched.
+	var buffer bytes.Buffer
+	if err = obj.GetObject(bucketName, objectName, 0, int64(len(data)), &buffer); err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	if !bytes.Equal(buffer.Bytes(), data) {
+		t.Errorf("%s: Data Mismatch: Data fetched back after updating metadata doesn't match the original one.", instanceType)
+	}
+
+	// Verify the content-type is guessed from the extension when not set.
+	textObjectName := "object.txt"
+	if _, err = obj.PutObject(bucketName, textObjectName, int64(len(data)), bytes.NewReader(data), metadata, sha256sum); err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	newObjInfo, err = obj.SetObjectMetadata(bucketName, textObjectName, map[string]string{"cache-control": "no-cache"})
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	if newObjInfo.ContentType != "text/plain" {
+		t.Errorf("%s: Expected content-type to be \"text/plain\", but found \"%s\"", instanceType, newObjInfo.ContentType)
+	}
+}
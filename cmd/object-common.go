@@ -22,6 +22,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	humanize "github.com/dustin/go-humanize"
 )
@@ -35,6 +36,12 @@ const (
 
 	// Buckets meta prefix.
 	bucketMetaPrefix = "buckets"
+
+	// Default age of temporary entries purged while the server runs.
+	globalDefaultTmpCleanupAge = time.Hour
+
+	// Default interval between purges of temporary entries.
+	globalDefaultTmpCleanupInterval = 30 * time.Minute
 )
 
 // Global object layer mutex, used for safely updating object layer.
@@ -94,6 +101,88 @@ func houseKeeping(storageDisks []StorageAPI) error {
 	return nil
 }
 
+// tmpDirEntry - a directory in the tmp volume of a disk.
+type tmpDirEntry struct {
+	disk StorageAPI
+	path string
+}
+
+// purgeStaleTmpEntries - deletes the temporary entries of the local disks
+// which were last modified before olderThan, left behind by uploads which
+// failed to clean up after themselves. Directories carry no modification
+// time, the empty directories found are returned and deleted by the next
+// purge if they are still listed in emptyDirs.
+func purgeStaleTmpEntries(storageDisks []StorageAPI, olderThan time.Time, emptyDirs map[tmpDirEntry]bool) map[tmpDirEntry]bool {
+	foundEmptyDirs := make(map[tmpDirEntry]bool)
+	var delFunc func(disk StorageAPI, entryPath string) error
+	delFunc = func(disk StorageAPI, entryPath string) error {
+		if !strings.HasSuffix(entryPath, slashSeparator) {
+			// Append files are owned by their background append.
+			if strings.HasSuffix(entryPath, bgAppendFileSuffix) {
+				return nil
+			}
+			fi, err := disk.StatFile(minioMetaTmpBucket, entryPath)
+			if err != nil {
+				return err
+			}
+			if !fi.ModTime.Before(olderThan) {
+				return nil
+			}
+			return disk.DeleteFile(minioMetaTmpBucket, entryPath)
+		}
+		entries, err := disk.ListDir(minioMetaTmpBucket, entryPath)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 && entryPath != retainSlash("") {
+			dir := tmpDirEntry{disk, entryPath}
+			if emptyDirs[dir] {
+				return disk.DeleteFile(minioMetaTmpBucket, entryPath)
+			}
+			foundEmptyDirs[dir] = true
+		}
+		for _, entry := range entries {
+			// Entries deleted since they were listed are ignored.
+			if err = delFunc(disk, pathJoin(entryPath, entry)); err != nil && err != errFileNotFound {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, disk := range storageDisks {
+		if disk == nil {
+			continue
+		}
+		if _, ok := disk.(*networkStorage); ok {
+			// Remote disks are purged by their own server.
+			continue
+		}
+		err := delFunc(disk, retainSlash(""))
+		if err != nil && !isErrIgnored(err, errDiskNotFound, errVolumeNotFound, errFileNotFound) {
+			errorIf(err, "Unable to purge stale temporary files.")
+		}
+	}
+	return foundEmptyDirs
+}
+
+// startTmpCleanup - purges temporary entries older than age from the local
+// disks every interval, until doneCh is closed.
+func startTmpCleanup(storageDisks []StorageAPI, age, interval time.Duration, doneCh <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var emptyDirs map[tmpDirEntry]bool
+		for {
+			select {
+			case <-ticker.C:
+				emptyDirs = purgeStaleTmpEntries(storageDisks, time.Now().UTC().Add(-age), emptyDirs)
+			case <-doneCh:
+				return
+			}
+		}
+	}()
+}
+
 // Check if a network path is local to this node.
 func isLocalStorage(ep *url.URL) bool {
 	if ep.Host == "" {
@@ -17,9 +17,13 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
 	"runtime"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestHouseKeeping(t *testing.T) {
@@ -99,6 +103,62 @@ func TestHouseKeeping(t *testing.T) {
 	}
 }
 
+// Tests that only temporary entries older than the cutoff are purged.
+func TestPurgeStaleTmpEntries(t *testing.T) {
+	disk, diskPath, err := newPosixTestSetup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(diskPath)
+
+	if err = disk.MakeVol(minioMetaBucket); err != nil {
+		t.Fatal(err)
+	}
+	if err = disk.MakeVol(minioMetaTmpBucket); err != nil {
+		t.Fatal(err)
+	}
+	// Entries left by a crashed or failed upload two hours ago, and
+	// entries of uploads in progress.
+	staleTime := time.Now().Add(-2 * time.Hour)
+	appendFile := getAppendFilePath("bucket", "upload-id")
+	for _, entry := range []string{"stale", "stale-dir/part.1", "fresh", "fresh-dir/part.1", appendFile} {
+		if err = disk.AppendFile(minioMetaTmpBucket, entry, []byte("data")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, entry := range []string{"stale", "stale-dir/part.1", appendFile} {
+		if err = os.Chtimes(filepath.Join(diskPath, minioMetaTmpBucket, entry), staleTime, staleTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Directory left empty by a crashed upload.
+	if err = os.MkdirAll(filepath.Join(diskPath, minioMetaTmpBucket, "empty-dir"), 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	// Append files of uploads in progress are not purged, empty
+	// directories only on the next purge.
+	emptyDirs := purgeStaleTmpEntries([]StorageAPI{nil, disk}, time.Now().Add(-time.Hour), nil)
+
+	entries, err := disk.ListDir(minioMetaTmpBucket, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{appendFile, "empty-dir/", "fresh", "fresh-dir/"}; !reflect.DeepEqual(entries, expected) {
+		t.Fatalf("Expected %v left after purge, got %v", expected, entries)
+	}
+
+	purgeStaleTmpEntries([]StorageAPI{nil, disk}, time.Now().Add(-time.Hour), emptyDirs)
+
+	entries, err = disk.ListDir(minioMetaTmpBucket, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{appendFile, "fresh", "fresh-dir/"}; !reflect.DeepEqual(entries, expected) {
+		t.Fatalf("Expected %v left after the second purge, got %v", expected, entries)
+	}
+}
+
 // Test getPath() - the path that needs to be passed to newPosix()
 func TestGetPath(t *testing.T) {
 	globalMinioHost = ""
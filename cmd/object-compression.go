@@ -0,0 +1,166 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// Only supported server side compression algorithm.
+const compressionAlgorithmGzip = "gzip"
+
+// Metadata keys saved along with compressed objects, they are internal
+// and never returned in responses.
+const (
+	compressionMetaKey     = "X-Minio-Internal-Compression"
+	compressionSizeMetaKey = "X-Minio-Internal-Compression-Size"
+	compressionMD5MetaKey  = "X-Minio-Internal-Compression-Md5"
+)
+
+// isCompressibleContentType - returns true if objects of the content
+// type are compressed when server side compression is enabled.
+func isCompressibleContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	if strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/javascript":
+		return true
+	}
+	return false
+}
+
+// isObjectCompressed - returns true if the object data is compressed.
+func isObjectCompressed(objInfo ObjectInfo) bool {
+	_, ok := objInfo.UserDefined[compressionMetaKey]
+	return ok
+}
+
+// getDecompressedSize - returns the size of the decompressed data of a
+// compressed object.
+func getDecompressedSize(objInfo ObjectInfo) (int64, error) {
+	if algorithm := objInfo.UserDefined[compressionMetaKey]; algorithm != compressionAlgorithmGzip {
+		return 0, ObjectTampered{}
+	}
+	size, err := strconv.ParseInt(objInfo.UserDefined[compressionSizeMetaKey], 10, 64)
+	if err != nil || size < 0 {
+		return 0, ObjectTampered{}
+	}
+	return size, nil
+}
+
+// gzipCompressReader - compresses the data read from src. Errors are
+// not traced here, the object layer traces them.
+type gzipCompressReader struct {
+	pr *io.PipeReader
+}
+
+// newGzipCompressReader - returns a reader of the compressed data of
+// size bytes read from src, the data is verified against the checksums
+// of verifier and its MD5 sum is saved in metadata before the end of the
+// compressed data is returned. Object layers save metadata only once all
+// data is read. Close should be called once the reader is not used anymore.
+func newGzipCompressReader(src io.Reader, size int64, verifier *plaintextVerifier, metadata map[string]string) *gzipCompressReader {
+	pr, pw := io.Pipe()
+	go func() {
+		gw := gzip.NewWriter(pw)
+		n, err := io.Copy(gw, io.TeeReader(io.LimitReader(src, size), verifier))
+		if err == nil && n != size {
+			err = IncompleteBody{}
+		}
+		if err == nil {
+			err = verifier.verify()
+		}
+		if err == nil {
+			metadata[compressionMD5MetaKey] = verifier.md5Hex()
+			err = gw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return &gzipCompressReader{pr: pr}
+}
+
+func (r *gzipCompressReader) Read(p []byte) (int, error) {
+	return r.pr.Read(p)
+}
+
+// Close - stops compressing, unblocking the compressing go-routine.
+func (r *gzipCompressReader) Close() error {
+	return r.pr.Close()
+}
+
+// gzipDecompressWriter - decompresses the compressed data written to
+// it, writing a range of the decompressed data to w.
+type gzipDecompressWriter struct {
+	pw     *io.PipeWriter
+	doneCh chan error
+}
+
+// newGzipDecompressWriter - returns a writer of the compressed data of
+// an object, writing length bytes at offset of the decompressed data to
+// w. Close should be called once all the data was written.
+func newGzipDecompressWriter(w io.Writer, offset, length int64) *gzipDecompressWriter {
+	pr, pw := io.Pipe()
+	doneCh := make(chan error, 1)
+	go func() {
+		err := decompressRange(pr, w, offset, length)
+		if err == nil {
+			// Drain the compressed data past the range.
+			_, err = io.Copy(ioutil.Discard, pr)
+		}
+		pr.CloseWithError(err)
+		doneCh <- err
+	}()
+	return &gzipDecompressWriter{pw: pw, doneCh: doneCh}
+}
+
+// decompressRange - writes length bytes at offset of the decompressed
+// data read from r to w.
+func decompressRange(r io.Reader, w io.Writer, offset, length int64) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return ObjectTampered{}
+	}
+	if _, err = io.CopyN(ioutil.Discard, gr, offset); err != nil {
+		return ObjectTampered{}
+	}
+	n, err := io.CopyN(w, gr, length)
+	if n != length && (err == io.EOF || err == io.ErrUnexpectedEOF || err == gzip.ErrChecksum) {
+		return ObjectTampered{}
+	}
+	return err
+}
+
+func (d *gzipDecompressWriter) Write(p []byte) (int, error) {
+	return d.pw.Write(p)
+}
+
+// Close - returns an error if the decompressed range could not be
+// written as a whole.
+func (d *gzipDecompressWriter) Close() error {
+	d.pw.Close()
+	return <-d.doneCh
+}
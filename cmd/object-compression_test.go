@@ -0,0 +1,109 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"testing"
+)
+
+// Tests detection of compressible content types.
+func TestIsCompressibleContentType(t *testing.T) {
+	testCases := []struct {
+		contentType  string
+		compressible bool
+	}{
+		{"text/plain", true},
+		{"text/csv; charset=utf-8", true},
+		{"application/json", true},
+		{"Application/JSON", true},
+		{"application/xml", true},
+		{"application/javascript", true},
+		{"application/octet-stream", false},
+		{"image/png", false},
+		{"application/gzip", false},
+		{"", false},
+		{"text/", false},
+	}
+	for i, testCase := range testCases {
+		if compressible := isCompressibleContentType(testCase.contentType); compressible != testCase.compressible {
+			t.Errorf("Test %d: expected %q compressible to be %v, got %v", i+1, testCase.contentType, testCase.compressible, compressible)
+		}
+	}
+}
+
+// Tests compressing data and decompressing ranges of it.
+func TestGzipCompressDecompress(t *testing.T) {
+	data := bytes.Repeat([]byte("compressible text\n"), 10*1024)
+	md5Sum := md5.Sum(data)
+
+	var compressed bytes.Buffer
+	metadata := make(map[string]string)
+	compressor := newGzipCompressReader(bytes.NewReader(data), int64(len(data)), newPlaintextVerifier(hex.EncodeToString(md5Sum[:]), "", ""), metadata)
+	if _, err := compressed.ReadFrom(compressor); err != nil {
+		t.Fatal(err)
+	}
+	compressor.Close()
+	if metadata[compressionMD5MetaKey] != hex.EncodeToString(md5Sum[:]) {
+		t.Fatalf("Expected the MD5 sum of the data to be saved, got %q", metadata[compressionMD5MetaKey])
+	}
+	if compressed.Len() >= len(data)/2 {
+		t.Fatalf("Expected data to be compressed, got %d bytes out of %d", compressed.Len(), len(data))
+	}
+
+	testCases := []struct {
+		offset, length int64
+	}{
+		{0, int64(len(data))},
+		{0, 0},
+		{100, 1000},
+		{int64(len(data)) - 1, 1},
+		{65530, 16},
+	}
+	for i, testCase := range testCases {
+		var decompressed bytes.Buffer
+		decompressor := newGzipDecompressWriter(&decompressed, testCase.offset, testCase.length)
+		if _, err := decompressor.Write(compressed.Bytes()); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if err := decompressor.Close(); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if !bytes.Equal(decompressed.Bytes(), data[testCase.offset:testCase.offset+testCase.length]) {
+			t.Errorf("Test %d: unexpected decompressed range", i+1)
+		}
+	}
+
+	// Truncated compressed data is detected.
+	decompressor := newGzipDecompressWriter(&bytes.Buffer{}, 0, int64(len(data)))
+	decompressor.Write(compressed.Bytes()[:compressed.Len()/2])
+	if err := decompressor.Close(); err != (ObjectTampered{}) {
+		t.Errorf("Expected %v, got %v", ObjectTampered{}, err)
+	}
+
+	// Data not matching the checksums and short data are rejected.
+	compressor = newGzipCompressReader(bytes.NewReader(data), int64(len(data)), newPlaintextVerifier(hex.EncodeToString(md5Sum[1:]), "", ""), make(map[string]string))
+	if _, err := compressed.ReadFrom(compressor); !isSameType(err, BadDigest{}) {
+		t.Errorf("Expected BadDigest, got %v", err)
+	}
+	compressor = newGzipCompressReader(bytes.NewReader(data), int64(len(data))+1, newPlaintextVerifier("", "", ""), make(map[string]string))
+	if _, err := compressed.ReadFrom(compressor); !isSameType(err, IncompleteBody{}) {
+		t.Errorf("Expected IncompleteBody, got %v", err)
+	}
+}
@@ -16,7 +16,11 @@
 
 package cmd
 
-import "time"
+import (
+	"time"
+
+	"github.com/minio/minio/pkg/disk"
+)
 
 // BackendType - represents different backend types.
 type BackendType int
@@ -48,6 +52,30 @@ type StorageInfo struct {
 		ReadQuorum   int // Minimum disks required for successful read operations.
 		WriteQuorum  int // Minimum disks required for successful write operations.
 	}
+	// Capacity of each online disk.
+	Disks []DiskMetrics
+}
+
+// DiskMetrics - represents capacity and inode usage of a single disk.
+type DiskMetrics struct {
+	Path       string
+	TotalBytes int64
+	UsedBytes  int64
+	FreeBytes  int64
+	InodeTotal int64
+	InodeFree  int64
+}
+
+// newDiskMetrics - converts the disk info of the disk at path.
+func newDiskMetrics(path string, info disk.Info) DiskMetrics {
+	return DiskMetrics{
+		Path:       path,
+		TotalBytes: info.Total,
+		UsedBytes:  info.Total - info.Free,
+		FreeBytes:  info.Free,
+		InodeTotal: info.Files,
+		InodeFree:  info.Ffree,
+	}
 }
 
 // BucketInfo - represents bucket metadata.
@@ -223,6 +251,13 @@ type uploadMetadata struct {
 	Initiated time.Time
 
 	StorageClass string // Not supported yet.
+
+	// Expected size of the object, 0 if it is unknown.
+	Size int64
+
+	// Total size and number of the parts committed so far.
+	CommittedSize int64
+	Parts         int
 }
 
 // completePart - completed part container.
@@ -0,0 +1,506 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Object data is encrypted in chunks of sseChunkSize bytes, each
+	// one authenticated by its own GCM tag of sseTagSize bytes.
+	sseChunkSize = 64 * 1024
+	sseTagSize   = 16
+
+	// Only supported server side encryption algorithm.
+	sseAlgorithmAES256 = "AES256"
+)
+
+// Metadata keys saved along with encrypted objects, all but the
+// algorithm are internal and never returned in responses.
+const (
+	sseAlgorithmMetaKey = "X-Amz-Server-Side-Encryption"
+	sseSealedKeyMetaKey = "X-Minio-Internal-Sse-Sealed-Key"
+	sseNonceMetaKey     = "X-Minio-Internal-Sse-Nonce"
+	sseChunksMetaKey    = "X-Minio-Internal-Sse-Chunks"
+	sseSizeMetaKey      = "X-Minio-Internal-Sse-Size"
+)
+
+// Metadata keys with this prefix are never returned in responses.
+const internalMetaPrefix = "X-Minio-Internal-"
+
+// parseSSEMasterKey - parses the hex encoded 256 bit master key sealing
+// the keys of encrypted objects, an empty key disables encryption.
+func parseSSEMasterKey(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	key, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, errors.New("master key should be 32 bytes long")
+	}
+	return key, nil
+}
+
+// Environment variable carrying the hex encoded master key, the key is
+// never accepted on the command line where it is visible to all users.
+const sseMasterKeyEnv = "MINIO_SSE_MASTER_KEY"
+
+// loadSSEMasterKey - loads the master key from keyFile if set and from
+// the MINIO_SSE_MASTER_KEY environment variable otherwise.
+func loadSSEMasterKey(keyFile string) ([]byte, error) {
+	envKey := os.Getenv(sseMasterKeyEnv)
+	if keyFile == "" {
+		return parseSSEMasterKey(envKey)
+	}
+	if envKey != "" {
+		return nil, errors.New("master key should be set either in " + sseMasterKeyEnv + " or in a file, not both")
+	}
+	buf, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parseSSEMasterKey(strings.TrimSpace(string(buf)))
+	if err == nil && key == nil {
+		return nil, errors.New("master key file is empty")
+	}
+	return key, err
+}
+
+// isSSERequested - returns true if the request asks for the object to
+// be encrypted at rest.
+func isSSERequested(r *http.Request) (bool, APIErrorCode) {
+	algorithm := r.Header.Get(sseAlgorithmMetaKey)
+	if algorithm == "" {
+		return false, ErrNone
+	}
+	if algorithm != sseAlgorithmAES256 {
+		return false, ErrInvalidEncryptionMethod
+	}
+	if globalSSEMasterKey == nil {
+		// Encryption is not configured on this server.
+		return false, ErrNotImplemented
+	}
+	return true, ErrNone
+}
+
+// isObjectEncrypted - returns true if the object is encrypted at rest.
+func isObjectEncrypted(objInfo ObjectInfo) bool {
+	_, ok := objInfo.UserDefined[sseSealedKeyMetaKey]
+	return ok
+}
+
+// sseChunks - number of chunks plaintext of size is encrypted in, empty
+// objects still carry a single authenticated chunk.
+func sseChunks(size int64) int64 {
+	if size <= 0 {
+		return 1
+	}
+	return (size + sseChunkSize - 1) / sseChunkSize
+}
+
+// sseEncryptedSize - size of the encrypted plaintext of size.
+func sseEncryptedSize(size int64) int64 {
+	return size + sseChunks(size)*sseTagSize
+}
+
+// newAESGCM - AES-256-GCM cipher with key.
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealObjectKey - encrypts the key of an object with the master key.
+func sealObjectKey(masterKey, key []byte) (string, error) {
+	aead, err := newAESGCM(masterKey)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, key, nil)), nil
+}
+
+// unsealObjectKey - decrypts the key of an object sealed with the master key.
+func unsealObjectKey(masterKey []byte, sealedKey string) ([]byte, error) {
+	aead, err := newAESGCM(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(sealedKey)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, traceError(ObjectTampered{})
+	}
+	key, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		// Either the sealed key was modified or the master key changed.
+		return nil, traceError(ObjectTampered{})
+	}
+	return key, nil
+}
+
+// sseObject - encryption parameters of an object.
+type sseObject struct {
+	aead   cipher.AEAD
+	prk    []byte // HKDF pseudo random key of the object key.
+	nonce  []byte // Master nonce the chunk nonces are derived from.
+	chunks int64  // Number of encrypted chunks.
+	size   int64  // Size of the plaintext.
+}
+
+// newSSEObjectFromKey - initializes the encryption parameters of an
+// object with its key.
+func newSSEObjectFromKey(key, nonce []byte, size int64) (*sseObject, error) {
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	// HKDF extract step with an all zero salt.
+	extractor := hmac.New(sha256.New, make([]byte, sha256.Size))
+	extractor.Write(key)
+	return &sseObject{
+		aead:   aead,
+		prk:    extractor.Sum(nil),
+		nonce:  nonce,
+		chunks: sseChunks(size),
+		size:   size,
+	}, nil
+}
+
+// newSSEObject - generates a new object key and master nonce for
+// plaintext of size, saves them in metadata with the key sealed by
+// masterKey.
+func newSSEObject(masterKey []byte, size int64, metadata map[string]string) (*sseObject, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	sealedKey, err := sealObjectKey(masterKey, key)
+	if err != nil {
+		return nil, err
+	}
+	sse, err := newSSEObjectFromKey(key, nonce, size)
+	if err != nil {
+		return nil, err
+	}
+	metadata[sseAlgorithmMetaKey] = sseAlgorithmAES256
+	metadata[sseSealedKeyMetaKey] = sealedKey
+	metadata[sseNonceMetaKey] = base64.StdEncoding.EncodeToString(nonce)
+	metadata[sseChunksMetaKey] = strconv.FormatInt(sse.chunks, 10)
+	metadata[sseSizeMetaKey] = strconv.FormatInt(size, 10)
+	return sse, nil
+}
+
+// getSSEObject - returns the encryption parameters saved with an
+// object, nil if the object is not encrypted.
+func getSSEObject(masterKey []byte, objInfo ObjectInfo) (*sseObject, error) {
+	if !isObjectEncrypted(objInfo) {
+		return nil, nil
+	}
+	if masterKey == nil {
+		// Encryption is not configured on this server.
+		return nil, traceError(NotImplemented{})
+	}
+	key, err := unsealObjectKey(masterKey, objInfo.UserDefined[sseSealedKeyMetaKey])
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := base64.StdEncoding.DecodeString(objInfo.UserDefined[sseNonceMetaKey])
+	if err != nil {
+		return nil, traceError(ObjectTampered{})
+	}
+	size, err := strconv.ParseInt(objInfo.UserDefined[sseSizeMetaKey], 10, 64)
+	if err != nil {
+		return nil, traceError(ObjectTampered{})
+	}
+	chunks, err := strconv.ParseInt(objInfo.UserDefined[sseChunksMetaKey], 10, 64)
+	if err != nil {
+		return nil, traceError(ObjectTampered{})
+	}
+	// Saved sizes should be consistent with the stored data.
+	if size < 0 || chunks != sseChunks(size) || objInfo.Size != sseEncryptedSize(size) {
+		return nil, traceError(ObjectTampered{})
+	}
+	return newSSEObjectFromKey(key, nonce, size)
+}
+
+// chunkNonce - nonce of the chunk at index, derived with HKDF-SHA256
+// from the object key and the master nonce, i.e HKDF(key, nonce || index).
+func (s *sseObject) chunkNonce(index int64) []byte {
+	expander := hmac.New(sha256.New, s.prk)
+	expander.Write(s.nonce)
+	var indexBytes [8]byte
+	binary.BigEndian.PutUint64(indexBytes[:], uint64(index))
+	expander.Write(indexBytes[:])
+	// A single expand round is enough for the nonce size.
+	expander.Write([]byte{1})
+	return expander.Sum(nil)[:s.aead.NonceSize()]
+}
+
+// chunkAdditionalData - authenticates if the chunk at index is the
+// last one, so that truncated objects are detected.
+func (s *sseObject) chunkAdditionalData(index int64) []byte {
+	if index == s.chunks-1 {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// encryptedRange - returns the range of encrypted data holding the
+// plaintext range of length bytes at offset.
+func (s *sseObject) encryptedRange(offset, length int64) (encOffset, encLength int64) {
+	firstChunk := offset / sseChunkSize
+	lastChunk := firstChunk
+	if length > 0 {
+		lastChunk = (offset + length - 1) / sseChunkSize
+	}
+	if lastChunk >= s.chunks {
+		lastChunk = s.chunks - 1
+	}
+	encOffset = firstChunk * (sseChunkSize + sseTagSize)
+	encEnd := (lastChunk + 1) * (sseChunkSize + sseTagSize)
+	if encSize := sseEncryptedSize(s.size); encEnd > encSize {
+		encEnd = encSize
+	}
+	return encOffset, encEnd - encOffset
+}
+
+// plaintextVerifier - verifies the checksums sent by the client for
+// plaintext which is not seen by the object layer.
+type plaintextVerifier struct {
+	md5Hash          hash.Hash
+	sha256Hash       hash.Hash
+	expectedMD5      string // Hex encoded, Content-Md5
+	expectedSHA256   string // Hex encoded, X-Amz-Content-Sha256
+	expectedChecksum string // Base64 encoded, x-amz-checksum-sha256
+}
+
+// newPlaintextVerifier - verifier of plaintext against the checksums
+// sent by the client.
+func newPlaintextVerifier(md5Hex, sha256Hex, checksum string) *plaintextVerifier {
+	return &plaintextVerifier{
+		md5Hash:          md5.New(),
+		sha256Hash:       sha256.New(),
+		expectedMD5:      md5Hex,
+		expectedSHA256:   sha256Hex,
+		expectedChecksum: checksum,
+	}
+}
+
+func (v *plaintextVerifier) Write(p []byte) (int, error) {
+	v.md5Hash.Write(p)
+	v.sha256Hash.Write(p)
+	return len(p), nil
+}
+
+// md5Hex - returns the hex encoded MD5 sum of the plaintext written so far.
+func (v *plaintextVerifier) md5Hex() string {
+	return hex.EncodeToString(v.md5Hash.Sum(nil))
+}
+
+// verify - returns an error if any of the checksums does not match.
+func (v *plaintextVerifier) verify() error {
+	if md5Hex := v.md5Hex(); v.expectedMD5 != "" && v.expectedMD5 != md5Hex {
+		return BadDigest{v.expectedMD5, md5Hex}
+	}
+	sha256Sum := v.sha256Hash.Sum(nil)
+	if v.expectedSHA256 != "" && v.expectedSHA256 != hex.EncodeToString(sha256Sum) {
+		return SHA256Mismatch{}
+	}
+	if checksum := base64.StdEncoding.EncodeToString(sha256Sum); v.expectedChecksum != "" && v.expectedChecksum != checksum {
+		return BadChecksum{v.expectedChecksum, checksum}
+	}
+	return nil
+}
+
+// sseEncryptReader - encrypts the plaintext read from src chunk by chunk.
+// Errors are not traced here, the object layer traces them.
+type sseEncryptReader struct {
+	src       io.Reader
+	sse       *sseObject
+	verifier  *plaintextVerifier
+	index     int64  // Index of the next chunk to encrypt.
+	remaining int64  // Plaintext bytes left to read from src.
+	plain     []byte // Plaintext of the current chunk.
+	sealed    []byte // Encrypted current chunk.
+	buf       []byte // Part of the encrypted current chunk left to read.
+}
+
+// newSSEEncryptReader - returns a reader of the encrypted plaintext of
+// size read from src, the plaintext is verified against the checksums
+// of verifier before the last chunk is returned.
+func newSSEEncryptReader(src io.Reader, sse *sseObject, verifier *plaintextVerifier) io.Reader {
+	return &sseEncryptReader{
+		src:       src,
+		sse:       sse,
+		verifier:  verifier,
+		remaining: sse.size,
+		plain:     make([]byte, sseChunkSize),
+		sealed:    make([]byte, 0, sseChunkSize+sseTagSize),
+	}
+}
+
+func (r *sseEncryptReader) Read(p []byte) (n int, err error) {
+	for len(r.buf) == 0 {
+		if r.index == r.sse.chunks {
+			return 0, io.EOF
+		}
+		if err = r.sealChunk(); err != nil {
+			return 0, err
+		}
+	}
+	n = copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// sealChunk - reads and encrypts the next chunk.
+func (r *sseEncryptReader) sealChunk() error {
+	size := r.remaining
+	if size > sseChunkSize {
+		size = sseChunkSize
+	}
+	plain := r.plain[:size]
+	if _, err := io.ReadFull(r.src, plain); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return IncompleteBody{}
+		}
+		return err
+	}
+	r.remaining -= size
+	r.verifier.Write(plain)
+	if r.index == r.sse.chunks-1 {
+		if err := r.verifier.verify(); err != nil {
+			return err
+		}
+	}
+	r.buf = r.sse.aead.Seal(r.sealed[:0], r.sse.chunkNonce(r.index), plain, r.sse.chunkAdditionalData(r.index))
+	r.index++
+	return nil
+}
+
+// sseDecryptWriter - decrypts the encrypted chunks written to it and
+// writes the requested plaintext range to w. Like for sseEncryptReader
+// errors are not traced here.
+type sseDecryptWriter struct {
+	w         io.Writer
+	sse       *sseObject
+	index     int64  // Index of the current chunk.
+	skip      int64  // Plaintext bytes to drop before the range.
+	remaining int64  // Plaintext bytes of the range left to write.
+	buf       []byte // Encrypted current chunk.
+	plain     []byte // Decrypted current chunk.
+}
+
+// newDecryptWriter - returns a writer of the encrypted data returned
+// by encryptedRange(offset, length), writing the plaintext range to w.
+// Close should be called once all the data was written.
+func (s *sseObject) newDecryptWriter(w io.Writer, offset, length int64) *sseDecryptWriter {
+	index := offset / sseChunkSize
+	return &sseDecryptWriter{
+		w:         w,
+		sse:       s,
+		index:     index,
+		skip:      offset - index*sseChunkSize,
+		remaining: length,
+		buf:       make([]byte, 0, sseChunkSize+sseTagSize),
+		plain:     make([]byte, 0, sseChunkSize),
+	}
+}
+
+func (d *sseDecryptWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		m := copy(d.buf[len(d.buf):cap(d.buf)], p)
+		d.buf = d.buf[:len(d.buf)+m]
+		p = p[m:]
+		n += m
+		if len(d.buf) == cap(d.buf) {
+			if err = d.openChunk(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// openChunk - decrypts the current chunk and writes its part of the
+// plaintext range.
+func (d *sseDecryptWriter) openChunk() error {
+	if d.index >= d.sse.chunks {
+		return ObjectTampered{}
+	}
+	plain, err := d.sse.aead.Open(d.plain[:0], d.sse.chunkNonce(d.index), d.buf, d.sse.chunkAdditionalData(d.index))
+	if err != nil {
+		return ObjectTampered{}
+	}
+	d.index++
+	d.buf = d.buf[:0]
+
+	if d.skip > int64(len(plain)) {
+		return ObjectTampered{}
+	}
+	plain = plain[d.skip:]
+	d.skip = 0
+	if int64(len(plain)) > d.remaining {
+		plain = plain[:d.remaining]
+	}
+	d.remaining -= int64(len(plain))
+	_, err = d.w.Write(plain)
+	return err
+}
+
+// Close - decrypts the last chunk, returns an error if the plaintext
+// range could not be written as a whole.
+func (d *sseDecryptWriter) Close() error {
+	if len(d.buf) > 0 {
+		if err := d.openChunk(); err != nil {
+			return err
+		}
+	}
+	if d.remaining > 0 {
+		return ObjectTampered{}
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests parsing of the server side encryption master key.
+func TestParseSSEMasterKey(t *testing.T) {
+	testCases := []struct {
+		key       string
+		keySize   int
+		shouldErr bool
+	}{
+		{"", 0, false},
+		{hex.EncodeToString(make([]byte, 32)), 32, false},
+		{hex.EncodeToString(make([]byte, 16)), 0, true},
+		{"not-hex", 0, true},
+	}
+	for i, testCase := range testCases {
+		key, err := parseSSEMasterKey(testCase.key)
+		if testCase.shouldErr != (err != nil) {
+			t.Fatalf("Test %d: Expected error %t, got %v", i+1, testCase.shouldErr, err)
+		}
+		if len(key) != testCase.keySize {
+			t.Errorf("Test %d: Expected key of %d bytes, got %d", i+1, testCase.keySize, len(key))
+		}
+	}
+}
+
+// Tests loading the master key from the environment or a key file.
+func TestLoadSSEMasterKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minio-sse-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(dir)
+	defer os.Setenv(sseMasterKeyEnv, os.Getenv(sseMasterKeyEnv))
+
+	hexKey := hex.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	keyFile := filepath.Join(dir, "sse.key")
+	if err = ioutil.WriteFile(keyFile, []byte(hexKey+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty.key")
+	if err = ioutil.WriteFile(emptyFile, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		envKey    string
+		keyFile   string
+		keySize   int
+		shouldErr bool
+	}{
+		// Encryption is disabled.
+		{"", "", 0, false},
+		{hexKey, "", 32, false},
+		{"", keyFile, 32, false},
+		{"not-hex", "", 0, true},
+		// Key set in both places.
+		{hexKey, keyFile, 0, true},
+		{"", emptyFile, 0, true},
+		{"", filepath.Join(dir, "non-existent.key"), 0, true},
+	}
+	for i, testCase := range testCases {
+		os.Setenv(sseMasterKeyEnv, testCase.envKey)
+		key, err := loadSSEMasterKey(testCase.keyFile)
+		if testCase.shouldErr != (err != nil) {
+			t.Fatalf("Test %d: Expected error %t, got %v", i+1, testCase.shouldErr, err)
+		}
+		if len(key) != testCase.keySize {
+			t.Errorf("Test %d: Expected key of %d bytes, got %d", i+1, testCase.keySize, len(key))
+		}
+	}
+}
+
+// encryptTestData - encrypts data with a new object key sealed by masterKey.
+func encryptTestData(t *testing.T, masterKey, data []byte) (ObjectInfo, []byte) {
+	metadata := make(map[string]string)
+	sse, err := newSSEObject(masterKey, int64(len(data)), metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := ioutil.ReadAll(newSSEEncryptReader(bytes.NewReader(data), sse, newPlaintextVerifier("", "", "")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(len(encrypted)) != sseEncryptedSize(int64(len(data))) {
+		t.Fatalf("Expected %d encrypted bytes, got %d", sseEncryptedSize(int64(len(data))), len(encrypted))
+	}
+	return ObjectInfo{Size: int64(len(encrypted)), UserDefined: metadata}, encrypted
+}
+
+// decryptTestData - decrypts the plaintext range of length at offset from encrypted.
+func decryptTestData(masterKey []byte, objInfo ObjectInfo, encrypted []byte, offset, length int64) ([]byte, error) {
+	sse, err := getSSEObject(masterKey, objInfo)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	decrypter := sse.newDecryptWriter(&buf, offset, length)
+	encOffset, encLength := sse.encryptedRange(offset, length)
+	if _, err = decrypter.Write(encrypted[encOffset : encOffset+encLength]); err != nil {
+		return nil, err
+	}
+	if err = decrypter.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Tests encryption and decryption of object data and of ranges of it.
+func TestSSEEncryptDecrypt(t *testing.T) {
+	masterKey := make([]byte, 32)
+	if _, err := rand.Read(masterKey); err != nil {
+		t.Fatal(err)
+	}
+	for i, size := range []int{0, 1, sseChunkSize - 1, sseChunkSize, sseChunkSize + 1, 3*sseChunkSize + 5} {
+		data := make([]byte, size)
+		if _, err := rand.Read(data); err != nil {
+			t.Fatal(err)
+		}
+		objInfo, encrypted := encryptTestData(t, masterKey, data)
+		if bytes.Contains(encrypted, data) && size > 16 {
+			t.Fatalf("Test %d: Encrypted data contains the plaintext", i+1)
+		}
+
+		ranges := [][2]int64{{0, int64(size)}}
+		if size > 2 {
+			ranges = append(ranges, [2]int64{1, int64(size) - 2}, [2]int64{int64(size) - 1, 1})
+		}
+		if size > sseChunkSize {
+			ranges = append(ranges, [2]int64{sseChunkSize - 1, 2}, [2]int64{sseChunkSize, int64(size) - sseChunkSize})
+		}
+		for _, rng := range ranges {
+			plain, err := decryptTestData(masterKey, objInfo, encrypted, rng[0], rng[1])
+			if err != nil {
+				t.Fatalf("Test %d: Unable to decrypt range %v: %v", i+1, rng, err)
+			}
+			if !bytes.Equal(plain, data[rng[0]:rng[0]+rng[1]]) {
+				t.Errorf("Test %d: Decrypted range %v does not match the plaintext", i+1, rng)
+			}
+		}
+	}
+}
+
+// Tests that modified encrypted data and metadata is detected.
+func TestSSETamperDetection(t *testing.T) {
+	masterKey := make([]byte, 32)
+	if _, err := rand.Read(masterKey); err != nil {
+		t.Fatal(err)
+	}
+	data := make([]byte, 3*sseChunkSize)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	objInfo, encrypted := encryptTestData(t, masterKey, data)
+	size := int64(len(data))
+	encChunkSize := sseChunkSize + sseTagSize
+
+	// Modified chunk.
+	modified := append([]byte(nil), encrypted...)
+	modified[encChunkSize+10] ^= 0xff
+	// Reordered chunks.
+	reordered := append([]byte(nil), encrypted[encChunkSize:2*encChunkSize]...)
+	reordered = append(reordered, encrypted[:encChunkSize]...)
+	reordered = append(reordered, encrypted[2*encChunkSize:]...)
+	// Last chunk dropped, with metadata matching the truncated object.
+	truncated := encrypted[:2*encChunkSize]
+	truncatedInfo := ObjectInfo{Size: int64(len(truncated)), UserDefined: make(map[string]string)}
+	for k, v := range objInfo.UserDefined {
+		truncatedInfo.UserDefined[k] = v
+	}
+	truncatedInfo.UserDefined[sseSizeMetaKey] = "131072"
+	truncatedInfo.UserDefined[sseChunksMetaKey] = "2"
+	// Sealed key of another object.
+	otherInfo, _ := encryptTestData(t, masterKey, data)
+	swappedInfo := ObjectInfo{Size: objInfo.Size, UserDefined: make(map[string]string)}
+	for k, v := range objInfo.UserDefined {
+		swappedInfo.UserDefined[k] = v
+	}
+	swappedInfo.UserDefined[sseSealedKeyMetaKey] = otherInfo.UserDefined[sseSealedKeyMetaKey]
+	// Master key changed.
+	otherMasterKey := make([]byte, 32)
+
+	testCases := []struct {
+		masterKey []byte
+		objInfo   ObjectInfo
+		encrypted []byte
+		length    int64
+	}{
+		{masterKey, objInfo, modified, size},
+		{masterKey, objInfo, reordered, size},
+		{masterKey, truncatedInfo, truncated, 2 * sseChunkSize},
+		{masterKey, swappedInfo, encrypted, size},
+		{otherMasterKey, objInfo, encrypted, size},
+	}
+	for i, testCase := range testCases {
+		_, err := decryptTestData(testCase.masterKey, testCase.objInfo, testCase.encrypted, 0, testCase.length)
+		if _, ok := errorCause(err).(ObjectTampered); !ok {
+			t.Errorf("Test %d: Expected ObjectTampered, got %v", i+1, err)
+		}
+	}
+}
+
+// Tests that plaintext is verified against the checksums sent by the client.
+func TestSSEEncryptReaderVerify(t *testing.T) {
+	masterKey := make([]byte, 32)
+	data := []byte("hello, world")
+	sum := md5.Sum(data)
+	otherSum := md5.Sum([]byte("hello, other world"))
+	testCases := []struct {
+		md5Hex    string
+		sha256Hex string
+		data      []byte
+		err       error
+	}{
+		{hex.EncodeToString(sum[:]), getSHA256Hash(data), data, nil},
+		{hex.EncodeToString(otherSum[:]), "", data, BadDigest{hex.EncodeToString(otherSum[:]), hex.EncodeToString(sum[:])}},
+		{"", getSHA256Hash([]byte("other")), data, SHA256Mismatch{}},
+		{"", "", data[:5], IncompleteBody{}},
+	}
+	for i, testCase := range testCases {
+		sse, err := newSSEObject(masterKey, int64(len(data)), make(map[string]string))
+		if err != nil {
+			t.Fatal(err)
+		}
+		verifier := newPlaintextVerifier(testCase.md5Hex, testCase.sha256Hex, "")
+		_, err = ioutil.ReadAll(newSSEEncryptReader(bytes.NewReader(testCase.data), sse, verifier))
+		if errorCause(err) != testCase.err {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.err, err)
+		}
+	}
+}
+
+// Tests the size of plain, encrypted and compressed objects as served.
+func TestGetObjectSize(t *testing.T) {
+	masterKey := make([]byte, 32)
+	if _, err := rand.Read(masterKey); err != nil {
+		t.Fatal(err)
+	}
+	data := make([]byte, sseChunkSize+1)
+	encInfo, _ := encryptTestData(t, masterKey, data)
+	encSSE, err := getSSEObject(masterKey, encInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressedMeta := map[string]string{
+		compressionMetaKey:     compressionAlgorithmGzip,
+		compressionSizeMetaKey: "1000",
+	}
+	testCases := []struct {
+		objInfo    ObjectInfo
+		sse        *sseObject
+		size       int64
+		shouldPass bool
+	}{
+		// Plain object.
+		{ObjectInfo{Size: 10}, nil, 10, true},
+		// Encrypted object.
+		{encInfo, encSSE, int64(len(data)), true},
+		// Compressed object.
+		{ObjectInfo{Size: 100, UserDefined: compressedMeta}, nil, 1000, true},
+		// Compressed and encrypted object.
+		{ObjectInfo{Size: sseEncryptedSize(100), UserDefined: compressedMeta}, &sseObject{size: 100}, 1000, true},
+		// Tampered compression metadata.
+		{ObjectInfo{Size: 100, UserDefined: map[string]string{compressionMetaKey: compressionAlgorithmGzip}}, nil, 0, false},
+	}
+	for i, testCase := range testCases {
+		size, err := getObjectSize(testCase.objInfo, testCase.sse)
+		if testCase.shouldPass != (err == nil) {
+			t.Fatalf("Test %d: Unexpected error %v", i+1, err)
+		}
+		if size != testCase.size {
+			t.Errorf("Test %d: Expected size %d, got %d", i+1, testCase.size, size)
+		}
+	}
+}
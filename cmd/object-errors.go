@@ -46,6 +46,8 @@ func toObjectErr(err error, params ...string) error {
 		}
 	case errDiskFull:
 		err = StorageFull{}
+	case errDiskInodesFull:
+		err = InodeExhausted{}
 	case errFileAccessDenied:
 		if len(params) >= 2 {
 			err = PrefixAccessDenied{
@@ -118,6 +120,20 @@ func (e StorageFull) Error() string {
 	return "Storage reached its minimum free disk threshold."
 }
 
+// InodeExhausted storage ran out of inodes, while there may be free space.
+type InodeExhausted struct{}
+
+func (e InodeExhausted) Error() string {
+	return "Storage reached its maximum number of files, no free inodes are left."
+}
+
+// ListingBusy the maximum number of concurrent listings is reached.
+type ListingBusy struct{}
+
+func (e ListingBusy) Error() string {
+	return "Too many concurrent listings, please try again."
+}
+
 // InsufficientReadQuorum storage cannot satisfy quorum for read operation.
 type InsufficientReadQuorum struct{}
 
@@ -159,6 +175,14 @@ func (e ObjectNotFound) Error() string {
 	return "Object not found: " + e.Bucket + "#" + e.Object
 }
 
+// ObjectLocked object is retained by its object lock, it cannot be
+// overwritten or deleted.
+type ObjectLocked GenericError
+
+func (e ObjectLocked) Error() string {
+	return "Object is locked: " + e.Bucket + "#" + e.Object
+}
+
 // ObjectExistsAsDirectory object already exists as a directory.
 type ObjectExistsAsDirectory GenericError
 
@@ -166,7 +190,14 @@ func (e ObjectExistsAsDirectory) Error() string {
 	return "Object exists on : " + e.Bucket + " as directory " + e.Object
 }
 
-//PrefixAccessDenied object access is denied.
+// ObjectInUse object is still open for reading and cannot be deleted.
+type ObjectInUse GenericError
+
+func (e ObjectInUse) Error() string {
+	return "Object in use: " + e.Bucket + "#" + e.Object
+}
+
+// PrefixAccessDenied object access is denied.
 type PrefixAccessDenied GenericError
 
 func (e PrefixAccessDenied) Error() string {
@@ -190,6 +221,17 @@ func (e BadDigest) Error() string {
 	return "Bad digest: Expected " + e.ExpectedMD5 + " is not valid with what we calculated " + e.CalculatedMD5
 }
 
+// BadChecksum - SHA-256 checksum of object data does not match the
+// x-amz-checksum-sha256 sent by the client.
+type BadChecksum struct {
+	ExpectedChecksum   string
+	CalculatedChecksum string
+}
+
+func (e BadChecksum) Error() string {
+	return "Bad checksum: Expected " + e.ExpectedChecksum + " is not valid with what we calculated " + e.CalculatedChecksum
+}
+
 // UnsupportedDelimiter - unsupported delimiter.
 type UnsupportedDelimiter struct {
 	Delimiter string
@@ -224,6 +266,27 @@ func (e BucketPolicyNotFound) Error() string {
 	return "No bucket policy found for bucket: " + e.Bucket
 }
 
+// BucketLifecycleNotFound - no bucket lifecycle found.
+type BucketLifecycleNotFound GenericError
+
+func (e BucketLifecycleNotFound) Error() string {
+	return "No bucket lifecycle found for bucket: " + e.Bucket
+}
+
+// BucketQuotaNotFound - no bucket quota found.
+type BucketQuotaNotFound GenericError
+
+func (e BucketQuotaNotFound) Error() string {
+	return "No bucket quota found for bucket: " + e.Bucket
+}
+
+// BucketObjectLockNotFound - no bucket object lock config found.
+type BucketObjectLockNotFound GenericError
+
+func (e BucketObjectLockNotFound) Error() string {
+	return "No bucket object lock found for bucket: " + e.Bucket
+}
+
 /// Bucket related errors.
 
 // BucketNameInvalid - bucketname provided is invalid.
@@ -318,6 +381,25 @@ func (e PartTooSmall) Error() string {
 	return fmt.Sprintf("Part size for %d should be atleast 5MB", e.PartNumber)
 }
 
+// PartNotLastTooSmall - error if a part smaller than 5MB is uploaded
+// while a part with a higher part number already exists.
+type PartNotLastTooSmall struct {
+	PartSize   int64
+	PartNumber int
+}
+
+func (e PartNotLastTooSmall) Error() string {
+	return fmt.Sprintf("Part size for %d should be atleast 5MB, only the last part may be smaller", e.PartNumber)
+}
+
+// ObjectTampered - encrypted object data or its encryption metadata
+// was modified.
+type ObjectTampered struct{}
+
+func (e ObjectTampered) Error() string {
+	return "The requested object was modified and may be compromised"
+}
+
 // NotImplemented If a feature is not implemented
 type NotImplemented struct{}
 
@@ -352,6 +434,36 @@ func isErrBucketPolicyNotFound(err error) bool {
 	return false
 }
 
+// Check if error type is BucketLifecycleNotFound.
+func isErrBucketLifecycleNotFound(err error) bool {
+	err = errorCause(err)
+	switch err.(type) {
+	case BucketLifecycleNotFound:
+		return true
+	}
+	return false
+}
+
+// Check if error type is BucketQuotaNotFound.
+func isErrBucketQuotaNotFound(err error) bool {
+	err = errorCause(err)
+	switch err.(type) {
+	case BucketQuotaNotFound:
+		return true
+	}
+	return false
+}
+
+// Check if error type is BucketObjectLockNotFound.
+func isErrBucketObjectLockNotFound(err error) bool {
+	err = errorCause(err)
+	switch err.(type) {
+	case BucketObjectLockNotFound:
+		return true
+	}
+	return false
+}
+
 // Check if error type is ObjectNameInvalid.
 func isErrObjectNameInvalid(err error) bool {
 	err = errorCause(err)
@@ -362,6 +474,16 @@ func isErrObjectNameInvalid(err error) bool {
 	return false
 }
 
+// Check if error type is BucketNotFound.
+func isErrBucketNotFound(err error) bool {
+	err = errorCause(err)
+	switch err.(type) {
+	case BucketNotFound:
+		return true
+	}
+	return false
+}
+
 // Check if error type is ObjectNotFound.
 func isErrObjectNotFound(err error) bool {
 	err = errorCause(err)
@@ -17,9 +17,13 @@
 package cmd
 
 import (
+	"io"
 	"net/http"
+	"path"
 	"strings"
 	"time"
+
+	"github.com/minio/minio/pkg/mimedb"
 )
 
 // Validates the preconditions for CopyObject, returns true if CopyObject operation should not proceed.
@@ -48,8 +52,8 @@ func checkCopyObjectPreconditions(w http.ResponseWriter, r *http.Request, objInf
 		// set object-related metadata headers
 		w.Header().Set("Last-Modified", objInfo.ModTime.UTC().Format(http.TimeFormat))
 
-		if objInfo.MD5Sum != "" {
-			w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
+		if getObjectETag(objInfo) != "" {
+			w.Header().Set("ETag", "\""+sanitizeHeaderValue(getObjectETag(objInfo))+"\"")
 		}
 	}
 	// x-amz-copy-source-if-modified-since: Return the object only if it has been modified
@@ -80,7 +84,7 @@ func checkCopyObjectPreconditions(w http.ResponseWriter, r *http.Request, objInf
 	// same as the one specified; otherwise return a 412 (precondition failed).
 	ifMatchETagHeader := r.Header.Get("x-amz-copy-source-if-match")
 	if ifMatchETagHeader != "" {
-		if objInfo.MD5Sum != "" && !isETagEqual(objInfo.MD5Sum, ifMatchETagHeader) {
+		if getObjectETag(objInfo) != "" && !isETagEqual(getObjectETag(objInfo), ifMatchETagHeader) {
 			// If the object ETag does not match with the specified ETag.
 			writeHeaders()
 			writeErrorResponse(w, r, ErrPreconditionFailed, r.URL.Path)
@@ -92,7 +96,7 @@ func checkCopyObjectPreconditions(w http.ResponseWriter, r *http.Request, objInf
 	// one specified otherwise, return a 304 (not modified).
 	ifNoneMatchETagHeader := r.Header.Get("x-amz-copy-source-if-none-match")
 	if ifNoneMatchETagHeader != "" {
-		if objInfo.MD5Sum != "" && isETagEqual(objInfo.MD5Sum, ifNoneMatchETagHeader) {
+		if getObjectETag(objInfo) != "" && isETagEqual(getObjectETag(objInfo), ifNoneMatchETagHeader) {
 			// If the object ETag matches with the specified ETag.
 			writeHeaders()
 			writeErrorResponse(w, r, ErrPreconditionFailed, r.URL.Path)
@@ -129,8 +133,8 @@ func checkPreconditions(w http.ResponseWriter, r *http.Request, objInfo ObjectIn
 		// set object-related metadata headers
 		w.Header().Set("Last-Modified", objInfo.ModTime.UTC().Format(http.TimeFormat))
 
-		if objInfo.MD5Sum != "" {
-			w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
+		if getObjectETag(objInfo) != "" {
+			w.Header().Set("ETag", "\""+sanitizeHeaderValue(getObjectETag(objInfo))+"\"")
 		}
 	}
 	// If-Modified-Since : Return the object only if it has been modified since the specified time,
@@ -161,7 +165,7 @@ func checkPreconditions(w http.ResponseWriter, r *http.Request, objInfo ObjectIn
 	// otherwise return a 412 (precondition failed).
 	ifMatchETagHeader := r.Header.Get("If-Match")
 	if ifMatchETagHeader != "" {
-		if !isETagEqual(objInfo.MD5Sum, ifMatchETagHeader) {
+		if !isETagEqual(getObjectETag(objInfo), ifMatchETagHeader) {
 			// If the object ETag does not match with the specified ETag.
 			writeHeaders()
 			writeErrorResponse(w, r, ErrPreconditionFailed, r.URL.Path)
@@ -173,7 +177,7 @@ func checkPreconditions(w http.ResponseWriter, r *http.Request, objInfo ObjectIn
 	// one specified otherwise, return a 304 (not modified).
 	ifNoneMatchETagHeader := r.Header.Get("If-None-Match")
 	if ifNoneMatchETagHeader != "" {
-		if isETagEqual(objInfo.MD5Sum, ifNoneMatchETagHeader) {
+		if isETagEqual(getObjectETag(objInfo), ifNoneMatchETagHeader) {
 			// If the object ETag matches with the specified ETag.
 			writeHeaders()
 			w.WriteHeader(http.StatusNotModified)
@@ -184,6 +188,26 @@ func checkPreconditions(w http.ResponseWriter, r *http.Request, objInfo ObjectIn
 	return false
 }
 
+// isIfRangeSatisfied - returns true if the requested range should be
+// served, that is when the object has not changed since the ETag or the
+// date given in If-Range. Otherwise the Range header is ignored and the
+// whole object is served. Requests without If-Range always get their range.
+func isIfRangeSatisfied(r *http.Request, objInfo ObjectInfo) bool {
+	ifRangeHeader := r.Header.Get("If-Range")
+	if ifRangeHeader == "" {
+		return true
+	}
+	// If-Range is either an HTTP date or an entity tag.
+	if _, err := time.Parse(http.TimeFormat, ifRangeHeader); err == nil {
+		return !ifModifiedSince(objInfo.ModTime, ifRangeHeader)
+	}
+	// Weak entity tags never match.
+	if strings.HasPrefix(ifRangeHeader, "W/") {
+		return false
+	}
+	return isETagEqual(getObjectETag(objInfo), ifRangeHeader)
+}
+
 // returns true if object was modified after givenTime.
 func ifModifiedSince(objTime time.Time, givenTimeStr string) bool {
 	givenTime, err := time.Parse(http.TimeFormat, givenTimeStr)
@@ -210,3 +234,119 @@ func canonicalizeETag(etag string) string {
 func isETagEqual(left, right string) bool {
 	return canonicalizeETag(left) == canonicalizeETag(right)
 }
+
+// checkPartSize - validates the size of an uploaded part, parts smaller
+// than minPartSize are accepted only as the last part, that is when no
+// part with a higher part number has been uploaded.
+func checkPartSize(bucket, object, uploadID string, partID int, size int64, objAPI ObjectLayer) error {
+	if isMinAllowedPartSize(size) {
+		return nil
+	}
+	partNumberMarker := 0
+	for {
+		result, err := objAPI.ListObjectParts(bucket, object, uploadID, partNumberMarker, maxPartsList)
+		if err != nil {
+			return err
+		}
+		for _, part := range result.Parts {
+			if part.PartNumber > partID {
+				return traceError(PartNotLastTooSmall{PartSize: size, PartNumber: partID})
+			}
+		}
+		if !result.IsTruncated {
+			return nil
+		}
+		partNumberMarker = result.NextPartNumberMarker
+	}
+}
+
+// closeOnChecksumMismatch - asks the server to close the connection after
+// a checksum mismatch error is sent, the client may still be sending a body
+// that is not going to be read.
+func closeOnChecksumMismatch(w http.ResponseWriter, err error) {
+	switch errorCause(err).(type) {
+	case BadDigest, SHA256Mismatch:
+		w.Header().Set("Connection", "close")
+	}
+}
+
+// getObjectSize - returns the size of the object as served to clients,
+// that is of the decrypted and decompressed data, sse is nil for objects
+// which are not encrypted.
+func getObjectSize(objInfo ObjectInfo, sse *sseObject) (int64, error) {
+	if isObjectCompressed(objInfo) {
+		return getDecompressedSize(objInfo)
+	}
+	if sse != nil {
+		return sse.size, nil
+	}
+	return objInfo.Size, nil
+}
+
+// getObjectETag - returns the ETag of the object as served to clients,
+// compressed objects are tagged with the MD5 sum of the decompressed data.
+func getObjectETag(objInfo ObjectInfo) string {
+	if md5Hex, ok := objInfo.UserDefined[compressionMD5MetaKey]; ok && isObjectCompressed(objInfo) {
+		return md5Hex
+	}
+	return objInfo.MD5Sum
+}
+
+// Number of bytes the content type of an object is detected from.
+const contentTypeSniffLen = 512
+
+// contentTypeReader - detects the content type of the data read through
+// it from its first bytes.
+type contentTypeReader struct {
+	src      io.Reader
+	head     []byte
+	headLen  int64             // Number of bytes the content type is detected from.
+	metadata map[string]string // Set to nil once the content type is saved.
+}
+
+// newContentTypeReader - returns a reader of size bytes of data saving the
+// content type detected from the data in metadata, once its first bytes
+// are read. Objects whose name has a known extension are typed from it by
+// the object layers, data is then returned as is.
+func newContentTypeReader(object string, data io.Reader, size int64, metadata map[string]string) io.Reader {
+	if objectExt := path.Ext(object); objectExt != "" {
+		if _, ok := mimedb.DB[strings.ToLower(strings.TrimPrefix(objectExt, "."))]; ok {
+			return data
+		}
+	}
+	headLen := int64(contentTypeSniffLen)
+	if size >= 0 && size < headLen {
+		headLen = size
+	}
+	r := &contentTypeReader{src: data, headLen: headLen, metadata: metadata}
+	if headLen == 0 {
+		r.detect()
+	}
+	return r
+}
+
+func (r *contentTypeReader) Read(p []byte) (int, error) {
+	n, err := r.src.Read(p)
+	if r.metadata != nil {
+		if need := r.headLen - int64(len(r.head)); need > 0 {
+			if int64(n) < need {
+				need = int64(n)
+			}
+			r.head = append(r.head, p[:need]...)
+		}
+		if int64(len(r.head)) == r.headLen || err == io.EOF {
+			r.detect()
+		}
+	}
+	return n, err
+}
+
+// detect - saves the content type detected from the bytes read so far.
+func (r *contentTypeReader) detect() {
+	if len(r.head) == 0 {
+		r.metadata["content-type"] = defaultContentType
+	} else {
+		r.metadata["content-type"] = http.DetectContentType(r.head)
+	}
+	r.metadata = nil
+}
@@ -23,10 +23,10 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
-	"path"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	mux "github.com/gorilla/mux"
 )
@@ -45,7 +45,11 @@ var supportedGetReqParams = map[string]string{
 func setGetRespHeaders(w http.ResponseWriter, reqParams url.Values) {
 	for k, v := range reqParams {
 		if header, ok := supportedGetReqParams[k]; ok {
-			w.Header()[header] = v
+			values := make([]string, len(v))
+			for i, value := range v {
+				values[i] = sanitizeHeaderValue(value)
+			}
+			w.Header()[header] = values
 		}
 	}
 }
@@ -57,10 +61,7 @@ func setGetRespHeaders(w http.ResponseWriter, reqParams url.Values) {
 func errAllowableObjectNotFound(bucket string, r *http.Request) APIErrorCode {
 	if getRequestAuthType(r) == authTypeAnonymous {
 		//we care about the bucket as a whole, not a particular resource
-		url := *r.URL
-		url.Path = "/" + bucket
-
-		if s3Error := enforceBucketPolicy(bucket, "s3:ListBucket", &url); s3Error != ErrNone {
+		if s3Error := enforceBucketPolicy(bucket, "", "s3:ListBucket", r.URL.Query()); s3Error != ErrNone {
 			return ErrAccessDenied
 		}
 	}
@@ -74,6 +75,16 @@ func (f funcToWriter) Write(p []byte) (int, error) {
 	return f(p)
 }
 
+// Simple way to add io.ReaderFrom to an io.Writer using a func.
+type funcToReaderFrom struct {
+	io.Writer
+	readFrom func(io.Reader) (int64, error)
+}
+
+func (f funcToReaderFrom) ReadFrom(r io.Reader) (int64, error) {
+	return f.readFrom(r)
+}
+
 // GetObjectHandler - GET Object
 // ----------
 // This implementation of the GET operation retrieves object. To use GET,
@@ -98,7 +109,7 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 
 	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
 	if err != nil {
-		errorIf(err, "Unable to fetch object info.")
+		errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to fetch object info.")
 		apiErr := toAPIErrorCode(err)
 		if apiErr == ErrNoSuchKey {
 			apiErr = errAllowableObjectNotFound(bucket, r)
@@ -107,10 +118,37 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Get request range.
+	// Verify anonymous access against the object ACL.
+	if s3Error := checkObjectACL(r, objInfo); s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+
+	// Encrypted objects are served decrypted.
+	sse, err := getSSEObject(globalSSEMasterKey, objInfo)
+	if err != nil {
+		errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to decrypt object.")
+		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+		return
+	}
+	compressed := isObjectCompressed(objInfo)
+	compressedSize := objInfo.Size
+	if sse != nil {
+		compressedSize = sse.size
+	}
+
+	// Ranges are validated against the size of the object as served.
+	if objInfo.Size, err = getObjectSize(objInfo, sse); err != nil {
+		errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to get object size.")
+		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+		return
+	}
+
+	// Get request range, the range is ignored if the object has
+	// changed since the validator given in If-Range.
 	var hrange *httpRange
 	rangeHeader := r.Header.Get("Range")
-	if rangeHeader != "" {
+	if rangeHeader != "" && isIfRangeSatisfied(r, objInfo) {
 		if hrange, err = parseRequestRange(rangeHeader, objInfo.Size); err != nil {
 			// Handle only errInvalidRange
 			// Ignore other parse error and treat it as regular Get request like Amazon S3.
@@ -120,7 +158,7 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 			}
 
 			// log the error.
-			errorIf(err, "Invalid request range")
+			errorIfContext(err, fields{"bucket": bucket, "object": object}, "Invalid request range")
 		}
 
 	}
@@ -139,10 +177,13 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 	}
 	// Indicates if any data was written to the http.ResponseWriter
 	dataWritten := false
-	// io.Writer type which keeps track if any data was written.
-	writer := funcToWriter(func(p []byte) (int, error) {
+	// Sets the response headers before any data is written.
+	writeHeaders := func() {
 		if !dataWritten {
 			// Set headers on the first write.
+			// Set expiration header if the object expires.
+			setExpirationHeader(w, objInfo, objectAPI)
+
 			// Set standard object headers.
 			setObjectHeaders(w, objInfo, hrange)
 
@@ -151,12 +192,72 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 
 			dataWritten = true
 		}
+	}
+	// io.Writer type which keeps track if any data was written.
+	writer := funcToWriter(func(p []byte) (int, error) {
+		writeHeaders()
 		return w.Write(p)
 	})
 
+	// Compressed data is decompressed from the beginning, the part before
+	// the requested range is dropped.
+	if compressed {
+		decompressor := newGzipDecompressWriter(writer, startOffset, length)
+		err = objectAPI.GetObject(bucket, object, 0, compressedSize, decompressor)
+		if cErr := decompressor.Close(); err == nil {
+			err = cErr
+		}
+		if err != nil {
+			errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to write to client.")
+			if !dataWritten {
+				writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+			}
+			return
+		}
+		if !dataWritten {
+			writer.Write(nil)
+		}
+		return
+	}
+
+	// Encrypted data is decrypted chunk by chunk, only the chunks holding
+	// the requested range are read.
+	if sse != nil {
+		decrypter := sse.newDecryptWriter(writer, startOffset, length)
+		encOffset, encLength := sse.encryptedRange(startOffset, length)
+		err = objectAPI.GetObject(bucket, object, encOffset, encLength, decrypter)
+		if err == nil {
+			err = decrypter.Close()
+		}
+		if err != nil {
+			errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to write to client.")
+			if !dataWritten {
+				writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+			}
+			return
+		}
+		if !dataWritten {
+			writer.Write(nil)
+		}
+		return
+	}
+
+	// Let the object layer hand over the data with ReadFrom, which can use
+	// sendfile(2).
+	var objWriter io.Writer = writer
+	if rf, ok := w.(io.ReaderFrom); ok {
+		objWriter = funcToReaderFrom{
+			Writer: writer,
+			readFrom: func(src io.Reader) (int64, error) {
+				writeHeaders()
+				return rf.ReadFrom(src)
+			},
+		}
+	}
+
 	// Reads the object at startOffset and writes to mw.
-	if err := objectAPI.GetObject(bucket, object, startOffset, length, writer); err != nil {
-		errorIf(err, "Unable to write to client.")
+	if err := objectAPI.GetObject(bucket, object, startOffset, length, objWriter); err != nil {
+		errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to write to client.")
 		if !dataWritten {
 			// Error response only if no data has been written to client yet. i.e if
 			// partial data has already been written before an error
@@ -197,7 +298,7 @@ func (api objectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 
 	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
 	if err != nil {
-		errorIf(err, "Unable to fetch object info.")
+		errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to fetch object info.")
 		apiErr := toAPIErrorCode(err)
 		if apiErr == ErrNoSuchKey {
 			apiErr = errAllowableObjectNotFound(bucket, r)
@@ -206,11 +307,33 @@ func (api objectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Verify anonymous access against the object ACL.
+	if s3Error := checkObjectACL(r, objInfo); s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+
+	// Encrypted objects are served decrypted.
+	sse, err := getSSEObject(globalSSEMasterKey, objInfo)
+	if err != nil {
+		errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to decrypt object.")
+		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+		return
+	}
+	if objInfo.Size, err = getObjectSize(objInfo, sse); err != nil {
+		errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to get object size.")
+		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+		return
+	}
+
 	// Validate pre-conditions if any.
 	if checkPreconditions(w, r, objInfo) {
 		return
 	}
 
+	// Set expiration header if the object expires.
+	setExpirationHeader(w, objInfo, objectAPI)
+
 	// Set standard object headers.
 	setObjectHeaders(w, objInfo, nil)
 
@@ -218,11 +341,11 @@ func (api objectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 	w.WriteHeader(http.StatusOK)
 }
 
-// CopyObjectHandler - Copy Object
-// ----------
-// This implementation of the PUT operation adds an object to a bucket
-// while reading the object from another source.
-func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
+// GetObjectACLHandler - GET Object ACL
+// -----------
+// This operation returns the access control list of an object, derived
+// from the canned ACL saved along with the object.
+func (api objectAPIHandlers) GetObjectACLHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	bucket := vars["bucket"]
 	object := vars["object"]
@@ -233,14 +356,83 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, bucket, "s3:PutObject", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := checkRequestAuthType(r, bucket, "s3:GetObjectAcl", serverConfig.GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, r, s3Error, r.URL.Path)
 		return
 	}
 
-	// TODO: Reject requests where body/payload is present, for now we don't even read it.
+	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to fetch object info.")
+		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+		return
+	}
 
-	// objectSource
+	response := generateAccessControlPolicyResponse(objInfo.UserDefined[objectACLMetaKey])
+	encodedSuccessResponse := encodeResponse(response)
+
+	// Write success response.
+	writeSuccessResponse(w, encodedSuccessResponse)
+}
+
+// PutObjectACLHandler - PUT Object ACL
+// -----------
+// This operation replaces the canned ACL of an existing object. Only
+// canned ACLs set through the "x-amz-acl" header are supported.
+func (api objectAPIHandlers) PutObjectACLHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, r, ErrServerNotInitialized, r.URL.Path)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutObjectAcl", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+
+	acl, s3Error := getACLType(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+	// ACLs through request body are not supported.
+	if acl == "" {
+		writeErrorResponse(w, r, ErrNotImplemented, r.URL.Path)
+		return
+	}
+
+	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to fetch object info.")
+		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+		return
+	}
+
+	// Preserve existing metadata, only the ACL changes.
+	metadata := make(map[string]string)
+	for k, v := range objInfo.UserDefined {
+		metadata[k] = v
+	}
+	metadata[objectACLMetaKey] = acl
+
+	if _, err = objectAPI.SetObjectMetadata(bucket, object, metadata); err != nil {
+		errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to update object ACL.")
+		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+		return
+	}
+
+	// Write success response.
+	writeSuccessResponse(w, nil)
+}
+
+// getCopySource - extracts source bucket and object from x-amz-copy-source
+// header, also returns the unescaped header value.
+func getCopySource(r *http.Request) (objectSource, sourceBucket, sourceObject string) {
 	objectSource, err := url.QueryUnescape(r.Header.Get("X-Amz-Copy-Source"))
 	if err != nil {
 		// Save unescaped string as is.
@@ -252,35 +444,114 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 	splits := strings.SplitN(objectSource, "/", 2)
 
 	// Save sourceBucket and sourceObject extracted from url Path.
-	var sourceBucket, sourceObject string
 	if len(splits) == 2 {
 		sourceBucket = splits[0]
 		sourceObject = splits[1]
 	}
+	return objectSource, sourceBucket, sourceObject
+}
+
+// CopyObjectHandler - Copy Object
+// ----------
+// This implementation of the PUT operation adds an object to a bucket
+// while reading the object from another source.
+func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, r, ErrServerNotInitialized, r.URL.Path)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutObject", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+
+	// TODO: Reject requests where body/payload is present, for now we don't even read it.
+
+	// objectSource
+	objectSource, sourceBucket, sourceObject := getCopySource(r)
 	// If source object is empty, reply back error.
 	if sourceObject == "" {
 		writeErrorResponse(w, r, ErrInvalidCopySource, r.URL.Path)
 		return
 	}
 
-	// Source and destination objects cannot be same, reply back error.
-	if sourceObject == object && sourceBucket == bucket {
+	// Anonymous requests need read access to the source object as
+	// well, source and destination buckets can have different policies.
+	if getRequestAuthType(r) == authTypeAnonymous {
+		if s3Error := enforceBucketPolicy(sourceBucket, sourceObject, "s3:GetObject", url.Values{}); s3Error != ErrNone {
+			writeErrorResponse(w, r, s3Error, objectSource)
+			return
+		}
+	}
+
+	// Validate canned ACL if any, ACL of the source object is never copied.
+	acl, s3Error := getACLType(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+
+	// Metadata of the destination object is replaced with the one
+	// provided in the request instead of being copied from the source.
+	isMetadataReplace := r.Header.Get("X-Amz-Metadata-Directive") == "REPLACE"
+
+	// Source and destination objects cannot be same unless only
+	// the metadata is being replaced, reply back error.
+	if sourceObject == object && sourceBucket == bucket && !isMetadataReplace {
 		writeErrorResponse(w, r, ErrInvalidCopyDest, r.URL.Path)
 		return
 	}
 
 	objInfo, err := objectAPI.GetObjectInfo(sourceBucket, sourceObject)
 	if err != nil {
-		errorIf(err, "Unable to fetch object info.")
+		errorIfContext(err, fields{"bucket": sourceBucket, "object": sourceObject}, "Unable to fetch object info.")
 		writeErrorResponse(w, r, toAPIErrorCode(err), objectSource)
 		return
 	}
 
+	// Verify anonymous read access against the ACL of the source object.
+	if s3Error := checkObjectACL(r, objInfo); s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, objectSource)
+		return
+	}
+
 	// Verify before x-amz-copy-source preconditions before continuing with CopyObject.
 	if checkCopyObjectPreconditions(w, r, objInfo) {
 		return
 	}
 
+	// Copying an object onto itself updates only its metadata,
+	// object data is not read or written again.
+	if sourceObject == object && sourceBucket == bucket {
+		metadata := extractMetadataFromHeader(r.Header)
+		// Object ACL is kept unless a new one is requested.
+		if acl == "" {
+			acl = objInfo.UserDefined[objectACLMetaKey]
+		}
+		if acl != "" {
+			metadata[objectACLMetaKey] = acl
+		}
+		objInfo, err = objectAPI.SetObjectMetadata(bucket, object, metadata)
+		if err != nil {
+			errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to update object metadata.")
+			writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+			return
+		}
+		response := generateCopyObjectResponse(getObjectETag(objInfo), objInfo.ModTime)
+		encodedSuccessResponse := encodeResponse(response)
+		// write headers
+		setCommonHeaders(w)
+		// write success response.
+		writeSuccessResponse(w, encodedSuccessResponse)
+		return
+	}
+
 	/// maximum Upload size for object in a single CopyObject operation.
 	if isMaxObjectSize(objInfo.Size) {
 		writeErrorResponse(w, r, ErrEntityTooLarge, objectSource)
@@ -290,13 +561,20 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 	// Size of object.
 	size := objInfo.Size
 
+	// Verify if the copy fits in the bucket quota.
+	quota, s3Error := checkBucketQuota(bucket, object, size, objectAPI)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+
 	pipeReader, pipeWriter := io.Pipe()
 	go func() {
 		startOffset := int64(0) // Read the whole file.
 		// Get the object.
 		gErr := objectAPI.GetObject(sourceBucket, sourceObject, startOffset, size, pipeWriter)
 		if gErr != nil {
-			errorIf(gErr, "Unable to read an object.")
+			errorIfContext(gErr, fields{"bucket": sourceBucket, "object": sourceObject}, "Unable to read an object.")
 			pipeWriter.CloseWithError(gErr)
 			return
 		}
@@ -305,10 +583,25 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 
 	// Save other metadata if available.
 	metadata := objInfo.UserDefined
+	if isMetadataReplace {
+		// Data is copied as is, it stays compressed or encrypted
+		// and the internal entries describing it are carried over.
+		metadata = replaceObjectMetadata(objInfo.UserDefined, extractMetadataFromHeader(r.Header))
+	}
 
 	// Remove the etag from source metadata because if it was uploaded as a multipart object
 	// then its ETag will not be MD5sum of the object.
 	delete(metadata, "md5Sum")
+	// Checksum of the source object is kept, the copied data is verified against it.
+	if checksum, ok := objInfo.UserDefined[objectChecksumMetaKey]; ok {
+		metadata[objectChecksumMetaKey] = checksum
+	}
+
+	// Save the requested canned ACL in place of the source object ACL.
+	delete(metadata, objectACLMetaKey)
+	if acl != "" {
+		metadata[objectACLMetaKey] = acl
+	}
 
 	sha256sum := ""
 	// Create the object.
@@ -316,15 +609,15 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 	if err != nil {
 		// Close the this end of the pipe upon error in PutObject.
 		pipeReader.CloseWithError(err)
-		errorIf(err, "Unable to create an object.")
+		errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to create an object.")
 		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
 		return
 	}
 	// Explicitly close the reader, before fetching object info.
 	pipeReader.Close()
+	quota.commit(objInfo.Size)
 
-	md5Sum := objInfo.MD5Sum
-	response := generateCopyObjectResponse(md5Sum, objInfo.ModTime)
+	response := generateCopyObjectResponse(getObjectETag(objInfo), objInfo.ModTime)
 	encodedSuccessResponse := encodeResponse(response)
 	// write headers
 	setCommonHeaders(w)
@@ -365,7 +658,7 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 	// Get Content-Md5 sent by client and verify if valid
 	md5Bytes, err := checkValidMD5(r.Header.Get("Content-Md5"))
 	if err != nil {
-		errorIf(err, "Unable to validate content-md5 format.")
+		errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to validate content-md5 format.")
 		writeErrorResponse(w, r, ErrInvalidDigest, r.URL.Path)
 		return
 	}
@@ -377,7 +670,7 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		sizeStr := r.Header.Get("x-amz-decoded-content-length")
 		size, err = strconv.ParseInt(sizeStr, 10, 64)
 		if err != nil {
-			errorIf(err, "Unable to parse `x-amz-decoded-content-length` into its integer value", sizeStr)
+			errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to parse `x-amz-decoded-content-length` %s into its integer value", sizeStr)
 			writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
 			return
 		}
@@ -393,14 +686,43 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Extract metadata to be saved from incoming HTTP header.
-	metadata := extractMetadataFromHeader(r.Header)
-	// Make sure we hex encode md5sum here.
-	metadata["md5Sum"] = hex.EncodeToString(md5Bytes)
+	// Validate canned ACL if any.
+	acl, s3Error := getACLType(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
 
-	sha256sum := ""
+	// Validate requested checksum of object data if any.
+	checksum, s3Error := getChecksumSHA256(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
 
-	var objInfo ObjectInfo
+	// Validate requested storage class if any.
+	storageClass, s3Error := getStorageClass(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+
+	// Validate requested server side encryption if any.
+	encrypt, s3Error := isSSERequested(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+	if encrypt && size == -1 {
+		// Encryption parameters are saved before the data is read.
+		writeErrorResponse(w, r, ErrMissingContentLength, r.URL.Path)
+		return
+	}
+
+	// Authenticate the request before the object layer is consulted,
+	// reader is the body to save.
+	var reader io.Reader = r.Body
+	sha256sum := ""
 	switch rAuthType {
 	default:
 		// For all unknown auth types return error.
@@ -408,47 +730,125 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		return
 	case authTypeAnonymous:
 		// http://docs.aws.amazon.com/AmazonS3/latest/dev/using-with-s3-actions.html
-		if s3Error := enforceBucketPolicy(bucket, "s3:PutObject", r.URL); s3Error != ErrNone {
+		if s3Error := enforceBucketPolicy(bucket, object, "s3:PutObject", r.URL.Query()); s3Error != ErrNone {
 			writeErrorResponse(w, r, s3Error, r.URL.Path)
 			return
 		}
-		// Create anonymous object.
-		objInfo, err = objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
+	case authTypeClientCert:
+		// Client certificate is already verified during TLS handshake.
 	case authTypeStreamingSigned:
 		// Initialize stream signature verifier.
-		reader, s3Error := newSignV4ChunkedReader(r)
+		reader, s3Error = newSignV4ChunkedReader(r)
 		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			errorIfContext(errSignatureMismatch, fields{"bucket": bucket, "object": object}, dumpRequest(r))
 			writeErrorResponse(w, r, s3Error, r.URL.Path)
 			return
 		}
-		objInfo, err = objectAPI.PutObject(bucket, object, size, reader, metadata, sha256sum)
 	case authTypeSignedV2, authTypePresignedV2:
-		s3Error := isReqAuthenticatedV2(r)
-		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+		if s3Error := isReqAuthenticatedV2(r); s3Error != ErrNone {
+			errorIfContext(errSignatureMismatch, fields{"bucket": bucket, "object": object}, dumpRequest(r))
 			writeErrorResponse(w, r, s3Error, r.URL.Path)
 			return
 		}
-		objInfo, err = objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
 	case authTypePresigned, authTypeSigned:
 		if s3Error := reqSignatureV4Verify(r); s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			errorIfContext(errSignatureMismatch, fields{"bucket": bucket, "object": object}, dumpRequest(r))
 			writeErrorResponse(w, r, s3Error, r.URL.Path)
 			return
 		}
 		if !skipContentSha256Cksum(r) {
 			sha256sum = r.Header.Get("X-Amz-Content-Sha256")
 		}
-		// Create object.
-		objInfo, err = objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
 	}
+
+	// Verify if the upload fits in the bucket quota.
+	quota, s3Error := checkBucketQuota(bucket, object, size, objectAPI)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+	// Uploads of unknown size are stopped once they cross the quota.
+	if size == -1 {
+		reader = quota.limitReader(reader)
+	}
+
+	// Locked objects cannot be overwritten until their retention expires.
+	lockMetadata, s3Error := checkPutObjectLock(bucket, object, r, objectAPI)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+
+	// Extract metadata to be saved from incoming HTTP header.
+	metadata := extractMetadataFromHeader(r.Header)
+	// Save the object lock along with the object.
+	for k, v := range lockMetadata {
+		metadata[k] = v
+	}
+	// Make sure we hex encode md5sum here.
+	metadata["md5Sum"] = hex.EncodeToString(md5Bytes)
+	// Save the canned ACL along with the object.
+	if acl != "" {
+		metadata[objectACLMetaKey] = acl
+	}
+	// Object data is verified against the checksum before it is saved.
+	if checksum != "" {
+		metadata[objectChecksumMetaKey] = checksum
+	}
+	// Save the storage class along with the object.
+	if storageClass != "" {
+		metadata[storageClassMetaKey] = storageClass
+	}
+
+	// Compressible objects of known size are compressed if enabled,
+	// encrypted objects are not.
+	compress := globalCompression && !encrypt && size >= 0 && isCompressibleContentType(r.Header.Get("Content-Type"))
+
+	// Encrypted and compressed data is verified against the checksums sent
+	// by the client while it is transformed, the object layer only sees the
+	// transformed data.
+	putObject := func(data io.Reader, sha256sum string) (ObjectInfo, error) {
+		// Objects are served with the content type saved here, HEAD
+		// and GET never detect it from the data.
+		if metadata["content-type"] == "" {
+			data = newContentTypeReader(object, data, size, metadata)
+		}
+		if compress {
+			verifier := newPlaintextVerifier(metadata["md5Sum"], sha256sum, metadata[objectChecksumMetaKey])
+			delete(metadata, "md5Sum")
+			delete(metadata, objectChecksumMetaKey)
+			metadata[compressionMetaKey] = compressionAlgorithmGzip
+			metadata[compressionSizeMetaKey] = strconv.FormatInt(size, 10)
+			compressor := newGzipCompressReader(data, size, verifier, metadata)
+			defer compressor.Close()
+			return objectAPI.PutObject(bucket, object, -1, compressor, metadata, "")
+		}
+		if !encrypt {
+			return objectAPI.PutObject(bucket, object, size, data, metadata, sha256sum)
+		}
+		sse, err := newSSEObject(globalSSEMasterKey, size, metadata)
+		if err != nil {
+			return ObjectInfo{}, err
+		}
+		verifier := newPlaintextVerifier(metadata["md5Sum"], sha256sum, metadata[objectChecksumMetaKey])
+		delete(metadata, "md5Sum")
+		delete(metadata, objectChecksumMetaKey)
+		return objectAPI.PutObject(bucket, object, sseEncryptedSize(size), newSSEEncryptReader(data, sse, verifier), metadata, "")
+	}
+
+	// Create object.
+	objInfo, err := putObject(reader, sha256sum)
 	if err != nil {
-		errorIf(err, "Unable to create an object.")
+		errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to create an object.")
+		closeOnChecksumMismatch(w, err)
 		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
 		return
 	}
-	w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
+	quota.commit(objInfo.Size)
+	w.Header().Set("ETag", "\""+getObjectETag(objInfo)+"\"")
+	if encrypt {
+		w.Header().Set(sseAlgorithmMetaKey, sseAlgorithmAES256)
+	}
 	writeSuccessResponse(w, nil)
 
 	// Notify object created event.
@@ -482,15 +882,44 @@ func (api objectAPIHandlers) NewMultipartUploadHandler(w http.ResponseWriter, r
 		return
 	}
 
+	// Multipart uploads are not encrypted.
+	if _, ok := r.Header[sseAlgorithmMetaKey]; ok {
+		writeErrorResponse(w, r, ErrNotImplemented, r.URL.Path)
+		return
+	}
+
+	// Verify if the bucket can take one more multipart upload.
+	if s3Error := checkMultipartUploadsLimit(bucket, objectAPI); s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+
+	// Validate requested storage class if any.
+	storageClass, s3Error := getStorageClass(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+
 	// Extract metadata that needs to be saved.
 	metadata := extractMetadataFromHeader(r.Header)
+	if storageClass != "" {
+		metadata[storageClassMetaKey] = storageClass
+	}
+
+	// Save the expected size of the object for the upload progress,
+	// if the client provided it.
+	if size := r.Header.Get("X-Amz-Decoded-Content-Length"); size != "" {
+		metadata[uploadSizeMetaKey] = size
+	}
 
 	uploadID, err := objectAPI.NewMultipartUpload(bucket, object, metadata)
 	if err != nil {
-		errorIf(err, "Unable to initiate new multipart upload id.")
+		errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to initiate new multipart upload id.")
 		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
 		return
 	}
+	globalMultipartUploadsCache.add(bucket)
 
 	response := generateInitiateMultipartUploadResponse(bucket, object, uploadID)
 	encodedSuccessResponse := encodeResponse(response)
@@ -502,6 +931,12 @@ func (api objectAPIHandlers) NewMultipartUploadHandler(w http.ResponseWriter, r
 
 // PutObjectPartHandler - Upload part
 func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http.Request) {
+	// Upload part copy requests carry x-amz-copy-source instead of part data.
+	if _, ok := r.Header["X-Amz-Copy-Source"]; ok {
+		api.CopyObjectPartHandler(w, r)
+		return
+	}
+
 	vars := mux.Vars(r)
 	bucket := vars["bucket"]
 	object := vars["object"]
@@ -528,7 +963,7 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		sizeStr := r.Header.Get("x-amz-decoded-content-length")
 		size, err = strconv.ParseInt(sizeStr, 10, 64)
 		if err != nil {
-			errorIf(err, "Unable to parse `x-amz-decoded-content-length` into its integer value", sizeStr)
+			errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to parse `x-amz-decoded-content-length` %s into its integer value", sizeStr)
 			writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
 			return
 		}
@@ -539,7 +974,7 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 	}
 
 	/// maximum Upload size for multipart objects in a single operation
-	if isMaxObjectSize(size) {
+	if isMaxPartSize(size) {
 		writeErrorResponse(w, r, ErrEntityTooLarge, r.URL.Path)
 		return
 	}
@@ -553,14 +988,15 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		return
 	}
 
-	// check partID with maximum part ID for multipart objects
-	if isMaxPartID(partID) {
-		writeErrorResponse(w, r, ErrInvalidMaxParts, r.URL.Path)
+	// check partID is within the allowed range for multipart objects
+	if !isValidPartID(partID) {
+		writeErrorResponse(w, r, ErrInvalidPartNumber, r.URL.Path)
 		return
 	}
 
-	var partMD5 string
-	incomingMD5 := hex.EncodeToString(md5Bytes)
+	// Authenticate the request before the object layer is consulted,
+	// reader is the body to save.
+	var reader io.Reader = r.Body
 	sha256sum := ""
 	switch rAuthType {
 	default:
@@ -569,32 +1005,30 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		return
 	case authTypeAnonymous:
 		// http://docs.aws.amazon.com/AmazonS3/latest/dev/mpuAndPermissions.html
-		if s3Error := enforceBucketPolicy(bucket, "s3:PutObject", r.URL); s3Error != ErrNone {
+		if s3Error := enforceBucketPolicy(bucket, object, "s3:PutObject", r.URL.Query()); s3Error != ErrNone {
 			writeErrorResponse(w, r, s3Error, r.URL.Path)
 			return
 		}
-		// No need to verify signature, anonymous request access is already allowed.
-		partMD5, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
+	case authTypeClientCert:
+		// Client certificate is already verified during TLS handshake.
 	case authTypeStreamingSigned:
 		// Initialize stream signature verifier.
-		reader, s3Error := newSignV4ChunkedReader(r)
+		var s3Error APIErrorCode
+		reader, s3Error = newSignV4ChunkedReader(r)
 		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			errorIfContext(errSignatureMismatch, fields{"bucket": bucket, "object": object, "uploadID": uploadID}, dumpRequest(r))
 			writeErrorResponse(w, r, s3Error, r.URL.Path)
 			return
 		}
-		partMD5, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, reader, incomingMD5, sha256sum)
 	case authTypeSignedV2, authTypePresignedV2:
-		s3Error := isReqAuthenticatedV2(r)
-		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+		if s3Error := isReqAuthenticatedV2(r); s3Error != ErrNone {
+			errorIfContext(errSignatureMismatch, fields{"bucket": bucket, "object": object, "uploadID": uploadID}, dumpRequest(r))
 			writeErrorResponse(w, r, s3Error, r.URL.Path)
 			return
 		}
-		partMD5, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
 	case authTypePresigned, authTypeSigned:
 		if s3Error := reqSignatureV4Verify(r); s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			errorIfContext(errSignatureMismatch, fields{"bucket": bucket, "object": object, "uploadID": uploadID}, dumpRequest(r))
 			writeErrorResponse(w, r, s3Error, r.URL.Path)
 			return
 		}
@@ -602,20 +1036,156 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		if !skipContentSha256Cksum(r) {
 			sha256sum = r.Header.Get("X-Amz-Content-Sha256")
 		}
-		partMD5, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
 	}
+
+	// Verify if the part fits in the bucket quota.
+	quota, s3Error := checkBucketQuota(bucket, "", size, objectAPI)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+
+	// Parts smaller than 5MiB are accepted only as the last part.
+	if err = checkPartSize(bucket, object, uploadID, partID, size, objectAPI); err != nil {
+		errorIfContext(err, fields{"bucket": bucket, "object": object, "uploadID": uploadID}, "Unable to create object part.")
+		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+		return
+	}
+
+	incomingMD5 := hex.EncodeToString(md5Bytes)
+	partMD5, err := objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, reader, incomingMD5, sha256sum)
 	if err != nil {
-		errorIf(err, "Unable to create object part.")
+		errorIfContext(err, fields{"bucket": bucket, "object": object, "uploadID": uploadID}, "Unable to create object part.")
+		closeOnChecksumMismatch(w, err)
 		// Verify if the underlying error is signature mismatch.
 		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
 		return
 	}
+	quota.commit(size)
 	if partMD5 != "" {
 		w.Header().Set("ETag", "\""+partMD5+"\"")
 	}
 	writeSuccessResponse(w, nil)
 }
 
+// CopyObjectPartHandler - Upload part copy
+// ----------
+// This implementation of the PUT operation uploads a part by copying
+// a byte range of an existing object as data source.
+func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, r, ErrServerNotInitialized, r.URL.Path)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutObject", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+
+	objectSource, sourceBucket, sourceObject := getCopySource(r)
+	// If source object is empty, reply back error.
+	if sourceObject == "" {
+		writeErrorResponse(w, r, ErrInvalidCopySource, r.URL.Path)
+		return
+	}
+
+	// Anonymous requests need read access to the source object as well.
+	if getRequestAuthType(r) == authTypeAnonymous {
+		if s3Error := enforceBucketPolicy(sourceBucket, sourceObject, "s3:GetObject", url.Values{}); s3Error != ErrNone {
+			writeErrorResponse(w, r, s3Error, objectSource)
+			return
+		}
+	}
+
+	uploadID := r.URL.Query().Get("uploadId")
+	partID, err := strconv.Atoi(r.URL.Query().Get("partNumber"))
+	if err != nil {
+		writeErrorResponse(w, r, ErrInvalidPart, r.URL.Path)
+		return
+	}
+
+	// check partID is within the allowed range for multipart objects
+	if !isValidPartID(partID) {
+		writeErrorResponse(w, r, ErrInvalidPartNumber, r.URL.Path)
+		return
+	}
+
+	objInfo, err := objectAPI.GetObjectInfo(sourceBucket, sourceObject)
+	if err != nil {
+		errorIfContext(err, fields{"bucket": sourceBucket, "object": sourceObject}, "Unable to fetch object info.")
+		writeErrorResponse(w, r, toAPIErrorCode(err), objectSource)
+		return
+	}
+
+	// Verify anonymous read access against the ACL of the source object.
+	if s3Error := checkObjectACL(r, objInfo); s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, objectSource)
+		return
+	}
+
+	// Parts are neither encrypted nor compressed, such sources
+	// cannot be copied into them.
+	if isObjectEncrypted(objInfo) || isObjectCompressed(objInfo) {
+		writeErrorResponse(w, r, ErrNotImplemented, objectSource)
+		return
+	}
+
+	// Verify before x-amz-copy-source preconditions before continuing with the copy.
+	if checkCopyObjectPreconditions(w, r, objInfo) {
+		return
+	}
+
+	// Copy the whole source object unless a range is requested.
+	startOffset, length := int64(0), objInfo.Size
+	if rangeHeader := r.Header.Get("X-Amz-Copy-Source-Range"); rangeHeader != "" {
+		hrange, rErr := parseCopyPartRange(rangeHeader, objInfo.Size)
+		if rErr != nil {
+			errorIfContext(rErr, fields{"bucket": sourceBucket, "object": sourceObject}, "Invalid copy source range %s", rangeHeader)
+			if rErr == errInvalidRange {
+				writeErrorResponse(w, r, ErrInvalidRange, objectSource)
+				return
+			}
+			writeErrorResponse(w, r, ErrInvalidCopyPartRange, r.URL.Path)
+			return
+		}
+		startOffset, length = hrange.offsetBegin, hrange.getLength()
+	}
+
+	/// maximum Upload size for multipart objects in a single operation
+	if isMaxObjectSize(length) {
+		writeErrorResponse(w, r, ErrEntityTooLarge, objectSource)
+		return
+	}
+
+	// Verify if the part fits in the bucket quota.
+	quota, s3Error := checkBucketQuota(bucket, "", length, objectAPI)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, r, s3Error, r.URL.Path)
+		return
+	}
+
+	partMD5, err := objectAPI.CopyObjectPart(sourceBucket, sourceObject, bucket, object, uploadID, partID, startOffset, length)
+	if err != nil {
+		errorIfContext(err, fields{"bucket": bucket, "object": object, "uploadID": uploadID}, "Unable to copy object part.")
+		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
+		return
+	}
+	quota.commit(length)
+
+	response := generateCopyObjectPartResponse(partMD5, time.Now().UTC())
+	encodedSuccessResponse := encodeResponse(response)
+	// write headers
+	setCommonHeaders(w)
+	// write success response.
+	writeSuccessResponse(w, encodedSuccessResponse)
+}
+
 // AbortMultipartUploadHandler - Abort multipart upload
 func (api objectAPIHandlers) AbortMultipartUploadHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -635,10 +1205,12 @@ func (api objectAPIHandlers) AbortMultipartUploadHandler(w http.ResponseWriter,
 
 	uploadID, _, _, _ := getObjectResources(r.URL.Query())
 	if err := objectAPI.AbortMultipartUpload(bucket, object, uploadID); err != nil {
-		errorIf(err, "Unable to abort multipart upload.")
+		errorIfContext(err, fields{"bucket": bucket, "object": object, "uploadID": uploadID}, "Unable to abort multipart upload.")
 		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
 		return
 	}
+	globalBucketQuotaCache.invalidate(bucket)
+	globalMultipartUploadsCache.invalidate(bucket)
 	writeSuccessNoContent(w)
 }
 
@@ -670,7 +1242,7 @@ func (api objectAPIHandlers) ListObjectPartsHandler(w http.ResponseWriter, r *ht
 	}
 	listPartsInfo, err := objectAPI.ListObjectParts(bucket, object, uploadID, partNumberMarker, maxParts)
 	if err != nil {
-		errorIf(err, "Unable to list uploaded parts.")
+		errorIfContext(err, fields{"bucket": bucket, "object": object, "uploadID": uploadID}, "Unable to list uploaded parts.")
 		writeErrorResponse(w, r, toAPIErrorCode(err), r.URL.Path)
 		return
 	}
@@ -705,13 +1277,13 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 	var md5Sum string
 	completeMultipartBytes, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		errorIf(err, "Unable to complete multipart upload.")
+		errorIfContext(err, fields{"bucket": bucket, "object": object, "uploadID": uploadID}, "Unable to complete multipart upload.")
 		writeErrorResponse(w, r, ErrInternalError, r.URL.Path)
 		return
 	}
 	complMultipartUpload := &completeMultipartUpload{}
 	if err = xml.Unmarshal(completeMultipartBytes, complMultipartUpload); err != nil {
-		errorIf(err, "Unable to parse complete multipart upload XML.")
+		errorIfContext(err, fields{"bucket": bucket, "object": object, "uploadID": uploadID}, "Unable to parse complete multipart upload XML.")
 		writeErrorResponse(w, r, ErrMalformedXML, r.URL.Path)
 		return
 	}
@@ -723,6 +1295,12 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 		writeErrorResponse(w, r, ErrInvalidPartOrder, r.URL.Path)
 		return
 	}
+	for _, part := range complMultipartUpload.Parts {
+		if !isValidPartID(part.PartNumber) {
+			writeErrorResponse(w, r, ErrInvalidPartNumber, r.URL.Path)
+			return
+		}
+	}
 	// Complete parts.
 	var completeParts []completePart
 	for _, part := range complMultipartUpload.Parts {
@@ -734,7 +1312,7 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 	md5Sum, err = objectAPI.CompleteMultipartUpload(bucket, object, uploadID, completeParts)
 	if err != nil {
 		err = errorCause(err)
-		errorIf(err, "Unable to complete multipart upload.")
+		errorIfContext(err, fields{"bucket": bucket, "object": object, "uploadID": uploadID}, "Unable to complete multipart upload.")
 		switch oErr := err.(type) {
 		case PartTooSmall:
 			// Write part too small error.
@@ -745,6 +1323,7 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 		}
 		return
 	}
+	globalMultipartUploadsCache.invalidate(bucket)
 
 	// Get object location.
 	location := getLocation(r)
@@ -752,7 +1331,7 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 	response := generateCompleteMultpartUploadResponse(bucket, object, location, md5Sum)
 	encodedSuccessResponse := encodeResponse(response)
 	if err != nil {
-		errorIf(err, "Unable to parse CompleteMultipartUpload response")
+		errorIfContext(err, fields{"bucket": bucket, "object": object, "uploadID": uploadID}, "Unable to parse CompleteMultipartUpload response")
 		writeErrorResponseNoHeader(w, r, ErrInternalError, r.URL.Path)
 		return
 	}
@@ -767,7 +1346,7 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 	// Fetch object info for notifications.
 	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
 	if err != nil {
-		errorIf(err, "Unable to fetch object info for \"%s\"", path.Join(bucket, object))
+		errorIfContext(err, fields{"bucket": bucket, "object": object}, "Unable to fetch object info.")
 		return
 	}
 
@@ -803,11 +1382,16 @@ func (api objectAPIHandlers) DeleteObjectHandler(w http.ResponseWriter, r *http.
 
 	/// http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectDELETE.html
 	/// Ignore delete object errors, since we are suppposed to reply
-	/// only 204.
+	/// only 204. Locked objects are the exception, they are not deleted.
 	if err := objectAPI.DeleteObject(bucket, object); err != nil {
+		if _, ok := errorCause(err).(ObjectLocked); ok {
+			writeErrorResponse(w, r, ErrAccessDenied, r.URL.Path)
+			return
+		}
 		writeSuccessNoContent(w)
 		return
 	}
+	globalBucketQuotaCache.invalidate(bucket)
 	writeSuccessNoContent(w)
 
 	// Notify object deleted event.
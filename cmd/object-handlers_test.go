@@ -17,17 +17,29 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/xml"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"path"
+	"runtime"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	humanize "github.com/dustin/go-humanize"
 )
@@ -191,6 +203,75 @@ func testAPIHeadObjectHandler(obj ObjectLayer, instanceType, bucketName string,
 	ExecObjectLayerAPINilTest(t, nilBucket, nilObject, instanceType, apiRouter, nilReq)
 }
 
+// readFileCountingDisk - counts the object data reads of a disk.
+type readFileCountingDisk struct {
+	StorageAPI
+	reads *int32
+}
+
+func (d readFileCountingDisk) ReadFile(volume string, path string, offset int64, buf []byte) (int64, error) {
+	atomic.AddInt32(d.reads, 1)
+	return d.StorageAPI.ReadFile(volume, path, offset, buf)
+}
+
+// Wrapper for calling HeadObject stat tests for both XL multiple disks and FS single drive setup.
+func TestAPIHeadObjectHandlerStat(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPIHeadObjectHandlerStat, []string{"HeadObject"})
+}
+
+// Tests HeadObject replies with the size and ETag of an object without
+// reading its data.
+func testAPIHeadObjectHandlerStat(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	objectName := "test-object"
+	data := generateBytesData(2 * humanize.MiByte)
+	objInfo, err := obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), nil, "")
+	if err != nil {
+		t.Fatalf("%s: Error uploading object: <ERROR> %v", instanceType, err)
+	}
+
+	// Serve HeadObject from disks counting the data reads.
+	var reads int32
+	var countingObj ObjectLayer
+	switch instanceType {
+	case FSTestStr:
+		fs := obj.(fsObjects)
+		fs.storage = readFileCountingDisk{fs.storage, &reads}
+		countingObj = fs
+	case XLTestStr:
+		xl := *obj.(*xlObjects)
+		xl.storageDisks = make([]StorageAPI, len(obj.(*xlObjects).storageDisks))
+		for i, disk := range obj.(*xlObjects).storageDisks {
+			xl.storageDisks[i] = readFileCountingDisk{disk, &reads}
+		}
+		countingObj = &xl
+	}
+	router := initTestAPIEndPoints(countingObj, []string{"HeadObject"})
+
+	req, err := newTestSignedRequestV4("HEAD", getHeadObjectURL("", bucketName, objectName),
+		0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for Head Object: <ERROR> %v", instanceType, err)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	if contentLength := rec.Header().Get("Content-Length"); contentLength != strconv.Itoa(len(data)) {
+		t.Errorf("%s: Expected Content-Length `%d`, but found `%s`", instanceType, len(data), contentLength)
+	}
+	if etag := rec.Header().Get("ETag"); etag != "\""+objInfo.MD5Sum+"\"" {
+		t.Errorf("%s: Expected ETag `\"%s\"`, but found `%s`", instanceType, objInfo.MD5Sum, etag)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("%s: Expected no response body, found %d bytes", instanceType, rec.Body.Len())
+	}
+	if n := atomic.LoadInt32(&reads); n != 0 {
+		t.Errorf("%s: Expected no object data to be read, read %d times", instanceType, n)
+	}
+}
+
 // Wrapper for calling GetObject API handler tests for both XL multiple disks and FS single drive setup.
 func TestAPIGetObjectHandler(t *testing.T) {
 	defer DetectTestLeak(t)()
@@ -704,6 +785,8 @@ func testAPIPutObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 	copySourceHeader.Set("X-Amz-Copy-Source", "somewhere")
 	invalidMD5Header := http.Header{}
 	invalidMD5Header.Set("Content-Md5", "42")
+	mismatchMD5Header := http.Header{}
+	mismatchMD5Header.Set("Content-Md5", getMD5HashBase64([]byte("other")))
 
 	addCustomHeaders := func(req *http.Request, customHeaders http.Header) {
 		for k, values := range customHeaders {
@@ -724,7 +807,8 @@ func testAPIPutObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 		secretKey  string
 		fault      Fault
 		// expected output.
-		expectedRespStatus int // expected response status body.
+		expectedRespStatus int  // expected response status body.
+		expectedConnClose  bool // expected connection to be closed after the response.
 	}{
 		// Test case - 1.
 		// Fetching the entire object and validating its contents.
@@ -798,6 +882,20 @@ func testAPIPutObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 			fault:              MissingContentLength,
 			expectedRespStatus: http.StatusLengthRequired,
 		},
+		// Test case - 7.
+		// Test Case with Content-Md5 not matching the data, the connection
+		// is closed after the error response.
+		{
+			bucketName:         bucketName,
+			objectName:         objectName,
+			headers:            mismatchMD5Header,
+			data:               bytesData,
+			dataLen:            len(bytesData),
+			accessKey:          credentials.AccessKeyID,
+			secretKey:          credentials.SecretAccessKey,
+			expectedRespStatus: http.StatusBadRequest,
+			expectedConnClose:  true,
+		},
 	}
 	// Iterating over the cases, fetching the object validating the response.
 	for i, testCase := range testCases {
@@ -810,8 +908,14 @@ func testAPIPutObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 		if err != nil {
 			t.Fatalf("Test %d: Failed to create HTTP request for Put Object: <ERROR> %v", i+1, err)
 		}
-		// Add test case specific headers to the request.
-		addCustomHeaders(req, testCase.headers)
+		// Add test case specific headers to the request, signing again
+		// so that the signature covers them.
+		if testCase.headers != nil {
+			addCustomHeaders(req, testCase.headers)
+			if err = signRequestV4(req, testCase.accessKey, testCase.secretKey); err != nil {
+				t.Fatalf("Test %d: Failed to sign HTTP request for Put Object: <ERROR> %v", i+1, err)
+			}
+		}
 
 		// Inject faults if specified in testCase.fault
 		switch testCase.fault {
@@ -828,6 +932,9 @@ func testAPIPutObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 		if rec.Code != testCase.expectedRespStatus {
 			t.Fatalf("Case %d: Expected the response status to be `%d`, but instead found `%d`", i+1, testCase.expectedRespStatus, rec.Code)
 		}
+		if connClose := rec.Header().Get("Connection") == "close"; connClose != testCase.expectedConnClose {
+			t.Fatalf("Case %d: Expected connection close to be `%t`, but instead found `%t`", i+1, testCase.expectedConnClose, connClose)
+		}
 		if testCase.expectedRespStatus == http.StatusOK {
 			buffer := new(bytes.Buffer)
 
@@ -853,8 +960,14 @@ func testAPIPutObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 			t.Fatalf("Test %d: %s: Failed to create HTTP request for PutObject: <ERROR> %v", i+1, instanceType, err)
 		}
 
-		// Add test case specific headers to the request.
-		addCustomHeaders(reqV2, testCase.headers)
+		// Add test case specific headers to the request, signing again
+		// so that the signature covers them.
+		if testCase.headers != nil {
+			addCustomHeaders(reqV2, testCase.headers)
+			if err = signRequestV2(reqV2, testCase.accessKey, testCase.secretKey); err != nil {
+				t.Fatalf("Test %d: %s: Failed to sign HTTP request for PutObject: <ERROR> %v", i+1, instanceType, err)
+			}
+		}
 
 		// Inject faults if specified in testCase.fault
 		switch testCase.fault {
@@ -918,6 +1031,133 @@ func testAPIPutObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 
 }
 
+// Wrapper for calling x-amz-checksum-sha256 tests for both XL multiple disks and single node setup.
+func TestAPIPutObjectChecksumHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIPutObjectChecksumHandler, []string{"PutObject", "GetObject"})
+}
+
+func testAPIPutObjectChecksumHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	data := []byte("hello, world")
+	sum := sha256.Sum256(data)
+	checksum := base64.StdEncoding.EncodeToString(sum[:])
+	otherSum := sha256.Sum256([]byte("hello, other world"))
+
+	testCases := []struct {
+		objectName string
+		checksum   string
+
+		expectedRespStatus int
+		expectedErrCode    string
+	}{
+		// Test case - 1.
+		// Matching checksum.
+		{"object-1", checksum, http.StatusOK, ""},
+		// Test case - 2.
+		// No checksum, it is computed by the server.
+		{"object-2", "", http.StatusOK, ""},
+		// Test case - 3.
+		// Checksum of some other data.
+		{"object-3", base64.StdEncoding.EncodeToString(otherSum[:]), http.StatusBadRequest, "BadChecksum"},
+		// Test case - 4.
+		// Checksum which is not a base64 encoded SHA-256.
+		{"object-4", base64.StdEncoding.EncodeToString([]byte("invalid")), http.StatusBadRequest, "InvalidDigest"},
+	}
+	for i, testCase := range testCases {
+		req, err := newTestSignedRequestV4("PUT", getPutObjectURL("", bucketName, testCase.objectName),
+			int64(len(data)), bytes.NewReader(data), credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request for Put Object: <ERROR> %v", i+1, err)
+		}
+		if testCase.checksum != "" {
+			req.Header.Set("X-Amz-Checksum-Sha256", testCase.checksum)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+		if testCase.expectedErrCode != "" {
+			errResp := APIErrorResponse{}
+			if err = xml.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+				t.Fatalf("Test %d: %s: Failed to parse the error response: <ERROR> %v", i+1, instanceType, err)
+			}
+			if errResp.Code != testCase.expectedErrCode {
+				t.Errorf("Test %d: %s: Expected error code `%s`, but found `%s`", i+1, instanceType, testCase.expectedErrCode, errResp.Code)
+			}
+			// Object should not be created.
+			if _, err = obj.GetObjectInfo(bucketName, testCase.objectName); err == nil {
+				t.Errorf("Test %d: %s: Expected object not to be created", i+1, instanceType)
+			}
+			continue
+		}
+
+		// Checksum is returned on GET.
+		req, err = newTestSignedRequestV4("GET", getGetObjectURL("", bucketName, testCase.objectName),
+			0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request for Get Object: <ERROR> %v", i+1, err)
+		}
+		rec = httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, http.StatusOK, rec.Code)
+		}
+		if gotChecksum := rec.Header().Get("X-Amz-Checksum-Sha256"); gotChecksum != checksum {
+			t.Errorf("Test %d: %s: Expected checksum `%s`, but found `%s`", i+1, instanceType, checksum, gotChecksum)
+		}
+	}
+
+	// Alter the saved checksum of an object, the checksum header of
+	// the GET response should not match the object data anymore.
+	objectName := "object-1"
+	alteredChecksum := base64.StdEncoding.EncodeToString(otherSum[:])
+	switch instanceType {
+	case FSTestStr:
+		fs := obj.(fsObjects)
+		fsMetaPath := path.Join(bucketMetaPrefix, bucketName, objectName, fsMetaJSONFile)
+		fsMeta, err := readFSMetadata(fs.storage, minioMetaBucket, fsMetaPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fsMeta.Meta[objectChecksumMetaKey] = alteredChecksum
+		if err = writeFSMetadata(fs.storage, minioMetaBucket, fsMetaPath, fsMeta); err != nil {
+			t.Fatal(err)
+		}
+	case XLTestStr:
+		xl := obj.(*xlObjects)
+		for _, disk := range xl.storageDisks {
+			xlMeta, err := readXLMeta(disk, bucketName, objectName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			xlMeta.Meta[objectChecksumMetaKey] = alteredChecksum
+			if err = disk.DeleteFile(bucketName, path.Join(objectName, xlMetaJSONFile)); err != nil {
+				t.Fatal(err)
+			}
+			if err = writeXLMetadata(disk, bucketName, objectName, xlMeta); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	req, err := newTestSignedRequestV4("GET", getGetObjectURL("", bucketName, objectName),
+		0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request for Get Object: <ERROR> %v", err)
+	}
+	rec := httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	bodySum := sha256.Sum256(rec.Body.Bytes())
+	if rec.Header().Get("X-Amz-Checksum-Sha256") == base64.StdEncoding.EncodeToString(bodySum[:]) {
+		t.Errorf("%s: Expected checksum header not to match the altered object", instanceType)
+	}
+}
+
 // Wrapper for calling Copy Object API handler tests for both XL multiple disks and single node setup.
 func TestAPICopyObjectHandler(t *testing.T) {
 	defer DetectTestLeak(t)()
@@ -978,11 +1218,12 @@ func testAPICopyObjectHandler(obj ObjectLayer, instanceType, bucketName string,
 
 	// test cases with inputs and expected result for Copy Object.
 	testCases := []struct {
-		bucketName       string
-		newObjectName    string // name of the newly copied object.
-		copySourceHeader string // data for "X-Amz-Copy-Source" header. Contains the object to be copied in the URL.
-		accessKey        string
-		secretKey        string
+		bucketName        string
+		newObjectName     string // name of the newly copied object.
+		copySourceHeader  string // data for "X-Amz-Copy-Source" header. Contains the object to be copied in the URL.
+		metadataDirective string // data for "X-Amz-Metadata-Directive" header.
+		accessKey         string
+		secretKey         string
 		// expected output.
 		expectedRespStatus int
 	}{
@@ -1056,6 +1297,20 @@ func testAPICopyObjectHandler(obj ObjectLayer, instanceType, bucketName string,
 
 			expectedRespStatus: http.StatusForbidden,
 		},
+		// Test case - 7.
+		// Test case with new object name is same as object to be copied
+		// but with metadata directive set to replace, only the metadata
+		// is updated.
+		{
+			bucketName:        bucketName,
+			newObjectName:     objectName,
+			copySourceHeader:  url.QueryEscape("/" + bucketName + "/" + objectName),
+			metadataDirective: "REPLACE",
+			accessKey:         credentials.AccessKeyID,
+			secretKey:         credentials.SecretAccessKey,
+
+			expectedRespStatus: http.StatusOK,
+		},
 	}
 
 	for i, testCase := range testCases {
@@ -1073,6 +1328,9 @@ func testAPICopyObjectHandler(obj ObjectLayer, instanceType, bucketName string,
 		if testCase.copySourceHeader != "" {
 			req.Header.Set("X-Amz-Copy-Source", testCase.copySourceHeader)
 		}
+		if testCase.metadataDirective != "" {
+			req.Header.Set("X-Amz-Metadata-Directive", testCase.metadataDirective)
+		}
 		// Since `apiRouter` satisfies `http.Handler` it has a ServeHTTP to execute the logic of the handler.
 		// Call the ServeHTTP to execute the handler, `func (api objectAPIHandlers) CopyObjectHandler` handles the request.
 		apiRouter.ServeHTTP(rec, req)
@@ -1105,6 +1363,9 @@ func testAPICopyObjectHandler(obj ObjectLayer, instanceType, bucketName string,
 		if testCase.copySourceHeader != "" {
 			reqV2.Header.Set("X-Amz-Copy-Source", testCase.copySourceHeader)
 		}
+		if testCase.metadataDirective != "" {
+			reqV2.Header.Set("X-Amz-Metadata-Directive", testCase.metadataDirective)
+		}
 
 		err = signRequestV2(reqV2, testCase.accessKey, testCase.secretKey)
 
@@ -1132,9 +1393,9 @@ func testAPICopyObjectHandler(obj ObjectLayer, instanceType, bucketName string,
 	// Its necessary to set the "X-Amz-Copy-Source" header for the request to be accepted by the handler.
 	anonReq.Header.Set("X-Amz-Copy-Source", url.QueryEscape("/"+bucketName+"/"+anonObject))
 	// ExecObjectLayerAPIAnonTest - Calls the HTTP API handler using the anonymous request, validates the ErrAccessDeniedResponse,
-	// sets the bucket policy using the policy statement generated from `getWriteOnlyObjectStatement` so that the
-	// unsigned request goes through and its validated again.
-	ExecObjectLayerAPIAnonTest(t, "TestAPICopyObjectHandler", bucketName, newCopyAnonObject, instanceType, apiRouter, anonReq, getWriteOnlyObjectStatement)
+	// sets the bucket policy using the policy statement generated from `getReadWriteObjectStatement` so that the
+	// unsigned request goes through and its validated again, reading the copy source needs the read permission.
+	ExecObjectLayerAPIAnonTest(t, "TestAPICopyObjectHandler", bucketName, newCopyAnonObject, instanceType, apiRouter, anonReq, getReadWriteObjectStatement)
 
 	// HTTP request to test the case of `objectLayer` being set to `nil`.
 	// There is no need to use an existing bucket or valid input for creating the request,
@@ -1160,6 +1421,126 @@ func testAPICopyObjectHandler(obj ObjectLayer, instanceType, bucketName string,
 
 }
 
+// Wrapper for calling Copy Object API handler tests across buckets with different policies.
+func TestAPICopyObjectHandlerAcrossBuckets(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPICopyObjectHandlerAcrossBuckets, []string{"CopyObject"})
+}
+
+func testAPICopyObjectHandlerAcrossBuckets(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	publicSource := getRandomBucketName()
+	privateSource := getRandomBucketName()
+	publicDest := getRandomBucketName()
+	privateDest := getRandomBucketName()
+	for _, bucket := range []string{publicSource, privateSource, publicDest, privateDest} {
+		if err := obj.MakeBucket(bucket); err != nil {
+			t.Fatalf("Minio %s: Failed to create bucket %s: <ERROR> %v", instanceType, bucket, err)
+		}
+	}
+
+	// Anonymous read access on the public source, write access on the public destination.
+	policies := map[string]policyStatement{
+		publicSource: getReadOnlyObjectStatement(publicSource, ""),
+		publicDest:   getWriteOnlyObjectStatement(publicDest, ""),
+	}
+	for bucket, statement := range policies {
+		policy := bucketPolicy{Version: "1.0", Statements: []policyStatement{statement}}
+		globalBucketPolicies.SetBucketPolicy(bucket, policyChange{false, &policy})
+	}
+	defer func() {
+		for bucket := range policies {
+			globalBucketPolicies.SetBucketPolicy(bucket, policyChange{IsRemove: true})
+		}
+	}()
+
+	objectName := "object"
+	privateObject := "private-object"
+	publicACLObject := "public-acl-object"
+	data := []byte("hello, world")
+	objects := []struct {
+		bucket, object string
+		metadata       map[string]string
+	}{
+		{publicSource, objectName, nil},
+		{publicSource, privateObject, map[string]string{objectACLMetaKey: "private"}},
+		{publicSource, publicACLObject, map[string]string{objectACLMetaKey: "public-read"}},
+		{privateSource, objectName, nil},
+	}
+	for _, o := range objects {
+		if _, err := obj.PutObject(o.bucket, o.object, int64(len(data)), bytes.NewReader(data), o.metadata, ""); err != nil {
+			t.Fatalf("Minio %s: Failed to create object %s/%s: <ERROR> %v", instanceType, o.bucket, o.object, err)
+		}
+	}
+
+	testCases := []struct {
+		sourceBucket, sourceObject string
+		destBucket, destObject     string
+		signed                     bool
+
+		expectedRespStatus int
+		expectedErrCode    string
+	}{
+		// Test case - 1.
+		// Anonymous copy from a readable source to a writable destination.
+		{publicSource, objectName, publicDest, "copy-1", false, http.StatusOK, ""},
+		// Test case - 2.
+		// Anonymous copy from a source bucket without read access.
+		{privateSource, objectName, publicDest, "copy-2", false, http.StatusForbidden, "AccessDenied"},
+		// Test case - 3.
+		// Anonymous copy of a source object with a private ACL.
+		{publicSource, privateObject, publicDest, "copy-3", false, http.StatusForbidden, "AccessDenied"},
+		// Test case - 4.
+		// Anonymous copy to a destination bucket without write access.
+		{publicSource, objectName, privateDest, "copy-4", false, http.StatusForbidden, "AccessDenied"},
+		// Test case - 5.
+		// Signed copy, the ACL of the source object is not inherited.
+		{publicSource, publicACLObject, privateDest, "copy-5", true, http.StatusOK, ""},
+		// Test case - 6.
+		// Signed copy of an object onto itself without replacing the metadata.
+		{privateSource, objectName, privateSource, objectName, true, http.StatusBadRequest, "InvalidRequest"},
+	}
+
+	for i, testCase := range testCases {
+		var req *http.Request
+		var err error
+		if testCase.signed {
+			req, err = newTestSignedRequestV4("PUT", getCopyObjectURL("", testCase.destBucket, testCase.destObject),
+				0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+		} else {
+			req, err = newTestRequest("PUT", getCopyObjectURL("", testCase.destBucket, testCase.destObject), 0, nil)
+		}
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request for copy Object: <ERROR> %v", i+1, err)
+		}
+		req.Header.Set("X-Amz-Copy-Source", url.QueryEscape("/"+testCase.sourceBucket+"/"+testCase.sourceObject))
+
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+		if testCase.expectedErrCode != "" {
+			errResp := APIErrorResponse{}
+			if err = xml.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+				t.Fatalf("Test %d: %s: Failed to parse the error response: <ERROR> %v", i+1, instanceType, err)
+			}
+			if errResp.Code != testCase.expectedErrCode {
+				t.Errorf("Test %d: %s: Expected error code `%s`, but found `%s`", i+1, instanceType, testCase.expectedErrCode, errResp.Code)
+			}
+			continue
+		}
+		objInfo, err := obj.GetObjectInfo(testCase.destBucket, testCase.destObject)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to stat the copied object: <ERROR> %v", i+1, instanceType, err)
+		}
+		if acl, ok := objInfo.UserDefined[objectACLMetaKey]; ok {
+			t.Errorf("Test %d: %s: Expected the source ACL not to be copied, but found `%s`", i+1, instanceType, acl)
+		}
+	}
+}
+
 // Wrapper for calling NewMultipartUpload tests for both XL multiple disks and single node setup.
 // First register the HTTP handler for NewMutlipartUpload, then a HTTP request for NewMultipart upload is made.
 // The UploadID from the response body is parsed and its existence is asserted with an attempt to ListParts using it.
@@ -1306,6 +1687,78 @@ func testAPINewMultipartHandler(obj ObjectLayer, instanceType, bucketName string
 
 }
 
+// Tests NewMultipartUpload is rejected once a bucket reaches the maximum
+// number of in-progress multipart uploads.
+func TestAPINewMultipartHandlerLimit(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPINewMultipartHandlerLimit, []string{"NewMultipart", "AbortMultipart"})
+}
+
+func testAPINewMultipartHandlerLimit(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	maxUploads := 3
+	defer func(limit int) { globalMaxMultipartUploadsPerBucket = limit }(globalMaxMultipartUploadsPerBucket)
+	globalMaxMultipartUploadsPerBucket = maxUploads
+	globalMultipartUploadsCache.invalidate(bucketName)
+	defer globalMultipartUploadsCache.invalidate(bucketName)
+
+	newMultipartUpload := func(objectName string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("POST", getNewMultipartURL("", bucketName, objectName),
+			0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for NewMultipart Request: <ERROR> %v", instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The first maxUploads uploads are initiated.
+	var uploadID string
+	for i := 1; i <= maxUploads; i++ {
+		rec := newMultipartUpload(fmt.Sprintf("object-%d", i))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Upload %d: expected the response status to be `%d`, but instead found `%d`", instanceType, i, http.StatusOK, rec.Code)
+		}
+		multipartResponse := &InitiateMultipartUploadResponse{}
+		if err := xml.NewDecoder(rec.Body).Decode(multipartResponse); err != nil {
+			t.Fatalf("%s: Error decoding the recorded response Body", instanceType)
+		}
+		uploadID = multipartResponse.UploadID
+	}
+
+	// The next one exceeds the limit.
+	rec := newMultipartUpload("object-rejected")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusTooManyRequests, rec.Code)
+	}
+	errResponse := APIErrorResponse{}
+	if err := xml.NewDecoder(rec.Body).Decode(&errResponse); err != nil {
+		t.Fatalf("%s: Error decoding the recorded response Body", instanceType)
+	}
+	if errResponse.Code != "TooManyRequests" {
+		t.Errorf("%s: Expected error code TooManyRequests, got %s", instanceType, errResponse.Code)
+	}
+
+	// Aborting an upload makes room for a new one.
+	rec = httptest.NewRecorder()
+	req, err := newTestSignedRequestV4("DELETE", getAbortMultipartUploadURL("", bucketName, fmt.Sprintf("object-%d", maxUploads), uploadID),
+		0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for AbortMultipart Request: <ERROR> %v", instanceType, err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNoContent, rec.Code)
+	}
+	if rec = newMultipartUpload("object-accepted"); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	if rec = newMultipartUpload("object-rejected"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusTooManyRequests, rec.Code)
+	}
+}
+
 // Wrapper for calling NewMultipartUploadParallel tests for both XL multiple disks and single node setup.
 // The objective of the test is to initialte multipart upload on the same object 10 times concurrently,
 // The UploadID from the response body is parsed and its existence is asserted with an attempt to ListParts using it.
@@ -1496,6 +1949,14 @@ func testAPICompleteMultipartHandler(obj ObjectLayer, instanceType, bucketName s
 				{ETag: validPartMD5, PartNumber: 2},
 			},
 		},
+		// inputParts - 6.
+		// Case with part number beyond the allowed range.
+		{
+			[]completePart{
+				{ETag: validPartMD5, PartNumber: 5},
+				{ETag: validPartMD5, PartNumber: maxPartID + 1},
+			},
+		},
 	}
 
 	// on successful complete multipart operation the s3MD5 for the parts uploaded will be returned.
@@ -1637,6 +2098,21 @@ func testAPICompleteMultipartHandler(obj ObjectLayer, instanceType, bucketName s
 			expectedContent:    encodedSuccessResponse,
 			expectedRespStatus: http.StatusOK,
 		},
+		// Test case - 9.
+		// Part number is out of the allowed range.
+		// This should return ErrInvalidPartNumber in the response body.
+		{
+			bucket:    bucketName,
+			object:    objectName,
+			uploadID:  uploadIDs[1],
+			parts:     inputParts[6].parts,
+			accessKey: credentials.AccessKeyID,
+			secretKey: credentials.SecretAccessKey,
+
+			expectedContent: encodeResponse(getAPIErrorResponse(getAPIError(ErrInvalidPartNumber),
+				getGetObjectURL("", bucketName, objectName))),
+			expectedRespStatus: http.StatusBadRequest,
+		},
 	}
 
 	for i, testCase := range testCases {
@@ -1723,6 +2199,72 @@ func testAPICompleteMultipartHandler(obj ObjectLayer, instanceType, bucketName s
 	ExecObjectLayerAPINilTest(t, nilBucket, nilObject, instanceType, apiRouter, nilReq)
 }
 
+// Tests the ETag of a completed multipart upload is the MD5 of the binary
+// MD5 sums of its parts followed by the number of parts.
+func TestAPICompleteMultipartETag(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPICompleteMultipartETag, []string{"CompleteMultipart"})
+}
+
+func testAPICompleteMultipartETag(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	objectName := "test-object-multipart-etag"
+	uploadID, err := obj.NewMultipartUpload(bucketName, objectName, nil)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	partsData := [][]byte{
+		bytes.Repeat([]byte("a"), 5*humanize.MiByte),
+		bytes.Repeat([]byte("b"), 5*humanize.MiByte),
+		[]byte("c"),
+	}
+	completeUploads := &completeMultipartUpload{}
+	var md5Sums []byte
+	for i, data := range partsData {
+		sum := md5.Sum(data)
+		md5Sums = append(md5Sums, sum[:]...)
+		if _, err = obj.PutObjectPart(bucketName, objectName, uploadID, i+1, int64(len(data)), bytes.NewReader(data), "", ""); err != nil {
+			t.Fatalf("%s: Part %d: %v", instanceType, i+1, err)
+		}
+		// Clients send the quoted ETags returned for the parts.
+		completeUploads.Parts = append(completeUploads.Parts, completePart{PartNumber: i + 1, ETag: fmt.Sprintf("\"%x\"", sum)})
+	}
+	expectedETag := fmt.Sprintf("%x-%d", md5.Sum(md5Sums), len(partsData))
+
+	completeBytes, err := xml.Marshal(completeUploads)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	req, err := newTestSignedRequestV4("POST", getCompleteMultipartUploadURL("", bucketName, objectName, uploadID),
+		int64(len(completeBytes)), bytes.NewReader(completeBytes), credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for CompleteMultipartUpload: <ERROR> %v", instanceType, err)
+	}
+	rec := httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	response := CompleteMultipartUploadResponse{}
+	if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("%s: Failed to parse CompleteMultipartUpload response: <ERROR> %v", instanceType, err)
+	}
+	if response.ETag != expectedETag {
+		t.Errorf("%s: Expected ETag %s in the response, got %s", instanceType, expectedETag, response.ETag)
+	}
+	if etag := rec.Header().Get("ETag"); etag != "\""+expectedETag+"\"" {
+		t.Errorf("%s: Expected ETag header %q, got %q", instanceType, "\""+expectedETag+"\"", etag)
+	}
+	objInfo, err := obj.GetObjectInfo(bucketName, objectName)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if objInfo.MD5Sum != expectedETag {
+		t.Errorf("%s: Expected object ETag %s, got %s", instanceType, expectedETag, objInfo.MD5Sum)
+	}
+}
+
 // The UploadID from the response body is parsed and its existence is asserted with an attempt to ListParts using it.
 func TestAPIAbortMultipartHandler(t *testing.T) {
 	defer DetectTestLeak(t)()
@@ -1893,6 +2435,52 @@ func testAPIAbortMultipartHandler(obj ObjectLayer, instanceType, bucketName stri
 }
 
 // Wrapper for calling Delete Object API handler tests for both XL multiple disks and FS single drive setup.
+// Wrapper for calling delete marker tests for both XL multiple disks and single node setup.
+func TestAPIDeleteObjectNoDeleteMarker(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPIDeleteObjectNoDeleteMarker, []string{"DeleteObject", "GetObject"})
+}
+
+// Buckets are not versioned, deleted objects leave no delete marker and
+// are reported as missing.
+func testAPIDeleteObjectNoDeleteMarker(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	objectName := "deleted-object"
+	data := []byte("hello, world")
+	if _, err := obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := newTestSignedRequestV4("DELETE", getDeleteObjectURL("", bucketName, objectName),
+		0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for Delete Object: <ERROR> %v", instanceType, err)
+	}
+	rec := httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNoContent, rec.Code)
+	}
+	for _, header := range []string{"X-Amz-Delete-Marker", "X-Amz-Version-Id"} {
+		if value := rec.Header().Get(header); value != "" {
+			t.Errorf("%s: Expected no %s header, got %q", instanceType, header, value)
+		}
+	}
+
+	req, err = newTestSignedRequestV4("GET", getGetObjectURL("", bucketName, objectName),
+		0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for Get Object: <ERROR> %v", instanceType, err)
+	}
+	rec = httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+	if rec.Header().Get("X-Amz-Delete-Marker") != "" {
+		t.Errorf("%s: Expected no delete marker header, got %q", instanceType, rec.Header().Get("X-Amz-Delete-Marker"))
+	}
+}
+
 func TestAPIDeleteObjectHandler(t *testing.T) {
 	defer DetectTestLeak(t)()
 	ExecObjectLayerAPITest(t, testAPIDeleteObjectHandler, []string{"DeleteObject"})
@@ -2248,8 +2836,8 @@ func testAPIPutObjectPartHandler(obj ObjectLayer, instanceType, bucketName strin
 	badChecksum := getAPIError(ErrInvalidDigest)
 	// expected error when the part number in the request is invalid.
 	invalidPart := getAPIError(ErrInvalidPart)
-	// expected error when maxPart is beyond the limit.
-	invalidMaxParts := getAPIError(ErrInvalidMaxParts)
+	// expected error when the part number is out of the allowed range.
+	invalidPartNumber := getAPIError(ErrInvalidPartNumber)
 	// expected error the when the uploadID is invalid.
 	noSuchUploadID := getAPIError(ErrNoSuchUpload)
 	// expected error when InvalidAccessID is set.
@@ -2300,7 +2888,7 @@ func testAPIPutObjectPartHandler(obj ObjectLayer, instanceType, bucketName strin
 			accessKey:  credentials.AccessKeyID,
 			secretKey:  credentials.SecretAccessKey,
 
-			expectedAPIError: invalidMaxParts,
+			expectedAPIError: invalidPartNumber,
 		},
 		// Test case - 4.
 		// Case where the content length is not set in the HTTP request.
@@ -2376,6 +2964,18 @@ func testAPIPutObjectPartHandler(obj ObjectLayer, instanceType, bucketName strin
 
 			expectedAPIError: invalidAccessID,
 		},
+		// Test case - 10.
+		// Case where the part number is below the allowed range.
+		{
+			objectName: testObject,
+			reader:     bytes.NewReader([]byte("hello")),
+			partNumber: "0",
+			fault:      None,
+			accessKey:  credentials.AccessKeyID,
+			secretKey:  credentials.SecretAccessKey,
+
+			expectedAPIError: invalidPartNumber,
+		},
 	}
 
 	reqV2Str := "V2 Signed HTTP request"
@@ -2528,6 +3128,117 @@ func testAPIPutObjectPartHandler(obj ObjectLayer, instanceType, bucketName strin
 	ExecObjectLayerAPINilTest(t, nilBucket, nilObject, instanceType, apiRouter, nilReq)
 }
 
+// Wrapper for calling Upload Part Copy handler tests for both XL multiple disks and single node setup.
+func TestAPICopyObjectPartHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPICopyObjectPartHandler, []string{"PutObjectPart"})
+}
+
+func testAPICopyObjectPartHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	sourceObject := "source-object"
+	objectName := "copied-object"
+	// 10 MiB of data, each half is a valid minimum sized part.
+	halfSize := int64(5 * 1024 * 1024)
+	data := bytes.Repeat([]byte("abcdefghijklmnop"), int(2*halfSize/16))
+	if _, err := obj.PutObject(bucketName, sourceObject, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("Minio %s: Failed to create the source object: <ERROR> %v", instanceType, err)
+	}
+	// Compressed objects cannot be copied into parts.
+	compressedObject := "compressed-object"
+	compressedMetadata := map[string]string{compressionMetaKey: compressionAlgorithmGzip}
+	if _, err := obj.PutObject(bucketName, compressedObject, 1024, bytes.NewReader(data[:1024]), compressedMetadata, ""); err != nil {
+		t.Fatalf("Minio %s: Failed to create the compressed source object: <ERROR> %v", instanceType, err)
+	}
+	uploadID, err := obj.NewMultipartUpload(bucketName, objectName, nil)
+	if err != nil {
+		t.Fatalf("Minio %s: Failed to initiate multipart upload: <ERROR> %v", instanceType, err)
+	}
+
+	testCases := []struct {
+		sourceObject string
+		uploadID     string
+		partNumber   string
+		copyRange    string
+
+		expectedRespStatus int
+		expectedErrCode    string
+	}{
+		// Test case - 1.
+		// First half of the source object as part 1.
+		{sourceObject, uploadID, "1", fmt.Sprintf("bytes=0-%d", halfSize-1), http.StatusOK, ""},
+		// Test case - 2.
+		// Last half of the source object as part 2.
+		{sourceObject, uploadID, "2", fmt.Sprintf("bytes=%d-%d", halfSize, 2*halfSize-1), http.StatusOK, ""},
+		// Test case - 3.
+		// Open ended ranges are not allowed.
+		{sourceObject, uploadID, "3", fmt.Sprintf("bytes=%d-", halfSize), http.StatusBadRequest, "InvalidArgument"},
+		// Test case - 4.
+		// Suffix ranges are not allowed.
+		{sourceObject, uploadID, "3", "bytes=-100", http.StatusBadRequest, "InvalidArgument"},
+		// Test case - 5.
+		// Range beyond the size of the source object.
+		{sourceObject, uploadID, "3", fmt.Sprintf("bytes=0-%d", 2*halfSize), http.StatusRequestedRangeNotSatisfiable, "InvalidRange"},
+		// Test case - 6.
+		// Non existent source object.
+		{"non-existent-object", uploadID, "3", "bytes=0-99", http.StatusNotFound, "NoSuchKey"},
+		// Test case - 7.
+		// Non existent upload id.
+		{sourceObject, "non-existent-upload-id", "3", "bytes=0-99", http.StatusNotFound, "NoSuchUpload"},
+		// Test case - 8.
+		// Compressed source object.
+		{compressedObject, uploadID, "3", "bytes=0-99", http.StatusNotImplemented, "NotImplemented"},
+	}
+
+	var completeParts []completePart
+	for i, testCase := range testCases {
+		req, err := newTestSignedRequestV4("PUT", getPutObjectPartURL("", bucketName, objectName, testCase.uploadID, testCase.partNumber),
+			0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request for upload part copy: <ERROR> %v", i+1, err)
+		}
+		req.Header.Set("X-Amz-Copy-Source", url.QueryEscape("/"+bucketName+"/"+testCase.sourceObject))
+		req.Header.Set("X-Amz-Copy-Source-Range", testCase.copyRange)
+
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+		if testCase.expectedErrCode != "" {
+			errResp := APIErrorResponse{}
+			if err = xml.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+				t.Fatalf("Test %d: %s: Failed to parse the error response: <ERROR> %v", i+1, instanceType, err)
+			}
+			if errResp.Code != testCase.expectedErrCode {
+				t.Errorf("Test %d: %s: Expected error code `%s`, but found `%s`", i+1, instanceType, testCase.expectedErrCode, errResp.Code)
+			}
+			continue
+		}
+		copyResp := CopyObjectPartResponse{}
+		if err = xml.Unmarshal(rec.Body.Bytes(), &copyResp); err != nil {
+			t.Fatalf("Test %d: %s: Failed to parse the copy part response: <ERROR> %v", i+1, instanceType, err)
+		}
+		if copyResp.LastModified == "" {
+			t.Errorf("Test %d: %s: Expected LastModified in the copy part response", i+1, instanceType)
+		}
+		partNumber, _ := strconv.Atoi(testCase.partNumber)
+		completeParts = append(completeParts, completePart{PartNumber: partNumber, ETag: strings.Trim(copyResp.ETag, "\"")})
+	}
+
+	if _, err = obj.CompleteMultipartUpload(bucketName, objectName, uploadID, completeParts); err != nil {
+		t.Fatalf("Minio %s: Failed to complete multipart upload: <ERROR> %v", instanceType, err)
+	}
+	var buffer bytes.Buffer
+	if err = obj.GetObject(bucketName, objectName, 0, int64(len(data)), &buffer); err != nil {
+		t.Fatalf("Minio %s: Failed to read the copied object: <ERROR> %v", instanceType, err)
+	}
+	if !bytes.Equal(buffer.Bytes(), data) {
+		t.Errorf("Minio %s: Copied object does not match the source object", instanceType)
+	}
+}
+
 // TestAPIListObjectPartsHandlerPreSign - Tests validate the response of ListObjectParts HTTP handler
 //  when signature type of the HTTP request is `Presigned`.
 func TestAPIListObjectPartsHandlerPreSign(t *testing.T) {
@@ -2830,3 +3541,909 @@ func testAPIListObjectPartsHandler(obj ObjectLayer, instanceType, bucketName str
 	// `ExecObjectLayerAPINilTest` sets the Object Layer to `nil` and calls the handler.
 	ExecObjectLayerAPINilTest(t, nilBucket, nilObject, instanceType, apiRouter, nilReq)
 }
+
+// Wrapper for calling object ACL tests for both XL multiple disks and FS single drive setup.
+func TestAPIObjectACLHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPIObjectACLHandlers, []string{"GetObjectACL", "PutObjectACL", "GetObject", "HeadObject", "PutObject"})
+}
+
+func testAPIObjectACLHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	privateObject := "private-object"
+	publicObject := "public-object"
+	defaultObject := "default-object"
+	bytesData := generateBytesData(6 * humanize.KiByte)
+
+	// Upload objects with different canned ACLs through the PutObject handler.
+	putObjectInputs := []struct {
+		objectName string
+		acl        string
+	}{
+		{privateObject, "private"},
+		{publicObject, "public-read"},
+		{defaultObject, ""},
+	}
+	for i, input := range putObjectInputs {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("PUT", getPutObjectURL("", bucketName, input.objectName),
+			int64(len(bytesData)), bytes.NewReader(bytesData), credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for Put Object: <ERROR> %v", i+1, instanceType, err)
+		}
+		if input.acl != "" {
+			req.Header.Set("X-Amz-Acl", input.acl)
+			// Re-sign the request since the header is part of the signature.
+			if err = signRequestV4(req, credentials.AccessKeyID, credentials.SecretAccessKey); err != nil {
+				t.Fatalf("Test %d: %s: Failed to sign request: <ERROR> %v", i+1, instanceType, err)
+			}
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, http.StatusOK, rec.Code)
+		}
+	}
+
+	// Unsupported canned ACL should be rejected.
+	rec := httptest.NewRecorder()
+	req, err := newTestRequest("PUT", getPutObjectURL("", bucketName, "invalid-acl-object"),
+		int64(len(bytesData)), bytes.NewReader(bytesData))
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for Put Object: <ERROR> %v", instanceType, err)
+	}
+	req.Header.Set("X-Amz-Acl", "bucket-owner-full-control")
+	if err = signRequestV4(req, credentials.AccessKeyID, credentials.SecretAccessKey); err != nil {
+		t.Fatalf("%s: Failed to sign request: <ERROR> %v", instanceType, err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+
+	// Verify the grants returned by GetObjectACL.
+	aclTestCases := []struct {
+		objectName     string
+		expectedGrants int
+	}{
+		{privateObject, 1},
+		{publicObject, 2},
+		{defaultObject, 1},
+	}
+	for i, testCase := range aclTestCases {
+		rec = httptest.NewRecorder()
+		req, err = newTestSignedRequestV4("GET", getObjectACLURL("", bucketName, testCase.objectName),
+			0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for Get Object ACL: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, http.StatusOK, rec.Code)
+		}
+		policy := AccessControlPolicy{}
+		if err = xml.Unmarshal(rec.Body.Bytes(), &policy); err != nil {
+			t.Fatalf("Test %d: %s: Failed to parse Get Object ACL response: <ERROR> %v", i+1, instanceType, err)
+		}
+		if len(policy.AccessControlList.Grants) != testCase.expectedGrants {
+			t.Errorf("Test %d: %s: Expected %d grants, found %d", i+1, instanceType, testCase.expectedGrants, len(policy.AccessControlList.Grants))
+		}
+	}
+
+	// Set a read-only bucket policy, anonymous reads of private objects must still be denied.
+	policy := bucketPolicy{
+		Version:    "1.0",
+		Statements: []policyStatement{getReadOnlyObjectStatement(bucketName, "")},
+	}
+	globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{false, &policy})
+
+	anonTestCases := []struct {
+		method             string
+		objectName         string
+		expectedRespStatus int
+	}{
+		{"GET", privateObject, http.StatusForbidden},
+		{"HEAD", privateObject, http.StatusForbidden},
+		{"GET", publicObject, http.StatusOK},
+		{"GET", defaultObject, http.StatusOK},
+		{"HEAD", defaultObject, http.StatusOK},
+	}
+	for i, testCase := range anonTestCases {
+		rec = httptest.NewRecorder()
+		req, err = newTestRequest(testCase.method, getGetObjectURL("", bucketName, testCase.objectName), 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create an anonymous request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+
+	// Make the private object public through PutObjectACL.
+	rec = httptest.NewRecorder()
+	req, err = newTestRequest("PUT", getObjectACLURL("", bucketName, privateObject), 0, nil)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for Put Object ACL: <ERROR> %v", instanceType, err)
+	}
+	req.Header.Set("X-Amz-Acl", "public-read")
+	if err = signRequestV4(req, credentials.AccessKeyID, credentials.SecretAccessKey); err != nil {
+		t.Fatalf("%s: Failed to sign request: <ERROR> %v", instanceType, err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+
+	// Anonymous read should go through now, with the data intact.
+	rec = httptest.NewRecorder()
+	req, err = newTestRequest("GET", getGetObjectURL("", bucketName, privateObject), 0, nil)
+	if err != nil {
+		t.Fatalf("%s: Failed to create an anonymous request: <ERROR> %v", instanceType, err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	if !bytes.Equal(rec.Body.Bytes(), bytesData) {
+		t.Errorf("%s: Object data differs after Put Object ACL", instanceType)
+	}
+}
+
+// Tests the Content-Type and Content-Length headers of GetObject and HeadObject responses.
+func TestAPIGetObjectContentType(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPIGetObjectContentType, []string{"GetObject", "HeadObject", "PutObject"})
+}
+
+func testAPIGetObjectContentType(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	pngData := append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), generateBytesData(1*humanize.KiByte)...)
+
+	testCases := []struct {
+		objectName          string
+		data                []byte
+		contentType         string
+		expectedContentType string
+	}{
+		// Saved content type is returned.
+		{"image", pngData, "image/png", "image/png"},
+		{"text-as-png", []byte("hello, world"), "image/png", "image/png"},
+		// Content type is guessed from the extension of the name.
+		{"image-as.txt", pngData, "", "text/plain"},
+		// Content type is detected from the data on upload.
+		{"detected-image", pngData, "", "image/png"},
+		{"detected-text", []byte("hello, world"), "", "text/plain; charset=utf-8"},
+		// Unknown binary data.
+		{"binary", []byte{0x00, 0x01, 0x02}, "", defaultContentType},
+		// Empty object.
+		{"empty", []byte{}, "", defaultContentType},
+	}
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestRequest("PUT", getPutObjectURL("", bucketName, testCase.objectName),
+			int64(len(testCase.data)), bytes.NewReader(testCase.data))
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for Put Object: <ERROR> %v", i+1, instanceType, err)
+		}
+		if testCase.contentType != "" {
+			req.Header.Set("Content-Type", testCase.contentType)
+		}
+		if err = signRequestV4(req, credentials.AccessKeyID, credentials.SecretAccessKey); err != nil {
+			t.Fatalf("Test %d: %s: Failed to sign request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, http.StatusOK, rec.Code)
+		}
+
+		for _, method := range []string{"GET", "HEAD"} {
+			rec = httptest.NewRecorder()
+			req, err = newTestSignedRequestV4(method, getGetObjectURL("", bucketName, testCase.objectName),
+				0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+			if err != nil {
+				t.Fatalf("Test %d: %s: Failed to create HTTP request for %s Object: <ERROR> %v", i+1, instanceType, method, err)
+			}
+			apiRouter.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Test %d: %s: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, method, http.StatusOK, rec.Code)
+			}
+			if contentType := rec.Header().Get("Content-Type"); contentType != testCase.expectedContentType {
+				t.Errorf("Test %d: %s: %s: Expected Content-Type `%s`, got `%s`", i+1, instanceType, method, testCase.expectedContentType, contentType)
+			}
+			expectedLength := strconv.Itoa(len(testCase.data))
+			if contentLength := rec.Header().Get("Content-Length"); contentLength != expectedLength {
+				t.Errorf("Test %d: %s: %s: Expected Content-Length `%s`, got `%s`", i+1, instanceType, method, expectedLength, contentLength)
+			}
+		}
+	}
+}
+
+// Wrapper for calling server side encryption handler tests for both XL multiple disks and single node setup.
+func TestAPIEncryptedObjectHandler(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPIEncryptedObjectHandler, []string{"PutObject", "GetObject", "HeadObject"})
+}
+
+func testAPIEncryptedObjectHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	defer func(masterKey []byte) { globalSSEMasterKey = masterKey }(globalSSEMasterKey)
+	globalSSEMasterKey = bytes.Repeat([]byte{'k'}, 32)
+
+	objectName := "encrypted-object"
+	data := bytes.Repeat([]byte("0123456789abcdef"), 10*1024)
+	putObject := func(algorithm string) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4("PUT", getPutObjectURL("", bucketName, objectName),
+			int64(len(data)), bytes.NewReader(data), credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for Put Object: <ERROR> %v", instanceType, err)
+		}
+		req.Header.Set("X-Amz-Server-Side-Encryption", algorithm)
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	getObject := func(method, rangeHeader string) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4(method, getGetObjectURL("", bucketName, objectName),
+			0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s Object: <ERROR> %v", instanceType, method, err)
+		}
+		if rangeHeader != "" {
+			req.Header.Set("Range", rangeHeader)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Unsupported algorithm.
+	if rec := putObject("aws:kms"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+
+	rec := putObject(sseAlgorithmAES256)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	if algorithm := rec.Header().Get("X-Amz-Server-Side-Encryption"); algorithm != sseAlgorithmAES256 {
+		t.Errorf("%s: Expected encryption header `%s`, but found `%s`", instanceType, sseAlgorithmAES256, algorithm)
+	}
+
+	// Data is stored encrypted.
+	var stored bytes.Buffer
+	objInfo, err := obj.GetObjectInfo(bucketName, objectName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = obj.GetObject(bucketName, objectName, 0, objInfo.Size, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(stored.Bytes(), data[:64]) {
+		t.Fatalf("%s: Expected object data to be stored encrypted", instanceType)
+	}
+
+	// Data is served decrypted, internal metadata is not returned.
+	for _, method := range []string{"GET", "HEAD"} {
+		rec = getObject(method, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: %s: Expected the response status to be `%d`, but instead found `%d`", instanceType, method, http.StatusOK, rec.Code)
+		}
+		if contentLength := rec.Header().Get("Content-Length"); contentLength != strconv.Itoa(len(data)) {
+			t.Errorf("%s: %s: Expected Content-Length `%d`, but found `%s`", instanceType, method, len(data), contentLength)
+		}
+		if algorithm := rec.Header().Get("X-Amz-Server-Side-Encryption"); algorithm != sseAlgorithmAES256 {
+			t.Errorf("%s: %s: Expected encryption header `%s`, but found `%s`", instanceType, method, sseAlgorithmAES256, algorithm)
+		}
+		for key := range rec.Header() {
+			if strings.HasPrefix(key, internalMetaPrefix) {
+				t.Errorf("%s: %s: Internal metadata %s returned", instanceType, method, key)
+			}
+		}
+	}
+	if rec = getObject("GET", ""); !bytes.Equal(rec.Body.Bytes(), data) {
+		t.Fatalf("%s: Expected decrypted object data", instanceType)
+	}
+	if rec = getObject("GET", "bytes=65530-65545"); rec.Code != http.StatusPartialContent || !bytes.Equal(rec.Body.Bytes(), data[65530:65546]) {
+		t.Fatalf("%s: Expected decrypted range, got `%d` %q", instanceType, rec.Code, rec.Body.Bytes())
+	}
+
+	// Modified encrypted data is detected.
+	altered := stored.Bytes()
+	altered[10] ^= 0xff
+	metadata := make(map[string]string)
+	for k, v := range objInfo.UserDefined {
+		if k != "md5Sum" && k != objectChecksumMetaKey {
+			metadata[k] = v
+		}
+	}
+	if _, err = obj.PutObject(bucketName, objectName, int64(len(altered)), bytes.NewReader(altered), metadata, ""); err != nil {
+		t.Fatal(err)
+	}
+	rec = getObject("GET", "")
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusInternalServerError, rec.Code)
+	}
+	errResp := APIErrorResponse{}
+	if err = xml.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("%s: Failed to parse the error response: <ERROR> %v", instanceType, err)
+	}
+	if errResp.Code != "ObjectTampered" {
+		t.Errorf("%s: Expected error code `ObjectTampered`, but found `%s`", instanceType, errResp.Code)
+	}
+
+	// Encryption is not available without a master key.
+	globalSSEMasterKey = nil
+	if rec = putObject(sseAlgorithmAES256); rec.Code != http.StatusNotImplemented {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotImplemented, rec.Code)
+	}
+}
+
+// Wrapper for calling copy of encrypted objects handler tests for both XL multiple disks and single node setup.
+func TestAPICopyEncryptedObjectHandler(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPICopyEncryptedObjectHandler, []string{"CopyObject", "PutObject", "GetObject"})
+}
+
+func testAPICopyEncryptedObjectHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	defer func(masterKey []byte) { globalSSEMasterKey = masterKey }(globalSSEMasterKey)
+	globalSSEMasterKey = bytes.Repeat([]byte{'k'}, 32)
+
+	objectName := "encrypted-object"
+	data := bytes.Repeat([]byte("0123456789abcdef"), 10*1024)
+	req, err := newTestSignedRequestV4("PUT", getPutObjectURL("", bucketName, objectName),
+		int64(len(data)), bytes.NewReader(data), credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for Put Object: <ERROR> %v", instanceType, err)
+	}
+	req.Header.Set("X-Amz-Server-Side-Encryption", sseAlgorithmAES256)
+	rec := httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+
+	testCases := []struct {
+		newObjectName     string
+		metadataDirective string
+	}{
+		{"copy-object", ""},
+		{"copy-object-replaced", "REPLACE"},
+		// Copy onto itself replacing the metadata.
+		{objectName, "REPLACE"},
+	}
+	for i, testCase := range testCases {
+		req, err = newTestSignedRequestV4("PUT", getCopyObjectURL("", bucketName, testCase.newObjectName),
+			0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request for copy Object: <ERROR> %v", i+1, err)
+		}
+		req.Header.Set("X-Amz-Copy-Source", url.QueryEscape("/"+bucketName+"/"+objectName))
+		if testCase.metadataDirective != "" {
+			req.Header.Set("X-Amz-Metadata-Directive", testCase.metadataDirective)
+			req.Header.Set("X-Amz-Meta-Copy", testCase.newObjectName)
+		}
+		rec = httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, http.StatusOK, rec.Code)
+		}
+
+		// The copy is served decrypted.
+		req, err = newTestSignedRequestV4("GET", getGetObjectURL("", bucketName, testCase.newObjectName),
+			0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request for Get Object: <ERROR> %v", i+1, err)
+		}
+		rec = httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, http.StatusOK, rec.Code)
+		}
+		if !bytes.Equal(rec.Body.Bytes(), data) {
+			t.Fatalf("Test %d: %s: Expected decrypted object data", i+1, instanceType)
+		}
+		if algorithm := rec.Header().Get("X-Amz-Server-Side-Encryption"); algorithm != sseAlgorithmAES256 {
+			t.Errorf("Test %d: %s: Expected encryption header `%s`, but found `%s`", i+1, instanceType, sseAlgorithmAES256, algorithm)
+		}
+		if testCase.metadataDirective != "" && rec.Header().Get("X-Amz-Meta-Copy") != testCase.newObjectName {
+			t.Errorf("Test %d: %s: Expected the metadata to be replaced", i+1, instanceType)
+		}
+	}
+}
+
+// Wrapper for calling server side compression handler tests for both XL multiple disks and single node setup.
+func TestAPICompressedObjectHandler(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPICompressedObjectHandler, []string{"PutObject", "GetObject", "HeadObject"})
+}
+
+func testAPICompressedObjectHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	defer func(compression bool) { globalCompression = compression }(globalCompression)
+	globalCompression = true
+
+	data := bytes.Repeat([]byte("id,name,value\n1,compressible,text\n"), 4*1024)
+	putObject := func(objectName, contentType string, md5Sum []byte) *httptest.ResponseRecorder {
+		req, err := newTestRequest("PUT", getPutObjectURL("", bucketName, objectName),
+			int64(len(data)), bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for Put Object: <ERROR> %v", instanceType, err)
+		}
+		req.Header.Set("Content-Type", contentType)
+		if md5Sum != nil {
+			req.Header.Set("Content-Md5", base64.StdEncoding.EncodeToString(md5Sum))
+		}
+		if err = signRequestV4(req, credentials.AccessKeyID, credentials.SecretAccessKey); err != nil {
+			t.Fatalf("%s: Failed to sign HTTP request for Put Object: <ERROR> %v", instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	getObject := func(method, objectName, rangeHeader string) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4(method, getGetObjectURL("", bucketName, objectName),
+			0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s Object: <ERROR> %v", instanceType, method, err)
+		}
+		if rangeHeader != "" {
+			req.Header.Set("Range", rangeHeader)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	storedSize := func(objectName string) int64 {
+		objInfo, err := obj.GetObjectInfo(bucketName, objectName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return objInfo.Size
+	}
+
+	md5Sum := md5.Sum(data)
+	expectedETag := "\"" + hex.EncodeToString(md5Sum[:]) + "\""
+	rec := putObject("object.csv", "text/csv", md5Sum[:])
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	// ETag is the MD5 sum of the uncompressed data.
+	if etag := rec.Header().Get("ETag"); etag != expectedETag {
+		t.Errorf("%s: Expected ETag `%s`, but found `%s`", instanceType, expectedETag, etag)
+	}
+	if rec := putObject("object.bin", "application/octet-stream", nil); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+
+	// Only compressible content types are stored compressed.
+	if size := storedSize("object.csv"); size >= int64(len(data))/2 {
+		t.Errorf("%s: Expected object data to be stored compressed, found %d bytes", instanceType, size)
+	}
+	if size := storedSize("object.bin"); size != int64(len(data)) {
+		t.Errorf("%s: Expected object data to be stored as is, found %d bytes", instanceType, size)
+	}
+
+	// Data is served decompressed without a Content-Encoding.
+	for _, method := range []string{"GET", "HEAD"} {
+		rec := getObject(method, "object.csv", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: %s: Expected the response status to be `%d`, but instead found `%d`", instanceType, method, http.StatusOK, rec.Code)
+		}
+		if contentLength := rec.Header().Get("Content-Length"); contentLength != strconv.Itoa(len(data)) {
+			t.Errorf("%s: %s: Expected Content-Length `%d`, but found `%s`", instanceType, method, len(data), contentLength)
+		}
+		if encoding := rec.Header().Get("Content-Encoding"); encoding != "" {
+			t.Errorf("%s: %s: Unexpected Content-Encoding `%s`", instanceType, method, encoding)
+		}
+		if etag := rec.Header().Get("ETag"); etag != expectedETag {
+			t.Errorf("%s: %s: Expected ETag `%s`, but found `%s`", instanceType, method, expectedETag, etag)
+		}
+		// Checksum of the compressed data is not returned.
+		if checksum := rec.Header().Get(objectChecksumMetaKey); checksum != "" {
+			t.Errorf("%s: %s: Unexpected checksum `%s`", instanceType, method, checksum)
+		}
+		for key := range rec.Header() {
+			if strings.HasPrefix(key, internalMetaPrefix) {
+				t.Errorf("%s: %s: Internal metadata %s returned", instanceType, method, key)
+			}
+		}
+	}
+	if rec := getObject("GET", "object.csv", ""); !bytes.Equal(rec.Body.Bytes(), data) {
+		t.Fatalf("%s: Expected decompressed object data", instanceType)
+	}
+	if rec := getObject("GET", "object.csv", "bytes=65530-65545"); rec.Code != http.StatusPartialContent || !bytes.Equal(rec.Body.Bytes(), data[65530:65546]) {
+		t.Fatalf("%s: Expected decompressed range, got `%d` %q", instanceType, rec.Code, rec.Body.Bytes())
+	}
+
+	// Uncompressed data is verified against Content-Md5.
+	wrongMD5Sum := md5.Sum([]byte("wrong"))
+	if rec := putObject("object-bad-digest.csv", "text/csv", wrongMD5Sum[:]); rec.Code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+	if _, err := obj.GetObjectInfo(bucketName, "object-bad-digest.csv"); err == nil {
+		t.Fatalf("%s: Expected object with bad digest not to be saved", instanceType)
+	}
+}
+
+// Wrapper for calling header injection tests for both XL multiple disks and single node setup.
+func TestAPIHeaderInjection(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPIHeaderInjection, []string{"PutObject", "GetObject", "HeadObject"})
+}
+
+// Tests that object names and metadata with CR and LF do not inject
+// headers in the responses.
+func testAPIHeaderInjection(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	injection := "\r\nX-Injected: evil"
+	data := []byte("hello, world")
+
+	// verifyHeaders - fails if a header was injected in the response.
+	verifyHeaders := func(method string, header http.Header) {
+		if _, ok := header["X-Injected"]; ok {
+			t.Errorf("%s: %s: Injected header found in the response", instanceType, method)
+		}
+		for key, values := range header {
+			for _, value := range values {
+				if strings.ContainsAny(value, "\r\n") {
+					t.Errorf("%s: %s: Header %s has CR or LF in its value %q", instanceType, method, key, value)
+				}
+			}
+		}
+	}
+
+	// Object names with CR and LF.
+	req, err := newTestSignedRequestV4("PUT", getPutObjectURL("", bucketName, "object"+injection),
+		int64(len(data)), bytes.NewReader(data), credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for Put Object: <ERROR> %v", instanceType, err)
+	}
+	rec := httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	verifyHeaders("PUT", rec.Header())
+
+	// Metadata, content type and requested response headers with CR and LF.
+	objectName := "object"
+	req, err = newTestSignedRequestV4("PUT", getPutObjectURL("", bucketName, objectName),
+		int64(len(data)), bytes.NewReader(data), credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for Put Object: <ERROR> %v", instanceType, err)
+	}
+	req.Header.Set("X-Amz-Meta-Note", "note"+injection)
+	req.Header.Set("Content-Type", "text/plain"+injection)
+	rec = httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+
+	queryValues := url.Values{}
+	queryValues.Set("response-content-disposition", "attachment"+injection)
+	for _, method := range []string{"GET", "HEAD"} {
+		req, err = newTestSignedRequestV4(method, makeTestTargetURL("", bucketName, objectName, queryValues),
+			0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s Object: <ERROR> %v", instanceType, method, err)
+		}
+		rec = httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: %s: Expected the response status to be `%d`, but instead found `%d`", instanceType, method, http.StatusOK, rec.Code)
+		}
+		verifyHeaders(method, rec.Header())
+		if note := rec.Header().Get("X-Amz-Meta-Note"); note != "noteX-Injected: evil" {
+			t.Errorf("%s: %s: Expected sanitized metadata, found %q", instanceType, method, note)
+		}
+	}
+
+	// Object names with null bytes are rejected.
+	req, err = newTestSignedRequestV4("PUT", getPutObjectURL("", bucketName, "object\x00name"),
+		int64(len(data)), bytes.NewReader(data), credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for Put Object: <ERROR> %v", instanceType, err)
+	}
+	rec = httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+}
+
+// Tests the storage class of objects is saved and returned in responses.
+func TestAPIObjectStorageClass(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPIObjectStorageClass, []string{"PutObject", "GetObject", "ListObjectsV1"})
+}
+
+func testAPIObjectStorageClass(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	data := []byte("hello, world")
+	putObject := func(objectName, storageClass string) *httptest.ResponseRecorder {
+		req, err := newTestRequest("PUT", getPutObjectURL("", bucketName, objectName),
+			int64(len(data)), bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for Put Object: <ERROR> %v", instanceType, err)
+		}
+		if storageClass != "" {
+			req.Header.Set("X-Amz-Storage-Class", storageClass)
+		}
+		if err = signRequestV4(req, credentials.AccessKeyID, credentials.SecretAccessKey); err != nil {
+			t.Fatalf("%s: Failed to sign HTTP request for Put Object: <ERROR> %v", instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := putObject("invalid", "GLACIER"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+	objects := map[string]string{
+		"reduced":  storageClassReducedRedundancy,
+		"standard": "",
+	}
+	for objectName, storageClass := range objects {
+		if rec := putObject(objectName, storageClass); rec.Code != http.StatusOK {
+			t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+		}
+	}
+
+	// Objects are of the STANDARD storage class unless requested.
+	expected := map[string]string{
+		"reduced":  storageClassReducedRedundancy,
+		"standard": storageClassStandard,
+	}
+	for objectName, storageClass := range expected {
+		req, err := newTestSignedRequestV4("GET", getGetObjectURL("", bucketName, objectName),
+			0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for Get Object: <ERROR> %v", instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+		}
+		if got := rec.Header().Get("X-Amz-Storage-Class"); got != storageClass {
+			t.Errorf("%s: %s: Expected storage class %s, got %s", instanceType, objectName, storageClass, got)
+		}
+	}
+
+	req, err := newTestSignedRequestV4("GET", getListObjectsV1URL("", bucketName, ""),
+		0, nil, credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for List Objects: <ERROR> %v", instanceType, err)
+	}
+	rec := httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	listResponse := ListObjectsResponse{}
+	if err = xml.Unmarshal(rec.Body.Bytes(), &listResponse); err != nil {
+		t.Fatalf("%s: Unable to parse list objects response: <ERROR> %v", instanceType, err)
+	}
+	if len(listResponse.Contents) != len(expected) {
+		t.Fatalf("%s: Expected %d objects, found %d", instanceType, len(expected), len(listResponse.Contents))
+	}
+	for _, content := range listResponse.Contents {
+		if content.StorageClass != expected[content.Key] {
+			t.Errorf("%s: %s: Expected storage class %s, got %s", instanceType, content.Key, expected[content.Key], content.StorageClass)
+		}
+	}
+}
+
+// Tests parts smaller than 5MiB are accepted only as the last part.
+func TestAPIPutObjectPartSizeHandler(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPIPutObjectPartSizeHandler, []string{"PutObjectPart"})
+}
+
+func testAPIPutObjectPartSizeHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	objectName := "test-object-part-size"
+	uploadID, err := obj.NewMultipartUpload(bucketName, objectName, nil)
+	if err != nil {
+		t.Fatalf("%s: <ERROR> %s", instanceType, err)
+	}
+	smallPart := bytes.Repeat([]byte("a"), 1*humanize.MiByte)
+	putObjectPart := func(partNumber int, data []byte) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4("PUT", getPutObjectPartURL("", bucketName, objectName, uploadID, strconv.Itoa(partNumber)),
+			int64(len(data)), bytes.NewReader(data), credentials.AccessKeyID, credentials.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for Put Object Part: <ERROR> %v", instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Small parts are accepted while no part with a higher part number exists.
+	for _, partNumber := range []int{1, 2} {
+		if rec := putObjectPart(partNumber, smallPart); rec.Code != http.StatusOK {
+			t.Fatalf("%s: Part %d: Expected the response status to be `%d`, but instead found `%d`", instanceType, partNumber, http.StatusOK, rec.Code)
+		}
+	}
+
+	// Once a third part exists the second part is no longer the last one.
+	if _, err = obj.PutObjectPart(bucketName, objectName, uploadID, 3, int64(len(smallPart)), bytes.NewReader(smallPart), "", ""); err != nil {
+		t.Fatalf("%s: <ERROR> %s", instanceType, err)
+	}
+	rec := putObjectPart(2, smallPart)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+	errResponse := APIErrorResponse{}
+	if err = xml.Unmarshal(rec.Body.Bytes(), &errResponse); err != nil {
+		t.Fatalf("%s: Unable to parse error response: <ERROR> %v", instanceType, err)
+	}
+	if errResponse.Code != "EntityTooSmall" {
+		t.Fatalf("%s: Expected error code EntityTooSmall, got %s", instanceType, errResponse.Code)
+	}
+
+	// Parts of at least 5MiB are always accepted.
+	if rec = putObjectPart(2, bytes.Repeat([]byte("a"), minPartSize)); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+}
+
+// Tests ranges are served only while the object matches If-Range.
+func TestAPIGetObjectIfRangeHandler(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPIGetObjectIfRangeHandler, []string{"GetObject"})
+}
+
+func testAPIGetObjectIfRangeHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	objectName := "test-object-if-range"
+	data := []byte("hello, world")
+	objInfo, err := obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), nil, "")
+	if err != nil {
+		t.Fatalf("%s: <ERROR> %s", instanceType, err)
+	}
+	lastModified := objInfo.ModTime.UTC().Format(http.TimeFormat)
+	before := objInfo.ModTime.UTC().Add(-time.Hour).Format(http.TimeFormat)
+
+	testCases := []struct {
+		ifRange      string
+		expectedCode int
+		expectedBody []byte
+	}{
+		// Unchanged objects are served the requested range.
+		{"\"" + objInfo.MD5Sum + "\"", http.StatusPartialContent, data[0:5]},
+		{lastModified, http.StatusPartialContent, data[0:5]},
+		{"", http.StatusPartialContent, data[0:5]},
+		// Changed objects are served whole.
+		{"\"0123456789abcdef0123456789abcdef\"", http.StatusOK, data},
+		{"W/\"" + objInfo.MD5Sum + "\"", http.StatusOK, data},
+		{before, http.StatusOK, data},
+	}
+	for i, testCase := range testCases {
+		req, err := newTestRequest("GET", getGetObjectURL("", bucketName, objectName), 0, nil)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for Get Object: <ERROR> %v", instanceType, err)
+		}
+		req.Header.Set("Range", "bytes=0-4")
+		if testCase.ifRange != "" {
+			req.Header.Set("If-Range", testCase.ifRange)
+		}
+		if err = signRequestV4(req, credentials.AccessKeyID, credentials.SecretAccessKey); err != nil {
+			t.Fatalf("%s: Failed to sign HTTP request for Get Object: <ERROR> %v", instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedCode {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedCode, rec.Code)
+			continue
+		}
+		if !bytes.Equal(rec.Body.Bytes(), testCase.expectedBody) {
+			t.Errorf("Test %d: %s: Expected body %q, got %q", i+1, instanceType, testCase.expectedBody, rec.Body.Bytes())
+		}
+	}
+}
+
+// Tests that objects are served with their Content-Length over the
+// wire, clients must not have to read until the connection is closed.
+func TestGetObjectContentLength(t *testing.T) {
+	defer restoreMinioAddrGlobals(globalMinioAddr, globalMinioHost, globalMinioPort)
+	for _, instanceType := range []string{FSTestStr, XLTestStr} {
+		func() {
+			testServer := StartTestServer(t, instanceType)
+			defer testServer.Stop()
+
+			bucketName, objectName := getRandomBucketName(), "object"
+			if err := testServer.Obj.MakeBucket(bucketName); err != nil {
+				t.Fatal(err)
+			}
+			data := bytes.Repeat([]byte("a"), 1024*1024)
+			if _, err := testServer.Obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+				t.Fatal(err)
+			}
+
+			req, err := newTestSignedRequestV4("GET", getGetObjectURL(testServer.Server.URL, bucketName, objectName),
+				0, nil, testServer.AccessKey, testServer.SecretKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, resp.StatusCode)
+			}
+			if resp.Header.Get("Content-Length") != "1048576" || resp.ContentLength != int64(len(data)) {
+				t.Errorf("%s: Expected Content-Length `1048576`, got `%s`", instanceType, resp.Header.Get("Content-Length"))
+			}
+			if len(resp.TransferEncoding) != 0 {
+				t.Errorf("%s: Expected no transfer encoding, got %v", instanceType, resp.TransferEncoding)
+			}
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(body, data) {
+				t.Errorf("%s: Object data mismatch", instanceType)
+			}
+		}()
+	}
+}
+
+// Tests uploads which do not fit on the disks are rejected before the
+// client is asked to send the body with "100 Continue".
+func TestPutObjectExpectContinue(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Free disk space is not verified on windows")
+	}
+	defer restoreMinioAddrGlobals(globalMinioAddr, globalMinioHost, globalMinioPort)
+	testServer := StartTestServer(t, XLTestStr)
+	defer testServer.Stop()
+
+	bucketName, objectName := getRandomBucketName(), "object"
+	if err := testServer.Obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+	// Make all the disks look full.
+	for _, disk := range testServer.Obj.(*xlObjects).storageDisks {
+		disk.(*retryStorage).remoteStorage.(*posix).minFreeSpace = math.MaxInt64
+	}
+
+	data := bytes.Repeat([]byte("a"), 1024*1024)
+	req, err := newTestSignedRequestV4("PUT", getPutObjectURL(testServer.Server.URL, bucketName, objectName),
+		int64(len(data)), bytes.NewReader(data), testServer.AccessKey, testServer.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Expect", "100-continue")
+
+	conn, err := net.Dial("tcp", testServer.Server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	// Send the headers only, the body is sent after "100 Continue".
+	start := time.Now()
+	fmt.Fprintf(conn, "PUT %s HTTP/1.1\r\nHost: %s\r\nContent-Length: %d\r\n", req.URL.RequestURI(), req.Host, len(data))
+	if err = req.Header.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = io.WriteString(conn, "\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("Expected the response status to be `%d`, but instead found `%d`", http.StatusInternalServerError, resp.StatusCode)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the upload to be rejected right away, took %s", elapsed)
+	}
+	if _, err = testServer.Obj.GetObjectInfo(bucketName, objectName); err == nil {
+		t.Error("Expected object not to be written")
+	}
+}
@@ -20,15 +20,21 @@ import (
 	"encoding/json"
 	"path"
 	"sort"
+	"strconv"
 	"sync"
 	"time"
 )
 
+// Metadata key carrying the expected size of a multipart upload, it is
+// not saved with the object.
+const uploadSizeMetaKey = "X-Minio-Internal-Upload-Size"
+
 // A uploadInfo represents the s3 compatible spec.
 type uploadInfo struct {
-	UploadID  string    `json:"uploadId"`  // UploadID for the active multipart upload.
-	Deleted   bool      `json:"deleted"`   // Currently unused, for future use.
-	Initiated time.Time `json:"initiated"` // Indicates when the uploadID was initiated.
+	UploadID  string    `json:"uploadId"`       // UploadID for the active multipart upload.
+	Deleted   bool      `json:"deleted"`        // Currently unused, for future use.
+	Initiated time.Time `json:"initiated"`      // Indicates when the uploadID was initiated.
+	Size      int64     `json:"size,omitempty"` // Expected size of the object, if known.
 }
 
 // A uploadsV1 represents `uploads.json` metadata header.
@@ -47,11 +53,13 @@ func (t byInitiatedTime) Less(i, j int) bool {
 	return t[i].Initiated.Before(t[j].Initiated)
 }
 
-// AddUploadID - adds a new upload id in order of its initiated time.
-func (u *uploadsV1) AddUploadID(uploadID string, initiated time.Time) {
+// AddUploadID - adds a new upload id in order of its initiated time,
+// size is the expected size of the object or 0 if unknown.
+func (u *uploadsV1) AddUploadID(uploadID string, initiated time.Time, size int64) {
 	u.Uploads = append(u.Uploads, uploadInfo{
 		UploadID:  uploadID,
 		Initiated: initiated,
+		Size:      size,
 	})
 	sort.Sort(byInitiatedTime(u.Uploads))
 }
@@ -67,6 +75,21 @@ func (u *uploadsV1) RemoveUploadID(uploadID string) {
 	}
 }
 
+// getUploadSize - removes the expected size of a multipart upload from
+// its metadata and returns it, 0 if it is unknown.
+func getUploadSize(meta map[string]string) int64 {
+	sizeStr, ok := meta[uploadSizeMetaKey]
+	if !ok {
+		return 0
+	}
+	delete(meta, uploadSizeMetaKey)
+	size, err := strconv.ParseInt(sizeStr, 10, 64)
+	if err != nil || size < 0 {
+		return 0
+	}
+	return size
+}
+
 // readUploadsJSON - get all the saved uploads JSON.
 func readUploadsJSON(bucket, object string, disk StorageAPI) (uploadIDs uploadsV1, err error) {
 	uploadJSONPath := path.Join(bucket, object, uploadsJSONFile)
@@ -155,8 +178,12 @@ func cleanupUploadedParts(bucket, object, uploadID string, storageDisks ...Stora
 	return nil
 }
 
-// listMultipartUploadIDs - list all the upload ids from a marker up to 'count'.
-func listMultipartUploadIDs(bucketName, objectName, uploadIDMarker string, count int, disk StorageAPI) ([]uploadMetadata, bool, error) {
+// readMetaPartsFunc - reads the parts of an upload id from its metadata.
+type readMetaPartsFunc func(disk StorageAPI, bucket, object string) ([]objectPartInfo, error)
+
+// listMultipartUploadIDs - list all the upload ids from a marker up to 'count',
+// the committed size of each upload is read from its metadata with readParts.
+func listMultipartUploadIDs(bucketName, objectName, uploadIDMarker string, count int, disk StorageAPI, readParts readMetaPartsFunc) ([]uploadMetadata, bool, error) {
 	var uploads []uploadMetadata
 	// Read `uploads.json`.
 	uploadsJSON, err := readUploadsJSON(bucketName, objectName, disk)
@@ -174,10 +201,21 @@ func listMultipartUploadIDs(bucketName, objectName, uploadIDMarker string, count
 		}
 	}
 	for index < len(uploadsJSON.Uploads) {
+		upload := uploadsJSON.Uploads[index]
+		// The upload may be in the middle of being initiated or
+		// removed, it is then listed without any committed parts.
+		parts, _ := readParts(disk, minioMetaMultipartBucket, path.Join(bucketName, objectName, upload.UploadID))
+		var committedSize int64
+		for _, part := range parts {
+			committedSize += part.Size
+		}
 		uploads = append(uploads, uploadMetadata{
-			Object:    objectName,
-			UploadID:  uploadsJSON.Uploads[index].UploadID,
-			Initiated: uploadsJSON.Uploads[index].Initiated,
+			Object:        objectName,
+			UploadID:      upload.UploadID,
+			Initiated:     upload.Initiated,
+			Size:          upload.Size,
+			CommittedSize: committedSize,
+			Parts:         len(parts),
 		})
 		count--
 		index++
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -63,6 +64,17 @@ func IsValidBucketName(bucket string) bool {
 		!strings.Contains(bucket, ".."))
 }
 
+// isValidNewBucketName verifies the name of a bucket being created, every
+// label between periods must also be DNS compatible, i.e neither beginning
+// nor ending with a dash and without consecutive dashes. Existing buckets
+// with such names are still served, see IsValidBucketName.
+func isValidNewBucketName(bucket string) bool {
+	return IsValidBucketName(bucket) &&
+		!strings.Contains(bucket, ".-") &&
+		!strings.Contains(bucket, "-.") &&
+		!strings.Contains(bucket, "--")
+}
+
 // IsValidObjectName verifies an object name in accordance with Amazon's
 // requirements. It cannot exceed 1024 characters and must be a valid UTF8
 // string.
@@ -78,7 +90,9 @@ func IsValidBucketName(bucket string) bool {
 //
 // - Backslash ("\")
 //
-// additionally minio does not support object names with trailing "/".
+// additionally minio does not support object names with trailing "/",
+// leading "/" or names which are not in their shortest form i.e "a//b"
+// or "a/./b", the backend would store them at the same path as "a/b".
 func IsValidObjectName(object string) bool {
 	if len(object) == 0 {
 		return false
@@ -89,6 +103,9 @@ func IsValidObjectName(object string) bool {
 	if strings.HasPrefix(object, slashSeparator) {
 		return false
 	}
+	if path.Clean(object) != object || object == "." {
+		return false
+	}
 	return IsValidObjectPrefix(object)
 }
 
@@ -105,7 +122,28 @@ func IsValidObjectPrefix(object string) bool {
 	if strings.ContainsAny(object, "\\") {
 		return false
 	}
-	return true
+	// Reject names which could escape the bucket on the backend.
+	if hasDotDotComponent(object) {
+		return false
+	}
+	return isValidObjectKey(object)
+}
+
+// hasDotDotComponent - returns whether any of the slash separated
+// components of the name is "..".
+func hasDotDotComponent(object string) bool {
+	for _, component := range strings.Split(object, slashSeparator) {
+		if component == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidObjectKey - rejects object names with null bytes, which are
+// not supported by the underlying filesystems.
+func isValidObjectKey(object string) bool {
+	return !strings.Contains(object, "\x00")
 }
 
 // Slash separator.
@@ -151,6 +189,25 @@ func getCompleteMultipartMD5(parts []completePart) (string, error) {
 	return s3MD5, nil
 }
 
+// replaceObjectMetadata - returns the metadata to be saved when the
+// incoming metadata replaces the existing metadata of an object. User
+// supplied entries are replaced as a whole, internal entries such as
+// "md5Sum" are carried over from the existing metadata.
+func replaceObjectMetadata(existing, metadata map[string]string) map[string]string {
+	newMetadata := make(map[string]string)
+	for k, v := range metadata {
+		newMetadata[k] = v
+	}
+	for _, key := range []string{"md5Sum", objectChecksumMetaKey, sseAlgorithmMetaKey,
+		sseSealedKeyMetaKey, sseNonceMetaKey, sseChunksMetaKey, sseSizeMetaKey,
+		compressionMetaKey, compressionSizeMetaKey, compressionMD5MetaKey} {
+		if value, ok := existing[key]; ok {
+			newMetadata[key] = value
+		}
+	}
+	return newMetadata
+}
+
 // byBucketName is a collection satisfying sort.Interface.
 type byBucketName []BucketInfo
 
@@ -158,6 +215,19 @@ func (d byBucketName) Len() int           { return len(d) }
 func (d byBucketName) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
 func (d byBucketName) Less(i, j int) bool { return d[i].Name < d[j].Name }
 
+// byBucketCreated is a collection satisfying sort.Interface, sorts
+// buckets by creation date and buckets created at the same time by name.
+type byBucketCreated []BucketInfo
+
+func (d byBucketCreated) Len() int      { return len(d) }
+func (d byBucketCreated) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+func (d byBucketCreated) Less(i, j int) bool {
+	if d[i].Created.Equal(d[j].Created) {
+		return d[i].Name < d[j].Name
+	}
+	return d[i].Created.Before(d[j].Created)
+}
+
 // rangeReader returns a Reader that reads from r
 // but returns error after Max bytes read as errDataTooLarge.
 // but returns error if reader exits before reading Min bytes
@@ -181,3 +251,18 @@ func (l *rangeReader) Read(p []byte) (n int, err error) {
 	}
 	return
 }
+
+// Metadata key under which the base64 encoded SHA-256 checksum of object
+// data is saved, it is returned as is in GET and HEAD responses.
+const objectChecksumMetaKey = "x-amz-checksum-sha256"
+
+// setObjectChecksum - verifies the SHA-256 checksum of object data with
+// the one requested in metadata if any, saves it in metadata otherwise.
+func setObjectChecksum(metadata map[string]string, sha256Sum []byte) error {
+	checksum := base64.StdEncoding.EncodeToString(sha256Sum)
+	if expected := metadata[objectChecksumMetaKey]; expected != "" && expected != checksum {
+		return traceError(BadChecksum{expected, checksum})
+	}
+	metadata[objectChecksumMetaKey] = checksum
+	return nil
+}
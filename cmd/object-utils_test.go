@@ -18,6 +18,8 @@ package cmd
 
 import (
 	"io/ioutil"
+	"math/rand"
+	"path"
 	"strings"
 	"testing"
 )
@@ -64,6 +66,11 @@ func TestIsValidBucketName(t *testing.T) {
 		{"ThisBeginsAndEndsWithUpperCase", false},
 		{"una ñina", false},
 		{"lalalallalallalalalallalallalala-theString-size-is-greater-than-64", false},
+		{"trailing.dot.", false},
+		{"abc.def", true},
+		{"a-b.c-d", true},
+		{"abcdefghijklmnopqrstuvwxyz0123456789abcdefghijklmnopqrstuvwxyz0", true},
+		{"abcdefghijklmnopqrstuvwxyz0123456789abcdefghijklmnopqrstuvwxyz01", false},
 	}
 
 	for i, testCase := range testCases {
@@ -77,6 +84,32 @@ func TestIsValidBucketName(t *testing.T) {
 	}
 }
 
+// Tests validate bucket names of new buckets, existing buckets with
+// labels which are not DNS compatible are still valid.
+func TestIsValidNewBucketName(t *testing.T) {
+	testCases := []struct {
+		bucketName    string
+		shouldPass    bool
+		shouldPassNew bool
+	}{
+		{"abc.def", true, true},
+		{"a-b.c-d", true, true},
+		{"my..bucket", false, false},
+		{"label.-starts-with-a-dash", true, false},
+		{"label-.ends-with-a-dash", true, false},
+		{"consecutive--dashes", true, false},
+	}
+
+	for i, testCase := range testCases {
+		if IsValidBucketName(testCase.bucketName) != testCase.shouldPass {
+			t.Errorf("Test case %d: Expected IsValidBucketName(\"%s\") to be %v", i+1, testCase.bucketName, testCase.shouldPass)
+		}
+		if isValidNewBucketName(testCase.bucketName) != testCase.shouldPassNew {
+			t.Errorf("Test case %d: Expected isValidNewBucketName(\"%s\") to be %v", i+1, testCase.bucketName, testCase.shouldPassNew)
+		}
+	}
+}
+
 // Tests for validate object name.
 func TestIsValidObjectName(t *testing.T) {
 	testCases := []struct {
@@ -96,6 +129,7 @@ func TestIsValidObjectName(t *testing.T) {
 		{"contains-\"-quote", true},
 		{"contains-`-tick", true},
 		{"There are far too many object names, and far too few bucket names!", true},
+		{"a..b/..c", true},
 		// cases for which test should fail.
 		// passing invalid object names.
 		{"", false},
@@ -103,6 +137,17 @@ func TestIsValidObjectName(t *testing.T) {
 		{"/a/b/c", false},
 		{"contains-\\-backslash", false},
 		{string([]byte{0xff, 0xfe, 0xfd}), false},
+		{"contains-\x00-null-byte", false},
+		{"../../etc/passwd", false},
+		{"a/../../b", false},
+		{"a/..", false},
+		{"a//b", false},
+		{"a/b//", false},
+		{"//a", false},
+		{".", false},
+		{"a/./b", false},
+		{"./a", false},
+		{"a/.", false},
 	}
 
 	for i, testCase := range testCases {
@@ -116,6 +161,30 @@ func TestIsValidObjectName(t *testing.T) {
 	}
 }
 
+// Tests valid object names never resolve outside of the bucket, with
+// random names built out of path separators, dots and letters.
+func TestIsValidObjectNameInBucket(t *testing.T) {
+	components := []string{"a", "b", ".", "..", "/", "\\", "", "...", "..a"}
+	bucketDir := "/export/bucket"
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		var name string
+		for n := random.Intn(8); n >= 0; n-- {
+			name += components[random.Intn(len(components))]
+		}
+		if !IsValidObjectName(name) {
+			continue
+		}
+		objectPath := path.Join(bucketDir, name)
+		if !strings.HasPrefix(objectPath, bucketDir+slashSeparator) {
+			t.Fatalf("Valid object name %q resolves to %q outside of the bucket", name, objectPath)
+		}
+		if path.Clean(name) != name {
+			t.Fatalf("Valid object name %q is not normalized", name)
+		}
+	}
+}
+
 // Tests rangeReader.
 func TestRangeReader(t *testing.T) {
 	testCases := []struct {
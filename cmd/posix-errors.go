@@ -34,6 +34,9 @@ func isSysErrOpNotSupported(err error) bool {
 
 // No space left on device error
 func isSysErrNoSpace(err error) bool {
+	if pathErr, ok := err.(*os.PathError); ok {
+		err = pathErr.Err
+	}
 	return err == syscall.ENOSPC
 }
 
@@ -18,9 +18,13 @@ package cmd
 
 import "strings"
 
-// List of reserved words for files, includes old and new ones.
+// List of reserved words for files, includes old and new ones. Names
+// starting with "$" are an internal namespace reserved for minio's
+// system files, e.g. "$tmpfile", "$multiparts" and "$tmpobject", they
+// are skipped while listing directories. Object names using this
+// namespace are rejected on write, see isReservedObjectName.
 var posixReservedPrefix = []string{
-	"$tmpfile",
+	"$",
 	// Add new reserved words if any used in future.
 }
 
@@ -35,3 +39,15 @@ func hasPosixReservedPrefix(name string) (isReserved bool) {
 
 	return isReserved
 }
+
+// isReservedObjectName - returns true if any element of the object
+// name has a reserved prefix, such objects would be hidden from
+// listing and are not allowed to be created.
+func isReservedObjectName(object string) bool {
+	for _, name := range strings.Split(object, slashSeparator) {
+		if hasPosixReservedPrefix(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -46,6 +46,11 @@ type posix struct {
 	minFreeSpace  int64
 	minFreeInodes int64
 	pool          sync.Pool
+	// Absolute path the disk was configured with, differs from diskPath
+	// for symlinks. Identifies the disk, i.e in the multi path index.
+	configPath string
+	// Returns the usage of the disk, replaced in tests.
+	diskInfo func(diskPath string) (disk.Info, error)
 }
 
 // checkPathLength - returns error if given path name length more than 255
@@ -71,6 +76,66 @@ func checkPathLength(pathName string) error {
 	return nil
 }
 
+// isPathUnder - returns whether the slash separated path p is base
+// or a path inside base.
+func isPathUnder(base, p string) bool {
+	return p == base || strings.HasPrefix(p, strings.TrimSuffix(base, slashSeparator)+slashSeparator)
+}
+
+// safeJoin - joins an untrusted path to base, returns errFileAccessDenied
+// if the joined path, with symbolic links resolved, is outside base.
+func safeJoin(base, untrusted string) (string, error) {
+	joined := pathJoin(base, untrusted)
+	if !isPathUnder(base, slashpath.Clean(joined)) {
+		return "", errFileAccessDenied
+	}
+	resolvedBase, err := filepath.EvalSymlinks(preparePath(base))
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Nothing to resolve yet.
+			return joined, nil
+		}
+		return "", err
+	}
+	resolvedBase = filepath.ToSlash(resolvedBase)
+
+	// Resolve the longest existing part of the path, rest of it is
+	// yet to be created.
+	for existing := slashpath.Clean(joined); existing != base && isPathUnder(base, existing); existing = slashpath.Dir(existing) {
+		resolved, err := filepath.EvalSymlinks(preparePath(existing))
+		if err != nil {
+			continue
+		}
+		if !isPathUnder(resolvedBase, filepath.ToSlash(resolved)) {
+			return "", errFileAccessDenied
+		}
+		break
+	}
+	return joined, nil
+}
+
+// syncFile - flushes the data of a regular file to stable storage,
+// directories are left alone.
+func syncFile(filePath string) error {
+	st, err := os.Lstat(preparePath(filePath))
+	if err != nil {
+		return err
+	}
+	if !st.Mode().IsRegular() {
+		return nil
+	}
+	// Write access is needed to flush files on windows.
+	f, err := os.OpenFile(preparePath(filePath), os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
 // isDirEmpty - returns whether given directory is empty or not.
 func isDirEmpty(dirname string) bool {
 	f, err := os.Open(dirname)
@@ -99,6 +164,21 @@ func isDirEmpty(dirname string) bool {
 	return true
 }
 
+// getCanonicalDiskPath - returns the absolute path of an existing disk
+// with all symlinks resolved, isSymlink is set when it differs from the
+// absolute path.
+func getCanonicalDiskPath(path string) (canonicalPath string, isSymlink bool, err error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", false, err
+	}
+	canonicalPath, err = filepath.EvalSymlinks(absPath)
+	if err != nil {
+		return "", false, err
+	}
+	return canonicalPath, canonicalPath != absPath, nil
+}
+
 // Initialize a new storage disk.
 func newPosix(path string) (StorageAPI, error) {
 	if path == "" {
@@ -111,8 +191,10 @@ func newPosix(path string) (StorageAPI, error) {
 	}
 	fs := &posix{
 		diskPath:      diskPath,
+		configPath:    diskPath,
 		minFreeSpace:  fsMinFreeSpace,
 		minFreeInodes: fsMinFreeInodes,
+		diskInfo:      getDiskInfo,
 		// 1MiB buffer pool for posix internal operations.
 		pool: sync.Pool{
 			New: func() interface{} {
@@ -134,6 +216,11 @@ func newPosix(path string) (StorageAPI, error) {
 			return nil, err
 		}
 	}
+	// Resolve symlinks, so that the free space checks run against the
+	// disk the buckets are created on.
+	if fs.diskPath, _, err = getCanonicalDiskPath(diskPath); err != nil {
+		return nil, err
+	}
 	if err = fs.checkDiskFree(); err != nil {
 		return nil, err
 	}
@@ -162,7 +249,7 @@ func (s *posix) checkDiskFree() (err error) {
 	}
 
 	var di disk.Info
-	di, err = getDiskInfo(preparePath(s.diskPath))
+	di, err = s.diskInfo(preparePath(s.diskPath))
 	if err != nil {
 		return err
 	}
@@ -180,7 +267,7 @@ func (s *posix) checkDiskFree() (err error) {
 	if di.Files != 0 {
 		availableFiles := int64(di.Ffree)
 		if availableFiles <= s.minFreeInodes {
-			return errDiskFull
+			return errDiskInodesFull
 		}
 	}
 
@@ -188,9 +275,20 @@ func (s *posix) checkDiskFree() (err error) {
 	return nil
 }
 
+// toDiskFullErr - ENOSPC is returned both when the disk is out of space
+// and out of inodes, tells them apart from the free inodes of the disk.
+func (s *posix) toDiskFullErr() error {
+	di, err := s.diskInfo(preparePath(s.diskPath))
+	// Inodes are only known when the filesystem reports the total.
+	if err == nil && di.Files != 0 && di.Ffree == 0 {
+		return errDiskInodesFull
+	}
+	return errDiskFull
+}
+
 // Implements stringer compatible interface.
 func (s *posix) String() string {
-	return s.diskPath
+	return s.configPath
 }
 
 // Init - this is a dummy call.
@@ -217,8 +315,7 @@ func (s *posix) getVolDir(volume string) (string, error) {
 	if !isValidVolname(volume) {
 		return "", errInvalidArgument
 	}
-	volumeDir := pathJoin(s.diskPath, volume)
-	return volumeDir, nil
+	return safeJoin(s.diskPath, volume)
 }
 
 // checkDiskFound - validates if disk is available,
@@ -435,7 +532,11 @@ func (s *posix) ListDir(volume, dirPath string) (entries []string, err error) {
 		}
 		return nil, err
 	}
-	return readDir(pathJoin(volumeDir, dirPath))
+	dirPath, err = safeJoin(volumeDir, dirPath)
+	if err != nil {
+		return nil, err
+	}
+	return readDir(dirPath)
 }
 
 // ReadAll reads from r until an error or EOF and returns the data it read.
@@ -473,7 +574,10 @@ func (s *posix) ReadAll(volume, path string) (buf []byte, err error) {
 	}
 
 	// Validate file path length, before reading.
-	filePath := pathJoin(volumeDir, path)
+	filePath, err := safeJoin(volumeDir, path)
+	if err != nil {
+		return nil, err
+	}
 	if err = checkPathLength(preparePath(filePath)); err != nil {
 		return nil, err
 	}
@@ -517,70 +621,84 @@ func (s *posix) ReadFile(volume string, path string, offset int64, buf []byte) (
 		}
 	}()
 
+	file, err := s.openFile(volume, path)
+	if err != nil {
+		return 0, err
+	}
+
+	// Close the file descriptor.
+	defer file.Close()
+
+	// Seek to requested offset.
+	_, err = file.Seek(offset, os.SEEK_SET)
+	if err != nil {
+		return 0, err
+	}
+
+	// Read full until buffer.
+	m, err := io.ReadFull(file, buf)
+
+	// Success.
+	return int64(m), err
+}
+
+// openFile - opens a regular file under the volume for reading.
+func (s *posix) openFile(volume, path string) (file *os.File, err error) {
 	if s.ioErrCount > maxAllowedIOError {
-		return 0, errFaultyDisk
+		return nil, errFaultyDisk
 	}
 
 	if err = s.checkDiskFound(); err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	volumeDir, err := s.getVolDir(volume)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	// Stat a volume entry.
 	_, err = os.Stat(preparePath(volumeDir))
 	if err != nil {
 		if os.IsNotExist(err) {
-			return 0, errVolumeNotFound
+			return nil, errVolumeNotFound
 		}
-		return 0, err
+		return nil, err
 	}
 
 	// Validate effective path length before reading.
-	filePath := pathJoin(volumeDir, path)
+	filePath, err := safeJoin(volumeDir, path)
+	if err != nil {
+		return nil, err
+	}
 	if err = checkPathLength(preparePath(filePath)); err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	// Open the file for reading.
-	file, err := os.Open(preparePath(filePath))
+	file, err = os.Open(preparePath(filePath))
 	if err != nil {
 		if os.IsNotExist(err) {
-			return 0, errFileNotFound
+			return nil, errFileNotFound
 		} else if os.IsPermission(err) {
-			return 0, errFileAccessDenied
+			return nil, errFileAccessDenied
 		} else if isSysErrNotDir(err) {
-			return 0, errFileAccessDenied
+			return nil, errFileAccessDenied
 		}
-		return 0, err
+		return nil, err
 	}
 
-	// Close the file descriptor.
-	defer file.Close()
-
 	st, err := file.Stat()
 	if err != nil {
-		return 0, err
+		file.Close()
+		return nil, err
 	}
 
 	// Verify if its not a regular file, since subsequent Seek is undefined.
 	if !st.Mode().IsRegular() {
-		return 0, errIsNotRegular
-	}
-
-	// Seek to requested offset.
-	_, err = file.Seek(offset, os.SEEK_SET)
-	if err != nil {
-		return 0, err
+		file.Close()
+		return nil, errIsNotRegular
 	}
-
-	// Read full until buffer.
-	m, err := io.ReadFull(file, buf)
-
-	// Success.
-	return int64(m), err
+	return file, nil
 }
 
 func (s *posix) createFile(volume, path string) (f *os.File, err error) {
@@ -611,7 +729,10 @@ func (s *posix) createFile(volume, path string) (f *os.File, err error) {
 		return nil, err
 	}
 
-	filePath := pathJoin(volumeDir, path)
+	filePath, err := safeJoin(volumeDir, path)
+	if err != nil {
+		return nil, err
+	}
 	if err = checkPathLength(preparePath(filePath)); err != nil {
 		return nil, err
 	}
@@ -642,6 +763,8 @@ func (s *posix) createFile(volume, path string) (f *os.File, err error) {
 		// File path cannot be verified since one of the parents is a file.
 		if isSysErrNotDir(err) {
 			return nil, errFileAccessDenied
+		} else if isSysErrNoSpace(err) {
+			return nil, s.toDiskFullErr()
 		}
 		return nil, err
 	}
@@ -731,6 +854,9 @@ func (s *posix) AppendFile(volume, path string, buf []byte) (err error) {
 
 	// Return io.Copy
 	_, err = io.CopyBuffer(w, bytes.NewReader(buf), *bufp)
+	if isSysErrNoSpace(err) {
+		return s.toDiskFullErr()
+	}
 	return err
 }
 
@@ -763,7 +889,10 @@ func (s *posix) StatFile(volume, path string) (file FileInfo, err error) {
 		return FileInfo{}, err
 	}
 
-	filePath := slashpath.Join(volumeDir, path)
+	filePath, err := safeJoin(volumeDir, slashpath.Clean(path))
+	if err != nil {
+		return FileInfo{}, err
+	}
 	if err = checkPathLength(preparePath(filePath)); err != nil {
 		return FileInfo{}, err
 	}
@@ -861,7 +990,10 @@ func (s *posix) DeleteFile(volume, path string) (err error) {
 
 	// Following code is needed so that we retain "/" suffix if any in
 	// path argument.
-	filePath := pathJoin(volumeDir, path)
+	filePath, err := safeJoin(volumeDir, path)
+	if err != nil {
+		return err
+	}
 	if err = checkPathLength(preparePath(filePath)); err != nil {
 		return err
 	}
@@ -915,11 +1047,17 @@ func (s *posix) RenameFile(srcVolume, srcPath, dstVolume, dstPath string) (err e
 	if !(srcIsDir && dstIsDir || !srcIsDir && !dstIsDir) {
 		return errFileAccessDenied
 	}
-	srcFilePath := slashpath.Join(srcVolumeDir, srcPath)
+	srcFilePath, err := safeJoin(srcVolumeDir, slashpath.Clean(srcPath))
+	if err != nil {
+		return err
+	}
 	if err = checkPathLength(preparePath(srcFilePath)); err != nil {
 		return err
 	}
-	dstFilePath := slashpath.Join(dstVolumeDir, dstPath)
+	dstFilePath, err := safeJoin(dstVolumeDir, slashpath.Clean(dstPath))
+	if err != nil {
+		return err
+	}
 	if err = checkPathLength(preparePath(dstFilePath)); err != nil {
 		return err
 	}
@@ -947,7 +1085,18 @@ func (s *posix) RenameFile(srcVolume, srcPath, dstVolume, dstPath string) (err e
 		}
 		return err
 	}
-	// Finally attempt a rename.
+	// Flush the source file before the rename makes it visible, so
+	// that a crash never leaves a partially written file behind.
+	if !srcIsDir {
+		if err = syncFile(srcFilePath); err != nil {
+			if os.IsNotExist(err) {
+				return errFileNotFound
+			}
+			return err
+		}
+	}
+	// Finally attempt a rename, on windows os.Rename replaces an existing
+	// destination through MoveFileEx.
 	err = os.Rename(preparePath(srcFilePath), preparePath(dstFilePath))
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -22,10 +22,13 @@ import (
 	"io/ioutil"
 	"os"
 	slashpath "path"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"syscall"
 	"testing"
+
+	"github.com/minio/minio/pkg/disk"
 )
 
 // creates a temp dir and sets up posix layer.
@@ -212,6 +215,50 @@ func TestNewPosix(t *testing.T) {
 	}
 }
 
+// TestNewPosixSymlink - tests a symlinked disk is served from the target
+// of the symlink under the name it was configured with.
+func TestNewPosixSymlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Symlinks require special privileges on windows")
+	}
+	diskPath, err := ioutil.TempDir("", "minio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(diskPath)
+	// The temporary directory itself may be behind a symlink.
+	if diskPath, err = filepath.EvalSymlinks(diskPath); err != nil {
+		t.Fatal(err)
+	}
+	linkPath := diskPath + "-link"
+	if err = os.Symlink(diskPath, linkPath); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(linkPath)
+
+	canonicalPath, isSymlink, err := getCanonicalDiskPath(linkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if canonicalPath != diskPath || !isSymlink {
+		t.Fatalf("Expected %s to be a symlink to %s, got %s, %t", linkPath, diskPath, canonicalPath, isSymlink)
+	}
+	if _, isSymlink, err = getCanonicalDiskPath(diskPath); err != nil || isSymlink {
+		t.Fatalf("Expected %s not to be a symlink, got %t, %v", diskPath, isSymlink, err)
+	}
+
+	storage, err := newPosix(linkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if storage.(*posix).diskPath != diskPath {
+		t.Fatalf("Expected disk path %s, got %s", diskPath, storage.(*posix).diskPath)
+	}
+	if storage.String() != linkPath {
+		t.Fatalf("Expected disk name %s, got %s", linkPath, storage.String())
+	}
+}
+
 // TestMakeVol - Test validate the logic for creation of new posix volume.
 // Asserts the failures too against the expected failures.
 func TestMakeVol(t *testing.T) {
@@ -1556,3 +1603,41 @@ func TestStatFile(t *testing.T) {
 		}
 	}
 }
+
+// Tests that ENOSPC is reported as out of inodes only when the disk has
+// no free inodes.
+func TestPosixToDiskFullErr(t *testing.T) {
+	posixStorage, diskPath, err := newPosixTestSetup()
+	if err != nil {
+		t.Fatalf("Unable to create posix test setup, %s", err)
+	}
+	defer removeAll(diskPath)
+	s := posixStorage.(*posix)
+
+	testCases := []struct {
+		info        disk.Info
+		expectedErr error
+	}{
+		{disk.Info{Total: 1024, Free: 0, Files: 1000, Ffree: 10}, errDiskFull},
+		{disk.Info{Total: 1024, Free: 512, Files: 1000, Ffree: 0}, errDiskInodesFull},
+		// Total inodes are not reported by some filesystems.
+		{disk.Info{Total: 1024, Free: 512, Files: 0, Ffree: 0}, errDiskFull},
+	}
+	for i, testCase := range testCases {
+		info := testCase.info
+		s.diskInfo = func(string) (disk.Info, error) { return info, nil }
+		if err = s.toDiskFullErr(); err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expectedErr, err)
+		}
+	}
+
+	// Disks below the minimum free inodes are reported as out of inodes.
+	if runtime.GOOS != "windows" {
+		s.diskInfo = func(string) (disk.Info, error) {
+			return disk.Info{Total: 100 * fsMinFreeSpace, Free: 100 * fsMinFreeSpace, Files: 1000, Ffree: 0}, nil
+		}
+		if err = s.checkDiskFree(); err != errDiskInodesFull {
+			t.Errorf("Expected %v, got %v", errDiskInodesFull, err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"strings"
+
+	router "github.com/gorilla/mux"
+)
+
+const (
+	pprofPath = "/debug/pprof"
+)
+
+// registerPprofRouter - registers the Go profiling handlers when enabled
+// using --enable-pprof, requests are authenticated with the RPC token.
+func registerPprofRouter(mux *router.Router) {
+	if !globalPprofEnabled {
+		return
+	}
+	pprofRouter := mux.NewRoute().PathPrefix(reservedBucket + pprofPath).Subrouter()
+	pprofRouter.Methods("GET").Path("/cmdline").Handler(pprofHandler(pprof.Cmdline))
+	pprofRouter.Methods("GET").Path("/profile").Handler(pprofHandler(pprof.Profile))
+	pprofRouter.Methods("GET").Path("/symbol").Handler(pprofHandler(pprof.Symbol))
+	pprofRouter.Methods("POST").Path("/symbol").Handler(pprofHandler(pprof.Symbol))
+	pprofRouter.Methods("GET").Path("/trace").Handler(pprofHandler(pprof.Trace))
+	// Index lists the profiles and serves the named ones, e.g. heap.
+	pprofRouter.Methods("GET").PathPrefix("/").Handler(pprofHandler(pprof.Index))
+}
+
+// pprofHandler - rejects requests without a valid RPC token, the
+// reserved bucket prefix is stripped for the paths pprof expects.
+func pprofHandler(fn http.HandlerFunc) http.Handler {
+	return rpcTokenAuthHandler(http.StripPrefix(reservedBucket, fn))
+}
+
+// rpcTokenAuthHandler - serves only requests carrying a valid RPC
+// token, i.e. the token of 'minio admin'.
+func rpcTokenAuthHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), jwtAlgorithm))
+		if !isRequestJWT(r) || !isRPCTokenValid(token) {
+			writeErrorResponse(w, r, ErrAccessDenied, r.URL.Path)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// getHeapProfileURL - returns the URL of the heap profile of this
+// server, empty when profiling is disabled.
+func getHeapProfileURL() string {
+	if !globalPprofEnabled {
+		return ""
+	}
+	host, port, err := net.SplitHostPort(globalMinioAddr)
+	if err != nil {
+		return ""
+	}
+	if host == "" {
+		// Listening on all addresses.
+		if host, err = os.Hostname(); err != nil {
+			return ""
+		}
+	}
+	scheme := "http"
+	if isServerTLS() {
+		scheme = "https"
+	}
+	return scheme + "://" + net.JoinHostPort(host, port) + reservedBucket + pprofPath + "/heap"
+}
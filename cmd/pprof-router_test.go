@@ -0,0 +1,121 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// Tests the profiling handlers of a server started with --enable-pprof.
+func TestPprofRouter(t *testing.T) {
+	// The test server changes the address globals.
+	defer restoreMinioAddrGlobals(globalMinioAddr, globalMinioHost, globalMinioPort)
+	globalPprofEnabled = true
+	defer func() { globalPprofEnabled = false }()
+
+	testServer := StartTestServer(t, "FS")
+	defer testServer.Stop()
+
+	jwt, err := newJWT(defaultInterNodeJWTExpiry, serverConfig.GetCredential())
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.GenerateToken(testServer.AccessKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		path          string
+		authorization string
+		status        int
+		contains      string
+	}{
+		// Index lists the profiles.
+		{"/minio/debug/pprof/", jwtAlgorithm + " " + token, http.StatusOK, "heap"},
+		{"/minio/debug/pprof/heap?debug=1", jwtAlgorithm + " " + token, http.StatusOK, "heap profile"},
+		{"/minio/debug/pprof/cmdline", jwtAlgorithm + " " + token, http.StatusOK, ""},
+		// Requests without a valid token are rejected.
+		{"/minio/debug/pprof/", "", http.StatusForbidden, "AccessDenied"},
+		// Invalid tokens are rejected by the auth handler.
+		{"/minio/debug/pprof/heap", jwtAlgorithm + " garbage", http.StatusUnauthorized, ""},
+	}
+	for i, testCase := range testCases {
+		req, err := http.NewRequest("GET", testServer.Server.URL+testCase.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if testCase.authorization != "" {
+			req.Header.Set("Authorization", testCase.authorization)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != testCase.status {
+			t.Fatalf("Test %d: Expected status %d, got %d", i+1, testCase.status, resp.StatusCode)
+		}
+		if !strings.Contains(string(body), testCase.contains) {
+			t.Errorf("Test %d: Expected response to contain %q, got %s", i+1, testCase.contains, body)
+		}
+	}
+
+	globalMinioAddr = "127.0.0.1:9000"
+	if url := getHeapProfileURL(); url != "http://127.0.0.1:9000/minio/debug/pprof/heap" {
+		t.Errorf("Unexpected heap profile URL %s", url)
+	}
+}
+
+// Tests that the profiling handlers are not served by default.
+func TestPprofRouterDisabled(t *testing.T) {
+	defer restoreMinioAddrGlobals(globalMinioAddr, globalMinioHost, globalMinioPort)
+
+	testServer := StartTestServer(t, "FS")
+	defer testServer.Stop()
+
+	resp, err := http.Get(testServer.Server.URL + "/minio/debug/pprof/heap?debug=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The path is left to the browser handlers.
+	if strings.Contains(string(body), "heap profile") {
+		t.Fatal("Expected the profiling handlers to be disabled")
+	}
+	if url := getHeapProfileURL(); url != "" {
+		t.Errorf("Expected no heap profile URL, got %s", url)
+	}
+}
+
+// restoreMinioAddrGlobals - restores the address globals set by a test server.
+func restoreMinioAddrGlobals(addr, host, port string) {
+	globalMinioAddr = addr
+	globalMinioHost = host
+	globalMinioPort = port
+}
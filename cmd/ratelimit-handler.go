@@ -0,0 +1,203 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Default number of requests per second of each client IP.
+	globalDefaultPerIPRateLimit = 100
+
+	// Rate limits of clients inactive for longer are dropped.
+	rateLimitMaxIdle = time.Hour
+
+	// Interval stale rate limits are dropped at.
+	rateLimitCleanupInterval = 10 * time.Minute
+
+	// Maximum number of clients whose rate limits are kept.
+	rateLimitMaxClients = 100000
+)
+
+// tokenBucket - requests of a client are allowed while tokens are left,
+// tokens are refilled at a fixed rate up to the size of the bucket.
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// ipRateLimiter - limits the rate of requests of each client IP.
+type ipRateLimiter struct {
+	mu          sync.Mutex
+	rate        float64
+	behindProxy bool
+	maxClients  int
+	buckets     map[string]*tokenBucket
+}
+
+func newIPRateLimiter(rate int, behindProxy bool) *ipRateLimiter {
+	return &ipRateLimiter{
+		rate:        float64(rate),
+		behindProxy: behindProxy,
+		maxClients:  rateLimitMaxClients,
+		buckets:     make(map[string]*tokenBucket),
+	}
+}
+
+// Rate limiter of client IPs set using --per-ip-rate-limit, requests
+// are not limited when nil.
+var globalIPRateLimiter *ipRateLimiter
+
+// allow - takes a token of the client ip, returns false along with the
+// time until the next token is available when none is left. New clients
+// are rejected while the rate limits of maxClients clients are in use.
+func (l *ipRateLimiter) allow(ip string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, ok := l.buckets[ip]
+	if !ok {
+		if len(l.buckets) >= l.maxClients {
+			l.dropRefilled(now)
+			if len(l.buckets) >= l.maxClients {
+				return false, time.Second
+			}
+		}
+		bucket = &tokenBucket{tokens: l.rate, lastSeen: now}
+		l.buckets[ip] = bucket
+	}
+	// Refill tokens for the time elapsed since the last request.
+	bucket.tokens = math.Min(l.rate, bucket.tokens+now.Sub(bucket.lastSeen).Seconds()*l.rate)
+	bucket.lastSeen = now
+	if bucket.tokens < 1 {
+		return false, time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+// cleanup - drops the rate limits of clients inactive for maxIdle.
+func (l *ipRateLimiter) cleanup(now time.Time, maxIdle time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for ip, bucket := range l.buckets {
+		if now.Sub(bucket.lastSeen) > maxIdle {
+			delete(l.buckets, ip)
+		}
+	}
+}
+
+// dropRefilled - drops the rate limits whose tokens are refilled, they
+// are the same as those of new clients. Caller must hold l.mu.
+func (l *ipRateLimiter) dropRefilled(now time.Time) {
+	for ip, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.lastSeen).Seconds()*l.rate >= l.rate {
+			delete(l.buckets, ip)
+		}
+	}
+}
+
+// startCleanup - drops stale rate limits every interval until doneCh
+// is closed.
+func (l *ipRateLimiter) startCleanup(interval time.Duration, doneCh <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				l.cleanup(now, rateLimitMaxIdle)
+			case <-doneCh:
+				return
+			}
+		}
+	}()
+}
+
+// clientIP - returns the IP of the client, the last address of
+// X-Forwarded-For is used behind a proxy. It is the one added by the
+// proxy, clients can send any other addresses.
+func (l *ipRateLimiter) clientIP(r *http.Request) string {
+	if forwardedFor := r.Header["X-Forwarded-For"]; l.behindProxy && len(forwardedFor) > 0 {
+		addrs := strings.Split(forwardedFor[len(forwardedFor)-1], ",")
+		if ip := strings.TrimSpace(addrs[len(addrs)-1]); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// rateLimitHandler - rejects requests of clients exceeding their rate.
+type rateLimitHandler struct {
+	handler http.Handler
+	limiter *ipRateLimiter
+}
+
+func setRateLimitHandler(h http.Handler) http.Handler {
+	if globalIPRateLimiter == nil {
+		return h
+	}
+	return rateLimitHandler{handler: h, limiter: globalIPRateLimiter}
+}
+
+// Paths of the RPC services servers call on each other.
+var interNodeRPCPaths = []string{
+	storageRPCPath,
+	lockRPCPath,
+	reservedBucket + adminPath,
+	reservedBucket + s3Path,
+	reservedBucket + browserPeerPath,
+}
+
+// isInterNodeRPCRequest - returns true if the request is a call to one
+// of the RPC services of other servers.
+func isInterNodeRPCRequest(r *http.Request) bool {
+	for _, rpcPath := range interNodeRPCPaths {
+		if r.URL.Path == rpcPath || strings.HasPrefix(r.URL.Path, rpcPath+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (h rateLimitHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// RPC calls of other servers are not limited, requests of the
+	// browser are limited like any other client.
+	if isInterNodeRPCRequest(r) {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	allowed, retryAfter := h.limiter.allow(h.limiter.clientIP(r), time.Now().UTC())
+	if !allowed {
+		// Retry-After is in whole seconds, round up.
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		writeErrorResponse(w, r, ErrTooManyRequests, r.URL.Path)
+		return
+	}
+	h.handler.ServeHTTP(w, r)
+}
@@ -0,0 +1,183 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Tests that a burst of requests of a client is limited to its rate.
+func TestRateLimitHandler(t *testing.T) {
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := rateLimitHandler{handler: okHandler, limiter: newIPRateLimiter(100, false)}
+
+	succeeded, rejected := 0, 0
+	for i := 0; i < 200; i++ {
+		req, err := http.NewRequest("GET", "http://127.0.0.1:9000/bucket/object", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.RemoteAddr = "192.168.1.10:5000"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		switch rec.Code {
+		case http.StatusOK:
+			succeeded++
+		case http.StatusTooManyRequests:
+			if rec.Header().Get("Retry-After") != "1" {
+				t.Fatalf("Expected Retry-After of 1 second, got %q", rec.Header().Get("Retry-After"))
+			}
+			rejected++
+		default:
+			t.Fatalf("Unexpected response status %d", rec.Code)
+		}
+	}
+	// A few tokens may be refilled while the burst is served.
+	if succeeded < 100 || succeeded > 110 || succeeded+rejected != 200 {
+		t.Fatalf("Expected about 100 requests to succeed, %d succeeded and %d were rejected", succeeded, rejected)
+	}
+
+	// Other clients are not limited.
+	req, err := http.NewRequest("GET", "http://127.0.0.1:9000/bucket/object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.RemoteAddr = "192.168.1.11:5000"
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the request of another client to succeed, got %d", rec.Code)
+	}
+
+	// RPC requests of other servers are not limited.
+	for _, rpcPath := range []string{lockRPCPath + "/export", storageRPCPath + "/export", reservedBucket + adminPath, reservedBucket + s3Path, reservedBucket + browserPeerPath} {
+		req, err = http.NewRequest("POST", "http://127.0.0.1:9000"+rpcPath, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.RemoteAddr = "192.168.1.10:5000"
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected RPC request to %s not to be limited, got %d", rpcPath, rec.Code)
+		}
+	}
+
+	// Requests of the browser are limited.
+	for _, browserPath := range []string{reservedBucket + "/webrpc", reservedBucket + "/upload/bucket/object", reservedBucket + "/lockout"} {
+		req, err = http.NewRequest("POST", "http://127.0.0.1:9000"+browserPath, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.RemoteAddr = "192.168.1.10:5000"
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("Expected request to %s to be limited, got %d", browserPath, rec.Code)
+		}
+	}
+}
+
+// Tests refilling and cleanup of the tokens of clients.
+func TestIPRateLimiter(t *testing.T) {
+	limiter := newIPRateLimiter(10, false)
+	now := time.Now().UTC()
+	for i := 0; i < 10; i++ {
+		if allowed, _ := limiter.allow("10.0.0.1", now); !allowed {
+			t.Fatalf("Expected request %d to be allowed", i)
+		}
+	}
+	allowed, retryAfter := limiter.allow("10.0.0.1", now)
+	if allowed || retryAfter != 100*time.Millisecond {
+		t.Fatalf("Expected request to be rejected for 100ms, got %v %s", allowed, retryAfter)
+	}
+	// Half a second refills five tokens.
+	now = now.Add(500 * time.Millisecond)
+	for i := 0; i < 5; i++ {
+		if allowed, _ = limiter.allow("10.0.0.1", now); !allowed {
+			t.Fatalf("Expected request %d to be allowed after refill", i)
+		}
+	}
+	if allowed, _ = limiter.allow("10.0.0.1", now); allowed {
+		t.Fatal("Expected request to be rejected after refill")
+	}
+
+	limiter.allow("10.0.0.2", now.Add(rateLimitMaxIdle))
+	limiter.cleanup(now.Add(rateLimitMaxIdle+time.Second), rateLimitMaxIdle)
+	if _, ok := limiter.buckets["10.0.0.1"]; ok {
+		t.Fatal("Expected the stale client to be dropped")
+	}
+	if _, ok := limiter.buckets["10.0.0.2"]; !ok {
+		t.Fatal("Expected the active client to be kept")
+	}
+}
+
+// Tests the number of clients whose rate limits are kept is capped.
+func TestIPRateLimiterMaxClients(t *testing.T) {
+	limiter := newIPRateLimiter(10, false)
+	limiter.maxClients = 2
+	now := time.Now().UTC()
+	limiter.allow("10.0.0.1", now)
+	limiter.allow("10.0.0.2", now)
+	if allowed, _ := limiter.allow("10.0.0.3", now); allowed {
+		t.Fatal("Expected a new client to be rejected while all rate limits are in use")
+	}
+	// Rate limits whose tokens are refilled make room for new clients.
+	now = now.Add(time.Second)
+	if allowed, _ := limiter.allow("10.0.0.3", now); !allowed {
+		t.Fatal("Expected a new client to be allowed once rate limits are refilled")
+	}
+	if len(limiter.buckets) > limiter.maxClients {
+		t.Fatalf("Expected at most %d rate limits, found %d", limiter.maxClients, len(limiter.buckets))
+	}
+}
+
+// Tests the client IP used for rate limiting.
+func TestRateLimitClientIP(t *testing.T) {
+	testCases := []struct {
+		behindProxy  bool
+		remoteAddr   string
+		forwardedFor string
+		ip           string
+	}{
+		{false, "192.168.1.10:5000", "", "192.168.1.10"},
+		{false, "192.168.1.10:5000", "10.0.0.1", "192.168.1.10"},
+		// Only the address added by the proxy is trusted.
+		{true, "192.168.1.10:5000", "10.0.0.1, 10.0.0.2", "10.0.0.2"},
+		{true, "192.168.1.10:5000", "10.0.0.1,", "192.168.1.10"},
+		{true, "192.168.1.10:5000", "", "192.168.1.10"},
+		{false, "[::1]:5000", "", "::1"},
+	}
+	for i, testCase := range testCases {
+		req, err := http.NewRequest("GET", "http://127.0.0.1:9000/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.RemoteAddr = testCase.remoteAddr
+		if testCase.forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", testCase.forwardedFor)
+		}
+		if ip := newIPRateLimiter(1, testCase.behindProxy).clientIP(req); ip != testCase.ip {
+			t.Errorf("Test %d: Expected client IP %s, got %s", i+1, testCase.ip, ip)
+		}
+	}
+}
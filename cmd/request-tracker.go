@@ -0,0 +1,150 @@
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Maximum number of in-flight requests listed in a dump.
+const inFlightDumpMaxRequests = 10
+
+// RequestInfo - information about an in-flight request.
+type RequestInfo struct {
+	RequestID string        `json:"requestId"`
+	Method    string        `json:"method"`
+	Path      string        `json:"path"`
+	StartTime time.Time     `json:"-"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// inFlightDump - summary of in-flight requests, written on a dump signal.
+type inFlightDump struct {
+	Count    int           `json:"count"`
+	Requests []RequestInfo `json:"requests"`
+}
+
+// inFlightRequests - keeps track of all the requests being served.
+type inFlightRequests struct {
+	mu       sync.RWMutex
+	requests map[string]RequestInfo
+}
+
+func newInFlightRequests() *inFlightRequests {
+	return &inFlightRequests{requests: make(map[string]RequestInfo)}
+}
+
+// Global tracker for in-flight requests.
+var globalInFlightRequests = newInFlightRequests()
+
+// add - tracks a new request, returns the id it is tracked with.
+func (f *inFlightRequests) add(r *http.Request) string {
+	info := RequestInfo{
+		RequestID: newRequestID(),
+		Method:    r.Method,
+		Path:      r.URL.Path,
+		StartTime: time.Now().UTC(),
+	}
+	f.mu.Lock()
+	f.requests[info.RequestID] = info
+	f.mu.Unlock()
+	return info.RequestID
+}
+
+// remove - stops tracking a finished request.
+func (f *inFlightRequests) remove(requestID string) {
+	f.mu.Lock()
+	delete(f.requests, requestID)
+	f.mu.Unlock()
+}
+
+// dump - writes a JSON summary of the in-flight requests, only the
+// longest running maxRequests are listed.
+func (f *inFlightRequests) dump(w io.Writer, maxRequests int) error {
+	now := time.Now().UTC()
+	f.mu.RLock()
+	requests := make([]RequestInfo, 0, len(f.requests))
+	for _, info := range f.requests {
+		info.Duration = now.Sub(info.StartTime)
+		requests = append(requests, info)
+	}
+	f.mu.RUnlock()
+
+	// Longest running requests first.
+	sort.Sort(byRequestDuration(requests))
+
+	summary := inFlightDump{Count: len(requests), Requests: requests}
+	if len(summary.Requests) > maxRequests {
+		summary.Requests = summary.Requests[:maxRequests]
+	}
+	return json.NewEncoder(w).Encode(summary)
+}
+
+// byRequestDuration is a collection satisfying sort.Interface, sorts
+// requests in descending order of their duration.
+type byRequestDuration []RequestInfo
+
+func (d byRequestDuration) Len() int           { return len(d) }
+func (d byRequestDuration) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+func (d byRequestDuration) Less(i, j int) bool { return d[i].Duration > d[j].Duration }
+
+// requestTrackerHandler - tracks all the requests while they are served.
+type requestTrackerHandler struct {
+	handler  http.Handler
+	inFlight *inFlightRequests
+}
+
+func setRequestTrackerHandler(h http.Handler) http.Handler {
+	return requestTrackerHandler{handler: h, inFlight: globalInFlightRequests}
+}
+
+func (h requestTrackerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	requestID := h.inFlight.add(r)
+	defer h.inFlight.remove(requestID)
+	h.handler.ServeHTTP(w, r)
+}
+
+// startInFlightDumper - registers the dump signals and writes a summary
+// of in-flight requests to w every time one of them is received, until
+// doneCh is closed.
+func startInFlightDumper(w io.Writer, doneCh <-chan struct{}) {
+	if len(inFlightDumpSignals) == 0 {
+		// Dump signals are not supported on this platform.
+		return
+	}
+	// Register before returning so that signals are not lost.
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, inFlightDumpSignals...)
+	go func() {
+		defer signal.Stop(sigCh)
+		for {
+			select {
+			case <-sigCh:
+				errorIf(globalInFlightRequests.dump(w, inFlightDumpMaxRequests), "Unable to dump in-flight requests.")
+			case <-doneCh:
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,127 @@
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// startSlowRequests - starts n concurrent requests through the request
+// tracker which block until the returned release function is called.
+func startSlowRequests(t *testing.T, inFlight *inFlightRequests, n int) (release func()) {
+	blockCh := make(chan struct{})
+	startedWg := &sync.WaitGroup{}
+	doneWg := &sync.WaitGroup{}
+	handler := requestTrackerHandler{
+		handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			startedWg.Done()
+			<-blockCh
+		}),
+		inFlight: inFlight,
+	}
+	for i := 0; i < n; i++ {
+		req, err := http.NewRequest("GET", fmt.Sprintf("/bucket/object-%d", i), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		startedWg.Add(1)
+		doneWg.Add(1)
+		go func() {
+			defer doneWg.Done()
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+		}()
+	}
+	startedWg.Wait()
+	return func() {
+		close(blockCh)
+		doneWg.Wait()
+	}
+}
+
+// Validates the in-flight requests dump.
+func validateInFlightDump(t *testing.T, summary inFlightDump, n int) {
+	if summary.Count != n {
+		t.Fatalf("Expected %d in-flight requests, got %d", n, summary.Count)
+	}
+	if len(summary.Requests) != n {
+		t.Fatalf("Expected %d requests in dump, got %d", n, len(summary.Requests))
+	}
+	paths := make(map[string]bool)
+	for i, info := range summary.Requests {
+		if info.RequestID == "" || info.Method != "GET" {
+			t.Errorf("Unexpected request info %#v", info)
+		}
+		if i > 0 && summary.Requests[i-1].Duration < info.Duration {
+			t.Errorf("Expected requests to be sorted by descending duration")
+		}
+		paths[info.Path] = true
+	}
+	for i := 0; i < n; i++ {
+		if path := fmt.Sprintf("/bucket/object-%d", i); !paths[path] {
+			t.Errorf("Expected %s in dump", path)
+		}
+	}
+}
+
+// Tests tracking and dumping of in-flight requests.
+func TestInFlightRequestsDump(t *testing.T) {
+	inFlight := newInFlightRequests()
+	release := startSlowRequests(t, inFlight, 5)
+
+	var buf bytes.Buffer
+	if err := inFlight.dump(&buf, inFlightDumpMaxRequests); err != nil {
+		t.Fatal(err)
+	}
+	var summary inFlightDump
+	if err := json.Unmarshal(buf.Bytes(), &summary); err != nil {
+		t.Fatal(err)
+	}
+	validateInFlightDump(t, summary, 5)
+
+	// Only the longest running requests are listed.
+	buf.Reset()
+	if err := inFlight.dump(&buf, 2); err != nil {
+		t.Fatal(err)
+	}
+	summary = inFlightDump{}
+	if err := json.Unmarshal(buf.Bytes(), &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.Count != 5 || len(summary.Requests) != 2 {
+		t.Fatalf("Unexpected dump %#v", summary)
+	}
+
+	// Finished requests should not be tracked anymore.
+	release()
+	buf.Reset()
+	if err := inFlight.dump(&buf, inFlightDumpMaxRequests); err != nil {
+		t.Fatal(err)
+	}
+	summary = inFlightDump{}
+	if err := json.Unmarshal(buf.Bytes(), &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.Count != 0 || len(summary.Requests) != 0 {
+		t.Fatalf("Expected no in-flight requests, got %#v", summary)
+	}
+}
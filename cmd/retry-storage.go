@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"net/rpc"
+	"os"
 
 	"github.com/minio/minio/pkg/disk"
 )
@@ -222,3 +223,8 @@ func (f retryStorage) RenameFile(srcVolume, srcPath, dstVolume, dstPath string)
 	}
 	return err
 }
+
+// openFile - opens the file if the underlying storage is a local disk.
+func (f retryStorage) openFile(volume, path string) (*os.File, error) {
+	return openLocalFile(f.remoteStorage, volume, path)
+}
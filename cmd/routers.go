@@ -107,6 +107,17 @@ func configureServerHandler(srvCmdConfig serverCmdConfig) (http.Handler, error)
 		return nil, err
 	}
 
+	// Register RPC router for the 'minio admin' command.
+	if err = registerAdminRPCRouter(mux); err != nil {
+		return nil, err
+	}
+
+	// Register router for the metrics.
+	registerMetricsRouter(mux)
+
+	// Register router for profiling, only when enabled.
+	registerPprofRouter(mux)
+
 	if err = registerWebRouter(mux); err != nil {
 		return nil, err
 	}
@@ -133,10 +144,24 @@ func configureServerHandler(srvCmdConfig serverCmdConfig) (http.Handler, error)
 		// Validates all incoming URL resources, for invalid/unsupported
 		// resources client receives a HTTP error.
 		setIgnoreResourcesHandler,
+		// Rejects object uploads with unsupported Content-Type.
+		setValidContentTypeHandler,
+		// Rejects object uploads with invalid or too large user metadata.
+		setMetadataSizeHandler,
 		// Auth handler verifies incoming authorization headers and
 		// routes them accordingly. Client receives a HTTP error for
 		// invalid/unsupported signatures.
 		setAuthHandler,
+		// Tracks all in-flight requests, dumped on signal.
+		setRequestTrackerHandler,
+		// Times out S3 API requests, slow object transfers are failed.
+		setTimeoutHandler,
+		// Adds HSTS and other security headers to all responses over TLS.
+		setHSTSHandler,
+		// Limits the rate of requests of each client IP.
+		setRateLimitHandler,
+		// Logs all served requests to the access log.
+		setAccessLogHandler,
 		// Add new handlers here.
 	}
 
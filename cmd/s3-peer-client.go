@@ -65,7 +65,7 @@ func makeS3Peers(eps []*url.URL) s3Peers {
 				accessKey:   serverConfig.GetCredential().AccessKeyID,
 				secretKey:   serverConfig.GetCredential().SecretAccessKey,
 				address:     ep.Host,
-				secureConn:  isSSL(),
+				secureConn:  isServerTLS(),
 				path:        path.Join(reservedBucket, s3Path),
 				loginMethod: "S3.LoginHandler",
 			}
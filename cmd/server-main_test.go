@@ -18,11 +18,13 @@ package cmd
 
 import (
 	"flag"
+	"net"
 	"net/http"
 	"os"
 	"runtime"
 	"testing"
 
+	humanize "github.com/dustin/go-humanize"
 	"github.com/minio/cli"
 )
 
@@ -62,6 +64,62 @@ func TestGetListenIPs(t *testing.T) {
 	}
 }
 
+// Tests normalizing server addresses without an explicit port.
+func TestNormalizeAddress(t *testing.T) {
+	testCases := []struct {
+		addr         string
+		expectedAddr string
+	}{
+		{"localhost:9001", "localhost:9001"},
+		{":9001", ":9001"},
+		{"192.168.1.1", "192.168.1.1:9000"},
+		{"localhost", "localhost:9000"},
+		{"", ":9000"},
+		{"::1", "[::1]:9000"},
+		{"[::1]", "[::1]:9000"},
+		{"[::1]:9001", "[::1]:9001"},
+	}
+	for i, testCase := range testCases {
+		addr := normalizeAddress(testCase.addr)
+		if addr != testCase.expectedAddr {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expectedAddr, addr)
+		}
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			t.Errorf("Test %d: Unable to parse normalized address %s: %s", i+1, addr, err)
+		}
+	}
+}
+
+// Tests parsing and validating erasure block sizes.
+func TestParseErasureBlockSize(t *testing.T) {
+	testCases := []struct {
+		sizeStr      string
+		expectedSize int64
+		shouldPass   bool
+	}{
+		{"10MiB", 10 * humanize.MiByte, true},
+		{"1MiB", humanize.MiByte, true},
+		{"64KiB", 64 * humanize.KiByte, true},
+		{"1048576", humanize.MiByte, true},
+		{"1KiB", 0, false},
+		{"1GiB", 0, false},
+		{"invalid", 0, false},
+		{"", 0, false},
+	}
+	for i, testCase := range testCases {
+		size, err := parseErasureBlockSize(testCase.sizeStr)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Expected to pass, but failed with %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected to fail, but passed", i+1)
+		}
+		if size != testCase.expectedSize {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.expectedSize, size)
+		}
+	}
+}
+
 func TestFinalizeEndpoints(t *testing.T) {
 	testCases := []struct {
 		tls  bool
@@ -347,3 +405,41 @@ func TestInitServerConfig(t *testing.T) {
 		initServerConfig(ctx)
 	}
 }
+
+// Tests overriding the region in config with MINIO_REGION.
+func TestSetRegionFromEnv(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal("Failed to set up test config")
+	}
+	defer removeAll(root)
+	defer os.Unsetenv("MINIO_REGION")
+
+	// Region in config is kept without MINIO_REGION.
+	os.Unsetenv("MINIO_REGION")
+	if err = setRegionFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if region := serverConfig.GetRegion(); region != "us-east-1" {
+		t.Fatalf("Expected region `us-east-1`, got `%s`", region)
+	}
+
+	os.Setenv("MINIO_REGION", "ap-southeast-1")
+	if err = setRegionFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if region := serverConfig.GetRegion(); region != "ap-southeast-1" {
+		t.Fatalf("Expected region `ap-southeast-1`, got `%s`", region)
+	}
+
+	// Invalid regions are rejected, config is not modified.
+	for _, region := range []string{"us_east_1", "-us-east-1", "us east 1", "us-east-1-"} {
+		os.Setenv("MINIO_REGION", region)
+		if err = setRegionFromEnv(); err != errInvalidArgument {
+			t.Errorf("Expected region `%s` to fail with %s, got %v", region, errInvalidArgument, err)
+		}
+	}
+	if region := serverConfig.GetRegion(); region != "ap-southeast-1" {
+		t.Fatalf("Expected region `ap-southeast-1`, got `%s`", region)
+	}
+}
@@ -19,7 +19,9 @@ package cmd
 import (
 	"bufio"
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
@@ -97,6 +99,15 @@ func (c *ConnMux) Read(b []byte) (int, error) {
 	return c.bufrw.Read(b)
 }
 
+// ReadFrom - implements io.ReaderFrom, lets the underlying connection
+// write the data so that sendfile(2) is used where supported.
+func (c *ConnMux) ReadFrom(r io.Reader) (int64, error) {
+	if rf, ok := c.Conn.(io.ReaderFrom); ok {
+		return rf.ReadFrom(r)
+	}
+	return io.Copy(c.Conn, r)
+}
+
 // Close the connection.
 func (c *ConnMux) Close() (err error) {
 	if err = c.bufrw.Flush(); err != nil {
@@ -241,6 +252,17 @@ type ServerMux struct {
 	mu              sync.Mutex // guards closed, conns, and listener
 	closed          bool
 	conns           map[net.Conn]http.ConnState // except terminal states
+
+	// Wildcard certificate served to sub domains of domain.
+	domain           string
+	wildcardCertFile string
+	wildcardKeyFile  string
+
+	// CAs verifying client certificates, nil if not required.
+	clientCAs *x509.CertPool
+
+	// Listeners are bound with SO_REUSEPORT.
+	reusePort bool
 }
 
 // NewServerMux constructor to create a ServerMux
@@ -253,6 +275,9 @@ func NewServerMux(addr string, handler http.Handler) *ServerMux {
 			// if they are not idle.
 			Handler:        handler,
 			MaxHeaderBytes: 1 << 20,
+			// HTTP/2 is only served when enabled by EnableHTTP2(),
+			// a non-nil empty TLSNextProto disables it.
+			TLSNextProto: map[string]func(*http.Server, *tls.Conn, http.Handler){},
 		},
 		WaitGroup: &sync.WaitGroup{},
 		// Wait for 5 seconds for new incoming connnections, otherwise
@@ -267,8 +292,69 @@ func NewServerMux(addr string, handler http.Handler) *ServerMux {
 	return m
 }
 
-// Initialize listeners on all ports.
-func initListeners(serverAddr string, tls *tls.Config) ([]*ListenerMux, error) {
+// EnableHTTP2 - enables HTTP/2 negotiation over TLS, clients only
+// use HTTP/1.1 otherwise.
+func (m *ServerMux) EnableHTTP2() {
+	// A nil TLSNextProto lets net/http configure HTTP/2 support.
+	m.Server.TLSNextProto = nil
+}
+
+// SetWildcardCert - serves the certificate in certFile and keyFile to
+// clients requesting any sub domain of domain through SNI, i.e for
+// virtual host style requests.
+func (m *ServerMux) SetWildcardCert(domain, certFile, keyFile string) {
+	m.domain = domain
+	m.wildcardCertFile = certFile
+	m.wildcardKeyFile = keyFile
+}
+
+// SetClientCAs - requires TLS clients to present a certificate signed
+// by one of the CAs in pool, i.e mutual TLS.
+func (m *ServerMux) SetClientCAs(pool *x509.CertPool) {
+	m.clientCAs = pool
+}
+
+// Returned when listening with SO_REUSEPORT on platforms without it.
+var errReusePortUnsupported = errors.New("SO_REUSEPORT is not supported on this platform")
+
+// EnableReusePort - binds the listeners with SO_REUSEPORT, so that a
+// new server process can listen on the same port while this one drains
+// its connections.
+func (m *ServerMux) EnableReusePort() {
+	m.reusePort = true
+}
+
+// isWildcardMatch - returns true if serverName is covered by a
+// wildcard certificate for "*.domain".
+func isWildcardMatch(serverName, domain string) bool {
+	if domain == "" {
+		return false
+	}
+	serverName = strings.ToLower(strings.TrimSuffix(serverName, "."))
+	label := strings.TrimSuffix(serverName, "."+domain)
+	// Wildcards only cover a single label.
+	return label != serverName && label != "" && !strings.Contains(label, ".")
+}
+
+// newGetCertificateFunc - returns a tls.Config.GetCertificate callback
+// selecting wildcardCert for sub domains of domain and defaultCert
+// otherwise, either of them can be nil.
+func newGetCertificateFunc(defaultCert, wildcardCert *tls.Certificate, domain string) func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		if wildcardCert != nil && (defaultCert == nil || isWildcardMatch(hello.ServerName, domain)) {
+			return wildcardCert, nil
+		}
+		return defaultCert, nil
+	}
+}
+
+// isHTTP2Enabled - returns true if HTTP/2 is enabled by EnableHTTP2().
+func (m *ServerMux) isHTTP2Enabled() bool {
+	return m.Server.TLSNextProto == nil || len(m.Server.TLSNextProto) > 0
+}
+
+// Initialize listeners on all ports, bound through listen.
+func initListeners(serverAddr string, tls *tls.Config, listen func(network, address string) (net.Listener, error)) ([]*ListenerMux, error) {
 	host, port, err := net.SplitHostPort(serverAddr)
 	if err != nil {
 		return nil, err
@@ -276,7 +362,7 @@ func initListeners(serverAddr string, tls *tls.Config) ([]*ListenerMux, error) {
 	var listeners []*ListenerMux
 	if host == "" {
 		var listener net.Listener
-		listener, err = net.Listen("tcp", serverAddr)
+		listener, err = listen("tcp", serverAddr)
 		if err != nil {
 			return nil, err
 		}
@@ -297,7 +383,7 @@ func initListeners(serverAddr string, tls *tls.Config) ([]*ListenerMux, error) {
 	}
 	for _, addr := range addrs {
 		var listener net.Listener
-		listener, err = net.Listen("tcp", net.JoinHostPort(addr, port))
+		listener, err = listen("tcp", net.JoinHostPort(addr, port))
 		if err != nil {
 			return nil, err
 		}
@@ -307,28 +393,54 @@ func initListeners(serverAddr string, tls *tls.Config) ([]*ListenerMux, error) {
 }
 
 // ListenAndServe - serve HTTP requests with protocol multiplexing support
-// TLS is actived when certFile and keyFile parameters are not empty.
+// TLS is actived when certFile and keyFile parameters are not empty or
+// a wildcard certificate is set.
 func (m *ServerMux) ListenAndServe(certFile, keyFile string) (err error) {
 
-	tlsEnabled := certFile != "" && keyFile != ""
+	hasCert := certFile != "" && keyFile != ""
+	hasWildcardCert := m.wildcardCertFile != "" && m.wildcardKeyFile != ""
+	tlsEnabled := hasCert || hasWildcardCert
 
 	config := &tls.Config{} // Always instantiate.
 
 	if tlsEnabled {
 		// Configure TLS in the server
 		if config.NextProtos == nil {
-			config.NextProtos = []string{"http/1.1", "h2"}
+			// Only HTTP/1.1 by default, HTTP/2 is preferred when enabled.
+			config.NextProtos = []string{"http/1.1"}
+			if m.isHTTP2Enabled() {
+				config.NextProtos = []string{"h2", "http/1.1"}
+			}
 		}
-		config.Certificates = make([]tls.Certificate, 1)
-		config.Certificates[0], err = tls.LoadX509KeyPair(certFile, keyFile)
-		if err != nil {
-			return err
+		var defaultCert, wildcardCert *tls.Certificate
+		if hasCert {
+			var cert tls.Certificate
+			if cert, err = tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+				return err
+			}
+			defaultCert = &cert
+		}
+		if hasWildcardCert {
+			var cert tls.Certificate
+			if cert, err = tls.LoadX509KeyPair(m.wildcardCertFile, m.wildcardKeyFile); err != nil {
+				return err
+			}
+			wildcardCert = &cert
+		}
+		config.GetCertificate = newGetCertificateFunc(defaultCert, wildcardCert, m.domain)
+		if m.clientCAs != nil {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+			config.ClientCAs = m.clientCAs
 		}
 	}
 
 	go m.handleServiceSignals()
 
-	listeners, err := initListeners(m.Server.Addr, config)
+	listen := net.Listen
+	if m.reusePort {
+		listen = listenReusePort
+	}
+	listeners, err := initListeners(m.Server.Addr, config, listen)
 	if err != nil {
 		return err
 	}
@@ -362,7 +474,11 @@ func (m *ServerMux) ListenAndServe(certFile, keyFile string) (err error) {
 		wg.Add(1)
 		go func(listener *ListenerMux) {
 			defer wg.Done()
-			serr := http.Serve(listener, httpHandler)
+			srv := &http.Server{
+				Handler:      httpHandler,
+				TLSNextProto: m.Server.TLSNextProto,
+			}
+			serr := srv.Serve(listener)
 			// Do not print the error if the listener is closed.
 			if !listener.IsClosed() {
 				errorIf(serr, "Unable to serve incoming requests.")
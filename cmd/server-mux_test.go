@@ -135,7 +135,7 @@ func TestInitListeners(t *testing.T) {
 		},
 	}
 	for i, testCase := range testCases {
-		listeners, err := initListeners(testCase.serverAddr, &tls.Config{})
+		listeners, err := initListeners(testCase.serverAddr, &tls.Config{}, net.Listen)
 		if testCase.shouldPass {
 			if err != nil {
 				t.Fatalf("Test %d: Unable to initialize listeners %s", i+1, err)
@@ -152,7 +152,7 @@ func TestInitListeners(t *testing.T) {
 	}
 	// Windows doesn't have 'localhost' hostname.
 	if runtime.GOOS != "windows" {
-		listeners, err := initListeners("localhost:"+getFreePort(), &tls.Config{})
+		listeners, err := initListeners("localhost:"+getFreePort(), &tls.Config{}, net.Listen)
 		if err != nil {
 			t.Fatalf("Test 3: Unable to initialize listeners %s", err)
 		}
@@ -164,6 +164,55 @@ func TestInitListeners(t *testing.T) {
 	}
 }
 
+// Tests two servers can listen on the same port with SO_REUSEPORT.
+func TestInitListenersReusePort(t *testing.T) {
+	serverAddr := "127.0.0.1:" + getFreePort()
+	if listener, err := listenReusePort("tcp", serverAddr); err == errReusePortUnsupported {
+		t.Skip(err)
+	} else if err != nil {
+		t.Fatal(err)
+	} else {
+		listener.Close()
+	}
+	var listeners []*ListenerMux
+	defer func() {
+		for _, listener := range listeners {
+			listener.Close()
+		}
+	}()
+	// The old and the new server.
+	for i := 0; i < 2; i++ {
+		lms, err := initListeners(serverAddr, &tls.Config{}, listenReusePort)
+		if err != nil {
+			t.Fatalf("Server %d: Unable to initialize listeners %s", i+1, err)
+		}
+		listeners = append(listeners, lms...)
+	}
+	if listeners[0].Addr().String() != serverAddr || listeners[1].Addr().String() != serverAddr {
+		t.Fatalf("Expected both listeners on %s, got %s and %s", serverAddr, listeners[0].Addr(), listeners[1].Addr())
+	}
+	// Servers listening without SO_REUSEPORT cannot share the port.
+	if listener, err := net.Listen("tcp", serverAddr); err == nil {
+		listener.Close()
+		t.Fatal("Expected listening without SO_REUSEPORT to fail")
+	}
+
+	// Connections are accepted by the listeners, closing the old one
+	// keeps the port open.
+	listeners[0].Close()
+	go func() {
+		conn, err := listeners[1].Accept()
+		if err == nil {
+			conn.Close()
+		}
+	}()
+	conn, err := net.Dial("tcp", serverAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+}
+
 func TestClose(t *testing.T) {
 	// Create ServerMux
 	m := NewServerMux("", nil)
@@ -415,6 +464,94 @@ func TestListenAndServeTLS(t *testing.T) {
 	}
 }
 
+// Tests that clients negotiating HTTP/2 fall back to HTTP/1.1 unless
+// HTTP/2 is enabled.
+func TestListenAndServeTLSEnableHTTP2(t *testing.T) {
+	// Create a cert
+	err := createCertsPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile := mustGetCertFile()
+	keyFile := mustGetKeyFile()
+	defer os.RemoveAll(certFile)
+	defer os.RemoveAll(keyFile)
+
+	for i, enableHTTP2 := range []bool{false, true} {
+		addr := net.JoinHostPort("127.0.0.1", getFreePort())
+		if err = generateTestCert(addr); err != nil {
+			t.Fatal(err)
+		}
+
+		// Initialize done channel specifically for each tests.
+		globalServiceDoneCh = make(chan struct{}, 1)
+
+		m := NewServerMux(addr, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "hello")
+		}))
+		if enableHTTP2 {
+			m.EnableHTTP2()
+		}
+		go m.ListenAndServe(certFile, keyFile)
+
+		// Keep trying the server until it's accepting connections.
+		var conn *tls.Conn
+		config := &tls.Config{
+			InsecureSkipVerify: true,
+			NextProtos:         []string{"h2", "http/1.1"},
+		}
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			conn, err = tls.Dial("tcp", addr, config)
+			if err == nil {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Test %d: Unable to connect to server: %s", i+1, err)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		expectedProto := "http/1.1"
+		if enableHTTP2 {
+			expectedProto = "h2"
+		}
+		if proto := conn.ConnectionState().NegotiatedProtocol; proto != expectedProto {
+			t.Errorf("Test %d: Expected protocol %q, got %q", i+1, expectedProto, proto)
+		}
+		conn.Close()
+
+		// Request should be served over the negotiated protocol.
+		client := http.Client{
+			Timeout: 5 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+				ForceAttemptHTTP2: true,
+			},
+		}
+		res, gerr := client.Get("https://" + addr)
+		if gerr != nil {
+			t.Fatalf("Test %d: %s", i+1, gerr)
+		}
+		body, rerr := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if rerr != nil {
+			t.Fatalf("Test %d: %s", i+1, rerr)
+		}
+		expectedProtoMajor := 1
+		if enableHTTP2 {
+			expectedProtoMajor = 2
+		}
+		if res.ProtoMajor != expectedProtoMajor || res.StatusCode != http.StatusOK {
+			t.Errorf("Test %d: Expected HTTP/%d 200 OK, got %s %s", i+1, expectedProtoMajor, res.Proto, res.Status)
+		}
+		if string(body) != "hello" {
+			t.Errorf("Test %d: Expected body \"hello\", got \"%s\"", i+1, string(body))
+		}
+		m.Close()
+	}
+}
+
 // generateTestCert creates a cert and a key used for testing only
 func generateTestCert(host string) error {
 	certPath := mustGetCertFile()
@@ -470,3 +607,98 @@ func generateTestCert(host string) error {
 	keyOut.Close()
 	return nil
 }
+
+// Tests wildcard certificate matching of SNI server names.
+func TestIsWildcardMatch(t *testing.T) {
+	testCases := []struct {
+		serverName string
+		domain     string
+		match      bool
+	}{
+		{"bucket.s3.example.com", "s3.example.com", true},
+		{"Bucket.S3.Example.com.", "s3.example.com", true},
+		{"s3.example.com", "s3.example.com", false},
+		// Wildcards cover a single label only.
+		{"my.bucket.s3.example.com", "s3.example.com", false},
+		{"bucket.s3.example.org", "s3.example.com", false},
+		{"bucket-s3.example.com", "s3.example.com", false},
+		{"bucket.s3.example.com", "", false},
+	}
+	for i, testCase := range testCases {
+		if match := isWildcardMatch(testCase.serverName, testCase.domain); match != testCase.match {
+			t.Errorf("Test %d: Expected %t for %s, got %t", i+1, testCase.match, testCase.serverName, match)
+		}
+	}
+}
+
+// newTestCertificate - self signed certificate for dnsNames.
+func newTestCertificate(dnsNames ...string) (tls.Certificate, error) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{"Minio Test Cert"}, CommonName: dnsNames[0]},
+		DNSNames:              dnsNames,
+		NotBefore:             time.Now().UTC(),
+		NotAfter:              time.Now().UTC().Add(time.Minute * 1),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{derBytes}, PrivateKey: priv}, nil
+}
+
+// Tests that the wildcard certificate is served to virtual host style
+// server names and the default certificate to all the others.
+func TestGetCertificateWildcard(t *testing.T) {
+	defaultCert, err := newTestCertificate("s3.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wildcardCert, err := newTestCertificate("*.s3.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		defaultCert  *tls.Certificate
+		wildcardCert *tls.Certificate
+		serverName   string
+		expectedName string
+	}{
+		{&defaultCert, &wildcardCert, "bucket.s3.example.com", "*.s3.example.com"},
+		{&defaultCert, &wildcardCert, "s3.example.com", "s3.example.com"},
+		{&defaultCert, &wildcardCert, "localhost", "s3.example.com"},
+		// Only the wildcard certificate is configured.
+		{nil, &wildcardCert, "localhost", "*.s3.example.com"},
+		// Only the default certificate is configured.
+		{&defaultCert, nil, "bucket.s3.example.com", "s3.example.com"},
+	}
+	for i, testCase := range testCases {
+		ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		ts.TLS = &tls.Config{
+			GetCertificate: newGetCertificateFunc(testCase.defaultCert, testCase.wildcardCert, "s3.example.com"),
+		}
+		ts.StartTLS()
+		conn, err := tls.Dial("tcp", ts.Listener.Addr().String(), &tls.Config{
+			ServerName:         testCase.serverName,
+			InsecureSkipVerify: true,
+		})
+		if err != nil {
+			ts.Close()
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		peerCerts := conn.ConnectionState().PeerCertificates
+		conn.Close()
+		ts.Close()
+		if len(peerCerts) == 0 || peerCerts[0].Subject.CommonName != testCase.expectedName {
+			t.Errorf("Test %d: Expected certificate for %s to be served to %s", i+1, testCase.expectedName, testCase.serverName)
+		}
+	}
+}
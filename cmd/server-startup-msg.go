@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"runtime"
 	"strings"
@@ -42,12 +43,46 @@ func getFormatStr(strLen int, padding int) string {
 	return "%" + formatStr
 }
 
+// serverStartupMsg - startup message printed in JSON log format, for
+// log aggregators to parse. The secret key is always redacted.
+type serverStartupMsg struct {
+	Endpoints []string `json:"endpoints"`
+	credential
+	Region  string `json:"region"`
+	TLS     bool   `json:"tls"`
+	Version string `json:"version"`
+}
+
+// getServerStartupMsgJSON - startup message as a single JSON object.
+func getServerStartupMsgJSON(endPoints []string) ([]byte, error) {
+	return json.Marshal(serverStartupMsg{
+		Endpoints:  endPoints,
+		credential: serverConfig.GetCredential().redacted(),
+		Region:     serverConfig.GetRegion(),
+		TLS:        isServerTLS(),
+		Version:    Version,
+	})
+}
+
 // Prints the formatted startup message.
 func printStartupMessage(endPoints []string) {
 	// If quiet flag is set do not print startup message.
 	if globalQuiet {
 		return
 	}
+	printServerMsg(endPoints, globalJSONLog)
+}
+
+// Prints the startup message, as a single JSON object with jsonOutput
+// and human readable otherwise.
+func printServerMsg(endPoints []string, jsonOutput bool) {
+	if jsonOutput {
+		msg, err := getServerStartupMsgJSON(endPoints)
+		fatalIf(err, "Unable to marshal startup message.")
+		console.Println(string(msg))
+		return
+	}
+
 	printServerCommonMsg(endPoints)
 	printCLIAccessMsg(endPoints[0])
 	printObjectAPIMsg()
@@ -57,7 +92,7 @@ func printStartupMessage(endPoints []string) {
 		printStorageInfo(objAPI.StorageInfo())
 	}
 
-	if isSSL() {
+	if isServerTLS() {
 		certs, err := readCertificateChain()
 		fatalIf(err, "Unable to read certificate chain.")
 		printCertificateMsg(certs)
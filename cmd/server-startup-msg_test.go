@@ -19,7 +19,10 @@ package cmd
 import (
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -94,3 +97,89 @@ func TestCertificateNotExpired(t *testing.T) {
 		t.Fatalf("Expected empty message was: %s", msg)
 	}
 }
+
+// Tests the JSON startup message has all the fields with the secret key redacted.
+func TestServerStartupMsgJSON(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	endPoints := []string{"http://127.0.0.1:9000", "http://192.168.1.10:9000"}
+	msgBytes, err := getServerStartupMsgJSON(endPoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg map[string]interface{}
+	if err = json.Unmarshal(msgBytes, &msg); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"endpoints", "accessKey", "secretKey", "region", "tls", "version"} {
+		if _, ok := msg[field]; !ok {
+			t.Errorf("Field %s missing in startup message %s", field, msgBytes)
+		}
+	}
+	cred := serverConfig.GetCredential()
+	if msg["accessKey"] != cred.AccessKeyID {
+		t.Errorf("Expected access key %s, got %v", cred.AccessKeyID, msg["accessKey"])
+	}
+	if msg["secretKey"] != redactedSecretKey || strings.Contains(string(msgBytes), cred.SecretAccessKey) {
+		t.Errorf("Expected secret key to be redacted, got %s", msgBytes)
+	}
+	if msg["region"] != "us-east-1" || msg["tls"] != false || msg["version"] != Version {
+		t.Errorf("Unexpected startup message %s", msgBytes)
+	}
+	if endpoints, ok := msg["endpoints"].([]interface{}); !ok || len(endpoints) != 2 || endpoints[1] != endPoints[1] {
+		t.Errorf("Unexpected endpoints in startup message %s", msgBytes)
+	}
+}
+
+// Tests the startup message reports TLS, and the certificate expiry of
+// a server serving only a wildcard certificate.
+func TestServerStartupMsgWildcardCert(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	if err = createCertsPath(); err != nil {
+		t.Fatal(err)
+	}
+	if err = generateTestCert("127.0.0.1:9000"); err != nil {
+		t.Fatal(err)
+	}
+	// Serve the generated certificate as the wildcard one only.
+	wildcardCert, wildcardKey := filepath.Join(root, "wildcard.crt"), filepath.Join(root, "wildcard.key")
+	if err = os.Rename(mustGetCertFile(), wildcardCert); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Rename(mustGetKeyFile(), wildcardKey); err != nil {
+		t.Fatal(err)
+	}
+	globalWildcardCertFile, globalWildcardKeyFile = wildcardCert, wildcardKey
+	defer func() { globalWildcardCertFile, globalWildcardKeyFile = "", "" }()
+
+	if isSSL() || !isServerTLS() {
+		t.Fatalf("Expected TLS to be served with the wildcard certificate only, isSSL %v, isServerTLS %v", isSSL(), isServerTLS())
+	}
+	msgBytes, err := getServerStartupMsgJSON([]string{"https://127.0.0.1:9000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg map[string]interface{}
+	if err = json.Unmarshal(msgBytes, &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg["tls"] != true {
+		t.Errorf("Expected TLS in startup message %s", msgBytes)
+	}
+	certs, err := readCertificateChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) != 1 {
+		t.Fatalf("Expected the wildcard certificate, got %d certificates", len(certs))
+	}
+}
@@ -1490,7 +1490,7 @@ func (s *TestSuiteCommon) TestListObjectsHandler(c *C) {
 	c.Assert(err, IsNil)
 
 	c.Assert(strings.Contains(string(getContent), "<Key>bar</Key>"), Equals, true)
-	c.Assert(strings.Contains(string(getContent), "<Owner><ID>minio</ID><DisplayName>minio</DisplayName></Owner>"), Equals, true)
+	c.Assert(strings.Contains(string(getContent), "<Owner><ID>"+getCanonicalOwnerID()+"</ID><DisplayName>minio</DisplayName></Owner>"), Equals, true)
 
 }
 
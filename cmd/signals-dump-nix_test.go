@@ -0,0 +1,57 @@
+// +build !windows,!plan9
+
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// Tests dumping in-flight requests on SIGUSR2.
+func TestInFlightDumpSignal(t *testing.T) {
+	release := startSlowRequests(t, globalInFlightRequests, 5)
+	defer release()
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	startInFlightDumper(pw, doneCh)
+
+	summaryCh := make(chan inFlightDump, 1)
+	go func() {
+		var summary inFlightDump
+		if err := json.NewDecoder(pr).Decode(&summary); err == nil {
+			summaryCh <- summary
+		}
+	}()
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case summary := <-summaryCh:
+		validateInFlightDump(t, summary, 5)
+	case <-time.After(5 * time.Second):
+		t.Fatal("No in-flight requests dump received")
+	}
+}
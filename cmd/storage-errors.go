@@ -30,6 +30,9 @@ var errUnformattedDisk = errors.New("unformatted disk found")
 // errDiskFull - cannot create volume or files when disk is full.
 var errDiskFull = errors.New("disk path full")
 
+// errDiskInodesFull - cannot create files when disk has no free inodes left.
+var errDiskInodesFull = errors.New("disk path has no free inodes")
+
 // errDiskNotFount - cannot find the underlying configured disk anymore.
 var errDiskNotFound = errors.New("disk not found")
 
@@ -65,3 +68,6 @@ var errVolumeAccessDenied = errors.New("volume access denied")
 
 // errVolumeAccessDenied - cannot access file, insufficient permissions.
 var errFileAccessDenied = errors.New("file access denied")
+
+// errNotLocalDisk - disk does not serve its files from the local filesystem.
+var errNotLocalDisk = errors.New("disk is not local")
@@ -16,7 +16,11 @@
 
 package cmd
 
-import "github.com/minio/minio/pkg/disk"
+import (
+	"os"
+
+	"github.com/minio/minio/pkg/disk"
+)
 
 // StorageAPI interface.
 type StorageAPI interface {
@@ -46,3 +50,21 @@ type StorageAPI interface {
 	// Read all.
 	ReadAll(volume string, path string) (buf []byte, err error)
 }
+
+// localFileStorage - optional interface of disks serving their files
+// from the local filesystem, wrappers of other disks implement it by
+// forwarding to them and return errNotLocalDisk if they do not.
+type localFileStorage interface {
+	// Opens a regular file under the volume for reading.
+	openFile(volume, path string) (*os.File, error)
+}
+
+// openLocalFile - opens the file on the local filesystem serving path
+// on the volume, returns errNotLocalDisk for remote disks.
+func openLocalFile(disk StorageAPI, volume, path string) (*os.File, error) {
+	l, ok := disk.(localFileStorage)
+	if !ok {
+		return nil, errNotLocalDisk
+	}
+	return l.openFile(volume, path)
+}
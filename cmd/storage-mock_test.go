@@ -0,0 +1,512 @@
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	slashpath "path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/disk"
+)
+
+// memDisk is an in-memory StorageAPI, it returns the same errors as
+// posix for the same sequence of calls. The purpose is to exercise the
+// XL layer on disks without touching the filesystem.
+type memDisk struct {
+	mu   sync.Mutex
+	vols map[string]*memVol
+}
+
+// memVol - an in-memory volume, directories are implied by file paths.
+type memVol struct {
+	created time.Time
+	files   map[string]*memFile
+}
+
+// memFile - an in-memory file.
+type memFile struct {
+	data    []byte
+	modTime time.Time
+}
+
+func newMemDisk() *memDisk {
+	return &memDisk{vols: make(map[string]*memVol)}
+}
+
+// cleanMemPath - clean path relative to the volume root.
+func cleanMemPath(path string) string {
+	return strings.TrimPrefix(slashpath.Clean(slashSeparator+path), slashSeparator)
+}
+
+// isDir - returns true if there are files under path.
+func (v *memVol) isDir(path string) bool {
+	if path == "" {
+		return true
+	}
+	for name := range v.files {
+		if strings.HasPrefix(name, path+slashSeparator) {
+			return true
+		}
+	}
+	return false
+}
+
+// parentIsFile - returns true if one of the parents of path is a file.
+func (v *memVol) parentIsFile(path string) bool {
+	for dir := slashpath.Dir(path); dir != "." && dir != slashSeparator; dir = slashpath.Dir(dir) {
+		if _, ok := v.files[dir]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *memDisk) getVol(volume string) (*memVol, error) {
+	if !isValidVolname(volume) {
+		return nil, errInvalidArgument
+	}
+	vol, ok := d.vols[volume]
+	if !ok {
+		return nil, errVolumeNotFound
+	}
+	return vol, nil
+}
+
+func (d *memDisk) String() string {
+	return "mem"
+}
+
+func (d *memDisk) Init() error {
+	return nil
+}
+
+func (d *memDisk) Close() error {
+	return nil
+}
+
+func (d *memDisk) DiskInfo() (info disk.Info, err error) {
+	return disk.Info{}, nil
+}
+
+func (d *memDisk) MakeVol(volume string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !isValidVolname(volume) {
+		return errInvalidArgument
+	}
+	if _, ok := d.vols[volume]; ok {
+		return errVolumeExists
+	}
+	d.vols[volume] = &memVol{created: time.Now().UTC(), files: make(map[string]*memFile)}
+	return nil
+}
+
+func (d *memDisk) ListVols() (vols []VolInfo, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for name, vol := range d.vols {
+		vols = append(vols, VolInfo{Name: name, Created: vol.created})
+	}
+	return vols, nil
+}
+
+func (d *memDisk) StatVol(volume string) (VolInfo, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	vol, err := d.getVol(volume)
+	if err != nil {
+		return VolInfo{}, err
+	}
+	return VolInfo{Name: volume, Created: vol.created}, nil
+}
+
+func (d *memDisk) DeleteVol(volume string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	vol, err := d.getVol(volume)
+	if err != nil {
+		return err
+	}
+	if len(vol.files) != 0 {
+		return errVolumeNotEmpty
+	}
+	delete(d.vols, volume)
+	return nil
+}
+
+func (d *memDisk) ListDir(volume, dirPath string) ([]string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	vol, err := d.getVol(volume)
+	if err != nil {
+		return nil, err
+	}
+	prefix := cleanMemPath(dirPath)
+	if !vol.isDir(prefix) {
+		return nil, errFileNotFound
+	}
+	if prefix != "" {
+		prefix += slashSeparator
+	}
+	var entries []string
+	seen := make(map[string]bool)
+	for name := range vol.files {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		entry := strings.TrimPrefix(name, prefix)
+		if i := strings.Index(entry, slashSeparator); i >= 0 {
+			entry = entry[:i+1]
+		}
+		if !seen[entry] {
+			seen[entry] = true
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (d *memDisk) ReadAll(volume, path string) ([]byte, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	vol, err := d.getVol(volume)
+	if err != nil {
+		return nil, err
+	}
+	file, ok := vol.files[cleanMemPath(path)]
+	if !ok {
+		return nil, errFileNotFound
+	}
+	return append([]byte(nil), file.data...), nil
+}
+
+func (d *memDisk) ReadFile(volume, path string, offset int64, buf []byte) (int64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	vol, err := d.getVol(volume)
+	if err != nil {
+		return 0, err
+	}
+	path = cleanMemPath(path)
+	file, ok := vol.files[path]
+	if !ok {
+		if vol.isDir(path) {
+			return 0, errIsNotRegular
+		}
+		if vol.parentIsFile(path) {
+			return 0, errFileAccessDenied
+		}
+		return 0, errFileNotFound
+	}
+	// Same semantics as io.ReadFull after a seek to offset.
+	if offset >= int64(len(file.data)) {
+		if len(buf) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+	n := copy(buf, file.data[offset:])
+	if n < len(buf) {
+		return int64(n), io.ErrUnexpectedEOF
+	}
+	return int64(n), nil
+}
+
+// createFile - returns the file at path, creating it if not found.
+func (d *memDisk) createFile(volume, path string) (*memFile, error) {
+	vol, err := d.getVol(volume)
+	if err != nil {
+		return nil, err
+	}
+	path = cleanMemPath(path)
+	if file, ok := vol.files[path]; ok {
+		return file, nil
+	}
+	if vol.isDir(path) {
+		return nil, errIsNotRegular
+	}
+	if vol.parentIsFile(path) {
+		return nil, errFileAccessDenied
+	}
+	file := &memFile{modTime: time.Now().UTC()}
+	vol.files[path] = file
+	return file, nil
+}
+
+func (d *memDisk) PrepareFile(volume, path string, length int64) error {
+	if length <= 0 {
+		return errInvalidArgument
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, err := d.createFile(volume, path)
+	return err
+}
+
+func (d *memDisk) AppendFile(volume, path string, buf []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	file, err := d.createFile(volume, path)
+	if err != nil {
+		return err
+	}
+	file.data = append(file.data, buf...)
+	file.modTime = time.Now().UTC()
+	return nil
+}
+
+func (d *memDisk) RenameFile(srcVolume, srcPath, dstVolume, dstPath string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	srcVol, err := d.getVol(srcVolume)
+	if err != nil {
+		return err
+	}
+	dstVol, err := d.getVol(dstVolume)
+	if err != nil {
+		return err
+	}
+	srcIsDir := strings.HasSuffix(srcPath, slashSeparator)
+	dstIsDir := strings.HasSuffix(dstPath, slashSeparator)
+	// Either src and dst have to be directories or files, else return error.
+	if srcIsDir != dstIsDir {
+		return errFileAccessDenied
+	}
+	srcPath, dstPath = cleanMemPath(srcPath), cleanMemPath(dstPath)
+	if dstVol.parentIsFile(dstPath) {
+		return errFileAccessDenied
+	}
+	if !srcIsDir {
+		file, ok := srcVol.files[srcPath]
+		if !ok {
+			return errFileNotFound
+		}
+		delete(srcVol.files, srcPath)
+		dstVol.files[dstPath] = file
+		return nil
+	}
+	// If source is a directory we expect the destination to be non-existent always.
+	if _, ok := dstVol.files[dstPath]; ok || dstVol.isDir(dstPath) {
+		return errFileAccessDenied
+	}
+	if !srcVol.isDir(srcPath) {
+		return errFileNotFound
+	}
+	for name, file := range srcVol.files {
+		if strings.HasPrefix(name, srcPath+slashSeparator) {
+			delete(srcVol.files, name)
+			dstVol.files[dstPath+strings.TrimPrefix(name, srcPath)] = file
+		}
+	}
+	return nil
+}
+
+func (d *memDisk) StatFile(volume, path string) (FileInfo, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	vol, err := d.getVol(volume)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	file, ok := vol.files[cleanMemPath(path)]
+	if !ok {
+		return FileInfo{}, errFileNotFound
+	}
+	return FileInfo{
+		Volume:  volume,
+		Name:    path,
+		ModTime: file.modTime,
+		Size:    int64(len(file.data)),
+		Mode:    0644,
+	}, nil
+}
+
+func (d *memDisk) DeleteFile(volume, path string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	vol, err := d.getVol(volume)
+	if err != nil {
+		return err
+	}
+	path = cleanMemPath(path)
+	if path == "" {
+		return nil
+	}
+	if _, ok := vol.files[path]; ok {
+		delete(vol.files, path)
+		return nil
+	}
+	// Non empty directories are left intact.
+	if vol.isDir(path) {
+		return nil
+	}
+	return errFileNotFound
+}
+
+// Tests that memDisk returns the same results and errors as posix.
+func TestMemDiskMatchesPosix(t *testing.T) {
+	diskPath, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(diskPath)
+	posixDisk, err := newPosix(diskPath + "/disk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	memStorage := newMemDisk()
+
+	testCases := []struct {
+		name string
+		op   func(disk StorageAPI) (interface{}, error)
+	}{
+		{"MakeVol", func(disk StorageAPI) (interface{}, error) { return nil, disk.MakeVol("bucket") }},
+		{"MakeVol exists", func(disk StorageAPI) (interface{}, error) { return nil, disk.MakeVol("bucket") }},
+		{"MakeVol invalid", func(disk StorageAPI) (interface{}, error) { return nil, disk.MakeVol("a") }},
+		{"MakeVol other", func(disk StorageAPI) (interface{}, error) { return nil, disk.MakeVol("other") }},
+		{"ListVols", func(disk StorageAPI) (interface{}, error) {
+			vols, err := disk.ListVols()
+			var names []string
+			for _, vol := range vols {
+				names = append(names, vol.Name)
+			}
+			sort.Strings(names)
+			return names, err
+		}},
+		{"StatVol", func(disk StorageAPI) (interface{}, error) {
+			vol, err := disk.StatVol("bucket")
+			return vol.Name, err
+		}},
+		{"StatVol missing", func(disk StorageAPI) (interface{}, error) {
+			_, err := disk.StatVol("missing")
+			return nil, err
+		}},
+		{"AppendFile", func(disk StorageAPI) (interface{}, error) {
+			return nil, disk.AppendFile("bucket", "dir/object", []byte("hello, "))
+		}},
+		{"AppendFile again", func(disk StorageAPI) (interface{}, error) {
+			return nil, disk.AppendFile("bucket", "dir/object", []byte("world"))
+		}},
+		{"AppendFile missing volume", func(disk StorageAPI) (interface{}, error) {
+			return nil, disk.AppendFile("missing", "object", []byte("hello"))
+		}},
+		{"AppendFile on directory", func(disk StorageAPI) (interface{}, error) {
+			return nil, disk.AppendFile("bucket", "dir", []byte("hello"))
+		}},
+		{"AppendFile under file", func(disk StorageAPI) (interface{}, error) {
+			return nil, disk.AppendFile("bucket", "dir/object/part", []byte("hello"))
+		}},
+		{"PrepareFile", func(disk StorageAPI) (interface{}, error) {
+			return nil, disk.PrepareFile("bucket", "prepared", 0)
+		}},
+		{"ReadAll", func(disk StorageAPI) (interface{}, error) {
+			buf, err := disk.ReadAll("bucket", "dir/object")
+			return string(buf), err
+		}},
+		{"ReadAll directory", func(disk StorageAPI) (interface{}, error) {
+			_, err := disk.ReadAll("bucket", "dir")
+			return nil, err
+		}},
+		{"ReadAll missing", func(disk StorageAPI) (interface{}, error) {
+			_, err := disk.ReadAll("bucket", "missing")
+			return nil, err
+		}},
+		{"ReadFile", func(disk StorageAPI) (interface{}, error) {
+			buf := make([]byte, 5)
+			n, err := disk.ReadFile("bucket", "dir/object", 7, buf)
+			return string(buf[:n]), err
+		}},
+		{"ReadFile short", func(disk StorageAPI) (interface{}, error) {
+			buf := make([]byte, 10)
+			n, err := disk.ReadFile("bucket", "dir/object", 7, buf)
+			return string(buf[:n]), err
+		}},
+		{"ReadFile past end", func(disk StorageAPI) (interface{}, error) {
+			n, err := disk.ReadFile("bucket", "dir/object", 100, make([]byte, 5))
+			return n, err
+		}},
+		{"ReadFile directory", func(disk StorageAPI) (interface{}, error) {
+			n, err := disk.ReadFile("bucket", "dir", 0, make([]byte, 5))
+			return n, err
+		}},
+		{"ReadFile under file", func(disk StorageAPI) (interface{}, error) {
+			n, err := disk.ReadFile("bucket", "dir/object/part", 0, make([]byte, 5))
+			return n, err
+		}},
+		{"StatFile", func(disk StorageAPI) (interface{}, error) {
+			fi, err := disk.StatFile("bucket", "dir/object")
+			return []interface{}{fi.Volume, fi.Name, fi.Size}, err
+		}},
+		{"StatFile directory", func(disk StorageAPI) (interface{}, error) {
+			_, err := disk.StatFile("bucket", "dir")
+			return nil, err
+		}},
+		{"ListDir", func(disk StorageAPI) (interface{}, error) {
+			entries, err := disk.ListDir("bucket", "")
+			sort.Strings(entries)
+			return entries, err
+		}},
+		{"ListDir sub directory", func(disk StorageAPI) (interface{}, error) {
+			return disk.ListDir("bucket", "dir")
+		}},
+		{"ListDir missing", func(disk StorageAPI) (interface{}, error) {
+			return disk.ListDir("bucket", "missing")
+		}},
+		{"DeleteVol not empty", func(disk StorageAPI) (interface{}, error) { return nil, disk.DeleteVol("bucket") }},
+		{"RenameFile mismatch", func(disk StorageAPI) (interface{}, error) {
+			return nil, disk.RenameFile("bucket", "dir/", "other", "object")
+		}},
+		{"RenameFile directory", func(disk StorageAPI) (interface{}, error) {
+			return nil, disk.RenameFile("bucket", "dir/", "other", "renamed/")
+		}},
+		{"RenameFile directory exists", func(disk StorageAPI) (interface{}, error) {
+			return nil, disk.RenameFile("other", "renamed/", "bucket", "prepared/")
+		}},
+		{"RenameFile file", func(disk StorageAPI) (interface{}, error) {
+			return nil, disk.RenameFile("other", "renamed/object", "bucket", "object")
+		}},
+		{"RenameFile missing", func(disk StorageAPI) (interface{}, error) {
+			return nil, disk.RenameFile("other", "renamed/object", "bucket", "object")
+		}},
+		{"ListDir after rename", func(disk StorageAPI) (interface{}, error) {
+			entries, err := disk.ListDir("bucket", "")
+			sort.Strings(entries)
+			return entries, err
+		}},
+		{"DeleteFile", func(disk StorageAPI) (interface{}, error) { return nil, disk.DeleteFile("bucket", "object") }},
+		{"DeleteFile missing", func(disk StorageAPI) (interface{}, error) { return nil, disk.DeleteFile("bucket", "object") }},
+		{"DeleteFile prepared", func(disk StorageAPI) (interface{}, error) { return nil, disk.DeleteFile("bucket", "prepared") }},
+		{"DeleteVol", func(disk StorageAPI) (interface{}, error) { return nil, disk.DeleteVol("bucket") }},
+		{"DeleteVol missing", func(disk StorageAPI) (interface{}, error) { return nil, disk.DeleteVol("bucket") }},
+	}
+	for _, testCase := range testCases {
+		expected, expectedErr := testCase.op(posixDisk)
+		got, gotErr := testCase.op(memStorage)
+		if gotErr != expectedErr {
+			t.Fatalf("%s: Expected error %v as posix, got %v", testCase.name, expectedErr, gotErr)
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("%s: Expected %#v as posix, got %#v", testCase.name, expected, got)
+		}
+	}
+}
@@ -0,0 +1,433 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio/pkg/disk"
+)
+
+// Index of the buckets and their storage paths, saved in the config
+// directory.
+const bucketPathIndexFile = "bucket-path.json"
+
+// multiPathStorage - a StorageAPI serving each bucket from one of many
+// paths, buckets are placed on the path with the most free space when
+// they are created. Meta volumes are served from the first path, only
+// the tmp volume is present on all of them. Temporary files named after
+// their destination bucket by getTmpObjectPath are served from the path
+// of that bucket, so that committing them is a rename.
+type multiPathStorage struct {
+	disks     []StorageAPI
+	indexFile string
+
+	mu      sync.RWMutex
+	buckets map[string]string // bucket name to path.
+}
+
+// newMultiPathStorage - initializes a multi path storage on the given
+// disks, buckets found on the disks but not in the index are added to it.
+// Buckets indexed on a path which is no longer configured are re-homed
+// to the configured path holding them, it is an error if there is none.
+func newMultiPathStorage(disks []StorageAPI, indexFile string) (StorageAPI, error) {
+	if len(disks) == 0 {
+		return nil, errInvalidArgument
+	}
+	m := &multiPathStorage{
+		disks:     disks,
+		indexFile: indexFile,
+		buckets:   make(map[string]string),
+	}
+	buf, err := ioutil.ReadFile(indexFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err = json.Unmarshal(buf, &m.buckets); err != nil {
+			return nil, err
+		}
+	}
+	// Paths holding each bucket.
+	bucketPaths := make(map[string][]string)
+	configured := make(map[string]bool)
+	for _, d := range disks {
+		if d == nil {
+			return nil, errDiskNotFound
+		}
+		configured[d.String()] = true
+		vols, err := d.ListVols()
+		if err != nil {
+			return nil, err
+		}
+		for _, vol := range vols {
+			if isMinioMetaVolume(vol.Name) {
+				continue
+			}
+			bucketPaths[vol.Name] = append(bucketPaths[vol.Name], d.String())
+			if _, ok := m.buckets[vol.Name]; !ok {
+				m.buckets[vol.Name] = d.String()
+			}
+		}
+	}
+	for bucket, diskPath := range m.buckets {
+		if configured[diskPath] {
+			continue
+		}
+		if paths := bucketPaths[bucket]; len(paths) == 1 {
+			m.buckets[bucket] = paths[0]
+			continue
+		}
+		return nil, fmt.Errorf("Bucket %s is indexed on %s which is not a configured path, and is found on %d of the configured paths", bucket, diskPath, len(bucketPaths[bucket]))
+	}
+	if err = m.saveIndex(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// isMinioMetaVolume - returns true for minio's internal volumes.
+func isMinioMetaVolume(volume string) bool {
+	return volume == minioMetaBucket || strings.HasPrefix(volume, minioMetaBucket+"/")
+}
+
+// saveIndex - saves the bucket index atomically, called with the lock held.
+func (m *multiPathStorage) saveIndex() error {
+	buf, err := json.MarshalIndent(m.buckets, "", "\t")
+	if err != nil {
+		return err
+	}
+	tmpFile := m.indexFile + ".tmp"
+	if err = ioutil.WriteFile(tmpFile, buf, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, m.indexFile)
+}
+
+// getDisk - returns the disk serving the volume.
+func (m *multiPathStorage) getDisk(volume string) (StorageAPI, error) {
+	if isMinioMetaVolume(volume) {
+		return m.disks[0], nil
+	}
+	m.mu.RLock()
+	diskPath, ok := m.buckets[volume]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, errVolumeNotFound
+	}
+	for _, d := range m.disks {
+		if d.String() == diskPath {
+			return d, nil
+		}
+	}
+	return nil, errVolumeNotFound
+}
+
+// getFileDisk - returns the disk serving the file, temporary files are
+// served from the path of the bucket they are committed to.
+func (m *multiPathStorage) getFileDisk(volume, path string) (StorageAPI, error) {
+	if volume == minioMetaTmpBucket {
+		if i := strings.Index(path, tmpBucketSeparator); i > 0 {
+			if d, err := m.getDisk(path[:i]); err == nil {
+				return d, nil
+			}
+		}
+	}
+	return m.getDisk(volume)
+}
+
+// String - all the paths.
+func (m *multiPathStorage) String() string {
+	paths := make([]string, len(m.disks))
+	for i, d := range m.disks {
+		paths[i] = d.String()
+	}
+	return strings.Join(paths, ",")
+}
+
+// Init - initializes all the paths.
+func (m *multiPathStorage) Init() error {
+	for _, d := range m.disks {
+		if err := d.Init(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close - closes all the paths.
+func (m *multiPathStorage) Close() error {
+	for _, d := range m.disks {
+		if err := d.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DiskInfo - total and free space of all the paths.
+func (m *multiPathStorage) DiskInfo() (info disk.Info, err error) {
+	for _, d := range m.disks {
+		di, err := d.DiskInfo()
+		if err != nil {
+			return disk.Info{}, err
+		}
+		info.Total += di.Total
+		info.Free += di.Free
+		info.Files += di.Files
+		info.Ffree += di.Ffree
+		info.FSType = di.FSType
+	}
+	return info, nil
+}
+
+// MakeVol - meta volumes are created on all the paths, buckets on the
+// path with the most free space.
+func (m *multiPathStorage) MakeVol(volume string) error {
+	if isMinioMetaVolume(volume) {
+		err := m.disks[0].MakeVol(volume)
+		for _, d := range m.disks[1:] {
+			if derr := d.MakeVol(volume); derr != nil && derr != errVolumeExists {
+				return derr
+			}
+		}
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.buckets[volume]; ok {
+		return errVolumeExists
+	}
+	var target StorageAPI
+	var maxFree int64
+	for _, d := range m.disks {
+		di, err := d.DiskInfo()
+		if err != nil {
+			continue
+		}
+		if target == nil || di.Free > maxFree {
+			target, maxFree = d, di.Free
+		}
+	}
+	if target == nil {
+		return errDiskNotFound
+	}
+	if err := target.MakeVol(volume); err != nil {
+		return err
+	}
+	m.buckets[volume] = target.String()
+	return m.saveIndex()
+}
+
+// ListVols - lists volumes of all the paths, meta volumes are listed once.
+func (m *multiPathStorage) ListVols() ([]VolInfo, error) {
+	var vols []VolInfo
+	seen := make(map[string]bool)
+	for _, d := range m.disks {
+		dvols, err := d.ListVols()
+		if err != nil {
+			return nil, err
+		}
+		for _, vol := range dvols {
+			if seen[vol.Name] {
+				continue
+			}
+			seen[vol.Name] = true
+			vols = append(vols, vol)
+		}
+	}
+	return vols, nil
+}
+
+// StatVol - stats the volume on its path.
+func (m *multiPathStorage) StatVol(volume string) (VolInfo, error) {
+	d, err := m.getDisk(volume)
+	if err != nil {
+		return VolInfo{}, err
+	}
+	return d.StatVol(volume)
+}
+
+// DeleteVol - deletes the volume and removes buckets from the index.
+func (m *multiPathStorage) DeleteVol(volume string) error {
+	if isMinioMetaVolume(volume) {
+		err := m.disks[0].DeleteVol(volume)
+		for _, d := range m.disks[1:] {
+			d.DeleteVol(volume)
+		}
+		return err
+	}
+
+	d, err := m.getDisk(volume)
+	if err != nil {
+		return err
+	}
+	if err = d.DeleteVol(volume); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.buckets, volume)
+	return m.saveIndex()
+}
+
+// ListDir - lists the directory on the volume's path.
+func (m *multiPathStorage) ListDir(volume, dirPath string) ([]string, error) {
+	d, err := m.getDisk(volume)
+	if err != nil {
+		return nil, err
+	}
+	return d.ListDir(volume, dirPath)
+}
+
+// ReadFile - reads the file on the volume's path.
+func (m *multiPathStorage) ReadFile(volume string, path string, offset int64, buf []byte) (int64, error) {
+	d, err := m.getFileDisk(volume, path)
+	if err != nil {
+		return 0, err
+	}
+	return d.ReadFile(volume, path, offset, buf)
+}
+
+// PrepareFile - prepares the file on the volume's path.
+func (m *multiPathStorage) PrepareFile(volume string, path string, len int64) error {
+	d, err := m.getFileDisk(volume, path)
+	if err != nil {
+		return err
+	}
+	return d.PrepareFile(volume, path, len)
+}
+
+// AppendFile - appends to the file on the volume's path.
+func (m *multiPathStorage) AppendFile(volume string, path string, buf []byte) error {
+	d, err := m.getFileDisk(volume, path)
+	if err != nil {
+		return err
+	}
+	return d.AppendFile(volume, path, buf)
+}
+
+// RenameFile - renames within a path, a file renamed across paths is
+// copied into the tmp volume of the destination path first.
+func (m *multiPathStorage) RenameFile(srcVolume, srcPath, dstVolume, dstPath string) error {
+	srcDisk, err := m.getFileDisk(srcVolume, srcPath)
+	if err != nil {
+		return err
+	}
+	dstDisk, err := m.getFileDisk(dstVolume, dstPath)
+	if err != nil {
+		return err
+	}
+	if srcDisk == dstDisk {
+		return srcDisk.RenameFile(srcVolume, srcPath, dstVolume, dstPath)
+	}
+	// Directories are only renamed within the meta volumes.
+	if strings.HasSuffix(srcPath, slashSeparator) {
+		return errFileAccessDenied
+	}
+
+	tmpPath := mustGetUUID()
+	if err = copyFile(srcDisk, srcVolume, srcPath, dstDisk, minioMetaTmpBucket, tmpPath); err != nil {
+		dstDisk.DeleteFile(minioMetaTmpBucket, tmpPath)
+		return err
+	}
+	if err = dstDisk.RenameFile(minioMetaTmpBucket, tmpPath, dstVolume, dstPath); err != nil {
+		dstDisk.DeleteFile(minioMetaTmpBucket, tmpPath)
+		return err
+	}
+	return srcDisk.DeleteFile(srcVolume, srcPath)
+}
+
+// copyFile - copies a file between disks.
+func copyFile(srcDisk StorageAPI, srcVolume, srcPath string, dstDisk StorageAPI, dstVolume, dstPath string) error {
+	// Create the file even if it is empty.
+	if err := dstDisk.AppendFile(dstVolume, dstPath, nil); err != nil {
+		return err
+	}
+	buf := make([]byte, readSizeV1)
+	var offset int64
+	for {
+		n, err := srcDisk.ReadFile(srcVolume, srcPath, offset, buf)
+		if n > 0 {
+			if werr := dstDisk.AppendFile(dstVolume, dstPath, buf[:n]); werr != nil {
+				return werr
+			}
+			offset += n
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// StatFile - stats the file on the volume's path.
+func (m *multiPathStorage) StatFile(volume string, path string) (FileInfo, error) {
+	d, err := m.getFileDisk(volume, path)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	return d.StatFile(volume, path)
+}
+
+// DeleteFile - deletes the file on the volume's path.
+func (m *multiPathStorage) DeleteFile(volume string, path string) error {
+	d, err := m.getFileDisk(volume, path)
+	if err != nil {
+		return err
+	}
+	return d.DeleteFile(volume, path)
+}
+
+// ReadAll - reads the whole file on the volume's path.
+func (m *multiPathStorage) ReadAll(volume string, path string) ([]byte, error) {
+	d, err := m.getFileDisk(volume, path)
+	if err != nil {
+		return nil, err
+	}
+	return d.ReadAll(volume, path)
+}
+
+// openFile - opens the file on the volume's path if it is a local disk.
+func (m *multiPathStorage) openFile(volume, path string) (*os.File, error) {
+	d, err := m.getFileDisk(volume, path)
+	if err != nil {
+		return nil, err
+	}
+	return openLocalFile(d, volume, path)
+}
+
+// parseStoragePaths - parses the comma separated list of --paths.
+func parseStoragePaths(paths string) []string {
+	var result []string
+	for _, p := range strings.Split(paths, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			result = append(result, filepath.Clean(p))
+		}
+	}
+	return result
+}
@@ -0,0 +1,289 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/minio/pkg/disk"
+)
+
+// freeSpaceDisk - a disk reporting a fixed amount of free space.
+type freeSpaceDisk struct {
+	StorageAPI
+	free int64
+}
+
+func (d *freeSpaceDisk) DiskInfo() (disk.Info, error) {
+	info, err := d.StorageAPI.DiskInfo()
+	info.Free = d.free
+	return info, err
+}
+
+// Tests buckets are created on the path with the most free space and
+// objects are served from the path of their bucket.
+func TestMultiPathStorage(t *testing.T) {
+	fsDirs, err := getRandomDisks(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	indexFile := filepath.Join(fsDirs[2], bucketPathIndexFile)
+
+	var disks []StorageAPI
+	var freeDisks []*freeSpaceDisk
+	for _, dir := range fsDirs[:2] {
+		posixDisk, err := newPosix(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		freeDisk := &freeSpaceDisk{StorageAPI: posixDisk}
+		disks = append(disks, freeDisk)
+		freeDisks = append(freeDisks, freeDisk)
+	}
+	storage, err := newMultiPathStorage(disks, indexFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoints, err := parseStorageEndpoints(fsDirs[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	formattedDisks, err := waitForFormatDisks(true, endpoints, []StorageAPI{storage})
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := newFSObjects(formattedDisks[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		bucket   string
+		free     [2]int64
+		expected int
+	}{
+		{"bucket1", [2]int64{100, 200}, 1},
+		{"bucket2", [2]int64{300, 200}, 0},
+		{"bucket3", [2]int64{50, 60}, 1},
+		{"bucket4", [2]int64{10, 5}, 0},
+	}
+	data := []byte("hello, world")
+	for i, testCase := range testCases {
+		freeDisks[0].free, freeDisks[1].free = testCase.free[0], testCase.free[1]
+		if err = obj.MakeBucket(testCase.bucket); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if _, err = disks[testCase.expected].StatVol(testCase.bucket); err != nil {
+			t.Fatalf("Test %d: expected bucket on path %d, %v", i+1, testCase.expected, err)
+		}
+		if _, err = disks[1-testCase.expected].StatVol(testCase.bucket); err != errVolumeNotFound {
+			t.Fatalf("Test %d: expected bucket only on path %d, %v", i+1, testCase.expected, err)
+		}
+		if _, err = obj.PutObject(testCase.bucket, "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if _, err = disks[testCase.expected].StatFile(testCase.bucket, "object"); err != nil {
+			t.Fatalf("Test %d: expected object on path %d, %v", i+1, testCase.expected, err)
+		}
+		// Temporary files are created on the path of their bucket.
+		tmpPath := getTmpObjectPath(testCase.bucket, mustGetUUID())
+		if err = storage.AppendFile(minioMetaTmpBucket, tmpPath, data); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if _, err = disks[testCase.expected].StatFile(minioMetaTmpBucket, tmpPath); err != nil {
+			t.Fatalf("Test %d: expected temporary file on path %d, %v", i+1, testCase.expected, err)
+		}
+		if err = storage.DeleteFile(minioMetaTmpBucket, tmpPath); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		var buf bytes.Buffer
+		if err = obj.GetObject(testCase.bucket, "object", 0, int64(len(data)), &buf); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if !bytes.Equal(buf.Bytes(), data) {
+			t.Fatalf("Test %d: expected %q, got %q", i+1, data, buf.Bytes())
+		}
+	}
+
+	buckets, err := obj.ListBuckets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != len(testCases) {
+		t.Fatalf("Expected %d buckets, got %d", len(testCases), len(buckets))
+	}
+
+	// The index maps every bucket to its path.
+	buf, err := ioutil.ReadFile(indexFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := make(map[string]string)
+	if err = json.Unmarshal(buf, &index); err != nil {
+		t.Fatal(err)
+	}
+	for i, testCase := range testCases {
+		if index[testCase.bucket] != disks[testCase.expected].String() {
+			t.Errorf("Test %d: expected %s in the index, got %s", i+1, disks[testCase.expected], index[testCase.bucket])
+		}
+	}
+
+	// Deleted buckets are removed from the index.
+	if err = obj.DeleteObject("bucket1", "object"); err != nil {
+		t.Fatal(err)
+	}
+	if err = obj.DeleteBucket("bucket1"); err != nil {
+		t.Fatal(err)
+	}
+	storage, err = newMultiPathStorage(disks, indexFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = storage.StatVol("bucket1"); err != errVolumeNotFound {
+		t.Fatalf("Expected deleted bucket to be missing, got %v", err)
+	}
+	if _, err = storage.StatVol("bucket3"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Tests the files of local disks are opened through the wrappers of
+// the disk, and not of other disks.
+func TestOpenLocalFile(t *testing.T) {
+	fsDirs, err := getRandomDisks(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	posixDisk, err := newPosix(fsDirs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = posixDisk.MakeVol("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if err = posixDisk.AppendFile("bucket", "object", []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	multiPath, err := newMultiPathStorage([]StorageAPI{&retryStorage{posixDisk}}, filepath.Join(fsDirs[1], bucketPathIndexFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		disk        StorageAPI
+		expectedErr error
+	}{
+		{posixDisk, nil},
+		{&retryStorage{posixDisk}, nil},
+		{multiPath, nil},
+		// Disks not known to be local.
+		{&freeSpaceDisk{StorageAPI: posixDisk}, errNotLocalDisk},
+		{&retryStorage{&freeSpaceDisk{StorageAPI: posixDisk}}, errNotLocalDisk},
+	}
+	for i, testCase := range testCases {
+		file, err := openLocalFile(testCase.disk, "bucket", "object")
+		if err != testCase.expectedErr {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if err != nil {
+			continue
+		}
+		buf, err := ioutil.ReadAll(file)
+		file.Close()
+		if err != nil || string(buf) != "hello" {
+			t.Fatalf("Test %d: expected %q, got %q, %v", i+1, "hello", buf, err)
+		}
+	}
+	if _, err = openLocalFile(multiPath, "bucket", "missing"); err != errFileNotFound {
+		t.Fatalf("Expected %v, got %v", errFileNotFound, err)
+	}
+}
+
+// Tests buckets indexed on paths which are no longer configured are
+// re-homed to the path holding them, or fail the initialization.
+func TestMultiPathStorageIndexValidation(t *testing.T) {
+	fsDirs, err := getRandomDisks(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	indexFile := filepath.Join(fsDirs[2], bucketPathIndexFile)
+
+	var disks []StorageAPI
+	for _, dir := range fsDirs[:2] {
+		posixDisk, err := newPosix(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		disks = append(disks, posixDisk)
+	}
+	if err = disks[1].MakeVol("moved"); err != nil {
+		t.Fatal(err)
+	}
+	writeIndex := func(index map[string]string) {
+		buf, err := json.Marshal(index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(indexFile, buf, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Bucket found on one of the configured paths is re-homed.
+	writeIndex(map[string]string{"moved": "/removed/path"})
+	storage, err := newMultiPathStorage(disks, indexFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = storage.StatVol("moved"); err != nil {
+		t.Fatalf("Expected the re-homed bucket to be served, got %v", err)
+	}
+	buf, err := ioutil.ReadFile(indexFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := make(map[string]string)
+	if err = json.Unmarshal(buf, &index); err != nil {
+		t.Fatal(err)
+	}
+	if index["moved"] != disks[1].String() {
+		t.Fatalf("Expected the bucket to be indexed on %s, got %s", disks[1], index["moved"])
+	}
+
+	// Bucket found on none of the configured paths.
+	writeIndex(map[string]string{"moved": disks[1].String(), "lost": "/removed/path"})
+	if _, err = newMultiPathStorage(disks, indexFile); err == nil {
+		t.Fatal("Expected a bucket indexed on a removed path to fail the initialization")
+	}
+
+	// Bucket found on more than one of the configured paths.
+	if err = disks[0].MakeVol("moved"); err != nil {
+		t.Fatal(err)
+	}
+	writeIndex(map[string]string{"moved": "/removed/path"})
+	if _, err = newMultiPathStorage(disks, indexFile); err == nil {
+		t.Fatal("Expected a bucket found on two paths to fail the initialization")
+	}
+}
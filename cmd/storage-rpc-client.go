@@ -60,6 +60,8 @@ func toStorageErr(err error) error {
 		return errUnexpected
 	case errDiskFull.Error():
 		return errDiskFull
+	case errDiskInodesFull.Error():
+		return errDiskInodesFull
 	case errVolumeNotFound.Error():
 		return errVolumeNotFound
 	case errVolumeExists.Error():
@@ -114,7 +116,7 @@ func newStorageRPC(ep *url.URL) (StorageAPI, error) {
 	rpcClient := newAuthClient(&authConfig{
 		accessKey:   accessKeyID,
 		secretKey:   secretAccessKey,
-		secureConn:  isSSL(),
+		secureConn:  isServerTLS(),
 		address:     rpcAddr,
 		path:        rpcPath,
 		loginMethod: "Storage.LoginHandler",
@@ -1356,6 +1356,13 @@ func getHeadObjectURL(endPoint, bucketName, objectName string) string {
 	return makeTestTargetURL(endPoint, bucketName, objectName, url.Values{})
 }
 
+// return URL for getting or setting the ACL of an object.
+func getObjectACLURL(endPoint, bucketName, objectName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("acl", "")
+	return makeTestTargetURL(endPoint, bucketName, objectName, queryValue)
+}
+
 // return url to be used while copying the object.
 func getCopyObjectURL(endPoint, bucketName, objectName string) string {
 	return makeTestTargetURL(endPoint, bucketName, objectName, url.Values{})
@@ -1389,6 +1396,27 @@ func getGetPolicyURL(endPoint, bucketName string) string {
 	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
 }
 
+// return URL for setting, fetching and removing bucket lifecycle.
+func getBucketLifecycleURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("lifecycle", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for setting and fetching bucket quota.
+func getBucketQuotaURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("quota", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for setting and fetching bucket object lock configuration.
+func getBucketObjectLockURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("object-lock", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
 // return URL for deleting bucket policy.
 func getDeletePolicyURL(endPoint, bucketName string) string {
 	queryValue := url.Values{}
@@ -1948,6 +1976,12 @@ func registerBucketLevelFunc(bucket *router.Router, api objectAPIHandlers, apiFu
 		case "HeadObject":
 			// Register HeadObject handler.
 			bucket.Methods("Head").Path("/{object:.+}").HandlerFunc(api.HeadObjectHandler)
+		case "GetObjectACL":
+			// Register GetObjectACL handler.
+			bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectACLHandler).Queries("acl", "")
+		case "PutObjectACL":
+			// Register PutObjectACL handler.
+			bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectACLHandler).Queries("acl", "")
 		case "GetObject":
 			// Register GetObject handler.
 			bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectHandler)
@@ -1969,6 +2003,30 @@ func registerBucketLevelFunc(bucket *router.Router, api objectAPIHandlers, apiFu
 		case "GetBucketPolicy":
 			// Register Get Bucket policy HTTP Handler.
 			bucket.Methods("GET").HandlerFunc(api.GetBucketPolicyHandler).Queries("policy", "")
+		case "PutBucketLifecycle":
+			// Register PutBucketLifecycle handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketLifecycleHandler).Queries("lifecycle", "")
+		case "GetBucketLifecycle":
+			// Register GetBucketLifecycle handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketLifecycleHandler).Queries("lifecycle", "")
+		case "DeleteBucketLifecycle":
+			// Register DeleteBucketLifecycle handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketLifecycleHandler).Queries("lifecycle", "")
+		case "PutBucketQuota":
+			// Register PutBucketQuota handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketQuotaHandler).Queries("quota", "")
+		case "GetBucketQuota":
+			// Register GetBucketQuota handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketQuotaHandler).Queries("quota", "")
+		case "PutBucketObjectLockConfig":
+			// Register PutBucketObjectLockConfig handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketObjectLockConfigHandler).Queries("object-lock", "")
+		case "GetBucketObjectLockConfig":
+			// Register GetBucketObjectLockConfig handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketObjectLockConfigHandler).Queries("object-lock", "")
+		case "PutBucket":
+			// Register PutBucket handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketHandler)
 		case "GetBucketLocation":
 			// Register GetBucketLocation handler.
 			bucket.Methods("GET").HandlerFunc(api.GetBucketLocationHandler).Queries("location", "")
@@ -1990,6 +2048,12 @@ func registerBucketLevelFunc(bucket *router.Router, api objectAPIHandlers, apiFu
 		case "ListMultipartUploads":
 			// Register ListMultipartUploads handler.
 			bucket.Methods("GET").HandlerFunc(api.ListMultipartUploadsHandler).Queries("uploads", "")
+		case "ListObjectsV2":
+			// Register ListObjectsV2 handler.
+			bucket.Methods("GET").HandlerFunc(api.ListObjectsV2Handler).Queries("list-type", "2")
+		case "ListObjectsV1":
+			// Register ListObjectsV1 handler.
+			bucket.Methods("GET").HandlerFunc(api.ListObjectsV1Handler)
 		case "CompleteMultipart":
 			// Register Complete Multipart Upload handler.
 			bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.CompleteMultipartUploadHandler).Queries("uploadId", "{uploadId:.*}")
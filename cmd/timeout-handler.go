@@ -0,0 +1,333 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiOperation - class of an S3 API request, derived from its path and
+// query, the expected duration of a request depends on its class.
+type apiOperation string
+
+const (
+	// Listing all the buckets.
+	apiOpService apiOperation = "service"
+	// Bucket level requests except listing, i.e location, policy etc.
+	apiOpBucket apiOperation = "bucket"
+	// Listing objects, multipart uploads and parts.
+	apiOpList apiOperation = "list"
+	// Object level requests which do not transfer object data.
+	apiOpObject apiOperation = "object"
+	// Requests transferring object data, their duration depends on
+	// the object size, only their transfer rate is enforced.
+	apiOpTransfer apiOperation = "transfer"
+	// Long lived requests which are never timed out.
+	apiOpStream apiOperation = "stream"
+)
+
+// apiTimeoutKey - key of the timeout table.
+type apiTimeoutKey struct {
+	method    string
+	operation apiOperation
+}
+
+// Default API timeouts, requests without an entry are not timed out.
+var defaultAPITimeouts = map[apiTimeoutKey]time.Duration{
+	{"GET", apiOpService}:   time.Minute,
+	{"GET", apiOpBucket}:    time.Minute,
+	{"HEAD", apiOpBucket}:   30 * time.Second,
+	{"PUT", apiOpBucket}:    time.Minute,
+	{"DELETE", apiOpBucket}: time.Minute,
+	// Deleting multiple objects.
+	{"POST", apiOpBucket}:   5 * time.Minute,
+	{"GET", apiOpList}:      5 * time.Minute,
+	{"GET", apiOpObject}:    time.Minute,
+	{"HEAD", apiOpObject}:   30 * time.Second,
+	{"PUT", apiOpObject}:    time.Minute,
+	{"DELETE", apiOpObject}: time.Minute,
+	// Completing multipart uploads may take longer on large objects.
+	{"POST", apiOpObject}: 15 * time.Minute,
+}
+
+// Window over which the transfer rate of object data is measured.
+const transferRateWindow = time.Minute
+
+// errTransferRateTooLow - object data was transferred slower than the
+// minimum transfer rate.
+var errTransferRateTooLow = errors.New("Transfer rate is lower than the minimum transfer rate")
+
+// getAPIOperation - classifies an S3 API request.
+func getAPIOperation(r *http.Request) apiOperation {
+	bucket, object := getRequestBucketObject(r)
+	query := r.URL.Query()
+	switch {
+	case bucket == "":
+		return apiOpService
+	case object == "":
+		if _, ok := query["events"]; ok && r.Method == "GET" {
+			return apiOpStream
+		}
+		if r.Method == "GET" && isBucketListRequest(query) {
+			return apiOpList
+		}
+		// Browser form uploads carry object data.
+		if r.Method == "POST" && strings.Contains(r.Header.Get("Content-Type"), "multipart/form-data") {
+			return apiOpTransfer
+		}
+		return apiOpBucket
+	}
+	if _, ok := query["acl"]; ok {
+		return apiOpObject
+	}
+	switch r.Method {
+	case "GET":
+		if _, ok := query["uploadId"]; ok {
+			return apiOpList
+		}
+		return apiOpTransfer
+	case "PUT":
+		return apiOpTransfer
+	}
+	return apiOpObject
+}
+
+// isBucketListRequest - returns true if a bucket GET request lists
+// objects or multipart uploads, rest of the bucket GET requests fetch
+// sub resources like location and policy.
+func isBucketListRequest(query map[string][]string) bool {
+	for _, resource := range []string{"location", "policy", "acl", "notification", "quota", "cors", "lifecycle", "versioning", "tagging"} {
+		if _, ok := query[resource]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// parseAPITimeouts - parses timeouts of the form "METHOD:operation=duration"
+// separated by commas, e.g "HEAD:object=10s,GET:list=10m". Parsed timeouts
+// are set on a copy of the default timeouts.
+func parseAPITimeouts(timeoutsStr string) (map[apiTimeoutKey]time.Duration, error) {
+	timeouts := make(map[apiTimeoutKey]time.Duration, len(defaultAPITimeouts))
+	for key, timeout := range defaultAPITimeouts {
+		timeouts[key] = timeout
+	}
+	if timeoutsStr == "" {
+		return timeouts, nil
+	}
+	for _, entry := range strings.Split(timeoutsStr, ",") {
+		keyValue := strings.SplitN(entry, "=", 2)
+		methodOp := strings.SplitN(keyValue[0], ":", 2)
+		if len(keyValue) != 2 || len(methodOp) != 2 {
+			return nil, fmt.Errorf("'%s' is not of the form METHOD:operation=duration", entry)
+		}
+		key := apiTimeoutKey{strings.ToUpper(methodOp[0]), apiOperation(methodOp[1])}
+		switch key.operation {
+		case apiOpService, apiOpBucket, apiOpList, apiOpObject:
+		default:
+			return nil, fmt.Errorf("'%s' has an unknown operation, should be one of service, bucket, list or object", entry)
+		}
+		timeout, err := time.ParseDuration(keyValue[1])
+		if err != nil {
+			return nil, fmt.Errorf("'%s' has an invalid duration: %s", entry, err)
+		}
+		// Zero timeout disables timing out the operation.
+		if timeout <= 0 {
+			delete(timeouts, key)
+			continue
+		}
+		timeouts[key] = timeout
+	}
+	return timeouts, nil
+}
+
+// transferRateMonitor - measures the transfer rate over a sliding window
+// made of fixed size slots, reports errTransferRateTooLow once the rate
+// over a full window is lower than the minimum rate.
+type transferRateMonitor struct {
+	mu        sync.Mutex
+	minRate   int64 // bytes/second
+	slotSize  time.Duration
+	slots     []int64
+	slotIndex int
+	slotStart time.Time
+	startTime time.Time
+	window    time.Duration
+}
+
+// Number of slots in a transfer rate window.
+const transferRateWindowSlots = 10
+
+func newTransferRateMonitor(minRate int64, window time.Duration) *transferRateMonitor {
+	now := time.Now().UTC()
+	return &transferRateMonitor{
+		minRate:   minRate,
+		slotSize:  window / transferRateWindowSlots,
+		slots:     make([]int64, transferRateWindowSlots),
+		slotStart: now,
+		startTime: now,
+		window:    window,
+	}
+}
+
+// record - records n bytes transferred now, time spent blocked on the
+// transfer counts towards the window as well.
+func (m *transferRateMonitor) record(n int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now().UTC()
+	// Move forward to the current slot, emptying the expired slots.
+	for expired := 0; now.Sub(m.slotStart) >= m.slotSize; expired++ {
+		m.slotStart = m.slotStart.Add(m.slotSize)
+		m.slotIndex = (m.slotIndex + 1) % len(m.slots)
+		m.slots[m.slotIndex] = 0
+		// All the slots expired, start over from now.
+		if expired == len(m.slots) {
+			m.slotStart = now
+		}
+	}
+	m.slots[m.slotIndex] += int64(n)
+
+	// Rate is judged only after a full window.
+	if now.Sub(m.startTime) < m.window {
+		return nil
+	}
+	var transferred int64
+	for _, slot := range m.slots {
+		transferred += slot
+	}
+	if transferred < m.minRate*int64(m.window)/int64(time.Second) {
+		return errTransferRateTooLow
+	}
+	return nil
+}
+
+// rateLimitedReader - fails reads once the transfer rate is too low.
+type rateLimitedReader struct {
+	io.ReadCloser
+	monitor *transferRateMonitor
+}
+
+func (r rateLimitedReader) Read(p []byte) (n int, err error) {
+	n, err = r.ReadCloser.Read(p)
+	if rErr := r.monitor.record(n); rErr != nil && err == nil {
+		err = rErr
+	}
+	return n, err
+}
+
+// rateLimitedWriter - fails writes once the transfer rate is too low.
+type rateLimitedWriter struct {
+	http.ResponseWriter
+	monitor *transferRateMonitor
+}
+
+func (w rateLimitedWriter) Write(p []byte) (n int, err error) {
+	n, err = w.ResponseWriter.Write(p)
+	if rErr := w.monitor.record(n); rErr != nil && err == nil {
+		err = rErr
+	}
+	return n, err
+}
+
+// ReadFrom - keeps the underlying io.ReaderFrom reachable, objects are
+// sent with sendfile(2) through it. The rate is only checked once the
+// data is sent.
+func (w rateLimitedWriter) ReadFrom(src io.Reader) (n int64, err error) {
+	if rf, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(src)
+	} else {
+		n, err = io.Copy(w.ResponseWriter, src)
+	}
+	if rErr := w.monitor.record(int(n)); rErr != nil && err == nil {
+		err = rErr
+	}
+	return n, err
+}
+
+// Flush - flushes the underlying writer, response writers are always
+// expected to be flushable by the API handlers.
+func (w rateLimitedWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// bufferedResponseWriter - ignores flushes of a writer which buffers the
+// whole response, like the writer of http.TimeoutHandler.
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w bufferedResponseWriter) Flush() {}
+
+// timeoutHandler - times out S3 API requests according to their class,
+// requests transferring object data are failed when their transfer rate
+// is too low instead.
+type timeoutHandler struct {
+	handler         http.Handler
+	timeouts        map[apiTimeoutKey]time.Duration
+	minTransferRate int64
+	rateWindow      time.Duration
+}
+
+func setTimeoutHandler(h http.Handler) http.Handler {
+	return timeoutHandler{
+		handler:         h,
+		timeouts:        globalAPITimeouts,
+		minTransferRate: globalMinTransferRate,
+		rateWindow:      transferRateWindow,
+	}
+}
+
+func (h timeoutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Browser and RPC requests are not timed out.
+	if strings.HasPrefix(r.URL.Path, reservedBucket+"/") {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	operation := getAPIOperation(r)
+	if operation == apiOpTransfer {
+		if h.minTransferRate > 0 {
+			monitor := newTransferRateMonitor(h.minTransferRate, h.rateWindow)
+			r.Body = rateLimitedReader{r.Body, monitor}
+			w = rateLimitedWriter{w, monitor}
+		}
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	timeout, ok := h.timeouts[apiTimeoutKey{r.Method, operation}]
+	if !ok {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	// Context of the request is cancelled after the timeout.
+	errorResponse := getAPIErrorResponse(getAPIError(ErrOperationTimedOut), r.URL.Path)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.handler.ServeHTTP(bufferedResponseWriter{w}, r)
+	})
+	http.TimeoutHandler(handler, timeout, string(encodeResponse(errorResponse))).ServeHTTP(w, r)
+}
@@ -0,0 +1,209 @@
+/*
+ * Minio Cloud Storage, (C) 2014-2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Tests classification of S3 API requests.
+func TestGetAPIOperation(t *testing.T) {
+	testCases := []struct {
+		method      string
+		url         string
+		contentType string
+		operation   apiOperation
+	}{
+		{"GET", "/", "", apiOpService},
+		{"GET", "/bucket", "", apiOpList},
+		{"GET", "/bucket/?prefix=photos&delimiter=/", "", apiOpList},
+		{"GET", "/bucket?uploads", "", apiOpList},
+		{"GET", "/bucket?location", "", apiOpBucket},
+		{"GET", "/bucket?policy", "", apiOpBucket},
+		{"GET", "/bucket?events=s3:ObjectCreated:*", "", apiOpStream},
+		{"HEAD", "/bucket", "", apiOpBucket},
+		{"PUT", "/bucket", "", apiOpBucket},
+		{"POST", "/bucket?delete", "", apiOpBucket},
+		{"POST", "/bucket", "multipart/form-data; boundary=xyz", apiOpTransfer},
+		{"GET", "/bucket/object", "", apiOpTransfer},
+		{"PUT", "/bucket/object", "", apiOpTransfer},
+		{"PUT", "/bucket/object?partNumber=1&uploadId=abc", "", apiOpTransfer},
+		{"GET", "/bucket/object?uploadId=abc", "", apiOpList},
+		{"GET", "/bucket/object?acl", "", apiOpObject},
+		{"PUT", "/bucket/object?acl", "", apiOpObject},
+		{"HEAD", "/bucket/object", "", apiOpObject},
+		{"DELETE", "/bucket/object", "", apiOpObject},
+		{"POST", "/bucket/object?uploads", "", apiOpObject},
+	}
+	for i, testCase := range testCases {
+		req, err := http.NewRequest(testCase.method, "http://localhost:9000"+testCase.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if testCase.contentType != "" {
+			req.Header.Set("Content-Type", testCase.contentType)
+		}
+		if operation := getAPIOperation(req); operation != testCase.operation {
+			t.Errorf("Test %d: Expected %s %s to be `%s`, got `%s`", i+1, testCase.method, testCase.url, testCase.operation, operation)
+		}
+	}
+}
+
+// Tests parsing of API timeout overrides.
+func TestParseAPITimeouts(t *testing.T) {
+	timeouts, err := parseAPITimeouts("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(timeouts) != len(defaultAPITimeouts) {
+		t.Fatalf("Expected default timeouts, got %v", timeouts)
+	}
+
+	timeouts, err = parseAPITimeouts("head:object=10s,GET:list=10m,DELETE:bucket=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timeouts[apiTimeoutKey{"HEAD", apiOpObject}] != 10*time.Second {
+		t.Errorf("Expected HEAD:object to be 10s, got %s", timeouts[apiTimeoutKey{"HEAD", apiOpObject}])
+	}
+	if timeouts[apiTimeoutKey{"GET", apiOpList}] != 10*time.Minute {
+		t.Errorf("Expected GET:list to be 10m, got %s", timeouts[apiTimeoutKey{"GET", apiOpList}])
+	}
+	if _, ok := timeouts[apiTimeoutKey{"DELETE", apiOpBucket}]; ok {
+		t.Error("Expected DELETE:bucket timeout to be disabled")
+	}
+	// Defaults should not be modified.
+	if defaultAPITimeouts[apiTimeoutKey{"HEAD", apiOpObject}] == 10*time.Second {
+		t.Error("Expected default timeouts not to be modified")
+	}
+
+	for _, timeoutsStr := range []string{"HEAD", "HEAD:object", "HEAD=10s", "HEAD:transfer=10s", "HEAD:object=invalid"} {
+		if _, err = parseAPITimeouts(timeoutsStr); err == nil {
+			t.Errorf("Expected `%s` to fail", timeoutsStr)
+		}
+	}
+}
+
+// Tests transfer rate measured over a sliding window.
+func TestTransferRateMonitor(t *testing.T) {
+	window := 200 * time.Millisecond
+	monitor := newTransferRateMonitor(1000, window)
+
+	// Rate is not judged before a full window.
+	if err := monitor.record(0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fast enough transfers over more than a window.
+	for i := 0; i < 10; i++ {
+		time.Sleep(window / 10 * 3 / 2)
+		if err := monitor.record(1000); err != nil {
+			t.Fatalf("Expected transfer to be fast enough, got %s", err)
+		}
+	}
+
+	// Stalled transfer fails once a full window passes without any data.
+	time.Sleep(window + window/10)
+	if err := monitor.record(1); err != errTransferRateTooLow {
+		t.Fatalf("Expected %s, got %v", errTransferRateTooLow, err)
+	}
+}
+
+// Tests responses sent through io.ReaderFrom are rate limited.
+func TestRateLimitedWriterReadFrom(t *testing.T) {
+	rec := &readerFromRecorder{ResponseRecorder: httptest.NewRecorder()}
+	monitor := newTransferRateMonitor(1000, time.Hour)
+	w := rateLimitedWriter{rec, monitor}
+	n, err := w.ReadFrom(strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rec.readFromCalled {
+		t.Error("Expected ReadFrom of the underlying writer to be used")
+	}
+	if n != 5 || monitor.slots[monitor.slotIndex] != 5 {
+		t.Errorf("Expected 5 bytes to be recorded, got %d, %d bytes", n, monitor.slots[monitor.slotIndex])
+	}
+}
+
+// Tests timing out of S3 API requests.
+func TestTimeoutHandler(t *testing.T) {
+	slowHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := timeoutHandler{
+		handler: slowHandler,
+		timeouts: map[apiTimeoutKey]time.Duration{
+			{"HEAD", apiOpObject}: 50 * time.Millisecond,
+		},
+	}
+
+	// HEAD object is timed out.
+	req, err := http.NewRequest("HEAD", "http://localhost:9000/bucket/object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+	errResp := APIErrorResponse{}
+	if err = xml.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+		t.Fatal(err)
+	}
+	if errResp.Code != "RequestTimeout" {
+		t.Errorf("Expected RequestTimeout, got %s", errResp.Code)
+	}
+
+	// Responses flushed before the timeout are sent, flushing is
+	// supported by the handlers of timed out operations.
+	handler.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeSuccessResponse(w, []byte("hello"))
+	})
+	req, err = http.NewRequest("GET", "http://localhost:9000/bucket/object?acl", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.timeouts[apiTimeoutKey{"GET", apiOpObject}] = time.Second
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Body.String() != "hello" {
+		t.Fatalf("Expected %d with `hello`, got %d with `%s`", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	// Object data transfers are never timed out.
+	handler.handler = slowHandler
+	req, err = http.NewRequest("PUT", "http://localhost:9000/bucket/object", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, rec.Code)
+	}
+}
@@ -27,6 +27,10 @@ const (
 	globalLookupTimeout = time.Minute * 30 // 30minutes.
 )
 
+// Maximum number of treeWalk go-routines kept in a treeWalkPool, beyond
+// which the oldest treeWalk is evicted.
+const maxTreeWalkPoolSize = 1000
+
 // listParams - list object params used for list object map
 type listParams struct {
 	bucket    string
@@ -47,25 +51,29 @@ type treeWalk struct {
 	resultCh   chan treeWalkResult
 	endWalkCh  chan struct{}   // To signal when treeWalk go-routine should end.
 	endTimerCh chan<- struct{} // To signal when timer go-routine should end.
+	addedTime  time.Time       // Time at which treeWalk was added to the pool.
 }
 
 // treeWalkPool - pool of treeWalk go routines.
 // A treeWalk is added to the pool by Set() and removed either by
-// doing a Release() or if the concerned timer goes off.
+// doing a Release(), if the concerned timer goes off or if it is evicted
+// by Set() when the pool has reached its maximum size.
 // treeWalkPool's purpose is to maintain active treeWalk go-routines in a map so that
 // it can be looked up across related list calls.
 type treeWalkPool struct {
-	pool    map[listParams][]treeWalk
-	timeOut time.Duration
-	lock    *sync.Mutex
+	pool     map[listParams][]treeWalk
+	timeOut  time.Duration
+	maxWalks int
+	lock     *sync.Mutex
 }
 
 // newTreeWalkPool - initialize new tree walk pool.
 func newTreeWalkPool(timeout time.Duration) *treeWalkPool {
 	tPool := &treeWalkPool{
-		pool:    make(map[listParams][]treeWalk),
-		timeOut: timeout,
-		lock:    &sync.Mutex{},
+		pool:     make(map[listParams][]treeWalk),
+		timeOut:  timeout,
+		maxWalks: maxTreeWalkPoolSize,
+		lock:     &sync.Mutex{},
 	}
 	return tPool
 }
@@ -96,7 +104,79 @@ func (t treeWalkPool) Release(params listParams) (resultCh chan treeWalkResult,
 	return nil, nil
 }
 
+// remove - removes walkInfo from the walks associated with params,
+// returns false if walkInfo is not in the pool anymore.
+// Should be called with t.lock held.
+func (t treeWalkPool) remove(params listParams, walkInfo treeWalk) bool {
+	walks := t.pool[params]
+	for i, walk := range walks {
+		if walk != walkInfo {
+			continue
+		}
+		walks = append(walks[:i], walks[i+1:]...)
+		if len(walks) == 0 {
+			// No more treeWalk go-routines associated with listParams
+			// hence remove map entry.
+			delete(t.pool, params)
+		} else {
+			// There are more treeWalk go-routines associated with listParams
+			// hence save the list in the map.
+			t.pool[params] = walks
+		}
+		return true
+	}
+	return false
+}
+
+// evictOldest - removes the oldest treeWalk from the pool, ends its timer
+// go-routine and signals the treeWalk go-routine to end so that it does
+// not block forever on a result nobody is going to read.
+// Should be called with t.lock held.
+func (t treeWalkPool) evictOldest() {
+	var oldestParams listParams
+	var oldest treeWalk
+	found := false
+	for params, walks := range t.pool {
+		// Walks are appended in the order they are added, the
+		// first one is the oldest for the given listParams.
+		if len(walks) == 0 {
+			continue
+		}
+		if !found || walks[0].addedTime.Before(oldest.addedTime) {
+			oldestParams, oldest, found = params, walks[0], true
+		}
+	}
+	if !found {
+		return
+	}
+	t.remove(oldestParams, oldest)
+	oldest.endTimerCh <- struct{}{}
+	close(oldest.endWalkCh)
+}
+
+// EvictOldest - ends the oldest treeWalk in the pool, returns false if
+// the pool is empty.
+func (t treeWalkPool) EvictOldest() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.size() == 0 {
+		return false
+	}
+	t.evictOldest()
+	return true
+}
+
+// size - returns the number of treeWalks in the pool.
+// Should be called with t.lock held.
+func (t treeWalkPool) size() (n int) {
+	for _, walks := range t.pool {
+		n += len(walks)
+	}
+	return n
+}
+
 // Set - adds a treeWalk to the treeWalkPool.
+// If the pool is full the oldest treeWalk is evicted and ended.
 // Also starts a timer go-routine that ends when:
 // 1) time.After() expires after t.timeOut seconds.
 //    The expiration is needed so that the treeWalk go-routine resources are freed after a timeout
@@ -108,12 +188,18 @@ func (t treeWalkPool) Set(params listParams, resultCh chan treeWalkResult, endWa
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
+	// Make room for the new treeWalk.
+	for t.maxWalks > 0 && t.size() >= t.maxWalks {
+		t.evictOldest()
+	}
+
 	// Should be a buffered channel so that Release() never blocks.
 	endTimerCh := make(chan struct{}, 1)
 	walkInfo := treeWalk{
 		resultCh:   resultCh,
 		endWalkCh:  endWalkCh,
 		endTimerCh: endTimerCh,
+		addedTime:  time.Now().UTC(),
 	}
 	// Append new walk info.
 	t.pool[params] = append(t.pool[params], walkInfo)
@@ -126,26 +212,12 @@ func (t treeWalkPool) Set(params listParams, resultCh chan treeWalkResult, endWa
 			// Timeout has expired. Remove the treeWalk from treeWalkPool and
 			// end the treeWalk go-routine.
 			t.lock.Lock()
-			walks, ok := t.pool[params]
-			if ok {
-				// Look for walkInfo, remove it from the walks list.
-				for i, walk := range walks {
-					if walk == walkInfo {
-						walks = append(walks[:i], walks[i+1:]...)
-					}
-				}
-				if len(walks) == 0 {
-					// No more treeWalk go-routines associated with listParams
-					// hence remove map entry.
-					delete(t.pool, params)
-				} else {
-					// There are more treeWalk go-routines associated with listParams
-					// hence save the list in the map.
-					t.pool[params] = walks
-				}
+			// The treeWalk might have been released or evicted while
+			// waiting for the lock, it should not be ended then.
+			if t.remove(params, walkInfo) {
+				// Signal the treeWalk go-routine to die.
+				close(endWalkCh)
 			}
-			// Signal the treeWalk go-routine to die.
-			close(endWalkCh)
 			t.lock.Unlock()
 		case <-endTimerCh:
 			return
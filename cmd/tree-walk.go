@@ -237,6 +237,12 @@ func doTreeWalk(bucket, prefixDir, entryPrefixMatch, marker string, recursive bo
 
 // Initiate a new treeWalk in a goroutine.
 func startTreeWalk(bucket, prefix, marker string, recursive bool, listDir listDirFunc, isLeaf isLeafFunc, endWalkCh chan struct{}) chan treeWalkResult {
+	return startTreeWalkRelease(bucket, prefix, marker, recursive, listDir, isLeaf, endWalkCh, nil)
+}
+
+// startTreeWalkRelease - same as startTreeWalk, release is called if
+// non-nil once the treeWalk goroutine returns.
+func startTreeWalkRelease(bucket, prefix, marker string, recursive bool, listDir listDirFunc, isLeaf isLeafFunc, endWalkCh chan struct{}, release func()) chan treeWalkResult {
 	// Example 1
 	// If prefix is "one/two/three/" and marker is "one/two/three/four/five.txt"
 	// treeWalk is called with prefixDir="one/two/three/" and marker="four/five.txt"
@@ -257,6 +263,9 @@ func startTreeWalk(bucket, prefix, marker string, recursive bool, listDir listDi
 	}
 	marker = strings.TrimPrefix(marker, prefixDir)
 	go func() {
+		if release != nil {
+			defer release()
+		}
 		isEnd := true // Indication to start walking the tree with end as true.
 		doTreeWalk(bucket, prefixDir, entryPrefixMatch, marker, recursive, listDir, isLeaf, resultCh, endWalkCh, isEnd)
 		close(resultCh)
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"reflect"
+	"runtime"
 	"sort"
 	"strings"
 	"testing"
@@ -271,6 +272,78 @@ func TestTreeWalkTimeout(t *testing.T) {
 	}
 }
 
+// Test if tree walk go-routines of abandoned listings exit cleanly when
+// they are evicted from a full tree walk pool.
+func TestTreeWalkEviction(t *testing.T) {
+	fsDir, err := ioutil.TempDir("", "minio-")
+	if err != nil {
+		t.Fatalf("Unable to create tmp directory: %s", err)
+	}
+	defer removeAll(fsDir)
+	endpoints, err := parseStorageEndpoints([]string{fsDir})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	disk, err := newStorageAPI(endpoints[0])
+	if err != nil {
+		t.Fatalf("Unable to create StorageAPI: %s", err)
+	}
+	var myfiles []string
+	// Create maxObjectsList+1 number of entries so that tree walk
+	// go-routines block on sending results.
+	for i := 0; i < maxObjectList+1; i++ {
+		myfiles = append(myfiles, fmt.Sprintf("file.%d", i))
+	}
+	if err = createNamespace(disk, volume, myfiles); err != nil {
+		t.Fatal(err)
+	}
+
+	isLeaf := func(volume, prefix string) bool {
+		return !strings.HasSuffix(prefix, slashSeparator)
+	}
+	listDir := listDirFactory(isLeaf, xlTreeWalkIgnoredErrs, disk)
+
+	// TreeWalk pool which never times out but holds only a few walks.
+	pool := newTreeWalkPool(time.Hour)
+	pool.maxWalks = 5
+
+	numGoroutines := runtime.NumGoroutine()
+
+	// Abandon many listings, each leaves a tree walk in the pool.
+	var resultChs []chan treeWalkResult
+	for i := 0; i < 50; i++ {
+		endWalkCh := make(chan struct{})
+		resultCh := startTreeWalk(volume, "", "", true, listDir, isLeaf, endWalkCh)
+		pool.Set(listParams{bucket: volume, recursive: true}, resultCh, endWalkCh)
+		resultChs = append(resultChs, resultCh)
+	}
+
+	pool.lock.Lock()
+	if n := pool.size(); n != pool.maxWalks {
+		t.Errorf("Expected %d walks in the pool, got %d", pool.maxWalks, n)
+	}
+	pool.lock.Unlock()
+
+	// Evicted tree walk go-routines should end and close their result channel.
+	for _, resultCh := range resultChs[:len(resultChs)-pool.maxWalks] {
+		for range resultCh {
+		}
+	}
+
+	// Only the walks in the pool and their timers should be left running.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		grown := runtime.NumGoroutine() - numGoroutines
+		if grown <= 2*pool.maxWalks {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected at most %d new go-routines, got %d", 2*pool.maxWalks, grown)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
 // Test ListDir - listDir should list entries from the first disk, if the first disk is down,
 // it should list from the next disk.
 func TestListDir(t *testing.T) {
@@ -18,10 +18,15 @@ package cmd
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
@@ -31,12 +36,19 @@ import (
 	"github.com/minio/mc/pkg/console"
 )
 
+var updateFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Print the release which would be installed without downloading it.",
+	},
+}
+
 // Check for new software updates.
 var updateCmd = cli.Command{
 	Name:   "update",
-	Usage:  "Check for a new software update.",
+	Usage:  "Check for a new software update and install it.",
 	Action: mainUpdate,
-	Flags:  globalFlags,
+	Flags:  append(updateFlags, globalFlags...),
 	CustomHelpTemplate: `Name:
    minio {{.Name}} - {{.Usage}}
 
@@ -47,8 +59,11 @@ FLAGS:
   {{range .Flags}}{{.}}
   {{end}}
 EXAMPLES:
-   1. Check for any new official release.
+   1. Check for any new official release and install it.
       $ minio {{.Name}}
+
+   2. Check for any new official release without installing it.
+      $ minio {{.Name}} --dry-run
 `,
 }
 
@@ -57,6 +72,9 @@ const (
 	minioUpdateStableURL = "https://dl.minio.io/server/minio/release"
 )
 
+// Timeout for downloading a new release binary.
+const minioUpdateDownloadTimeout = 10 * time.Minute
+
 // updateMessage container to hold update messages.
 type updateMessage struct {
 	Update    bool          `json:"update"`
@@ -263,6 +281,109 @@ func getReleaseUpdate(updateURL string, duration time.Duration) (updateMsg updat
 	return updateMsg, "", nil
 }
 
+// getReleaseChecksum - fetches the SHA256 checksum of the latest release
+// along with its release date.
+func getReleaseChecksum(updateURL string, duration time.Duration) (sha256Hex string, latest time.Time, err error) {
+	checksumURL := updateURL + "/" + runtime.GOOS + "-" + runtime.GOARCH + "/minio.sha256sum"
+	client := &http.Client{
+		Timeout:   duration,
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment},
+	}
+	resp, err := client.Get(checksumURL)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, errors.New("http status : " + resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	latest, err = parseReleaseData(string(data))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	sha256Hex = strings.Fields(string(data))[0]
+	if sum, derr := hex.DecodeString(sha256Hex); derr != nil || len(sum) != sha256.Size {
+		return "", time.Time{}, errors.New("Update data malformed, invalid sha256 checksum")
+	}
+	return sha256Hex, latest, nil
+}
+
+// getMinioBinaryPath - absolute path of the running minio binary.
+func getMinioBinaryPath() (string, error) {
+	binaryPath, err := exec.LookPath(os.Args[0])
+	if err != nil {
+		return "", err
+	}
+	binaryPath, err = filepath.Abs(binaryPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(binaryPath)
+}
+
+// installReleaseUpdate - downloads the binary at downloadURL, verifies its
+// SHA256 checksum and replaces binaryPath with it. The binary is written
+// to a temporary file in the same directory first, so binaryPath is left
+// intact on any failure.
+func installReleaseUpdate(downloadURL, sha256Hex, binaryPath string, duration time.Duration) (err error) {
+	client := &http.Client{
+		Timeout:   duration,
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment},
+	}
+	resp, err := client.Get(downloadURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("http status : " + resp.Status)
+	}
+
+	fi, err := os.Stat(binaryPath)
+	if err != nil {
+		return err
+	}
+	tmpFile, err := ioutil.TempFile(filepath.Dir(binaryPath), ".minio.update.")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(tmpFile.Name())
+		}
+	}()
+
+	sha256Writer := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmpFile, sha256Writer), resp.Body)
+	if cerr := tmpFile.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if hex.EncodeToString(sha256Writer.Sum(nil)) != strings.ToLower(sha256Hex) {
+		return errors.New("Downloaded binary does not match the release sha256 checksum")
+	}
+	if err = os.Chmod(tmpFile.Name(), fi.Mode()); err != nil {
+		return err
+	}
+
+	// A running binary cannot be replaced on windows, it can only be
+	// moved out of the way.
+	if runtime.GOOS == "windows" {
+		oldPath := binaryPath + ".old"
+		os.Remove(oldPath)
+		if err = os.Rename(binaryPath, oldPath); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmpFile.Name(), binaryPath)
+}
+
 // main entry point for update command.
 func mainUpdate(ctx *cli.Context) {
 
@@ -284,4 +405,31 @@ func mainUpdate(ctx *cli.Context) {
 	updateMsg, errMsg, err = getReleaseUpdate(minioUpdateStableURL, secs)
 	fatalIf(err, errMsg)
 	console.Println(updateMsg)
+
+	// Docker images are upgraded by pulling a new image.
+	if !updateMsg.Update || isDocker() {
+		return
+	}
+
+	binaryPath, err := getMinioBinaryPath()
+	fatalIf(err, "Unable to find the path of the running minio binary.")
+
+	if ctx.Bool("dry-run") {
+		console.Printf("Dry run, %s would be installed at %s.\n", updateMsg.Download, binaryPath)
+		return
+	}
+
+	sha256Hex, latest, err := getReleaseChecksum(minioUpdateStableURL, secs)
+	fatalIf(err, "Unable to fetch the checksum of the latest release.")
+	current, err := getCurrentMinioVersion()
+	fatalIf(err, "Unable to fetch the current version of Minio server.")
+	if !latest.After(current) {
+		console.Println("Latest release checksum is not yet available, please try again later.")
+		return
+	}
+
+	err = installReleaseUpdate(updateMsg.Download, sha256Hex, binaryPath, minioUpdateDownloadTimeout)
+	fatalIf(err, "Unable to install %s at %s.", updateMsg.Download, binaryPath)
+	console.Printf("Minio updated to %s at %s, please restart the server to run the new version.\n",
+		latest.Format(time.RFC3339), binaryPath)
 }
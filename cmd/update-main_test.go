@@ -0,0 +1,141 @@
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// Tests fetching the sha256 checksum of the latest release.
+func TestGetReleaseChecksum(t *testing.T) {
+	sum := sha256.Sum256([]byte("new minio binary"))
+	sha256Hex := hex.EncodeToString(sum[:])
+	checksumPath := "/" + runtime.GOOS + "-" + runtime.GOARCH + "/minio.sha256sum"
+
+	testCases := []struct {
+		data        string
+		shouldPass  bool
+		expectedSum string
+	}{
+		{sha256Hex + " minio.RELEASE.2016-10-07T01-16-39Z", true, sha256Hex},
+		// Checksum is a sha1 sum.
+		{"fbe246edbd382902db9a4035df7dce8cb441357d minio.RELEASE.2016-10-07T01-16-39Z", false, ""},
+		// Missing release tag.
+		{sha256Hex, false, ""},
+		// Checksum is not hex.
+		{"zz minio.RELEASE.2016-10-07T01-16-39Z", false, ""},
+	}
+	for i, testCase := range testCases {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != checksumPath {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			fmt.Fprintln(w, testCase.data)
+		}))
+		gotSum, latest, err := getReleaseChecksum(ts.URL, 3*time.Second)
+		ts.Close()
+		if testCase.shouldPass && err != nil {
+			t.Fatalf("Test %d: Unexpected error %v", i+1, err)
+		}
+		if !testCase.shouldPass {
+			if err == nil {
+				t.Fatalf("Test %d: Expected to fail", i+1)
+			}
+			continue
+		}
+		if gotSum != testCase.expectedSum {
+			t.Fatalf("Test %d: Expected checksum %s, got %s", i+1, testCase.expectedSum, gotSum)
+		}
+		if !latest.Equal(time.Date(2016, 10, 7, 1, 16, 39, 0, time.UTC)) {
+			t.Fatalf("Test %d: Unexpected release date %s", i+1, latest)
+		}
+	}
+}
+
+// Tests downloading and installing a new release binary.
+func TestInstallReleaseUpdate(t *testing.T) {
+	newBinary := []byte("new minio binary")
+	sum := sha256.Sum256(newBinary)
+	sha256Hex := hex.EncodeToString(sum[:])
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(newBinary)
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "minio-update-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(dir)
+	binaryPath := filepath.Join(dir, "minio")
+	if err = ioutil.WriteFile(binaryPath, []byte("old minio binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Checksum mismatch should leave the binary intact.
+	badSum := sha256.Sum256([]byte("tampered minio binary"))
+	err = installReleaseUpdate(ts.URL+"/minio", hex.EncodeToString(badSum[:]), binaryPath, 3*time.Second)
+	if err == nil {
+		t.Fatal("Expected checksum mismatch to fail")
+	}
+	data, err := ioutil.ReadFile(binaryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "old minio binary" {
+		t.Fatalf("Binary was modified on a failed update: %s", data)
+	}
+
+	// Matching checksum should replace the binary.
+	if err = installReleaseUpdate(ts.URL+"/minio", sha256Hex, binaryPath, 3*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	data, err = ioutil.ReadFile(binaryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(newBinary) {
+		t.Fatalf("Expected the new binary to be installed, got %s", data)
+	}
+	fi, err := os.Stat(binaryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && fi.Mode().Perm() != 0755 {
+		t.Fatalf("Expected binary mode to be preserved, got %s", fi.Mode())
+	}
+
+	// No temporary files should be left behind.
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 && runtime.GOOS != "windows" {
+		t.Fatalf("Expected only the binary in %s, found %d entries", dir, len(entries))
+	}
+}
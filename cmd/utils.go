@@ -116,6 +116,8 @@ const (
 	maxObjectSize = 5 * humanize.GiByte
 	// minimum Part size for multipart upload is 5MiB
 	minPartSize = 5 * humanize.MiByte
+	// maximum Part size for multipart upload is 5GiB
+	maxPartSize = 5 * humanize.GiByte
 	// maximum Part ID for multipart upload is 10000 (Acceptable values range from 1 to 10000 inclusive)
 	maxPartID = 10000
 )
@@ -130,9 +132,14 @@ func isMinAllowedPartSize(size int64) bool {
 	return size >= minPartSize
 }
 
-// isMaxPartNumber - Check if part ID is greater than the maximum allowed ID.
-func isMaxPartID(partID int) bool {
-	return partID > maxPartID
+// isMaxPartSize - Check if part size is more than the maximum allowed size.
+func isMaxPartSize(size int64) bool {
+	return size > maxPartSize
+}
+
+// isValidPartID - Check if part ID is within the allowed range 1 to maxPartID.
+func isValidPartID(partID int) bool {
+	return partID >= 1 && partID <= maxPartID
 }
 
 func contains(stringList []string, element string) bool {
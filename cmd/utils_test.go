@@ -194,28 +194,43 @@ func TestMinAllowedPartSize(t *testing.T) {
 	}
 }
 
-// Tests maximum allowed part number.
-func TestMaxPartID(t *testing.T) {
+// Tests allowed part number range.
+func TestValidPartID(t *testing.T) {
 	sizes := []struct {
-		isMax bool
-		partN int
+		isValid bool
+		partN   int
 	}{
 		// Test - 1 part number within max part number.
 		{
-			false,
+			true,
 			maxPartID - 1,
 		},
 		// Test - 2 part number bigger than max part number.
 		{
-			true,
+			false,
 			maxPartID + 1,
 		},
+		// Test - 3 part number zero.
+		{
+			false,
+			0,
+		},
+		// Test - 4 lowest allowed part number.
+		{
+			true,
+			1,
+		},
+		// Test - 5 highest allowed part number.
+		{
+			true,
+			maxPartID,
+		},
 	}
 
 	for i, s := range sizes {
-		isMax := isMaxPartID(s.partN)
-		if isMax != s.isMax {
-			t.Errorf("Test %d: Expected %t, got %t", i+1, s.isMax, isMax)
+		isValid := isValidPartID(s.partN)
+		if isValid != s.isValid {
+			t.Errorf("Test %d: Expected %t, got %t", i+1, s.isValid, isValid)
 		}
 	}
 }
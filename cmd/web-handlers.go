@@ -297,6 +297,64 @@ func (web *webAPIHandlers) RemoveObject(r *http.Request, args *RemoveObjectArgs,
 	return nil
 }
 
+// GetUploadProgressArgs - args to get the progress of a multipart upload.
+type GetUploadProgressArgs struct {
+	BucketName string `json:"bucketName"`
+	ObjectName string `json:"objectName"`
+	UploadID   string `json:"uploadId"`
+}
+
+// GetUploadProgressRep - progress of a multipart upload.
+type GetUploadProgressRep struct {
+	UIVersion string `json:"uiVersion"`
+	// Total size of the committed parts.
+	Committed int64 `json:"committed"`
+	// Expected size of the object, 0 if the client did not provide it.
+	Total int64 `json:"total"`
+	// Number of committed parts.
+	Parts int `json:"parts"`
+}
+
+// GetUploadProgress - returns the committed size of a multipart upload.
+func (web *webAPIHandlers) GetUploadProgress(r *http.Request, args *GetUploadProgressArgs, reply *GetUploadProgressRep) error {
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(errServerNotInitialized)
+	}
+	if !isJWTReqAuthenticated(r) {
+		return toJSONError(errAuthentication)
+	}
+	upload, err := getMultipartUpload(objectAPI, args.BucketName, args.ObjectName, args.UploadID)
+	if err != nil {
+		return toJSONError(err, args.BucketName, args.ObjectName)
+	}
+	reply.UIVersion = miniobrowser.UIVersion
+	reply.Committed = upload.CommittedSize
+	reply.Total = upload.Size
+	reply.Parts = upload.Parts
+	return nil
+}
+
+// getMultipartUpload - looks up an upload id among the uploads of an object.
+func getMultipartUpload(objectAPI ObjectLayer, bucket, object, uploadID string) (uploadMetadata, error) {
+	keyMarker, uploadIDMarker := "", ""
+	for {
+		result, err := objectAPI.ListMultipartUploads(bucket, object, keyMarker, uploadIDMarker, "", maxUploadsList)
+		if err != nil {
+			return uploadMetadata{}, err
+		}
+		for _, upload := range result.Uploads {
+			if upload.Object == object && upload.UploadID == uploadID {
+				return upload, nil
+			}
+		}
+		if !result.IsTruncated {
+			return uploadMetadata{}, traceError(InvalidUploadID{UploadID: uploadID})
+		}
+		keyMarker, uploadIDMarker = result.NextKeyMarker, result.NextUploadIDMarker
+	}
+}
+
 // LoginArgs - login arguments.
 type LoginArgs struct {
 	Username string `json:"username" form:"username"`
@@ -532,7 +590,7 @@ func (web *webAPIHandlers) Download(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// Add content disposition.
-	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", path.Base(object)))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", sanitizeHeaderValue(path.Base(object))))
 
 	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
 	if err != nil {
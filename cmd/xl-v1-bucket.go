@@ -32,7 +32,7 @@ var bucketMetadataOpIgnoredErrs = append(bucketOpIgnoredErrs, errVolumeNotFound)
 // MakeBucket - make a bucket.
 func (xl xlObjects) MakeBucket(bucket string) error {
 	// Verify if bucket is valid.
-	if !IsValidBucketName(bucket) {
+	if !isValidNewBucketName(bucket) {
 		return traceError(BucketNameInvalid{Bucket: bucket})
 	}
 
@@ -32,3 +32,6 @@ var errXLReadQuorum = errors.New("Read failed. Insufficient number of disks onli
 
 // errXLWriteQuorum - did not meet write quorum.
 var errXLWriteQuorum = errors.New("Write failed. Insufficient number of disks online")
+
+// errXLBlockCorrupted - erasure block does not match its checksum.
+var errXLBlockCorrupted = errors.New("Erasure block is corrupted")
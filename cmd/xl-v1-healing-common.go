@@ -16,7 +16,11 @@
 
 package cmd
 
-import "time"
+import (
+	"bytes"
+	"sync"
+	"time"
+)
 
 // commonTime returns a maximally occurring time from a list of time.
 func commonTime(modTimes []time.Time) (modTime time.Time) {
@@ -133,3 +137,83 @@ func xlShouldHeal(partsMetadata []xlMetaV1, errs []error) bool {
 	}
 	return false
 }
+
+// Duration for which the result of a disk probe is re-used, avoids
+// hammering a failing disk with repeated probes.
+const diskProbeCacheExpiry = 30 * time.Second
+
+// diskProbeResult - cached result of a disk probe, mu is held while
+// the disk is probed so that concurrent callers wait for the result
+// instead of probing the same disk again.
+type diskProbeResult struct {
+	mu        sync.Mutex
+	ok        bool
+	lastProbe time.Time
+}
+
+// diskProbeCache - caches disk probe results keyed by probe type and disk.
+type diskProbeCache struct {
+	mu      sync.Mutex
+	results map[string]*diskProbeResult
+}
+
+// Global cache for disk probe results.
+var globalDiskProbeCache = &diskProbeCache{
+	results: make(map[string]*diskProbeResult),
+}
+
+// probe - returns the cached result for key if it hasn't expired yet,
+// otherwise runs probeFn and caches its result. Only the entry of key
+// is locked while probing, a hung disk does not block the others.
+func (c *diskProbeCache) probe(key string, probeFn func() bool) bool {
+	c.mu.Lock()
+	result, ok := c.results[key]
+	if !ok {
+		result = &diskProbeResult{}
+		c.results[key] = result
+	}
+	c.mu.Unlock()
+
+	result.mu.Lock()
+	defer result.mu.Unlock()
+	if !result.lastProbe.IsZero() && time.Since(result.lastProbe) < diskProbeCacheExpiry {
+		return result.ok
+	}
+	result.ok = probeFn()
+	result.lastProbe = time.Now().UTC()
+	return result.ok
+}
+
+// isDiskUsable - verifies if a disk can receive data by writing a
+// 1-byte temporary file, reading it back and deleting it. Returns
+// false if any of these operations fail.
+func isDiskUsable(disk StorageAPI) bool {
+	if disk == nil {
+		return false
+	}
+	return globalDiskProbeCache.probe("usable:"+disk.String(), func() bool {
+		probeData := []byte("m")
+		probePath := mustGetUUID()
+		if err := disk.AppendFile(minioMetaTmpBucket, probePath, probeData); err != nil {
+			return false
+		}
+		buf, err := disk.ReadAll(minioMetaTmpBucket, probePath)
+		// Cleanup the probe file, a failure here also renders the disk unusable.
+		if dErr := disk.DeleteFile(minioMetaTmpBucket, probePath); dErr != nil {
+			return false
+		}
+		return err == nil && bytes.Equal(buf, probeData)
+	})
+}
+
+// isDiskReadable - verifies if a disk can serve data, only read
+// access is tested.
+func isDiskReadable(disk StorageAPI) bool {
+	if disk == nil {
+		return false
+	}
+	return globalDiskProbeCache.probe("readable:"+disk.String(), func() bool {
+		_, err := disk.StatVol(minioMetaBucket)
+		return err == nil
+	})
+}
@@ -99,11 +99,21 @@ func healBucket(storageDisks []StorageAPI, bucket string, writeQuorum int) error
 		// Make a volume inside a go-routine.
 		go func(index int, disk StorageAPI) {
 			defer wg.Done()
+			// Skip disks which cannot be read from.
+			if !isDiskReadable(disk) {
+				dErrs[index] = traceError(errFaultyDisk)
+				return
+			}
 			if _, err := disk.StatVol(bucket); err != nil {
 				if err != errVolumeNotFound {
 					dErrs[index] = traceError(err)
 					return
 				}
+				// Skip disks which cannot receive healed data.
+				if !isDiskUsable(disk) {
+					dErrs[index] = traceError(errFaultyDisk)
+					return
+				}
 				if err = disk.MakeVol(bucket); err != nil {
 					dErrs[index] = traceError(err)
 				}
@@ -128,6 +138,34 @@ func healBucket(storageDisks []StorageAPI, bucket string, writeQuorum int) error
 	return nil
 }
 
+// xlShouldHealBucket - returns true if the bucket is missing on any
+// of the online disks.
+func xlShouldHealBucket(storageDisks []StorageAPI, bucket string) bool {
+	for _, disk := range storageDisks {
+		if disk == nil {
+			continue
+		}
+		if _, err := disk.StatVol(bucket); err == errVolumeNotFound {
+			return true
+		}
+	}
+	return false
+}
+
+// xlShouldHealObject - returns true if the object has missing or
+// outdated entries on any of the disks.
+func xlShouldHealObject(xl xlObjects, bucket, object string) (bool, error) {
+	objectLock := nsMutex.NewNSLock(bucket, object)
+	objectLock.RLock()
+	defer objectLock.RUnlock()
+
+	partsMetadata, errs := readAllXLMetadata(xl.storageDisks, bucket, object)
+	if reducedErr := reduceReadQuorumErrs(errs, nil, xl.readQuorum); reducedErr != nil {
+		return false, toObjectErr(reducedErr, bucket, object)
+	}
+	return xlShouldHeal(partsMetadata, errs), nil
+}
+
 // Heals all the metadata associated for a given bucket, this function
 // heals `policy.json`, `notification.xml` and `listeners.json`.
 func healBucketMetadata(storageDisks []StorageAPI, bucket string, readQuorum int) error {
@@ -290,7 +328,7 @@ func healObject(storageDisks []StorageAPI, bucket string, object string, quorum
 		erasure := latestMeta.Erasure
 		sumInfo := latestMeta.Erasure.GetCheckSumInfo(partName)
 		// Heal the part file.
-		checkSums, err := erasureHealFile(latestDisks, outDatedDisks,
+		checkSums, blockSums, err := erasureHealFile(latestDisks, outDatedDisks,
 			bucket, pathJoin(object, partName),
 			minioMetaTmpBucket, pathJoin(tmpID, partName),
 			partSize, erasure.BlockSize, erasure.DataBlocks, erasure.ParityBlocks, sumInfo.Algorithm)
@@ -300,9 +338,10 @@ func healObject(storageDisks []StorageAPI, bucket string, object string, quorum
 		for index, sum := range checkSums {
 			if outDatedDisks[index] != nil {
 				checkSumInfos[index] = append(checkSumInfos[index], checkSumInfo{
-					Name:      partName,
-					Algorithm: sumInfo.Algorithm,
-					Hash:      sum,
+					Name:        partName,
+					Algorithm:   sumInfo.Algorithm,
+					Hash:        sum,
+					BlockHashes: blockSums[index],
 				})
 			}
 		}
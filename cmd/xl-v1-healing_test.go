@@ -19,6 +19,7 @@ package cmd
 import (
 	"fmt"
 	"testing"
+	"time"
 )
 
 // Tests healing of format XL.
@@ -423,3 +424,123 @@ func TestQuickHeal(t *testing.T) {
 		t.Fatal("Got an unexpected error: ", err)
 	}
 }
+
+// writeFaultyDisk - wraps a disk and fails all write operations,
+// keeps track of the number of attempted MakeVol calls.
+type writeFaultyDisk struct {
+	StorageAPI
+	makeVolCalls int
+}
+
+func (d *writeFaultyDisk) MakeVol(volume string) error {
+	d.makeVolCalls++
+	return errFaultyDisk
+}
+
+func (d *writeFaultyDisk) AppendFile(volume, path string, buf []byte) error {
+	return errFaultyDisk
+}
+
+// Tests that healing buckets skips disks which cannot be written to.
+func TestHealBucketUnusableDisk(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	nDisks := 16
+	fsDirs, err := getRandomDisks(nDisks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	endpoints, err := parseStorageEndpoints(fsDirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj, _, err := initObjectLayer(endpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+
+	xl := obj.(*xlObjects)
+	for i := 0; i <= 2; i++ {
+		if err = xl.storageDisks[i].DeleteVol(bucketName); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Disk readable but cannot receive any data.
+	faultyDisk := &writeFaultyDisk{StorageAPI: xl.storageDisks[0]}
+	xl.storageDisks[0] = faultyDisk
+	if !isDiskReadable(faultyDisk) {
+		t.Fatal("Expected disk to be readable")
+	}
+	if isDiskUsable(faultyDisk) {
+		t.Fatal("Expected disk to be unusable")
+	}
+
+	if err = healBucket(xl.storageDisks, bucketName, xl.writeQuorum); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unusable disk should be skipped for writes.
+	if faultyDisk.makeVolCalls != 0 {
+		t.Fatalf("Expected no MakeVol calls on unusable disk, got %d", faultyDisk.makeVolCalls)
+	}
+	// Validate if buckets were healed on the remaining disks.
+	for i := 1; i <= 2; i++ {
+		if _, err = xl.storageDisks[i].StatVol(bucketName); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !isDiskUsable(xl.storageDisks[1]) {
+		t.Fatal("Expected disk to be usable")
+	}
+}
+
+// Tests that a disk probe in progress does not block probes of other
+// disks, and that the result of a probe is cached.
+func TestDiskProbeCache(t *testing.T) {
+	cache := &diskProbeCache{results: make(map[string]*diskProbeResult)}
+
+	// Hung probe of the first disk.
+	unblockCh := make(chan struct{})
+	doneCh := make(chan bool)
+	go func() {
+		doneCh <- cache.probe("usable:disk1", func() bool {
+			<-unblockCh
+			return true
+		})
+	}()
+
+	resultCh := make(chan bool)
+	go func() {
+		resultCh <- cache.probe("usable:disk2", func() bool { return false })
+	}()
+	select {
+	case ok := <-resultCh:
+		if ok {
+			t.Fatal("Expected the probe of disk2 to fail")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Probe of disk2 waited for the probe of disk1")
+	}
+
+	close(unblockCh)
+	if !<-doneCh {
+		t.Fatal("Expected the probe of disk1 to succeed")
+	}
+	// The result is cached, the disk is not probed again.
+	if !cache.probe("usable:disk1", func() bool { return false }) {
+		t.Fatal("Expected the cached result of disk1")
+	}
+}
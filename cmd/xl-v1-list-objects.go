@@ -16,7 +16,99 @@
 
 package cmd
 
-import "strings"
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// listDirQuorumFactory - returns listDir which lists a prefix on all the
+// disks and merges the entries found on at least quorum disks. Entries
+// on fewer disks may be partially written objects and are skipped, the
+// listing fails if fewer than quorum disks respond.
+func listDirQuorumFactory(isLeaf isLeafFunc, treeWalkIgnoredErrs []error, quorum int, disks ...StorageAPI) listDirFunc {
+	listDir := func(bucket, prefixDir, prefixEntry string) (entries []string, delayIsLeaf bool, err error) {
+		// List the prefix on all the disks in parallel.
+		var wg = &sync.WaitGroup{}
+		diskEntries := make([][]string, len(disks))
+		errs := make([]error, len(disks))
+		for index, disk := range disks {
+			if disk == nil {
+				errs[index] = errDiskNotFound
+				continue
+			}
+			wg.Add(1)
+			go func(index int, disk StorageAPI) {
+				defer wg.Done()
+				diskEntries[index], errs[index] = disk.ListDir(bucket, prefixDir)
+			}(index, disk)
+		}
+		wg.Wait()
+
+		entryCount := make(map[string]int)
+		listed, responded := 0, 0
+		var notFoundErr error
+		for index, err := range errs {
+			switch {
+			case err == nil:
+				listed++
+				responded++
+				for _, entry := range diskEntries[index] {
+					entryCount[entry]++
+				}
+			case err == errFileNotFound || err == errVolumeNotFound:
+				// Disk has nothing under the prefix.
+				responded++
+				notFoundErr = err
+			case isErrIgnored(err, treeWalkIgnoredErrs...):
+				// For any reason disk was deleted or goes offline, continue
+				// and list from other disks if possible.
+			default:
+				return nil, false, traceError(err)
+			}
+		}
+		// Entries cannot be told apart from partially written objects
+		// unless quorum disks are listed.
+		if responded < quorum {
+			return nil, false, traceError(errXLReadQuorum)
+		}
+		// Return error if the prefix is not found on any disk.
+		if listed == 0 {
+			return nil, false, traceError(notFoundErr)
+		}
+
+		entries = []string{}
+		for entry, count := range entryCount {
+			if count >= quorum {
+				entries = append(entries, entry)
+			}
+		}
+		// Listing needs to be sorted.
+		sort.Strings(entries)
+
+		// Filter entries that have the prefix prefixEntry.
+		entries = filterMatchingPrefix(entries, prefixEntry)
+
+		// Can isLeaf() check be delayed till when it has to be sent down the
+		// treeWalkResult channel?
+		delayIsLeaf = delayIsLeafCheck(entries)
+		if delayIsLeaf {
+			return entries, delayIsLeaf, nil
+		}
+
+		// isLeaf() check has to happen here so that trailing "/" for objects can be removed.
+		for i, entry := range entries {
+			if isLeaf(bucket, pathJoin(prefixDir, entry)) {
+				entries[i] = strings.TrimSuffix(entry, slashSeparator)
+			}
+		}
+		// Sort again after removing trailing "/" for objects as the previous sort
+		// does not hold good anymore.
+		sort.Strings(entries)
+		return entries, delayIsLeaf, nil
+	}
+	return listDir
+}
 
 // listObjects - wrapper function implemented over file tree walk.
 func (xl xlObjects) listObjects(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
@@ -31,7 +123,7 @@ func (xl xlObjects) listObjects(bucket, prefix, marker, delimiter string, maxKey
 	if walkResultCh == nil {
 		endWalkCh = make(chan struct{})
 		isLeaf := xl.isObject
-		listDir := listDirFactory(isLeaf, xlTreeWalkIgnoredErrs, xl.getLoadBalancedDisks()...)
+		listDir := listDirQuorumFactory(isLeaf, xlTreeWalkIgnoredErrs, xl.readQuorum, xl.getLoadBalancedDisks()...)
 		walkResultCh = startTreeWalk(bucket, prefix, marker, recursive, listDir, isLeaf, endWalkCh)
 	}
 
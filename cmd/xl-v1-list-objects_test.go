@@ -0,0 +1,190 @@
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Tests objects stored as erasure coded parts across disks are listed
+// once by their object names.
+func TestXLListObjectsAcrossDisks(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	nDisks := 4
+	fsDirs, err := getRandomDisks(nDisks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	endpoints, err := parseStorageEndpoints(fsDirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, _, err := initObjectLayer(endpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bucket := "bucket"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two single part objects.
+	data := []byte("hello, world")
+	for _, object := range []string{"object", "dir/object"} {
+		if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// One object with two parts, stored as part.1 and part.2 on every disk.
+	uploadID, err := obj.NewMultipartUpload(bucket, "multipart-object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parts []completePart
+	for i, partData := range [][]byte{bytes.Repeat([]byte("a"), 5*humanize.MiByte), data} {
+		md5Sum, perr := obj.PutObjectPart(bucket, "multipart-object", uploadID, i+1, int64(len(partData)), bytes.NewReader(partData), "", "")
+		if perr != nil {
+			t.Fatal(perr)
+		}
+		parts = append(parts, completePart{PartNumber: i + 1, ETag: md5Sum})
+	}
+	if _, err = obj.CompleteMultipartUpload(bucket, "multipart-object", uploadID, parts); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		delimiter string
+		names     []string
+	}{
+		// Recursive listing.
+		{"", []string{"dir/object", "multipart-object", "object"}},
+		// Non recursive listing, objects are followed by prefixes.
+		{slashSeparator, []string{"multipart-object", "object", "dir/"}},
+	}
+	for i, testCase := range testCases {
+		result, lerr := obj.ListObjects(bucket, "", "", testCase.delimiter, 1000)
+		if lerr != nil {
+			t.Fatalf("Test %d: %s", i+1, lerr)
+		}
+		var names []string
+		for _, objInfo := range result.Objects {
+			names = append(names, objInfo.Name)
+		}
+		for _, prefix := range result.Prefixes {
+			names = append(names, prefix)
+		}
+		if !reflect.DeepEqual(names, testCase.names) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.names, names)
+		}
+		if result.IsTruncated {
+			t.Errorf("Test %d: Expected listing not to be truncated", i+1)
+		}
+	}
+}
+
+// Tests objects found on fewer than read quorum disks are not listed.
+func TestXLListObjectsQuorum(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	nDisks := 4
+	fsDirs, err := getRandomDisks(nDisks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	endpoints, err := parseStorageEndpoints(fsDirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, _, err := initObjectLayer(endpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xl := obj.(*xlObjects)
+
+	bucket := "bucket"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("hello, world")
+	if _, err = obj.PutObject(bucket, "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Copy the metadata of "object" as "ghost" to a single disk and as
+	// "half" to read quorum disks.
+	xlMetaBuf, err := xl.storageDisks[0].ReadAll(bucket, pathJoin("object", xlMetaJSONFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	copies := map[string]int{"ghost": 1, "half": xl.readQuorum}
+	for object, count := range copies {
+		for _, disk := range xl.storageDisks[:count] {
+			if err = disk.AppendFile(bucket, pathJoin(object, xlMetaJSONFile), xlMetaBuf); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	for _, delimiter := range []string{"", slashSeparator} {
+		result, lerr := obj.ListObjects(bucket, "", "", delimiter, 1000)
+		if lerr != nil {
+			t.Fatal(lerr)
+		}
+		var names []string
+		for _, objInfo := range result.Objects {
+			names = append(names, objInfo.Name)
+		}
+		expected := []string{"half", "object"}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("Delimiter %q: Expected %v, got %v", delimiter, expected, names)
+		}
+	}
+
+	// Listing fails when fewer than read quorum disks respond, instead of
+	// returning an empty listing.
+	disks := make([]StorageAPI, len(xl.storageDisks))
+	copy(disks[:xl.readQuorum-1], xl.storageDisks)
+	listDir := listDirQuorumFactory(xl.isObject, xlTreeWalkIgnoredErrs, xl.readQuorum, disks...)
+	if _, _, err = listDir(bucket, "", ""); errorCause(err) != errXLReadQuorum {
+		t.Errorf("Expected listing with %d disks to fail with %v, got %v", xl.readQuorum-1, errXLReadQuorum, err)
+	}
+	// Prefixes found on none of the disks are still reported as such.
+	copy(disks, xl.storageDisks)
+	listDir = listDirQuorumFactory(xl.isObject, xlTreeWalkIgnoredErrs, xl.readQuorum, disks...)
+	if _, _, err = listDir(bucket, "non-existent/", ""); errorCause(err) != errFileNotFound {
+		t.Errorf("Expected listing of a missing prefix to fail with %v, got %v", errFileNotFound, err)
+	}
+}
@@ -51,6 +51,8 @@ type checkSumInfo struct {
 	Name      string `json:"name"`
 	Algorithm string `json:"algorithm"`
 	Hash      string `json:"hash"`
+	// SHA-512/256 checksums of each erasure block of the part, in order.
+	BlockHashes []string `json:"blockHashes,omitempty"`
 }
 
 // Constant indicates current bit-rot algo used when creating objects.
@@ -116,7 +118,7 @@ type xlMetaV1 struct {
 }
 
 // newXLMetaV1 - initializes new xlMetaV1, adds version, allocates a fresh erasure info.
-func newXLMetaV1(object string, dataBlocks, parityBlocks int) (xlMeta xlMetaV1) {
+func newXLMetaV1(object string, dataBlocks, parityBlocks int, blockSize int64) (xlMeta xlMetaV1) {
 	xlMeta = xlMetaV1{}
 	xlMeta.Version = "1.0.0"
 	xlMeta.Format = "xl"
@@ -125,7 +127,7 @@ func newXLMetaV1(object string, dataBlocks, parityBlocks int) (xlMeta xlMetaV1)
 		Algorithm:    erasureAlgorithmKlauspost,
 		DataBlocks:   dataBlocks,
 		ParityBlocks: parityBlocks,
-		BlockSize:    blockSizeV1,
+		BlockSize:    blockSize,
 		Distribution: hashOrder(object, dataBlocks+parityBlocks),
 	}
 	return xlMeta
@@ -45,7 +45,7 @@ func TestAddObjectPart(t *testing.T) {
 	}
 
 	// Setup.
-	xlMeta := newXLMetaV1("test-object", 8, 8)
+	xlMeta := newXLMetaV1("test-object", 8, 8, blockSizeV1)
 	if !xlMeta.IsValid() {
 		t.Fatalf("unable to get xl meta")
 	}
@@ -78,7 +78,7 @@ func TestObjectPartIndex(t *testing.T) {
 	}
 
 	// Setup.
-	xlMeta := newXLMetaV1("test-object", 8, 8)
+	xlMeta := newXLMetaV1("test-object", 8, 8, blockSizeV1)
 	if !xlMeta.IsValid() {
 		t.Fatalf("unable to get xl meta")
 	}
@@ -106,7 +106,7 @@ func TestObjectPartIndex(t *testing.T) {
 // Test xlMetaV1.ObjectToPartOffset().
 func TestObjectToPartOffset(t *testing.T) {
 	// Setup.
-	xlMeta := newXLMetaV1("test-object", 8, 8)
+	xlMeta := newXLMetaV1("test-object", 8, 8, blockSizeV1)
 	if !xlMeta.IsValid() {
 		t.Fatalf("unable to get xl meta")
 	}
@@ -168,7 +168,7 @@ func isXLMetaSimilar(m, n xlMetaV1) bool {
 
 func TestPickValidXLMeta(t *testing.T) {
 	obj := "object"
-	x1 := newXLMetaV1(obj, 4, 4)
+	x1 := newXLMetaV1(obj, 4, 4, blockSizeV1)
 	now := time.Now().UTC()
 	x1.Stat.ModTime = now
 	invalidX1 := x1
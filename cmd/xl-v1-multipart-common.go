@@ -22,8 +22,9 @@ import (
 	"time"
 )
 
-// updateUploadJSON - add or remove upload ID info in all `uploads.json`.
-func (xl xlObjects) updateUploadJSON(bucket, object, uploadID string, initiated time.Time, isRemove bool) error {
+// updateUploadJSON - applies the update to all `uploads.json`, the files
+// are removed once they have no more upload IDs.
+func (xl xlObjects) updateUploadJSON(bucket, object string, update func(uploadsJSON *uploadsV1)) error {
 	uploadsPath := path.Join(bucket, object, uploadsJSONFile)
 	tmpUploadsPath := mustGetUUID()
 
@@ -57,15 +58,9 @@ func (xl xlObjects) updateUploadJSON(bucket, object, uploadID string, initiated
 				return
 			}
 
-			if !isRemove {
-				// Add the uploadID
-				uploadsJSON.AddUploadID(uploadID, initiated)
-			} else {
-				// Remove the upload ID
-				uploadsJSON.RemoveUploadID(uploadID)
-				if len(uploadsJSON.Uploads) == 0 {
-					isDelete[index] = true
-				}
+			update(&uploadsJSON)
+			if len(uploadsJSON.Uploads) == 0 {
+				isDelete[index] = true
 			}
 
 			// For delete, rename to tmp, for the
@@ -145,14 +140,19 @@ func (xl xlObjects) updateUploadJSON(bucket, object, uploadID string, initiated
 	return nil
 }
 
-// addUploadID - add upload ID and its initiated time to 'uploads.json'.
-func (xl xlObjects) addUploadID(bucket, object string, uploadID string, initiated time.Time) error {
-	return xl.updateUploadJSON(bucket, object, uploadID, initiated, false)
+// addUploadID - add upload ID, its initiated time and the expected size
+// of the object to 'uploads.json'.
+func (xl xlObjects) addUploadID(bucket, object string, uploadID string, initiated time.Time, size int64) error {
+	return xl.updateUploadJSON(bucket, object, func(uploadsJSON *uploadsV1) {
+		uploadsJSON.AddUploadID(uploadID, initiated, size)
+	})
 }
 
 // removeUploadID - remove upload ID in 'uploads.json'.
 func (xl xlObjects) removeUploadID(bucket, object string, uploadID string) error {
-	return xl.updateUploadJSON(bucket, object, uploadID, time.Time{}, true)
+	return xl.updateUploadJSON(bucket, object, func(uploadsJSON *uploadsV1) {
+		uploadsJSON.RemoveUploadID(uploadID)
+	})
 }
 
 // Returns if the prefix is a multipart upload.
@@ -56,7 +56,12 @@ func TestUpdateUploadJSON(t *testing.T) {
 
 	xl := obj.(*xlObjects)
 	for i, test := range testCases {
-		testErrVal := xl.updateUploadJSON(bucket, object, test.uploadID, test.initiated, test.isRemove)
+		var testErrVal error
+		if test.isRemove {
+			testErrVal = xl.removeUploadID(bucket, object, test.uploadID)
+		} else {
+			testErrVal = xl.addUploadID(bucket, object, test.uploadID, test.initiated, 0)
+		}
 		if testErrVal != test.errVal {
 			t.Errorf("Test %d: Expected error value %v, but got %v",
 				i+1, test.errVal, testErrVal)
@@ -68,7 +73,7 @@ func TestUpdateUploadJSON(t *testing.T) {
 		xl.storageDisks[i] = newNaughtyDisk(xl.storageDisks[i].(*retryStorage), nil, errFaultyDisk)
 	}
 
-	testErrVal := xl.updateUploadJSON(bucket, object, "222abc", time.Now().UTC(), false)
+	testErrVal := xl.addUploadID(bucket, object, "222abc", time.Now().UTC(), 0)
 	if testErrVal == nil || testErrVal.Error() != errXLWriteQuorum.Error() {
 		t.Errorf("Expected write quorum error, but got: %v", testErrVal)
 	}
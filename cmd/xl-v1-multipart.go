@@ -72,7 +72,7 @@ func (xl xlObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 			if disk == nil {
 				continue
 			}
-			uploads, _, err = listMultipartUploadIDs(bucket, keyMarker, uploadIDMarker, maxUploads, disk)
+			uploads, _, err = listMultipartUploadIDs(bucket, keyMarker, uploadIDMarker, maxUploads, disk, readXLMetaParts)
 			if err == nil {
 				break
 			}
@@ -143,7 +143,7 @@ func (xl xlObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 				if disk == nil {
 					continue
 				}
-				newUploads, end, err = listMultipartUploadIDs(bucket, entry, uploadIDMarker, maxUploads, disk)
+				newUploads, end, err = listMultipartUploadIDs(bucket, entry, uploadIDMarker, maxUploads, disk, readXLMetaParts)
 				if err == nil {
 					break
 				}
@@ -264,7 +264,9 @@ func (xl xlObjects) ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 // disks. `uploads.json` carries metadata regarding on-going multipart
 // operation(s) on the object.
 func (xl xlObjects) newMultipartUpload(bucket string, object string, meta map[string]string) (string, error) {
-	xlMeta := newXLMetaV1(object, xl.dataBlocks, xl.parityBlocks)
+	xlMeta := newXLMetaV1(object, xl.dataBlocks, xl.parityBlocks, xl.blockSize)
+	// Expected size of the object is only saved in `uploads.json`.
+	size := getUploadSize(meta)
 	// If not set default to "application/octet-stream"
 	if meta["content-type"] == "" {
 		contentType := "application/octet-stream"
@@ -306,7 +308,7 @@ func (xl xlObjects) newMultipartUpload(bucket string, object string, meta map[st
 
 	initiated := time.Now().UTC()
 	// Create or update 'uploads.json'
-	if err := xl.addUploadID(bucket, object, uploadID, initiated); err != nil {
+	if err := xl.addUploadID(bucket, object, uploadID, initiated, size); err != nil {
 		return "", err
 	}
 	// Return success.
@@ -328,7 +330,7 @@ func (xl xlObjects) NewMultipartUpload(bucket, object string, meta map[string]st
 		return "", traceError(BucketNotFound{Bucket: bucket})
 	}
 	// Verify if object name is valid.
-	if !IsValidObjectName(object) {
+	if !IsValidObjectName(object) || isReservedObjectName(object) {
 		return "", traceError(ObjectNameInvalid{Bucket: bucket, Object: object})
 	}
 	// No metadata is set, allocate a new one.
@@ -436,7 +438,7 @@ func (xl xlObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 	}
 
 	// Erasure code data and write across all disks.
-	sizeWritten, checkSums, err := erasureCreateFile(onlineDisks, minioMetaTmpBucket, tmpPartPath, teeReader, xlMeta.Erasure.BlockSize, xl.dataBlocks, xl.parityBlocks, bitRotAlgo, xl.writeQuorum)
+	sizeWritten, checkSums, blockSums, err := erasureCreateFile(onlineDisks, minioMetaTmpBucket, tmpPartPath, teeReader, xlMeta.Erasure.BlockSize, xl.dataBlocks, xl.parityBlocks, bitRotAlgo, xl.writeQuorum)
 	if err != nil {
 		return "", toObjectErr(err, bucket, object)
 	}
@@ -513,9 +515,10 @@ func (xl xlObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 		}
 		partsMetadata[index].Parts = xlMeta.Parts
 		partsMetadata[index].Erasure.AddCheckSumInfo(checkSumInfo{
-			Name:      partSuffix,
-			Hash:      checkSums[index],
-			Algorithm: bitRotAlgo,
+			Name:        partSuffix,
+			Hash:        checkSums[index],
+			Algorithm:   bitRotAlgo,
+			BlockHashes: blockSums[index],
 		})
 	}
 
@@ -536,6 +539,33 @@ func (xl xlObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 	return newMD5Hex, nil
 }
 
+// CopyObjectPart - copies a byte range of an existing object as a part
+// of an ongoing multipart transaction, source data is streamed through
+// GetObject into PutObjectPart.
+//
+// Implements S3 compatible Upload Part Copy API.
+func (xl xlObjects) CopyObjectPart(srcBucket, srcObject, dstBucket, dstObject, uploadID string, partID int, startOffset int64, length int64) (string, error) {
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		if gerr := xl.GetObject(srcBucket, srcObject, startOffset, length, pipeWriter); gerr != nil {
+			errorIf(gerr, "Unable to read %s/%s.", srcBucket, srcObject)
+			pipeWriter.CloseWithError(gerr)
+			return
+		}
+		pipeWriter.Close() // Close writer explicitly signalling we wrote all data.
+	}()
+
+	md5Sum, err := xl.PutObjectPart(dstBucket, dstObject, uploadID, partID, length, pipeReader, "", "")
+	if err != nil {
+		// Unblock the reading go-routine upon error.
+		pipeReader.CloseWithError(err)
+		return "", err
+	}
+	// Explicitly close the reader.
+	pipeReader.Close()
+	return md5Sum, nil
+}
+
 // listObjectParts - wrapper reading `xl.json` for a given object and
 // uploadID. Lists all the parts captured inside `xl.json` content.
 func (xl xlObjects) listObjectParts(bucket, object, uploadID string, partNumberMarker, maxParts int) (ListPartsInfo, error) {
@@ -799,6 +829,11 @@ func (xl xlObjects) CompleteMultipartUpload(bucket string, object string, upload
 	// Rename if an object already exists to temporary location.
 	uniqueID := mustGetUUID()
 	if xl.isObject(bucket, object) {
+		// Locked objects cannot be overwritten until their retention expires.
+		if err = xl.checkObjectRetention(bucket, object); err != nil {
+			return "", err
+		}
+
 		// NOTE: Do not use online disks slice here.
 		// The reason is that existing object should be purged
 		// regardless of `xl.json` status and rolled back in case of errors.
@@ -20,7 +20,6 @@ import (
 	"crypto/md5"
 	"crypto/sha256"
 	"encoding/hex"
-	"hash"
 	"io"
 	"path"
 	"strings"
@@ -185,6 +184,7 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 
 		// Get the checksums of the current part.
 		checkSums := make([]string, len(onlineDisks))
+		blockSums := make([][]string, len(onlineDisks))
 		var ckSumAlgo string
 		for index, disk := range onlineDisks {
 			// Disk is not found skip the checksum.
@@ -194,6 +194,7 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 			}
 			ckSumInfo := metaArr[index].Erasure.GetCheckSumInfo(partName)
 			checkSums[index] = ckSumInfo.Hash
+			blockSums[index] = ckSumInfo.BlockHashes
 			// Set checksum algo only once, while it is possible to have
 			// different algos per block because of our `xl.json`.
 			// It is not a requirement, set this only once for all the disks.
@@ -203,7 +204,7 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 		}
 
 		// Start erasure decoding and writing to the client.
-		n, err := erasureReadFile(mw, onlineDisks, bucket, pathJoin(object, partName), partOffset, readSize, partSize, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, checkSums, ckSumAlgo, pool)
+		n, err := erasureReadFile(mw, onlineDisks, bucket, pathJoin(object, partName), partOffset, readSize, partSize, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, checkSums, blockSums, ckSumAlgo, pool)
 		if err != nil {
 			errorIf(err, "Unable to read %s of the object `%s/%s`.", partName, bucket, object)
 			return toObjectErr(err, bucket, object)
@@ -373,7 +374,7 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 	if !xl.isBucketExist(bucket) {
 		return ObjectInfo{}, traceError(BucketNotFound{Bucket: bucket})
 	}
-	if !IsValidObjectName(object) {
+	if !IsValidObjectName(object) || isReservedObjectName(object) {
 		return ObjectInfo{}, traceError(ObjectNameInvalid{
 			Bucket: bucket,
 			Object: object,
@@ -393,11 +394,10 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 
 	writers := []io.Writer{md5Writer}
 
-	var sha256Writer hash.Hash
-	if sha256sum != "" {
-		sha256Writer = sha256.New()
-		writers = append(writers, sha256Writer)
-	}
+	// SHA-256 of object data is always computed, it is saved as the
+	// object checksum.
+	sha256Writer := sha256.New()
+	writers = append(writers, sha256Writer)
 
 	// Proceed to set the cache.
 	var newBuffer io.WriteCloser
@@ -437,7 +437,7 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 	teeReader := io.TeeReader(limitDataReader, mw)
 
 	// Initialize xl meta.
-	xlMeta := newXLMetaV1(object, xl.dataBlocks, xl.parityBlocks)
+	xlMeta := newXLMetaV1(object, xl.dataBlocks, xl.parityBlocks, xl.blockSize)
 
 	onlineDisks := getOrderedDisks(xlMeta.Erasure.Distribution, xl.storageDisks)
 
@@ -447,16 +447,25 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 	defer xl.deleteObject(minioMetaTmpBucket, tempObj)
 
 	if size > 0 {
-		for _, disk := range onlineDisks {
-			if disk != nil {
-				actualSize := xl.sizeOnDisk(size, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks)
-				disk.PrepareFile(minioMetaTmpBucket, tempErasureObj, actualSize)
+		// Fail before any data is read when the object does not fit on
+		// enough disks, clients waiting for "100 Continue" before sending
+		// the body get the error right away.
+		prepareErrs := make([]error, len(onlineDisks))
+		for index, disk := range onlineDisks {
+			if disk == nil {
+				prepareErrs[index] = errDiskNotFound
+				continue
 			}
+			actualSize := xl.sizeOnDisk(size, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks)
+			prepareErrs[index] = disk.PrepareFile(minioMetaTmpBucket, tempErasureObj, actualSize)
+		}
+		if err = reduceWriteQuorumErrs(prepareErrs, objectOpIgnoredErrs, xl.writeQuorum); err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
 		}
 	}
 
 	// Erasure code data and write across all disks.
-	sizeWritten, checkSums, err := erasureCreateFile(onlineDisks, minioMetaTmpBucket, tempErasureObj, teeReader, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, bitRotAlgo, xl.writeQuorum)
+	sizeWritten, checkSums, blockSums, err := erasureCreateFile(onlineDisks, minioMetaTmpBucket, tempErasureObj, teeReader, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, bitRotAlgo, xl.writeQuorum)
 	if err != nil {
 		return ObjectInfo{}, toObjectErr(err, minioMetaTmpBucket, tempErasureObj)
 	}
@@ -506,6 +515,11 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 		}
 	}
 
+	// Verify the checksum requested by the client if any and save it.
+	if err = setObjectChecksum(metadata, sha256Writer.Sum(nil)); err != nil {
+		return ObjectInfo{}, err
+	}
+
 	// Lock the object.
 	objectLock := nsMutex.NewNSLock(bucket, object)
 	objectLock.Lock()
@@ -520,6 +534,11 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 	// Rename if an object already exists to temporary location.
 	newUniqueID := mustGetUUID()
 	if xl.isObject(bucket, object) {
+		// Locked objects cannot be overwritten until their retention expires.
+		if err = xl.checkObjectRetention(bucket, object); err != nil {
+			return ObjectInfo{}, err
+		}
+
 		// Delete the temporary copy of the object that existed before this PutObject request.
 		defer xl.deleteObject(minioMetaTmpBucket, newUniqueID)
 
@@ -545,9 +564,10 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 	for index := range partsMetadata {
 		partsMetadata[index] = xlMeta
 		partsMetadata[index].Erasure.AddCheckSumInfo(checkSumInfo{
-			Name:      "part.1",
-			Hash:      checkSums[index],
-			Algorithm: bitRotAlgo,
+			Name:        "part.1",
+			Hash:        checkSums[index],
+			Algorithm:   bitRotAlgo,
+			BlockHashes: blockSums[index],
 		})
 	}
 
@@ -582,6 +602,105 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 	return objInfo, nil
 }
 
+// checkObjectRetention - returns ObjectLocked if the existing object is
+// retained by its object lock, the object has to be locked by the caller.
+func (xl xlObjects) checkObjectRetention(bucket, object string) error {
+	objInfo, err := xl.getObjectInfo(bucket, object)
+	if err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+	if isObjectRetained(objInfo.UserDefined) {
+		return traceError(ObjectLocked{Bucket: bucket, Object: object})
+	}
+	return nil
+}
+
+// SetObjectMetadata - replaces the metadata of an existing object by
+// rewriting `xl.json` on all disks, erasure coded parts are left untouched.
+func (xl xlObjects) SetObjectMetadata(bucket, object string, metadata map[string]string) (ObjectInfo, error) {
+	// Verify if bucket is valid.
+	if !IsValidBucketName(bucket) {
+		return ObjectInfo{}, traceError(BucketNameInvalid{Bucket: bucket})
+	}
+	// Verify bucket exists.
+	if !xl.isBucketExist(bucket) {
+		return ObjectInfo{}, traceError(BucketNotFound{Bucket: bucket})
+	}
+	if !IsValidObjectName(object) {
+		return ObjectInfo{}, traceError(ObjectNameInvalid{Bucket: bucket, Object: object})
+	}
+
+	// Lock the object before updating its metadata.
+	objectLock := nsMutex.NewNSLock(bucket, object)
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	// Validate object exists.
+	if !xl.isObject(bucket, object) {
+		return ObjectInfo{}, traceError(ObjectNotFound{bucket, object})
+	}
+
+	// Read metadata associated with the object from all disks.
+	partsMetadata, errs := readAllXLMetadata(xl.storageDisks, bucket, object)
+	if !isDiskQuorum(errs, xl.writeQuorum) {
+		return ObjectInfo{}, toObjectErr(traceError(errXLWriteQuorum), bucket, object)
+	}
+
+	// List all online disks.
+	onlineDisks, modTime := listOnlineDisks(xl.storageDisks, partsMetadata, errs)
+
+	// Pick latest valid metadata.
+	xlMeta, err := pickValidXLMeta(partsMetadata, modTime)
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	// Metadata of locked objects cannot be replaced.
+	if isObjectRetained(xlMeta.Meta) {
+		return ObjectInfo{}, traceError(ObjectLocked{Bucket: bucket, Object: object})
+	}
+
+	// Reorder online disks and parts metadata based on erasure
+	// distribution order, each `xl.json` carries its disk index.
+	onlineDisks = getOrderedDisks(xlMeta.Erasure.Distribution, onlineDisks)
+	partsMetadata = getOrderedPartsMetadata(xlMeta.Erasure.Distribution, partsMetadata)
+
+	newMetadata := replaceObjectMetadata(xlMeta.Meta, metadata)
+
+	// Guess content-type from the extension if possible.
+	if newMetadata["content-type"] == "" {
+		if objectExt := path.Ext(object); objectExt != "" {
+			if content, ok := mimedb.DB[strings.ToLower(strings.TrimPrefix(objectExt, "."))]; ok {
+				newMetadata["content-type"] = content.ContentType
+			}
+		}
+	}
+
+	// Metadata change is reflected as the new modification time.
+	modTime = time.Now().UTC()
+	for index, disk := range onlineDisks {
+		if disk == nil {
+			continue
+		}
+		partsMetadata[index].Meta = newMetadata
+		partsMetadata[index].Stat.ModTime = modTime
+	}
+
+	// Write unique `xl.json` for each disk and commit it in place.
+	tempObj := mustGetUUID()
+	if err = writeUniqueXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, partsMetadata, xl.writeQuorum); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+	if err = commitXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, xl.writeQuorum); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	// Cached entries are keyed by the previous modification time.
+	xl.objCache.Delete(path.Join(bucket, object))
+
+	return xl.getObjectInfo(bucket, object)
+}
+
 // deleteObject - wrapper for delete object, deletes an object from
 // all the disks in parallel, including `xl.json` associated with the
 // object.
@@ -640,6 +759,11 @@ func (xl xlObjects) DeleteObject(bucket, object string) (err error) {
 		return traceError(ObjectNotFound{bucket, object})
 	} // else proceed to delete the object.
 
+	// Locked objects cannot be deleted until their retention expires.
+	if err = xl.checkObjectRetention(bucket, object); err != nil {
+		return err
+	}
+
 	// Delete the object on all disks.
 	err = xl.deleteObject(bucket, object)
 	if err != nil {
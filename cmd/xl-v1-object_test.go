@@ -362,3 +362,62 @@ func TestHealing(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// Tests that objects are erasure coded with the configured block size
+// and are read back using the block size saved in their metadata.
+func TestXLPutObjectBlockSize(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	// Create an instance of xl backend.
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	xl := obj.(*xlObjects)
+
+	bucket := "bucket"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	data := make([]byte, 3*humanize.MiByte+1)
+	if _, err = rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+
+	// Write objects with different block sizes.
+	blockSizes := []int64{humanize.MiByte, blockSizeV1}
+	for i, blockSize := range blockSizes {
+		xl.blockSize = blockSize
+		object := "object-" + humanize.IBytes(uint64(blockSize))
+		if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		xlMeta, rErr := readXLMeta(xl.storageDisks[0], bucket, object)
+		if rErr != nil {
+			t.Fatalf("Test %d: %s", i+1, rErr)
+		}
+		if xlMeta.Erasure.BlockSize != blockSize {
+			t.Fatalf("Test %d: Expected block size %d, got %d", i+1, blockSize, xlMeta.Erasure.BlockSize)
+		}
+	}
+
+	// Read back all the objects with a different configured block size.
+	xl.blockSize = 2 * humanize.MiByte
+	for i, blockSize := range blockSizes {
+		object := "object-" + humanize.IBytes(uint64(blockSize))
+		var buf bytes.Buffer
+		if err = obj.GetObject(bucket, object, 0, int64(len(data)), &buf); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if !bytes.Equal(buf.Bytes(), data) {
+			t.Errorf("Test %d: Object data mismatch", i+1)
+		}
+	}
+}
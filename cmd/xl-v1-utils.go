@@ -183,6 +183,9 @@ func parseXLErasureInfo(xlMetaBuf []byte) erasureInfo {
 		checkSum.Name = checkSumResult.Get("name").String()
 		checkSum.Algorithm = checkSumResult.Get("algorithm").String()
 		checkSum.Hash = checkSumResult.Get("hash").String()
+		for _, blockHash := range checkSumResult.Get("blockHashes").Array() {
+			checkSum.BlockHashes = append(checkSum.BlockHashes, blockHash.String())
+		}
 		checkSums[i] = checkSum
 	}
 	erasure.Checksum = checkSums
@@ -155,9 +155,10 @@ func newTestXLMetaV1() xlMetaV1 {
 
 func (m *xlMetaV1) AddTestObjectCheckSum(checkSumNum int, name string, hash string, algo string) {
 	checkSum := checkSumInfo{
-		Name:      name,
-		Algorithm: algo,
-		Hash:      hash,
+		Name:        name,
+		Algorithm:   algo,
+		Hash:        hash,
+		BlockHashes: []string{blockSum([]byte(name)), blockSum([]byte(hash))},
 	}
 	m.Erasure.Checksum[checkSumNum] = checkSum
 }
@@ -253,9 +254,12 @@ func compareXLMetaV1(t *testing.T, unMarshalXLMeta, gjsonXLMeta xlMetaV1) {
 			if unMarshalXLMeta.Erasure.Checksum[i].Algorithm != gjsonXLMeta.Erasure.Checksum[i].Algorithm {
 				t.Errorf("Expected the Erasure Checksum Algorithm to be \"%s\", got \"%s.\"", unMarshalXLMeta.Erasure.Checksum[i].Algorithm, gjsonXLMeta.Erasure.Checksum[i].Algorithm)
 			}
-			if unMarshalXLMeta.Erasure.Checksum[i] != gjsonXLMeta.Erasure.Checksum[i] {
+			if unMarshalXLMeta.Erasure.Checksum[i].Hash != gjsonXLMeta.Erasure.Checksum[i].Hash {
 				t.Errorf("Expected the Erasure Checksum Hash to be \"%s\", got \"%s\".", unMarshalXLMeta.Erasure.Checksum[i].Hash, gjsonXLMeta.Erasure.Checksum[i].Hash)
 			}
+			if !reflect.DeepEqual(unMarshalXLMeta.Erasure.Checksum[i].BlockHashes, gjsonXLMeta.Erasure.Checksum[i].BlockHashes) {
+				t.Errorf("Expected the Erasure Checksum block hashes to be %v, got %v.", unMarshalXLMeta.Erasure.Checksum[i].BlockHashes, gjsonXLMeta.Erasure.Checksum[i].BlockHashes)
+			}
 		}
 	}
 	if unMarshalXLMeta.Minio.Release != gjsonXLMeta.Minio.Release {
@@ -58,6 +58,7 @@ type xlObjects struct {
 	storageDisks []StorageAPI // Collection of initialized backend disks.
 	dataBlocks   int          // dataBlocks count caculated for erasure.
 	parityBlocks int          // parityBlocks count calculated for erasure.
+	blockSize    int64        // blockSize used for erasure coding new objects.
 	readQuorum   int          // readQuorum minimum required disks to read data.
 	writeQuorum  int          // writeQuorum minimum required disks to write data.
 
@@ -76,6 +77,23 @@ var xlTreeWalkIgnoredErrs = append(baseIgnoredErrs, errDiskAccessDenied, errVolu
 
 // newXLObjects - initialize new xl object layer.
 func newXLObjects(storageDisks []StorageAPI) (ObjectLayer, error) {
+	xl, err := initXLObjects(storageDisks)
+	if err != nil {
+		return nil, err
+	}
+
+	// Do a quick heal on the buckets themselves for any discrepancies.
+	if err := quickHeal(xl.storageDisks, xl.writeQuorum, xl.readQuorum); err != nil {
+		return xl, err
+	}
+
+	// Return successfully initialized object layer.
+	return xl, nil
+}
+
+// initXLObjects - initialize xl object layer without healing any
+// discrepancies on the buckets.
+func initXLObjects(storageDisks []StorageAPI) (*xlObjects, error) {
 	if storageDisks == nil {
 		return nil, errInvalidArgument
 	}
@@ -107,6 +125,7 @@ func newXLObjects(storageDisks []StorageAPI) (ObjectLayer, error) {
 		storageDisks:    newStorageDisks,
 		dataBlocks:      dataBlocks,
 		parityBlocks:    parityBlocks,
+		blockSize:       globalErasureBlockSize,
 		listPool:        listPool,
 		objCache:        objCache,
 		objCacheEnabled: !objCacheDisabled,
@@ -122,12 +141,6 @@ func newXLObjects(storageDisks []StorageAPI) (ObjectLayer, error) {
 	xl.readQuorum = readQuorum
 	xl.writeQuorum = writeQuorum
 
-	// Do a quick heal on the buckets themselves for any discrepancies.
-	if err := quickHeal(xl.storageDisks, xl.writeQuorum, xl.readQuorum); err != nil {
-		return xl, err
-	}
-
-	// Return successfully initialized object layer.
 	return xl, nil
 }
 
@@ -210,6 +223,13 @@ func getStorageInfo(disks []StorageAPI) StorageInfo {
 	storageInfo.Backend.Type = XL
 	storageInfo.Backend.OnlineDisks = onlineDisks
 	storageInfo.Backend.OfflineDisks = offlineDisks
+	for i, info := range disksInfo {
+		// Offline disks have no disk info.
+		if disks[i] == nil || info.Total == 0 {
+			continue
+		}
+		storageInfo.Disks = append(storageInfo.Disks, newDiskMetrics(disks[i].String(), info))
+	}
 	return storageInfo
 }
 
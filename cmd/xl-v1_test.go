@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -81,6 +82,44 @@ func TestStorageInfo(t *testing.T) {
 	}
 }
 
+// Tests the per disk metrics are reported for every online disk and
+// are consistent across calls.
+func TestStorageInfoDisks(t *testing.T) {
+	objLayer, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatalf("Unable to initialize 'XL' object layer.")
+	}
+
+	// Remove all dirs.
+	for _, dir := range fsDirs {
+		defer removeAll(dir)
+	}
+
+	first := objLayer.StorageInfo().Disks
+	if len(first) != len(fsDirs) {
+		t.Fatalf("Expected metrics of %d disks, got %d", len(fsDirs), len(first))
+	}
+	second := objLayer.StorageInfo().Disks
+	for i, d := range first {
+		if d.Path != fsDirs[i] {
+			t.Errorf("Disk %d: expected path %s, got %s", i, fsDirs[i], d.Path)
+		}
+		if d.TotalBytes <= 0 || d.UsedBytes+d.FreeBytes != d.TotalBytes {
+			t.Errorf("Disk %d: inconsistent capacity %#v", i, d)
+		}
+		if d.Path != second[i].Path || d.TotalBytes != second[i].TotalBytes || d.InodeTotal != second[i].InodeTotal {
+			t.Errorf("Disk %d: metrics changed across calls, %#v and %#v", i, d, second[i])
+		}
+	}
+
+	// Offline disks are not reported.
+	xl := objLayer.(*xlObjects)
+	xl.storageDisks[0] = nil
+	if disks := xl.StorageInfo().Disks; len(disks) != len(fsDirs)-1 {
+		t.Fatalf("Expected metrics of %d disks, got %d", len(fsDirs)-1, len(disks))
+	}
+}
+
 // Sort valid disks info.
 func TestSortingValidDisks(t *testing.T) {
 	testCases := []struct {
@@ -176,3 +215,73 @@ func TestNewXL(t *testing.T) {
 		t.Fatalf("Unable to initialize erasure, %s", err)
 	}
 }
+
+// TestXLDiskOrderRestart - tests that objects are read correctly after a
+// restart with the disks passed in a different order, the shard of each
+// disk is found through the disk order saved in format.json.
+func TestXLDiskOrderRestart(t *testing.T) {
+	root, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	fsDirs, err := getRandomDisks(16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	endpoints, err := parseStorageEndpoints(fsDirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, _, err := initObjectLayer(endpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("0123456789abcdef"), 2*1024*1024/16)
+	if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Restart with the disks in the reverse order.
+	reversed := make([]string, len(fsDirs))
+	for i, fsDir := range fsDirs {
+		reversed[len(fsDirs)-1-i] = fsDir
+	}
+	endpoints, err = parseStorageEndpoints(reversed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, _, err = initObjectLayer(endpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xl := obj.(*xlObjects)
+
+	// The disks are back in their original order, each holding the
+	// shard its metadata says.
+	for i, disk := range xl.storageDisks {
+		xlMeta, rErr := readXLMeta(disk, bucket, object)
+		if rErr != nil {
+			t.Fatal(rErr)
+		}
+		if xlMeta.Erasure.Index != xlMeta.Erasure.Distribution[i] {
+			t.Fatalf("Disk %d: expected shard %d, found shard %d", i, xlMeta.Erasure.Distribution[i], xlMeta.Erasure.Index)
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err = obj.GetObject(bucket, object, 0, int64(len(data)), &buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buffer.Bytes(), data) {
+		t.Fatal("Object read after restart does not match the object written")
+	}
+}